@@ -74,7 +74,7 @@ func main() {
 	})
 
 	r.Get("/ping", func(w http.ResponseWriter, r *http.Request) {
-		_, _ = w.Write([]byte("pong"))
+		_ = render.OK(w, r, responders.M{"ping": "pong"})
 	})
 
 	r.Get("/panic", func(w http.ResponseWriter, r *http.Request) {
@@ -155,10 +155,40 @@ func ArticleCtx(next http.Handler) http.Handler {
 	})
 }
 
-// SearchArticles searches the Articles data for a matching article.
-// It's just a stub, but you get the idea.
+// ArticleSearch is the request payload for searching Articles; it is bound
+// from the query string, e.g. /articles/search?q=hi&author=100
+type ArticleSearch struct {
+	Query    string `query:"q" normalize:"trim,lower"`
+	AuthorID int64  `query:"author"`
+	Limit    int    `query:"limit" default:"10"`
+
+	render.NilBinder
+}
+
+// SearchArticles searches the Articles data for matching articles.
 func SearchArticles(w http.ResponseWriter, r *http.Request) {
-	_ = render.FromContext(r).RenderList(w, r, NewArticleListResponse(articles))
+	render := render.FromContext(r)
+	search := &ArticleSearch{}
+	if err := render.BindSearch(r, search); err != nil {
+		invalidRequest := &ErrInvalidRequest{}
+		invalidRequest.Err = err
+		_ = render.Render(w, r, invalidRequest)
+		return
+	}
+	var found []*Article
+	for _, article := range articles {
+		if len(found) == search.Limit {
+			break
+		}
+		if search.AuthorID != 0 && article.UserID != search.AuthorID {
+			continue
+		}
+		if !strings.Contains(strings.ToLower(article.Title), search.Query) {
+			continue
+		}
+		found = append(found, article)
+	}
+	_ = render.RenderList(w, r, NewArticleListResponse(found))
 }
 
 // CreateArticle persists the posted Article and returns it
@@ -315,7 +345,7 @@ type ArticleRequest struct {
 
 	User *UserPayload `json:"user,omitempty"`
 
-	ProtectedID string `json:"id"` // override 'id' json to have more control
+	ProtectedID string `json:"id" protect:"true"` // override 'id' json to have more control
 }
 
 func (a *ArticleRequest) Bind(_ *http.Request) error {
@@ -329,9 +359,7 @@ func (a *ArticleRequest) Bind(_ *http.Request) error {
 	// this won't cause a panic, but checks in the Bind method may be required if
 	// a.User or further nested fields like a.User.Name are accessed elsewhere.
 
-	// just a post-process after a decode..
-	a.ProtectedID = ""                                 // unset the protected ID
-	a.Article.Title = strings.ToLower(a.Article.Title) // as an example, we down-case
+	// the protected ID is unset by the protect tag, no post-process needed
 	return nil
 }
 
@@ -477,8 +505,8 @@ type User struct {
 // and powerful data persistence adapter.
 type Article struct {
 	ID     string `json:"id"`
-	UserID int64  `json:"user_id"` // the author
-	Title  string `json:"title"`
+	UserID int64  `json:"user_id"`                      // the author
+	Title  string `json:"title" normalize:"trim,lower"` // as an example, we down-case on Bind
 	Slug   string `json:"slug"`
 }
 
@@ -0,0 +1,297 @@
+// HYBRID
+// ======
+// This example demonstrates a single route tree serving JSON to API clients
+// and templated HTML to browsers, through the same Renderers. The content type
+// of the response is negotiated from the Accept header; browsers ask for
+// text/html, everything else gets JSON.
+//
+// New notes can be created by API clients posting JSON, or by browsers posting
+// the form on /notes/new. Invalid forms are rendered again with the values
+// that were sent and the errors of the fields, and forms are protected by a
+// CSRF token bound to the session cookie.
+//
+// Boot the server, from this directory:
+// --------------------------------------
+// $ go run .
+//
+// Client requests:
+// ----------------
+// $ curl http://localhost:3333/notes
+// {"notes":[{"id":1,"title":"Hi","body":"Hello world"},{"id":2,"title":"sup","body":"Not much"}]}
+//
+// $ curl -H 'Accept: text/html' http://localhost:3333/notes/1
+// <!DOCTYPE html>
+// <html>
+// <head><title>Hi</title></head>
+// ...
+//
+// $ curl -X POST -H 'Content-Type: application/json' -d '{"title":"awesomeness"}' http://localhost:3333/notes
+// {"id":3,"title":"awesomeness","body":""}
+//
+// $ curl -X POST -H 'Content-Type: application/json' -d '{"body":"no title"}' http://localhost:3333/notes
+// {"status":"Unprocessable Entity","code":"...","error":"render: invalid fields: title: is required"}
+//
+// Or open http://localhost:3333/notes in a browser.
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+
+	render "github.com/gdey/chi-render"
+	"github.com/gdey/chi-render/responders"
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+)
+
+func main() {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		log.Fatal(err)
+	}
+	router, err := NewRouter(NewNoteStore(), key)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Fatal(http.ListenAndServe(":3333", router))
+}
+
+// NewRouter returns the routes of the application, rendering with a controller
+// that has the html templates of the pages along with the default responders.
+// key is the secret the CSRF tokens are signed with.
+func NewRouter(notes *NoteStore, key []byte) (http.Handler, error) {
+	ctrl, err := NewController(key)
+	if err != nil {
+		return nil, err
+	}
+	handlers := &Handlers{Notes: notes}
+
+	r := chi.NewRouter()
+	r.Use(middleware.Recoverer)
+	r.Use(Sessions)
+	r.Use(render.WithCtx(ctrl))
+
+	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/notes", http.StatusFound)
+	})
+	r.Route("/notes", func(r chi.Router) {
+		r.Get("/", handlers.ListNotes)       // GET /notes
+		r.Post("/", handlers.CreateNote)     // POST /notes
+		r.Get("/new", handlers.NewNote)      // GET /notes/new
+		r.Get("/{noteID}", handlers.GetNote) // GET /notes/1
+	})
+	return r, nil
+}
+
+// NewController returns a controller that renders the pages with the html
+// templates for browsers, and verifies the CSRF token of the forms they post.
+func NewController(key []byte) (*render.Controller, error) {
+	tmpls := responders.NewTemplates()
+	tmpls.AddLayout("base", "templates/layout.html")
+	tmpls.AddPage(NoteListResponse{}, "base", "templates/notes.html")
+	tmpls.AddPage(NoteResponse{}, "base", "templates/note.html")
+	tmpls.AddPage(NewNotePage{}, "base", "templates/new_note.html")
+	tmpls.AddPage(render.ErrResponse{}, "base", "templates/error.html")
+
+	ctrl := render.CloneDefault()
+	if err := ctrl.RegisterResponder(render.ContentTypeHTML, tmpls.Registration()); err != nil {
+		return nil, err
+	}
+	ctrl.CSRF = render.SessionCSRF{Key: key, Session: SessionID}
+
+	// Catch errors in the templates at startup, rather than on the first
+	// request for the page
+	if err := ctrl.ValidateTemplates(); err != nil {
+		return nil, err
+	}
+	return ctrl, nil
+}
+
+// SessionCookie is the name of the cookie with the id of the session
+const SessionCookie = "session"
+
+// Sessions is a middleware that makes sure every request has a session
+// cookie, which the CSRF tokens of the forms are bound to.
+func Sessions(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if SessionID(r) == "" {
+			id := make([]byte, 16)
+			if _, err := rand.Read(id); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			cookie := &http.Cookie{Name: SessionCookie, Value: hex.EncodeToString(id), Path: "/", HttpOnly: true}
+			http.SetCookie(w, cookie)
+			r.AddCookie(cookie)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// SessionID returns the id of the session of the request
+func SessionID(r *http.Request) string {
+	cookie, err := r.Cookie(SessionCookie)
+	if err != nil {
+		return ""
+	}
+	return cookie.Value
+}
+
+// Handlers are the http handlers of the notes
+type Handlers struct {
+	Notes *NoteStore
+}
+
+// ListNotes renders all the notes
+func (h *Handlers) ListNotes(w http.ResponseWriter, r *http.Request) {
+	_ = render.FromContext(r).Render(w, r, &NoteListResponse{Notes: h.Notes.List()})
+}
+
+// GetNote renders the note with the id in the url
+func (h *Handlers) GetNote(w http.ResponseWriter, r *http.Request) {
+	ctrl := render.FromContext(r)
+	id, _ := strconv.Atoi(chi.URLParam(r, "noteID"))
+	note, ok := h.Notes.Get(id)
+	if !ok {
+		_ = ctrl.Render(w, r, ErrNotFound("note"))
+		return
+	}
+	_ = ctrl.Render(w, r, &NoteResponse{Note: note})
+}
+
+// NewNote renders the form to create a note
+func (h *Handlers) NewNote(w http.ResponseWriter, r *http.Request) {
+	_ = render.FromContext(r).Render(w, r, &NewNotePage{})
+}
+
+// CreateNote creates a note from the JSON or the form that was posted. If the
+// form is invalid it is rendered again, for browsers to correct it.
+func (h *Handlers) CreateNote(w http.ResponseWriter, r *http.Request) {
+	ctrl := render.FromContext(r)
+	var data NoteRequest
+	if !ctrl.BindForm(w, r, &data, &NewNotePage{}) {
+		return
+	}
+	note := h.Notes.Add(data.Title, data.Body)
+	ctrl.Status(r, http.StatusCreated)
+	_ = ctrl.Render(w, r, &NoteResponse{Note: note})
+}
+
+// ErrNotFound is rendered when the resource does not exist
+func ErrNotFound(resource string) *render.ErrResponse {
+	return &render.ErrResponse{
+		StatusCode: http.StatusNotFound,
+		StatusText: "Resource not found.",
+		ErrorText:  resource + " not found",
+	}
+}
+
+//--
+// Request and Response payloads
+//--
+
+// NoteRequest is the payload of the JSON requests, and the forms, that
+// create notes
+type NoteRequest struct {
+	Title string `json:"title" form:"title"`
+	Body  string `json:"body" form:"body"`
+}
+
+// Bind checks the note has a title
+func (req *NoteRequest) Bind(r *http.Request) error {
+	if req.Title == "" {
+		return render.FieldErrors{"title": "is required"}
+	}
+	return nil
+}
+
+// NoteResponse is the payload of a note; the body of JSON responses, and the
+// data of its page
+type NoteResponse struct {
+	*Note
+}
+
+// Render does nothing, the note is rendered as is
+func (rd *NoteResponse) Render(w http.ResponseWriter, r *http.Request) error { return nil }
+
+// NoteListResponse is the payload of the list of notes
+type NoteListResponse struct {
+	Notes []*Note `json:"notes"`
+}
+
+// Render does nothing, the notes are rendered as is
+func (rd *NoteListResponse) Render(w http.ResponseWriter, r *http.Request) error { return nil }
+
+// NewNotePage is the payload of the page with the form to create notes
+type NewNotePage struct {
+	// Form is the state of the form after it failed to bind; nil on the
+	// first render of the page
+	Form *render.FormState `json:"-"`
+}
+
+// Render gets the state of the form that failed to bind
+func (page *NewNotePage) Render(w http.ResponseWriter, r *http.Request) error {
+	page.Form = render.GetFormState(r)
+	return nil
+}
+
+//--
+// Data model objects and persistence mocks
+//--
+
+// Note data model
+type Note struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+// NoteStore is an in memory store of notes
+type NoteStore struct {
+	lck    sync.RWMutex
+	lastID int
+	notes  map[int]*Note
+}
+
+// NewNoteStore returns a store with some fixture data
+func NewNoteStore() *NoteStore {
+	store := &NoteStore{notes: make(map[int]*Note)}
+	store.Add("Hi", "Hello world")
+	store.Add("sup", "Not much")
+	return store
+}
+
+// Add a note to the store
+func (store *NoteStore) Add(title, body string) *Note {
+	store.lck.Lock()
+	defer store.lck.Unlock()
+	store.lastID++
+	note := &Note{ID: store.lastID, Title: title, Body: body}
+	store.notes[note.ID] = note
+	return note
+}
+
+// Get the note with the id
+func (store *NoteStore) Get(id int) (*Note, bool) {
+	store.lck.RLock()
+	defer store.lck.RUnlock()
+	note, ok := store.notes[id]
+	return note, ok
+}
+
+// List the notes, ordered by id
+func (store *NoteStore) List() []*Note {
+	store.lck.RLock()
+	defer store.lck.RUnlock()
+	notes := make([]*Note, 0, len(store.notes))
+	for _, note := range store.notes {
+		notes = append(notes, note)
+	}
+	sort.Slice(notes, func(i, j int) bool { return notes[i].ID < notes[j].ID })
+	return notes
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func newTestRouter(t *testing.T) http.Handler {
+	router, err := NewRouter(NewNoteStore(), []byte("secret"))
+	if err != nil {
+		t.Fatalf("error, expected nil, got %v", err)
+	}
+	return router
+}
+
+func TestNotes(t *testing.T) {
+	type tcase struct {
+		Method      string
+		Path        string
+		Accept      string
+		ContentType string
+		Body        string
+		Status      int
+		Contains    string
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			router := newTestRouter(t)
+			r := httptest.NewRequest(tc.Method, tc.Path, strings.NewReader(tc.Body))
+			if tc.Accept != "" {
+				r.Header.Set("Accept", tc.Accept)
+			}
+			if tc.ContentType != "" {
+				r.Header.Set("Content-Type", tc.ContentType)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, r)
+			if w.Code != tc.Status {
+				t.Errorf("status, expected %v, got %v", tc.Status, w.Code)
+			}
+			if !strings.Contains(w.Body.String(), tc.Contains) {
+				t.Errorf("body, expected %q in %q", tc.Contains, w.Body.String())
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"list json": {
+			Method:   http.MethodGet,
+			Path:     "/notes",
+			Status:   http.StatusOK,
+			Contains: `{"notes":[{"id":1,"title":"Hi","body":"Hello world"},{"id":2,"title":"sup","body":"Not much"}]}`,
+		},
+		"list html": {
+			Method:   http.MethodGet,
+			Path:     "/notes",
+			Accept:   "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8",
+			Status:   http.StatusOK,
+			Contains: `<li><a href="/notes/2">sup</a></li>`,
+		},
+		"get json": {
+			Method:   http.MethodGet,
+			Path:     "/notes/1",
+			Accept:   "application/json",
+			Status:   http.StatusOK,
+			Contains: `{"id":1,"title":"Hi","body":"Hello world"}`,
+		},
+		"get html": {
+			Method:   http.MethodGet,
+			Path:     "/notes/1",
+			Accept:   "text/html",
+			Status:   http.StatusOK,
+			Contains: "<h1>Hi</h1>\n<p>Hello world</p>",
+		},
+		"not found json": {
+			Method:   http.MethodGet,
+			Path:     "/notes/9",
+			Status:   http.StatusNotFound,
+			Contains: `"error":"note not found"`,
+		},
+		"not found html": {
+			Method:   http.MethodGet,
+			Path:     "/notes/9",
+			Accept:   "text/html",
+			Status:   http.StatusNotFound,
+			Contains: "<h1>Resource not found.</h1>\n<p>note not found</p>",
+		},
+		"create json": {
+			Method:      http.MethodPost,
+			Path:        "/notes",
+			ContentType: "application/json",
+			Body:        `{"title":"awesomeness"}`,
+			Status:      http.StatusCreated,
+			Contains:    `{"id":3,"title":"awesomeness","body":""}`,
+		},
+		"create json invalid": {
+			Method:      http.MethodPost,
+			Path:        "/notes",
+			ContentType: "application/json",
+			Body:        `{"body":"no title"}`,
+			Status:      http.StatusUnprocessableEntity,
+			Contains:    `"error":"render: invalid fields: title: is required"`,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+var csrfFieldRe = regexp.MustCompile(`<input type="hidden" name="csrf_token" value="([^"]+)">`)
+
+func TestNoteForm(t *testing.T) {
+	router := newTestRouter(t)
+
+	// A browser gets the form, along with its session cookie
+	r := httptest.NewRequest(http.MethodGet, "/notes/new", nil)
+	r.Header.Set("Accept", "text/html")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	cookies := w.Result().Cookies()
+	match := csrfFieldRe.FindStringSubmatch(w.Body.String())
+	if len(cookies) != 1 || match == nil {
+		t.Fatalf("form, expected a session cookie and a csrf token, got %v in %q", cookies, w.Body.String())
+	}
+	token := match[1]
+
+	post := func(fields map[string]string) *httptest.ResponseRecorder {
+		// the form has no enctype, so browsers send it urlencoded
+		values := make(url.Values)
+		for name, value := range fields {
+			values.Set(name, value)
+		}
+		r := httptest.NewRequest(http.MethodPost, "/notes", strings.NewReader(values.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		r.Header.Set("Accept", "text/html")
+		r.AddCookie(cookies[0])
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		return w
+	}
+
+	t.Run("missing csrf token", func(t *testing.T) {
+		w := post(map[string]string{"title": "awesomeness"})
+		if w.Code != http.StatusForbidden {
+			t.Errorf("status, expected %v, got %v", http.StatusForbidden, w.Code)
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		w := post(map[string]string{"body": "no title", "csrf_token": token})
+		if w.Code != http.StatusUnprocessableEntity {
+			t.Errorf("status, expected %v, got %v", http.StatusUnprocessableEntity, w.Code)
+		}
+		for _, expected := range []string{
+			`<p class="error">is required</p>`,
+			`<textarea name="body">no title</textarea>`,
+		} {
+			if !strings.Contains(w.Body.String(), expected) {
+				t.Errorf("body, expected %q in %q", expected, w.Body.String())
+			}
+		}
+	})
+	t.Run("valid", func(t *testing.T) {
+		w := post(map[string]string{"title": "awesomeness", "body": "from a form", "csrf_token": token})
+		if w.Code != http.StatusCreated {
+			t.Errorf("status, expected %v, got %v", http.StatusCreated, w.Code)
+		}
+		if expected := "<h1>awesomeness</h1>\n<p>from a form</p>"; !strings.Contains(w.Body.String(), expected) {
+			t.Errorf("body, expected %q in %q", expected, w.Body.String())
+		}
+	})
+}
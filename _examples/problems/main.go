@@ -0,0 +1,354 @@
+// PROBLEMS
+// ========
+// This example demonstrates a production style error handling setup: every
+// error, whether returned by a handler, found while binding the request, or a
+// panic, is sent to the client as RFC 7807 problem details.
+//
+//   - Errors maps the errors of the application to problem types, so handlers
+//     just return errors rather than building error responses.
+//   - Handle adapts handlers that return errors into http.HandlerFuncs.
+//   - Recoverer renders panics as problems, without leaking their details.
+//   - The controller uses responders.ProblemJSON as the error responder for
+//     JSON clients, and for clients asking for application/problem+json.
+//
+// Boot the server:
+// ----------------
+// $ go run .
+//
+// Client requests:
+// ----------------
+// $ curl http://localhost:3333/orders/1
+// {"id":1,"item":"book","quantity":1}
+//
+// $ curl http://localhost:3333/orders/9
+// {"detail":"order 9 does not exist: order not found","instance":"/orders/9","request_id":"...","status":404,"title":"Order not found","type":"https://example.com/problems/order-not-found"}
+//
+// $ curl -X POST -H 'Content-Type: application/json' -d '{"item":"book","quantity":100}' http://localhost:3333/orders
+// {"detail":"only 3 book left: out of stock","instance":"/orders","request_id":"...","status":409,"title":"Out of stock","type":"https://example.com/problems/out-of-stock"}
+//
+// $ curl -X POST -H 'Content-Type: application/json' -d '{"item":"book","quantity":"one"}' http://localhost:3333/orders
+// {"detail":"render: bind 'quantity': ...","instance":"/orders","path":"quantity","request_id":"...","status":400,"title":"Bad Request","type":"about:blank"}
+//
+// $ curl -X POST -H 'Content-Type: application/json' -d '{"quantity":1}' http://localhost:3333/orders
+// {"detail":"render: invalid fields: item: is required","errors":{"item":"is required"},"instance":"/orders","request_id":"...","status":422,"title":"Unprocessable Entity","type":"about:blank"}
+//
+// $ curl http://localhost:3333/panic
+// {"detail":"the server failed to handle the request","instance":"/panic","request_id":"...","status":500,"title":"Internal Server Error","type":"about:blank"}
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"reflect"
+	"runtime/debug"
+	"strconv"
+	"sync"
+
+	render "github.com/gdey/chi-render"
+	"github.com/gdey/chi-render/responders"
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+)
+
+func main() {
+	router, err := NewRouter(NewShop())
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Fatal(http.ListenAndServe(":3333", router))
+}
+
+// NewRouter returns the routes of the application
+func NewRouter(shop *Shop) (http.Handler, error) {
+	ctrl, err := NewController()
+	if err != nil {
+		return nil, err
+	}
+
+	r := chi.NewRouter()
+	r.Use(middleware.RequestID)
+	r.Use(render.WithCtx(ctrl))
+	r.Use(Recoverer)
+
+	r.NotFound(Handle(func(w http.ResponseWriter, r *http.Request) error { return ErrRouteNotFound }))
+	r.MethodNotAllowed(Handle(func(w http.ResponseWriter, r *http.Request) error { return ErrMethodNotAllowed }))
+
+	r.Get("/orders/{orderID}", Handle(shop.GetOrder)) // GET /orders/1
+	r.Post("/orders", Handle(shop.CreateOrder))       // POST /orders
+	r.Get("/panic", func(w http.ResponseWriter, r *http.Request) {
+		panic("something went very wrong")
+	})
+	return r, nil
+}
+
+// NewController returns a controller that sends errors as problem details to
+// JSON clients, while other payloads are sent as plain JSON.
+func NewController() (*render.Controller, error) {
+	ctrl := render.CloneDefault()
+	for _, ct := range []render.ContentType{render.ContentTypeDefault, render.ContentTypeJSON, render.ContentTypeProblemJSON} {
+		if err := ctrl.SetErrorResponder(ct, responders.ProblemJSON); err != nil {
+			return nil, err
+		}
+	}
+	// Clients that only accept problem details still get them for errors
+	if err := ctrl.SetResponder(render.ContentTypeProblemJSON, responders.ProblemJSON); err != nil {
+		return nil, err
+	}
+	return ctrl, nil
+}
+
+//--
+// Error handling
+//--
+
+// ProblemType describes a kind of problem; Type is a URI that identifies it,
+// and documents it for humans.
+type ProblemType struct {
+	Type   string
+	Title  string
+	Status int
+}
+
+// ErrorRegistry maps the errors of the application to problem types
+type ErrorRegistry struct {
+	lck     sync.RWMutex
+	entries []errorEntry
+}
+
+type errorEntry struct {
+	err     error
+	problem ProblemType
+}
+
+// Register the problem type for errors that match err, using errors.Is
+func (reg *ErrorRegistry) Register(err error, problem ProblemType) {
+	reg.lck.Lock()
+	defer reg.lck.Unlock()
+	reg.entries = append(reg.entries, errorEntry{err: err, problem: problem})
+}
+
+// Lookup returns the problem type of the first registered error err matches
+func (reg *ErrorRegistry) Lookup(err error) (ProblemType, bool) {
+	reg.lck.RLock()
+	defer reg.lck.RUnlock()
+	for _, entry := range reg.entries {
+		if errors.Is(err, entry.err) {
+			return entry.problem, true
+		}
+	}
+	return ProblemType{}, false
+}
+
+// Errors is the registry of the errors of the application
+var Errors = new(ErrorRegistry)
+
+var (
+	// ErrRouteNotFound is returned for requests with an unknown path
+	ErrRouteNotFound = errors.New("no such route")
+	// ErrMethodNotAllowed is returned for requests with a method the route does not support
+	ErrMethodNotAllowed = errors.New("method not allowed")
+	// ErrOrderNotFound is returned when the order does not exist
+	ErrOrderNotFound = errors.New("order not found")
+	// ErrOutOfStock is returned when there is not enough of an item left
+	ErrOutOfStock = errors.New("out of stock")
+)
+
+func init() {
+	Errors.Register(ErrRouteNotFound, ProblemType{Type: "about:blank", Title: "Not Found", Status: http.StatusNotFound})
+	Errors.Register(ErrMethodNotAllowed, ProblemType{Type: "about:blank", Title: "Method Not Allowed", Status: http.StatusMethodNotAllowed})
+	Errors.Register(ErrOrderNotFound, ProblemType{
+		Type:   "https://example.com/problems/order-not-found",
+		Title:  "Order not found",
+		Status: http.StatusNotFound,
+	})
+	Errors.Register(ErrOutOfStock, ProblemType{
+		Type:   "https://example.com/problems/out-of-stock",
+		Title:  "Out of stock",
+		Status: http.StatusConflict,
+	})
+}
+
+// Problem is the error payload rendered for every error
+type Problem struct {
+	Err     error                     `json:"-" xml:"-"`
+	Details responders.ProblemDetails `json:"problem" xml:"problem"`
+}
+
+// NewProblem describes the error as a problem. Registered errors get their
+// problem type; errors with a StatusCode method, like the errors of Bind, get
+// their status; everything else is an internal error whose details are logged
+// rather than sent to the client.
+func NewProblem(r *http.Request, err error) *Problem {
+	pd := responders.ProblemDetails{
+		Type:     "about:blank",
+		Detail:   err.Error(),
+		Instance: r.URL.Path,
+		Extensions: map[string]interface{}{
+			"request_id": middleware.GetReqID(r.Context()),
+		},
+	}
+	var (
+		coder   interface{ StatusCode() int }
+		bindErr *render.BindError
+		fields  render.FieldErrors
+	)
+	switch problem, ok := Errors.Lookup(err); {
+	case ok:
+		pd.Type, pd.Title, pd.Status = problem.Type, problem.Title, problem.Status
+	case errors.As(err, &coder) && coder.StatusCode() < http.StatusInternalServerError:
+		pd.Status = coder.StatusCode()
+	default:
+		log.Printf("[%v] %v %v: %v", pd.Extensions["request_id"], r.Method, r.URL.Path, err)
+		pd.Status = http.StatusInternalServerError
+		pd.Detail = "the server failed to handle the request"
+	}
+	if pd.Title == "" {
+		pd.Title = http.StatusText(pd.Status)
+	}
+	if errors.As(err, &bindErr) && bindErr.Path != "" {
+		pd.Extensions["path"] = bindErr.Path
+	}
+	if errors.As(err, &fields) {
+		pd.Extensions["errors"] = map[string]string(fields)
+	}
+	return &Problem{Err: err, Details: pd}
+}
+
+func (p *Problem) Error() string { return p.Err.Error() }
+
+// Unwrap returns the error the problem describes
+func (p *Problem) Unwrap() error { return p.Err }
+
+// ProblemDetails are the details sent with responders.ProblemJSON
+func (p *Problem) ProblemDetails() responders.ProblemDetails { return p.Details }
+
+// Render sets the status of the response
+func (p *Problem) Render(w http.ResponseWriter, r *http.Request) error {
+	render.Status(r, p.Details.Status)
+	return nil
+}
+
+// HandlerFunc is a handler that returns the error it failed with
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+// Handle adapts the handler into an http.HandlerFunc that renders the errors
+// it returns as problems.
+func Handle(fn HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := fn(w, r); err != nil {
+			_ = render.FromContext(r).Render(w, r, NewProblem(r, err))
+		}
+	}
+}
+
+// Recoverer is a middleware that renders panics as internal error problems,
+// logging the panic and its stack trace. http.ErrAbortHandler is left to
+// unwind, as it aborts the response on purpose.
+func Recoverer(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			p := recover()
+			if p == nil {
+				return
+			}
+			if p == http.ErrAbortHandler {
+				panic(p)
+			}
+			err := &render.PanicError{
+				Type:   reflect.TypeOf(next),
+				Method: "ServeHTTP",
+				Value:  p,
+				Stack:  debug.Stack(),
+			}
+			log.Printf("%s", err.Stack)
+			_ = render.FromContext(r).Render(w, r, NewProblem(r, err))
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+//--
+// Handlers, payloads and data
+//--
+
+// Order is an order of a number of an item
+type Order struct {
+	ID       int    `json:"id"`
+	Item     string `json:"item"`
+	Quantity int    `json:"quantity"`
+}
+
+// Render does nothing, the order is rendered as is
+func (o *Order) Render(w http.ResponseWriter, r *http.Request) error { return nil }
+
+// OrderRequest is the payload to create orders
+type OrderRequest struct {
+	Item     string `json:"item"`
+	Quantity int    `json:"quantity" default:"1"`
+}
+
+// Bind checks the order is for an item
+func (req *OrderRequest) Bind(r *http.Request) error {
+	if req.Item == "" {
+		return render.FieldErrors{"item": "is required"}
+	}
+	return nil
+}
+
+// Shop has the orders, and the stock of the items
+type Shop struct {
+	lck    sync.Mutex
+	stock  map[string]int
+	orders map[int]*Order
+	lastID int
+}
+
+// NewShop returns a shop with some fixture data
+func NewShop() *Shop {
+	return &Shop{
+		stock:  map[string]int{"book": 3, "pen": 20},
+		orders: map[int]*Order{1: {ID: 1, Item: "book", Quantity: 1}},
+		lastID: 1,
+	}
+}
+
+// GetOrder renders the order with the id in the url
+func (shop *Shop) GetOrder(w http.ResponseWriter, r *http.Request) error {
+	id, err := strconv.Atoi(chi.URLParam(r, "orderID"))
+	if err != nil {
+		return fmt.Errorf("order %q does not exist: %w", chi.URLParam(r, "orderID"), ErrOrderNotFound)
+	}
+	shop.lck.Lock()
+	order, ok := shop.orders[id]
+	shop.lck.Unlock()
+	if !ok {
+		return fmt.Errorf("order %d does not exist: %w", id, ErrOrderNotFound)
+	}
+	return render.FromContext(r).Render(w, r, order)
+}
+
+// CreateOrder takes the items of the order out of stock
+func (shop *Shop) CreateOrder(w http.ResponseWriter, r *http.Request) error {
+	ctrl := render.FromContext(r)
+	var data OrderRequest
+	if err := ctrl.Bind(r, &data); err != nil {
+		return err
+	}
+
+	shop.lck.Lock()
+	left := shop.stock[data.Item]
+	if left < data.Quantity {
+		shop.lck.Unlock()
+		return fmt.Errorf("only %d %s left: %w", left, data.Item, ErrOutOfStock)
+	}
+	shop.stock[data.Item] = left - data.Quantity
+	shop.lastID++
+	order := &Order{ID: shop.lastID, Item: data.Item, Quantity: data.Quantity}
+	shop.orders[order.ID] = order
+	shop.lck.Unlock()
+
+	ctrl.Status(r, http.StatusCreated)
+	return ctrl.Render(w, r, order)
+}
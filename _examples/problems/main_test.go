@@ -0,0 +1,161 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestProblems(t *testing.T) {
+	type tcase struct {
+		Method      string
+		Path        string
+		Accept      string
+		Body        string
+		Status      int
+		ContentType string
+		// Problem are the members of the problem details that are checked
+		Problem map[string]interface{}
+	}
+
+	// the panics and internal errors are logged
+	log.SetOutput(ioutil.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			router, err := NewRouter(NewShop())
+			if err != nil {
+				t.Fatalf("error, expected nil, got %v", err)
+			}
+			r := httptest.NewRequest(tc.Method, tc.Path, strings.NewReader(tc.Body))
+			if tc.Body != "" {
+				r.Header.Set("Content-Type", "application/json")
+			}
+			if tc.Accept != "" {
+				r.Header.Set("Accept", tc.Accept)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, r)
+
+			if w.Code != tc.Status {
+				t.Errorf("status, expected %v, got %v", tc.Status, w.Code)
+			}
+			if ct := w.Header().Get("Content-Type"); ct != tc.ContentType {
+				t.Errorf("content type, expected %q, got %q", tc.ContentType, ct)
+			}
+			if tc.Problem == nil {
+				return
+			}
+			var problem map[string]interface{}
+			if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+				t.Fatalf("problem, expected json, got %q", w.Body.String())
+			}
+			if id, _ := problem["request_id"].(string); id == "" {
+				t.Errorf("request id, expected one, got %v", problem["request_id"])
+			}
+			for name, expected := range tc.Problem {
+				if !reflect.DeepEqual(problem[name], expected) {
+					t.Errorf("%s, expected %v, got %v", name, expected, problem[name])
+				}
+			}
+		}
+	}
+
+	const problemJSON = "application/problem+json; charset=utf-8"
+	tests := map[string]tcase{
+		"order": {
+			Method:      http.MethodGet,
+			Path:        "/orders/1",
+			Status:      http.StatusOK,
+			ContentType: "application/json; charset=utf-8",
+		},
+		"order not found": {
+			Method:      http.MethodGet,
+			Path:        "/orders/9",
+			Status:      http.StatusNotFound,
+			ContentType: problemJSON,
+			Problem: map[string]interface{}{
+				"type":     "https://example.com/problems/order-not-found",
+				"title":    "Order not found",
+				"status":   float64(http.StatusNotFound),
+				"detail":   "order 9 does not exist: order not found",
+				"instance": "/orders/9",
+			},
+		},
+		"problem accept": {
+			Method:      http.MethodGet,
+			Path:        "/orders/9",
+			Accept:      "application/problem+json",
+			Status:      http.StatusNotFound,
+			ContentType: problemJSON,
+			Problem:     map[string]interface{}{"title": "Order not found"},
+		},
+		"out of stock": {
+			Method:      http.MethodPost,
+			Path:        "/orders",
+			Body:        `{"item":"book","quantity":100}`,
+			Status:      http.StatusConflict,
+			ContentType: problemJSON,
+			Problem: map[string]interface{}{
+				"type":   "https://example.com/problems/out-of-stock",
+				"detail": "only 3 book left: out of stock",
+			},
+		},
+		"bind error": {
+			Method:      http.MethodPost,
+			Path:        "/orders",
+			Body:        `{"item":"book","quantity":"one"}`,
+			Status:      http.StatusBadRequest,
+			ContentType: problemJSON,
+			Problem: map[string]interface{}{
+				"type": "about:blank",
+				"path": "quantity",
+			},
+		},
+		"invalid fields": {
+			Method:      http.MethodPost,
+			Path:        "/orders",
+			Body:        `{"quantity":1}`,
+			Status:      http.StatusUnprocessableEntity,
+			ContentType: problemJSON,
+			Problem: map[string]interface{}{
+				"title":  "Unprocessable Entity",
+				"errors": map[string]interface{}{"item": "is required"},
+			},
+		},
+		"panic": {
+			Method:      http.MethodGet,
+			Path:        "/panic",
+			Status:      http.StatusInternalServerError,
+			ContentType: problemJSON,
+			Problem: map[string]interface{}{
+				"title":  "Internal Server Error",
+				"detail": "the server failed to handle the request",
+			},
+		},
+		"unknown route": {
+			Method:      http.MethodGet,
+			Path:        "/customers",
+			Status:      http.StatusNotFound,
+			ContentType: problemJSON,
+			Problem:     map[string]interface{}{"detail": "no such route"},
+		},
+		"method not allowed": {
+			Method:      http.MethodDelete,
+			Path:        "/orders",
+			Status:      http.StatusMethodNotAllowed,
+			ContentType: problemJSON,
+			Problem:     map[string]interface{}{"title": "Method Not Allowed"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
@@ -0,0 +1,326 @@
+// UPLOAD
+// ======
+// This example demonstrates file uploads with the multipart decoder, limits on
+// the size of the request bodies, streaming the uploaded files to disk, and
+// file downloads that support Range requests, so downloads can be resumed and
+// media can be seeked. Downloads are served straight from disk by the handler,
+// rather than by a responder, as the controller buffers what responders write.
+//
+// Boot the server, from this directory:
+// --------------------------------------
+// $ go run . -dir /tmp/uploads
+//
+// Client requests:
+// ----------------
+// $ curl -F file=@notes.txt -F description='my notes' http://localhost:3333/files
+// {"id":1,"name":"notes.txt","description":"my notes","content_type":"text/plain","size":1204,"sha256":"...","url":"/files/1/content","uploaded":"..."}
+//
+// $ curl http://localhost:3333/files
+// [{"id":1,"name":"notes.txt","description":"my notes","content_type":"text/plain","size":1204,"sha256":"...","url":"/files/1/content","uploaded":"..."}]
+//
+// $ curl -H 'Range: bytes=0-99' http://localhost:3333/files/1/content
+// (the first 100 bytes of notes.txt)
+//
+// $ curl -F file=@too-big.iso http://localhost:3333/files
+// {"status":"Request Entity Too Large","code":"...","error":"render: request body for 'multipart/form-data' exceeds limit of 10485760 bytes"}
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	render "github.com/gdey/chi-render"
+	"github.com/gdey/chi-render/decoders"
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+)
+
+// MaxUploadSize is the largest request body accepted for uploads
+const MaxUploadSize = 10 << 20
+
+var dir = flag.String("dir", "", "directory to store the uploaded files in; a temporary directory if empty")
+
+func main() {
+	flag.Parse()
+	if err := run(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// run serves the files until the server fails or is interrupted; the temporary
+// directory, if one was made, is removed before it returns.
+func run() error {
+	if *dir == "" {
+		tmp, err := ioutil.TempDir("", "uploads")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(tmp)
+		*dir = tmp
+	}
+	router, err := NewRouter(NewFileStore(*dir))
+	if err != nil {
+		return err
+	}
+
+	srv := &http.Server{Addr: ":3333", Handler: router}
+	interrupted := make(chan os.Signal, 1)
+	signal.Notify(interrupted, os.Interrupt, syscall.SIGTERM)
+	stopped := make(chan error, 1)
+	go func() {
+		<-interrupted
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		// wait for the uploads in flight, so their files are released
+		// before the directory is removed
+		stopped <- srv.Shutdown(ctx)
+	}()
+	if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+		return err
+	}
+	return <-stopped
+}
+
+func init() {
+	// Only keep the first megabyte of the uploaded files in memory while the
+	// form is decoded; the rest goes to temporary files.
+	decoders.MultipartMaxMemory = 1 << 20
+}
+
+// NewRouter returns the routes of the application
+func NewRouter(files *FileStore) (http.Handler, error) {
+	ctrl, err := NewController()
+	if err != nil {
+		return nil, err
+	}
+	handlers := &Handlers{Files: files}
+
+	r := chi.NewRouter()
+	r.Use(middleware.Recoverer)
+	r.Use(render.WithCtx(ctrl))
+
+	r.Route("/files", func(r chi.Router) {
+		r.Get("/", handlers.ListFiles)                    // GET /files
+		r.Post("/", handlers.Upload)                      // POST /files
+		r.Get("/{fileID}", handlers.GetFile)              // GET /files/1
+		r.Get("/{fileID}/content", handlers.DownloadFile) // GET /files/1/content
+	})
+	return r, nil
+}
+
+// NewController returns a controller that limits the size of multipart bodies
+func NewController() (*render.Controller, error) {
+	ctrl := render.CloneDefault()
+	if err := ctrl.SetDecoderLimit(render.ContentTypeForm, MaxUploadSize); err != nil {
+		return nil, err
+	}
+	return ctrl, nil
+}
+
+// Handlers are the http handlers of the files
+type Handlers struct {
+	Files *FileStore
+}
+
+// Upload streams the file of the form to disk. The temporary files the form was
+// decoded into are removed by the controller once the handler returns, so the
+// file is copied into the store before then.
+func (h *Handlers) Upload(w http.ResponseWriter, r *http.Request) {
+	ctrl := render.FromContext(r)
+	var data UploadRequest
+	if err := ctrl.Bind(r, &data); err != nil {
+		_ = ctrl.Render(w, r, &render.ErrResponse{Err: err, StatusCode: (&render.BindError{Cause: err}).StatusCode()})
+		return
+	}
+	file, err := h.Files.Add(data.File, data.Description)
+	if err != nil {
+		_ = ctrl.Render(w, r, &render.ErrResponse{Err: err, StatusCode: http.StatusInternalServerError})
+		return
+	}
+	ctrl.Status(r, http.StatusCreated)
+	_ = ctrl.Render(w, r, file)
+}
+
+// ListFiles renders the metadata of all the files
+func (h *Handlers) ListFiles(w http.ResponseWriter, r *http.Request) {
+	files := h.Files.List()
+	list := make([]render.Renderer, 0, len(files))
+	for _, file := range files {
+		list = append(list, file)
+	}
+	_ = render.FromContext(r).RenderList(w, r, list)
+}
+
+// GetFile renders the metadata of the file with the id in the url
+func (h *Handlers) GetFile(w http.ResponseWriter, r *http.Request) {
+	ctrl := render.FromContext(r)
+	file, ok := h.file(r)
+	if !ok {
+		_ = ctrl.Render(w, r, &render.ErrResponse{StatusCode: http.StatusNotFound, ErrorText: "file not found"})
+		return
+	}
+	_ = ctrl.Render(w, r, file)
+}
+
+// DownloadFile sends the content of the file with the id in the url. The file
+// is streamed with http.ServeContent, which answers Range and conditional
+// requests, rather than rendered, so it is never held in memory.
+func (h *Handlers) DownloadFile(w http.ResponseWriter, r *http.Request) {
+	ctrl := render.FromContext(r)
+	file, ok := h.file(r)
+	if !ok {
+		_ = ctrl.Render(w, r, &render.ErrResponse{StatusCode: http.StatusNotFound, ErrorText: "file not found"})
+		return
+	}
+	f, err := os.Open(file.path)
+	if err != nil {
+		_ = ctrl.Render(w, r, &render.ErrResponse{Err: err, StatusCode: http.StatusInternalServerError})
+		return
+	}
+	defer f.Close()
+
+	w.Header().Set("Content-Type", file.ContentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": file.Name}))
+	w.Header().Set("ETag", strconv.Quote(file.SHA256))
+	http.ServeContent(w, r, file.Name, file.Uploaded, f)
+}
+
+func (h *Handlers) file(r *http.Request) (*File, bool) {
+	id, _ := strconv.Atoi(chi.URLParam(r, "fileID"))
+	return h.Files.Get(id)
+}
+
+//--
+// Request and Response payloads
+//--
+
+// UploadRequest is the multipart form of an upload
+type UploadRequest struct {
+	File        *multipart.FileHeader `form:"file"`
+	Description string                `form:"description"`
+}
+
+// Bind checks a file was sent
+func (req *UploadRequest) Bind(r *http.Request) error {
+	if req.File == nil {
+		return render.FieldErrors{"file": "is required"}
+	}
+	return nil
+}
+
+//--
+// Data model objects and persistence
+//--
+
+// File is the metadata of an uploaded file
+type File struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	SHA256      string    `json:"sha256"`
+	URL         string    `json:"url"`
+	Uploaded    time.Time `json:"uploaded"`
+
+	// path is where the content of the file is stored
+	path string
+}
+
+// Render does nothing, the metadata is rendered as is
+func (f *File) Render(w http.ResponseWriter, r *http.Request) error { return nil }
+
+// FileStore stores the uploaded files in a directory, and their metadata in
+// memory
+type FileStore struct {
+	dir    string
+	lck    sync.RWMutex
+	lastID int
+	files  map[int]*File
+}
+
+// NewFileStore returns a store for the files in dir
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{dir: dir, files: make(map[int]*File)}
+}
+
+// Add streams the uploaded file to disk, computing its checksum on the way
+func (store *FileStore) Add(header *multipart.FileHeader, description string) (*File, error) {
+	src, err := header.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	dst, err := ioutil.TempFile(store.dir, "upload-")
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.New()
+	size, err := io.Copy(io.MultiWriter(dst, sum), src)
+	if cerr := dst.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(dst.Name())
+		return nil, fmt.Errorf("storing %s: %w", header.Filename, err)
+	}
+
+	contentType := header.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	store.lck.Lock()
+	defer store.lck.Unlock()
+	store.lastID++
+	file := &File{
+		ID:          store.lastID,
+		Name:        filepath.Base(header.Filename),
+		Description: description,
+		ContentType: contentType,
+		Size:        size,
+		SHA256:      hex.EncodeToString(sum.Sum(nil)),
+		URL:         fmt.Sprintf("/files/%d/content", store.lastID),
+		Uploaded:    time.Now().UTC().Truncate(time.Second),
+		path:        dst.Name(),
+	}
+	store.files[file.ID] = file
+	return file, nil
+}
+
+// Get the file with the id
+func (store *FileStore) Get(id int) (*File, bool) {
+	store.lck.RLock()
+	defer store.lck.RUnlock()
+	file, ok := store.files[id]
+	return file, ok
+}
+
+// List the files, ordered by id
+func (store *FileStore) List() []*File {
+	store.lck.RLock()
+	defer store.lck.RUnlock()
+	files := make([]*File, 0, len(store.files))
+	for _, file := range store.files {
+		files = append(files, file)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].ID < files[j].ID })
+	return files
+}
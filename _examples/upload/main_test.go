@@ -0,0 +1,192 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	dir, err := ioutil.TempDir("", "uploads")
+	if err != nil {
+		t.Fatal(err)
+	}
+	router, err := NewRouter(NewFileStore(dir))
+	if err != nil {
+		t.Fatalf("error, expected nil, got %v", err)
+	}
+	srv := httptest.NewServer(router)
+	t.Cleanup(func() {
+		srv.Close()
+		os.RemoveAll(dir)
+	})
+	return srv
+}
+
+// upload posts the form, with the content as the file if it is not nil
+func upload(t *testing.T, srv *httptest.Server, content []byte, description string) *http.Response {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	if content != nil {
+		fw, err := mw.CreateFormFile("file", "notes.txt")
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = fw.Write(content)
+	}
+	_ = mw.WriteField("description", description)
+	_ = mw.Close()
+	resp, err := http.Post(srv.URL+"/files", mw.FormDataContentType(), &body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+func TestUpload(t *testing.T) {
+	srv := newTestServer(t)
+	content := []byte("0123456789 hello uploads")
+
+	resp := upload(t, srv, content, "my notes")
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("status, expected %v, got %v", http.StatusCreated, resp.StatusCode)
+	}
+	var file File
+	if err := json.NewDecoder(resp.Body).Decode(&file); err != nil {
+		t.Fatal(err)
+	}
+	if file.ID != 1 || file.Name != "notes.txt" || file.Description != "my notes" || file.Size != int64(len(content)) {
+		t.Errorf("file, expected notes.txt of %v bytes, got %+v", len(content), file)
+	}
+	if sum := sha256.Sum256(content); file.SHA256 != hex.EncodeToString(sum[:]) {
+		t.Errorf("sha256, expected %x, got %q", sum, file.SHA256)
+	}
+
+	t.Run("list", func(t *testing.T) {
+		resp, err := http.Get(srv.URL + "/files")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var files []File
+		if err := json.NewDecoder(resp.Body).Decode(&files); err != nil {
+			t.Fatal(err)
+		}
+		if len(files) != 1 || files[0].URL != "/files/1/content" {
+			t.Errorf("files, expected the upload, got %+v", files)
+		}
+	})
+
+	download := func(rng string) *http.Response {
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+file.URL, nil)
+		if rng != "" {
+			req.Header.Set("Range", rng)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	type tcase struct {
+		Range  string
+		Status int
+		Body   string
+	}
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			resp := download(tc.Range)
+			defer resp.Body.Close()
+			if resp.StatusCode != tc.Status {
+				t.Errorf("status, expected %v, got %v", tc.Status, resp.StatusCode)
+			}
+			body, _ := ioutil.ReadAll(resp.Body)
+			if string(body) != tc.Body {
+				t.Errorf("body, expected %q, got %q", tc.Body, body)
+			}
+			if cd := resp.Header.Get("Content-Disposition"); cd != `attachment; filename=notes.txt` {
+				t.Errorf("content disposition, expected attachment, got %q", cd)
+			}
+		}
+	}
+	tests := map[string]tcase{
+		"whole file": {
+			Status: http.StatusOK,
+			Body:   string(content),
+		},
+		"range": {
+			Range:  "bytes=0-4",
+			Status: http.StatusPartialContent,
+			Body:   "01234",
+		},
+		"suffix range": {
+			Range:  "bytes=-7",
+			Status: http.StatusPartialContent,
+			Body:   "uploads",
+		},
+		"unsatisfiable range": {
+			Range:  "bytes=100-200",
+			Status: http.StatusRequestedRangeNotSatisfiable,
+			Body:   "invalid range: failed to overlap\n",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestUploadErrors(t *testing.T) {
+	srv := newTestServer(t)
+
+	type tcase struct {
+		Content []byte
+		Status  int
+		Body    string
+	}
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			resp := upload(t, srv, tc.Content, "")
+			defer resp.Body.Close()
+			if resp.StatusCode != tc.Status {
+				t.Errorf("status, expected %v, got %v", tc.Status, resp.StatusCode)
+			}
+			body, _ := ioutil.ReadAll(resp.Body)
+			if !strings.Contains(string(body), tc.Body) {
+				t.Errorf("body, expected %q in %q", tc.Body, body)
+			}
+		}
+	}
+	tests := map[string]tcase{
+		"no file": {
+			Status: http.StatusUnprocessableEntity,
+			Body:   `"error":"render: invalid fields: file: is required"`,
+		},
+		"too large": {
+			Content: bytes.Repeat([]byte("x"), MaxUploadSize+1),
+			Status:  http.StatusRequestEntityTooLarge,
+			Body:    "exceeds limit of 10485760 bytes",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+
+	resp, err := http.Get(srv.URL + "/files/7/content")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("missing file, expected %v, got %v", http.StatusNotFound, resp.StatusCode)
+	}
+}
@@ -0,0 +1,75 @@
+package render
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"net/http"
+	"time"
+)
+
+// AuditRecord describes a rendered response. The body itself is not part of
+// the record, only its hash, so records can be kept without retaining
+// sensitive payloads.
+type AuditRecord struct {
+	// Method is the request method
+	Method string
+	// Route is the route of the request as returned by RoutePattern
+	Route string
+	// PayloadType is the Go type of the rendered payload
+	PayloadType string
+	// Status is the status code of the response
+	Status int
+	// Size is the number of bytes in the response body
+	Size int64
+	// ContentType is the value of the Content-Type header of the response
+	ContentType string
+	// BodyHash is the hex encoded SHA-256 of the response body
+	BodyHash string
+	// Duration is the time spent responding
+	Duration time.Duration
+}
+
+// Auditor receives an AuditRecord for every response rendered by a controller.
+// It is called after the response has been written, from the handler's goroutine.
+type Auditor func(r *http.Request, record AuditRecord)
+
+// RoutePattern returns the route of the request for audit records; it defaults
+// to the path of the request. Routers that know the pattern the request
+// matched can replace it, for example with chi:
+//
+//	render.RoutePattern = func(r *http.Request) string {
+//		return chi.RouteContext(r.Context()).RoutePattern()
+//	}
+var RoutePattern = func(r *http.Request) string { return r.URL.Path }
+
+// auditTrail collects what is needed for an audit record while responding
+type auditTrail struct {
+	start       time.Time
+	payloadType string
+	written     int64
+	hash        hash.Hash
+}
+
+func newAuditTrail(v interface{}, written int64) *auditTrail {
+	return &auditTrail{
+		start:       time.Now(),
+		payloadType: fmt.Sprintf("%T", v),
+		written:     written,
+		hash:        sha256.New(),
+	}
+}
+
+func (trail *auditTrail) record(r *http.Request, status int, written int64, contentType string) AuditRecord {
+	return AuditRecord{
+		Method:      r.Method,
+		Route:       RoutePattern(r),
+		PayloadType: trail.payloadType,
+		Status:      status,
+		Size:        written - trail.written,
+		ContentType: contentType,
+		BodyHash:    hex.EncodeToString(trail.hash.Sum(nil)),
+		Duration:    time.Since(trail.start),
+	}
+}
@@ -0,0 +1,70 @@
+package render
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAudit(t *testing.T) {
+	type tcase struct {
+		Accept string
+		V      interface{}
+		Record AuditRecord
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			var records []AuditRecord
+			ctrl := defaultCtrl.Clone()
+			ctrl.Audit = func(_ *http.Request, record AuditRecord) { records = append(records, record) }
+
+			r := httptest.NewRequest(http.MethodGet, "/articles/1", nil)
+			r.Header.Set("Accept", tc.Accept)
+			w := httptest.NewRecorder()
+			ctrl.respond(w, r, tc.V)
+
+			if len(records) != 1 {
+				t.Fatalf("records, expected 1, got %v", len(records))
+			}
+			got := records[0]
+			got.Duration = 0
+			sum := sha256.Sum256(w.Body.Bytes())
+			tc.Record.BodyHash = hex.EncodeToString(sum[:])
+			tc.Record.Size = int64(w.Body.Len())
+			if got != tc.Record {
+				t.Errorf("record, expected %+v, got %+v", tc.Record, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"json": {
+			Accept: "application/json",
+			V:      map[string]int{"answer": 42},
+			Record: AuditRecord{
+				Method:      http.MethodGet,
+				Route:       "/articles/1",
+				PayloadType: "map[string]int",
+				Status:      http.StatusOK,
+				ContentType: "application/json; charset=utf-8",
+			},
+		},
+		"binary": {
+			Accept: "application/octet-stream",
+			V:      []byte("hello"),
+			Record: AuditRecord{
+				Method:      http.MethodGet,
+				Route:       "/articles/1",
+				PayloadType: "[]uint8",
+				Status:      http.StatusOK,
+				ContentType: "application/octet-stream",
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
@@ -0,0 +1,143 @@
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"reflect"
+
+	"github.com/gdey/chi-render/responders"
+	"github.com/gdey/chi-render/responders/helpers"
+)
+
+// ContentTypeMultipartMixed is the content type of batch responses written by
+// BatchMultipart
+const ContentTypeMultipartMixed = ContentType("multipart/mixed")
+
+// BatchItem is the result of one of the operations of a batch request
+type BatchItem struct {
+	// Status is the http status code of the operation; if zero it is the status
+	// set by the Render method of the body, or 200 OK
+	Status int `json:"status"`
+	// Headers are the headers of the operation the client should see
+	Headers http.Header `json:"headers,omitempty"`
+	// Body is the payload of the operation; Renderers are rendered
+	Body interface{} `json:"body,omitempty"`
+}
+
+// Batch is a renderer for the results of a batch request; it responds with
+// 207 Multi-Status and a status, headers and body for every item. The structured
+// responders write it as JSON:
+//
+//	{"responses":[{"status":201,"body":{...}},{"status":404,"body":{...}}]}
+//
+// Register BatchMultipart for ContentTypeMultipartMixed to also offer it as a
+// multipart/mixed response, with a application/http part for each item.
+type Batch struct {
+	Items []BatchItem `json:"responses"`
+}
+
+// Render renders the bodies of the items, collecting the status and headers
+// each Render method set for the item.
+func (batch *Batch) Render(_ http.ResponseWriter, r *http.Request) error {
+	for i := range batch.Items {
+		item := &batch.Items[i]
+		if body, ok := item.Body.(Renderer); ok {
+			ctx := r.Context()
+			rec := newResponseRecorder(nil)
+			if err := renderer(rec, r, body); err != nil {
+				return wrapPanicError(fmt.Sprintf("responses[%d]", i), err)
+			}
+			status, _ := r.Context().Value(helpers.StatusCtxKey).(int)
+			*r = *r.WithContext(ctx)
+			if item.Status == 0 && status != 0 {
+				item.Status = status
+			}
+			for name, values := range rec.header {
+				if item.Headers == nil {
+					item.Headers = make(http.Header)
+				}
+				if _, ok := item.Headers[name]; !ok {
+					item.Headers[name] = values
+				}
+			}
+		}
+		if item.Status == 0 {
+			item.Status = http.StatusOK
+		}
+	}
+	Status(r, http.StatusMultiStatus)
+	return nil
+}
+
+// BatchMultipart is a responder that writes a *Batch as multipart/mixed, with a
+// application/http part holding the status line, headers and body of each item.
+// Bodies that are not a string or []byte are written as JSON. It is structured
+// however it is registered: the policies of the controller, like redaction,
+// are applied to each body as it is written.
+func BatchMultipart(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	var p *projector
+	if pr, ok := v.(*projection); ok {
+		p, v = pr.p, pr.v.Interface()
+	}
+	batch, ok := v.(*Batch)
+	if !ok {
+		return responders.ErrCanNotEncodeObject
+	}
+	var buff bytes.Buffer
+	mw := multipart.NewWriter(&buff)
+	for _, item := range batch.Items {
+		body, contentType, err := batchItemBody(p, item.Body)
+		if err != nil {
+			return err
+		}
+		part, err := mw.CreatePart(textproto.MIMEHeader{"Content-Type": {"application/http"}})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(part, "HTTP/1.1 %03d %s\r\n", item.Status, http.StatusText(item.Status))
+		header := item.Headers.Clone()
+		if header == nil {
+			header = make(http.Header)
+		}
+		if contentType != "" && header.Get("Content-Type") == "" {
+			header.Set("Content-Type", contentType)
+		}
+		if err := header.Write(part); err != nil {
+			return err
+		}
+		fmt.Fprint(part, "\r\n")
+		if _, err := part.Write(body); err != nil {
+			return err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return err
+	}
+	helpers.SetContentTypeHeader(w, string(ContentTypeMultipartMixed)+"; boundary="+mw.Boundary())
+	helpers.WriteStatus(w, r.Context())
+	_, err := w.Write(buff.Bytes())
+	return err
+}
+
+// batchItemBody returns the bytes of the body of a batch item, and the content
+// type they are in, if known. JSON bodies are projected with p, if not nil.
+func batchItemBody(p *projector, v interface{}) (body []byte, contentType string, err error) {
+	switch vv := v.(type) {
+	case nil:
+		return nil, "", nil
+	case []byte:
+		return vv, "", nil
+	case string:
+		return []byte(vv), "text/plain; charset=utf-8", nil
+	default:
+		if p != nil {
+			v = &projection{p: p, v: reflect.ValueOf(v)}
+		}
+		body, err = json.Marshal(v)
+		return body, "application/json", err
+	}
+}
@@ -0,0 +1,137 @@
+package render
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gdey/chi-render/responders"
+)
+
+func newTestBatch() *Batch {
+	return &Batch{Items: []BatchItem{
+		{Status: http.StatusCreated, Body: responders.M{"id": 1}},
+		{Body: &ErrResponse{StatusCode: http.StatusNotFound}},
+		{Body: "plain"},
+	}}
+}
+
+func TestBatch(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/batch", nil)
+	r.Header.Set("Accept", "application/json")
+	w := httptest.NewRecorder()
+	if err := defaultCtrl.Render(w, r, newTestBatch()); err != nil {
+		t.Fatalf("error, expected nil, got %v", err)
+	}
+	if w.Code != http.StatusMultiStatus {
+		t.Errorf("status, expected %v, got %v", http.StatusMultiStatus, w.Code)
+	}
+	var got struct {
+		Responses []struct {
+			Status  int         `json:"status"`
+			Headers http.Header `json:"headers"`
+			Body    interface{} `json:"body"`
+		} `json:"responses"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("unmarshal error, expected nil, got %v: %s", err, w.Body.String())
+	}
+	if len(got.Responses) != 3 {
+		t.Fatalf("responses, expected 3, got %v", len(got.Responses))
+	}
+	for i, status := range []int{http.StatusCreated, http.StatusNotFound, http.StatusOK} {
+		if got.Responses[i].Status != status {
+			t.Errorf("responses[%d] status, expected %v, got %v", i, status, got.Responses[i].Status)
+		}
+	}
+	if got.Responses[1].Headers.Get(ErrorHeaderPrefix+errorStatusHeader) != "Not Found" {
+		t.Errorf("responses[1] headers, expected the error headers, got %v", got.Responses[1].Headers)
+	}
+	if body, _ := got.Responses[1].Body.(map[string]interface{}); body["status"] != "Not Found" {
+		t.Errorf("responses[1] body, expected the error response, got %v", got.Responses[1].Body)
+	}
+}
+
+func TestBatchMultipart(t *testing.T) {
+	ctrl := defaultCtrl.Clone()
+	_ = ctrl.SetResponder(ContentTypeMultipartMixed, BatchMultipart)
+
+	r := httptest.NewRequest(http.MethodPost, "/batch", nil)
+	r.Header.Set("Accept", "multipart/mixed")
+	w := httptest.NewRecorder()
+	if err := ctrl.Render(w, r, newTestBatch()); err != nil {
+		t.Fatalf("error, expected nil, got %v", err)
+	}
+	if w.Code != http.StatusMultiStatus {
+		t.Errorf("status, expected %v, got %v", http.StatusMultiStatus, w.Code)
+	}
+	mediaType, params, err := mime.ParseMediaType(w.Header().Get("Content-Type"))
+	if err != nil || mediaType != string(ContentTypeMultipartMixed) {
+		t.Fatalf("content type, expected multipart/mixed, got %v", w.Header().Get("Content-Type"))
+	}
+
+	type part struct {
+		Status      int
+		ContentType string
+		Body        string
+	}
+	expected := []part{
+		{http.StatusCreated, "application/json", `{"id":1}`},
+		{http.StatusNotFound, "application/json", ""},
+		{http.StatusOK, "text/plain; charset=utf-8", "plain"},
+	}
+	mr := multipart.NewReader(w.Body, params["boundary"])
+	for i, exp := range expected {
+		p, err := mr.NextPart()
+		if err != nil {
+			t.Fatalf("part %d error, expected nil, got %v", i, err)
+		}
+		resp, err := http.ReadResponse(bufio.NewReader(p), nil)
+		if err != nil {
+			t.Fatalf("part %d response error, expected nil, got %v", i, err)
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+		if resp.StatusCode != exp.Status || resp.Header.Get("Content-Type") != exp.ContentType {
+			t.Errorf("part %d, expected %v %v, got %v %v", i, exp.Status, exp.ContentType, resp.StatusCode, resp.Header.Get("Content-Type"))
+		}
+		if exp.Body != "" && string(body) != exp.Body {
+			t.Errorf("part %d body, expected %s, got %s", i, exp.Body, body)
+		}
+	}
+	if _, err := mr.NextPart(); err != io.EOF {
+		t.Errorf("parts, expected io.EOF after %d parts, got %v", len(expected), err)
+	}
+}
+
+// TestBatchMultipartRedact checks that the bodies of the parts have the
+// policies applied
+func TestBatchMultipartRedact(t *testing.T) {
+	ctrl := defaultCtrl.Clone()
+	ctrl.Redact = RedactMask
+	_ = ctrl.SetResponder(ContentTypeMultipartMixed, BatchMultipart)
+
+	r := httptest.NewRequest(http.MethodPost, "/batch", nil)
+	r.Header.Set("Accept", "multipart/mixed")
+	w := httptest.NewRecorder()
+	batch := &Batch{Items: []BatchItem{{Body: &projectionUser{ID: 1, Name: "gopher", Email: "g@example.org"}}}}
+	if err := ctrl.Render(w, r, batch); err != nil {
+		t.Fatalf("error, expected nil, got %v", err)
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, string(ContentTypeMultipartMixed)) {
+		t.Errorf("content type, expected multipart/mixed, got %v", ct)
+	}
+	expected := `{"id":1,"name":"gopher","email":"[REDACTED]","token":"[REDACTED]"}`
+	if !strings.Contains(w.Body.String(), expected) {
+		t.Errorf("body, expected %s in %s", expected, w.Body.String())
+	}
+	if strings.Contains(w.Body.String(), "g@example.org") {
+		t.Errorf("body, expected the email to be redacted, got %s", w.Body.String())
+	}
+}
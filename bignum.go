@@ -0,0 +1,154 @@
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// maxSafeInteger is the largest integer a float64, and so JavaScript, can
+// represent exactly
+const maxSafeInteger = 1<<53 - 1
+
+var (
+	bigIntType = reflect.TypeOf(big.Int{})
+)
+
+// bigNumber returns the integer as a string if it is too large to be safely
+// read by JavaScript clients
+func bigNumber(v reflect.Value) (interface{}, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int64:
+		if i := v.Int(); i > maxSafeInteger || i < -maxSafeInteger {
+			return strconv.FormatInt(i, 10), true
+		}
+	case reflect.Uint, reflect.Uint64, reflect.Uintptr:
+		if u := v.Uint(); u > maxSafeInteger {
+			return strconv.FormatUint(u, 10), true
+		}
+	}
+	return nil, false
+}
+
+// bigNumberLeaf returns the value of a type that writes itself as a JSON number,
+// like big.Int or a decimal type, as a string if it is a decimal or too large
+// to be safely read by JavaScript clients.
+func bigNumberLeaf(leaf interface{}) (interface{}, bool, error) {
+	marshaler, ok := leaf.(json.Marshaler)
+	if !ok {
+		return nil, false, nil
+	}
+	if v := reflect.ValueOf(leaf); v.Kind() == reflect.Ptr && v.IsNil() {
+		return nil, false, nil
+	}
+	b, err := marshaler.MarshalJSON()
+	if err != nil {
+		return nil, false, err
+	}
+	num := string(bytes.TrimSpace(b))
+	if num == "" || !(num[0] == '-' || (num[0] >= '0' && num[0] <= '9')) {
+		return nil, false, nil
+	}
+	if !strings.ContainsAny(num, ".eE") {
+		if i, err := strconv.ParseInt(num, 10, 64); err == nil && i <= maxSafeInteger && i >= -maxSafeInteger {
+			return nil, false, nil
+		}
+	}
+	return num, true, nil
+}
+
+// unquoteNumbers reads the JSON body, replacing the strings that are going to
+// be decoded into numbers, as written when BigNumbersAsStrings is set, with the
+// numbers they hold.
+func unquoteNumbers(body io.Reader, v interface{}) (io.Reader, error) {
+	b, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var tree interface{}
+	if err := dec.Decode(&tree); err != nil {
+		// let the decoder report the error
+		return bytes.NewReader(b), nil
+	}
+	tree, changed := unquoteValue(tree, reflect.TypeOf(v))
+	if !changed {
+		return bytes.NewReader(b), nil
+	}
+	b, err = json.Marshal(tree)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(b), nil
+}
+
+// unquoteValue walks the decoded JSON along side the type it is going to be
+// decoded into
+func unquoteValue(tree interface{}, typ reflect.Type) (interface{}, bool) {
+	for typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ == nil {
+		return tree, false
+	}
+	switch tree := tree.(type) {
+	case string:
+		switch typ.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+			reflect.Float32, reflect.Float64:
+		default:
+			if typ != bigIntType {
+				return tree, false
+			}
+		}
+		if _, err := strconv.ParseFloat(tree, 64); err != nil {
+			return tree, false
+		}
+		return json.Number(tree), true
+
+	case []interface{}:
+		if typ.Kind() != reflect.Slice && typ.Kind() != reflect.Array {
+			return tree, false
+		}
+		changed := false
+		for i := range tree {
+			var c bool
+			tree[i], c = unquoteValue(tree[i], typ.Elem())
+			changed = changed || c
+		}
+		return tree, changed
+
+	case map[string]interface{}:
+		changed := false
+		switch typ.Kind() {
+		case reflect.Map:
+			for key := range tree {
+				var c bool
+				tree[key], c = unquoteValue(tree[key], typ.Elem())
+				changed = changed || c
+			}
+		case reflect.Struct:
+			fields := structFields(typ)
+			for key := range tree {
+				for _, sf := range fields {
+					if sf.json.skip || !strings.EqualFold(sf.json.name, key) {
+						continue
+					}
+					var c bool
+					tree[key], c = unquoteValue(tree[key], sf.typ)
+					changed = changed || c
+					break
+				}
+			}
+		}
+		return tree, changed
+	}
+	return tree, false
+}
@@ -0,0 +1,108 @@
+package render
+
+import (
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// price is a stand in for a decimal type
+type price string
+
+func (p price) MarshalJSON() ([]byte, error) { return []byte(p), nil }
+
+func TestBigNumbersAsStrings(t *testing.T) {
+	type order struct {
+		ID       int64    `json:"id"`
+		Count    uint64   `json:"count"`
+		Small    int      `json:"small"`
+		Total    price    `json:"total"`
+		Serial   *big.Int `json:"serial"`
+		Children []int64  `json:"children,omitempty"`
+	}
+	type tcase struct {
+		Order order
+		Body  string
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			ctrl := defaultCtrl.Clone()
+			ctrl.BigNumbersAsStrings = true
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			w := httptest.NewRecorder()
+			ctrl.respond(w, r, tc.Order)
+			if got := w.Body.String(); got != tc.Body {
+				t.Errorf("body, expected %s, got %s", tc.Body, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"safe": {
+			Order: order{ID: 1, Count: 2, Small: 3, Total: "4", Serial: big.NewInt(5)},
+			Body:  `{"id":1,"count":2,"small":3,"total":4,"serial":5}` + "\n",
+		},
+		"big": {
+			Order: order{
+				ID: -1 << 60, Count: 1 << 63, Small: 3, Total: "12.50",
+				Serial: new(big.Int).Lsh(big.NewInt(1), 70), Children: []int64{1, 1 << 54},
+			},
+			Body: `{"id":"-1152921504606846976","count":"9223372036854775808","small":3,"total":"12.50",` +
+				`"serial":"1180591620717411303424","children":[1,"18014398509481984"]}` + "\n",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestBindBigNumbersAsStrings(t *testing.T) {
+	type payload struct {
+		ID       int64             `json:"id"`
+		Amount   float64           `json:"amount"`
+		Serial   *big.Int          `json:"serial"`
+		Name     string            `json:"name"`
+		Children []uint64          `json:"children"`
+		Counts   map[string]int64  `json:"counts"`
+		Labels   map[string]string `json:"labels"`
+		NilBinder
+	}
+
+	ctrl := CloneDefault()
+	ctrl.BigNumbersAsStrings = true
+
+	body := `{"ID":"-1152921504606846976","amount":"12.5","serial":"1180591620717411303424","name":"42",` +
+		`"children":[1,"18014398509481984"],"counts":{"a":"9007199254740993"},"labels":{"a":"1"}}`
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+
+	var p payload
+	if err := ctrl.Bind(r, &p); err != nil {
+		t.Fatalf("error, expected nil, got %v", err)
+	}
+	if p.ID != -1<<60 {
+		t.Errorf("id, expected %v, got %v", int64(-1<<60), p.ID)
+	}
+	if p.Amount != 12.5 {
+		t.Errorf("amount, expected 12.5, got %v", p.Amount)
+	}
+	if p.Serial == nil || p.Serial.String() != "1180591620717411303424" {
+		t.Errorf("serial, expected 1180591620717411303424, got %v", p.Serial)
+	}
+	if p.Name != "42" {
+		t.Errorf("name, expected 42, got %v", p.Name)
+	}
+	if len(p.Children) != 2 || p.Children[1] != 1<<54 {
+		t.Errorf("children, expected [1 %v], got %v", uint64(1<<54), p.Children)
+	}
+	if p.Counts["a"] != 9007199254740993 {
+		t.Errorf("counts, expected 9007199254740993, got %v", p.Counts["a"])
+	}
+	if p.Labels["a"] != "1" {
+		t.Errorf("labels, expected 1, got %v", p.Labels["a"])
+	}
+}
@@ -0,0 +1,106 @@
+package render
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// BindError is returned by Bind when decoding the request body, or the Bind
+// method of a nested payload, fails; it records the path of the field that
+// failed, like `items[3].price`, so clients get an actionable message.
+type BindError struct {
+	// Path is the JSON path of the field that failed; empty if unknown
+	Path string
+	// Cause is the underlying error
+	Cause error
+}
+
+func (err *BindError) Error() string {
+	if err.Path == "" {
+		return err.Cause.Error()
+	}
+	return fmt.Sprintf("render: bind '%s': %v", err.Path, err.Cause)
+}
+
+// Unwrap returns the underlying error
+func (err *BindError) Unwrap() error { return err.Cause }
+
+// StatusCode is the http status code that should be reported to the client;
+// the status code of the underlying error if it has one, otherwise 400.
+func (err *BindError) StatusCode() int {
+	var coder interface{ StatusCode() int }
+	if errors.As(err.Cause, &coder) {
+		return coder.StatusCode()
+	}
+	return http.StatusBadRequest
+}
+
+// joinBindPath joins the path of a field, or slice index, under the prefix
+func joinBindPath(prefix, path string) string {
+	switch {
+	case prefix == "":
+		return path
+	case path == "":
+		return prefix
+	case strings.HasPrefix(path, "["):
+		return prefix + path
+	default:
+		return prefix + "." + path
+	}
+}
+
+// wrapBindError wraps err in a *BindError for the field name; if err is already
+// a *BindError, or a *PanicError, the name is prepended to its path.
+func wrapBindError(name string, err error) error {
+	if _, ok := err.(*PanicError); ok {
+		return wrapPanicError(name, err)
+	}
+	if be, ok := err.(*BindError); ok {
+		return &BindError{Path: joinBindPath(name, be.Path), Cause: be.Cause}
+	}
+	if name == "" {
+		return err
+	}
+	return &BindError{Path: name, Cause: err}
+}
+
+// wrapDecodeError wraps decode errors that know the field that failed in a *BindError
+func wrapDecodeError(err error) error {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return &BindError{Path: jsonFieldPath(typeErr.Field), Cause: err}
+	}
+	return err
+}
+
+// jsonFieldPath converts the dotted field of a json.UnmarshalTypeError, which
+// may include slice indexes, like `items.3.price`, into a bind path like
+// `items[3].price`
+func jsonFieldPath(field string) string {
+	var path string
+	for _, name := range strings.Split(field, ".") {
+		if _, err := strconv.Atoi(name); err == nil {
+			name = "[" + name + "]"
+		}
+		path = joinBindPath(path, name)
+	}
+	return path
+}
+
+// bindFieldName is the name used in bind paths for a struct field; embedded
+// structs are promoted, so they have no name.
+func bindFieldName(sf reflect.StructField) string {
+	tag := parseTag(sf.Tag.Get("json"))
+	if tag.name != "" {
+		return tag.name
+	}
+	if sf.Anonymous {
+		return ""
+	}
+	return sf.Name
+}
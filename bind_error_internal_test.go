@@ -0,0 +1,103 @@
+package render
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+var errNegativePrice = errors.New("price must be positive")
+
+type testPrice float64
+
+func (p testPrice) Bind(*http.Request) error {
+	if p < 0 {
+		return errNegativePrice
+	}
+	return nil
+}
+
+type testLineItem struct {
+	Name  string    `json:"name"`
+	Price testPrice `json:"price"`
+}
+
+func (*testLineItem) Bind(*http.Request) error { return nil }
+
+type testOrder struct {
+	Items []*testLineItem `json:"items"`
+	NilBinder
+}
+
+func TestBindError(t *testing.T) {
+	type tcase struct {
+		Body   string
+		Path   string
+		Cause  error
+		Status int
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.Body))
+			r.Header.Set("Content-Type", "application/json")
+
+			var order testOrder
+			err := defaultCtrl.Bind(r, &order)
+			var bindErr *BindError
+			if !errors.As(err, &bindErr) {
+				t.Errorf("error, expected *BindError, got %v", err)
+				return
+			}
+			// older versions of encoding/json do not report the slice index
+			if bindErr.Path != tc.Path && bindErr.Path != strings.Replace(tc.Path, "[0]", "", 1) {
+				t.Errorf("path, expected %v, got %v", tc.Path, bindErr.Path)
+			}
+			if tc.Cause != nil && !errors.Is(err, tc.Cause) {
+				t.Errorf("cause, expected %v, got %v", tc.Cause, bindErr.Cause)
+			}
+			if bindErr.StatusCode() != tc.Status {
+				t.Errorf("status code, expected %v, got %v", tc.Status, bindErr.StatusCode())
+			}
+
+			w := httptest.NewRecorder()
+			errResp := &ErrResponse{Err: err, StatusCode: bindErr.StatusCode(), LogTo: func(*ErrResponse) {}}
+			if err := defaultCtrl.Render(w, r, errResp); err != nil {
+				t.Errorf("render error, expected nil, got %v", err)
+				return
+			}
+			if !strings.Contains(w.Body.String(), `"path":"`+bindErr.Path+`"`) {
+				t.Errorf("body, expected path %v, got %s", tc.Path, w.Body.String())
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"nested bind": {
+			Body:   `{"items":[{"name":"a","price":1},{"name":"b","price":-1}]}`,
+			Path:   "items[1].price",
+			Cause:  errNegativePrice,
+			Status: http.StatusBadRequest,
+		},
+		"decode": {
+			Body:   `{"items":[{"name":"a","price":"free"}]}`,
+			Path:   "items[0].price",
+			Status: http.StatusBadRequest,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestBindErrorPath(t *testing.T) {
+	if got, expected := jsonFieldPath("items.3.tags.0"), "items[3].tags[0]"; got != expected {
+		t.Errorf("json path, expected %v, got %v", expected, got)
+	}
+	err := wrapBindError("order", wrapBindError("items[2]", wrapBindError("price", errNegativePrice)))
+	if got, expected := err.Error(), "render: bind 'order.items[2].price': price must be positive"; got != expected {
+		t.Errorf("error, expected %v, got %v", expected, got)
+	}
+}
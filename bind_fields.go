@@ -0,0 +1,179 @@
+package render
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// fieldBinder walks the decoded payload, before the Binders are called, applying
+// the struct tags and types that Bind handles for every field: protected fields
+// are put back, strings are normalized, zero valued fields are given their
+// default, then enums are validated and canonicalized.
+type fieldBinder struct {
+	ctrl *Controller
+	// protected are the values of the protected fields before the body was
+	// decoded, by path
+	protected map[string]reflect.Value
+	// protectOnly has only the protected fields put back, or rejected
+	protectOnly bool
+}
+
+// bindFields applies the field binding to the decoded payload
+func (ctrl *Controller) bindFields(v interface{}, protected map[string]reflect.Value) error {
+	b := fieldBinder{
+		ctrl:      ctrl,
+		protected: protected,
+	}
+	return b.value(reflect.ValueOf(v), "", 0)
+}
+
+// restoreProtected puts back, or rejects, the protected fields of v that were
+// changed from their protected values
+func restoreProtected(v reflect.Value, protected map[string]reflect.Value) error {
+	b := fieldBinder{
+		protected:   protected,
+		protectOnly: true,
+	}
+	return b.value(v, "", 0)
+}
+
+func (b *fieldBinder) value(v reflect.Value, path string, depth int) error {
+	if !v.IsValid() || depth > maxProjectionDepth {
+		return nil
+	}
+	if _, ok := isEnum(v); ok && !b.protectOnly {
+		return bindEnum(v, path)
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return b.value(v.Elem(), path, depth+1)
+
+	case reflect.Struct:
+		for _, sf := range boundFields(v.Type()) {
+			fv, ok := fieldByIndex(v, sf.index)
+			if !ok {
+				continue
+			}
+			fieldPath := joinPath(path, sf.json.name)
+			if err := b.bindProtected(sf, fv, fieldPath); err != nil {
+				return err
+			}
+			if b.protectOnly {
+				if err := b.value(fv, fieldPath, depth+1); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := bindNormalize(sf, fv, fieldPath); err != nil {
+				return err
+			}
+			if err := b.ctrl.bindDefault(sf, fv, fieldPath); err != nil {
+				return err
+			}
+			if err := b.value(fv, fieldPath, depth+1); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := b.value(v.Index(i), path+"["+strconv.Itoa(i)+"]", depth+1); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			// map values can not be set in place, so work on a copy
+			item := reflect.New(iter.Value().Type()).Elem()
+			item.Set(iter.Value())
+			key, _ := mapKey(iter.Key())
+			if err := b.value(item, joinPath(path, key), depth+1); err != nil {
+				return err
+			}
+			v.SetMapIndex(iter.Key(), item)
+		}
+	}
+	return nil
+}
+
+// boundFieldsCache caches the fields of the struct types that are bound
+var boundFieldsCache sync.Map // map[reflect.Type][]structField
+
+// bindTags are the struct tags of the fields bound from other sources than
+// the JSON body
+var bindTags = []string{"query", "path", "header", "cookie", "form"}
+
+// boundFields returns the fields of the struct that Bind sets and applies its
+// tags to: the fields encoding/json sees, along with the ones it skips that
+// are bound from the URL, headers, cookies or forms, like `json:"-" query:"q"`.
+// Fields skipped by encoding/json are named by their Go name in the paths.
+func boundFields(typ reflect.Type) []structField {
+	if fields, ok := boundFieldsCache.Load(typ); ok {
+		return fields.([]structField)
+	}
+	fields := walkStructFields(typ)
+	tagged := fields[:0]
+	for _, f := range fields {
+		if !f.json.skip || hasBindTag(f) {
+			tagged = append(tagged, f)
+		}
+	}
+	fields = tagged
+	hidden := dominated(fields, func(f structField) string {
+		if f.json.skip {
+			// a name encoding/json can not give a field
+			return "-" + f.json.name
+		}
+		return f.json.name
+	})
+	bound := fields[:0]
+	for i, f := range fields {
+		if !hidden[i] {
+			bound = append(bound, f)
+		}
+	}
+	stored, _ := boundFieldsCache.LoadOrStore(typ, bound)
+	return stored.([]structField)
+}
+
+// hasBindTag reports whether the field is bound from a source other than the
+// JSON body
+func hasBindTag(sf structField) bool {
+	for _, tag := range bindTags {
+		if name, ok := sf.tag.Lookup(tag); ok && name != "-" && !strings.HasPrefix(name, "-,") {
+			return true
+		}
+	}
+	return false
+}
+
+// bindDefault sets a zero valued field to the value of its default tag, using
+// the same conversions as the query, form and header binding. As a zero value
+// can not be told apart from a missing one, bool fields can not have a default,
+// or false could never be sent; use a *bool.
+//
+//	PageSize int    `json:"page_size" default:"20"`
+//	Sort     string `json:"sort" default:"created"`
+//	Desc     *bool  `json:"desc" default:"true"`
+func (ctrl *Controller) bindDefault(sf structField, v reflect.Value, path string) error {
+	def, ok := sf.tag.Lookup("default")
+	if !ok || !v.CanSet() {
+		return nil
+	}
+	if v.Kind() == reflect.Bool {
+		return fmt.Errorf("render: default for bool field '%s', use a *bool so it can be false", path)
+	}
+	if !v.IsZero() {
+		return nil
+	}
+	return ctrl.convert(path, def, v)
+}
@@ -0,0 +1,167 @@
+package render
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBindDefault(t *testing.T) {
+	type filter struct {
+		Status testStatus `json:"status" default:"Published"`
+	}
+	type payload struct {
+		PageSize int           `json:"page_size" default:"20"`
+		Sort     string        `json:"sort" default:"created"`
+		Desc     *bool         `json:"desc" default:"true"`
+		Timeout  time.Duration `json:"timeout" default:"5s"`
+		Limit    *int          `json:"limit" default:"10"`
+		Filter   filter        `json:"filter"`
+		Filters  []filter      `json:"filters"`
+		NilBinder
+	}
+	type tcase struct {
+		Body     string
+		Expected payload
+	}
+
+	ten, five, yes, no := 10, 5, true, false
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.Body))
+			r.Header.Set("Content-Type", "application/json")
+
+			var p payload
+			if err := defaultCtrl.Bind(r, &p); err != nil {
+				t.Errorf("error, expected nil, got %v", err)
+				return
+			}
+			if !reflect.DeepEqual(p, tc.Expected) {
+				t.Errorf("payload, expected %+v, got %+v", tc.Expected, p)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"empty": {
+			Body: `{}`,
+			Expected: payload{
+				PageSize: 20, Sort: "created", Desc: &yes, Timeout: 5 * time.Second, Limit: &ten,
+				Filter: filter{Status: "published"},
+			},
+		},
+		"set": {
+			Body: `{"page_size":50,"sort":"title","desc":false,"timeout":1000,"limit":5,"filter":{"status":"draft"},"filters":[{}]}`,
+			Expected: payload{
+				PageSize: 50, Sort: "title", Desc: &no, Timeout: time.Microsecond, Limit: &five,
+				Filter:  filter{Status: "draft"},
+				Filters: []filter{{Status: "published"}},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestBindDefaultInvalid(t *testing.T) {
+	type payload struct {
+		PageSize int `json:"page_size" default:"many"`
+		NilBinder
+	}
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{}`))
+	r.Header.Set("Content-Type", "application/json")
+
+	var p payload
+	err := defaultCtrl.Bind(r, &p)
+	var convErr *ConversionError
+	if !errors.As(err, &convErr) {
+		t.Fatalf("error, expected *ConversionError, got %v", err)
+	}
+	if convErr.Field != "page_size" {
+		t.Errorf("field, expected page_size, got %v", convErr.Field)
+	}
+
+	t.Run("bool", func(t *testing.T) {
+		type payload struct {
+			Desc bool `json:"desc" default:"true"`
+			NilBinder
+		}
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"desc":false}`))
+		r.Header.Set("Content-Type", "application/json")
+		var p payload
+		if err := defaultCtrl.Bind(r, &p); err == nil || !strings.Contains(err.Error(), "*bool") {
+			t.Errorf("error, expected the default to be rejected, got %v", err)
+		}
+	})
+}
+
+func TestBindNormalize(t *testing.T) {
+	type payload struct {
+		Title  string     `json:"title" normalize:"trim,collapse_space"`
+		Email  *string    `json:"email" normalize:"trim,lower"`
+		Tags   []string   `json:"tags" normalize:"upper"`
+		Status testStatus `json:"status" normalize:"trim" default:"draft"`
+		Raw    string     `json:"raw"`
+		NilBinder
+	}
+	type tcase struct {
+		Body     string
+		Expected payload
+	}
+
+	email := "gopher@example.com"
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.Body))
+			r.Header.Set("Content-Type", "application/json")
+
+			var p payload
+			if err := defaultCtrl.Bind(r, &p); err != nil {
+				t.Errorf("error, expected nil, got %v", err)
+				return
+			}
+			if !reflect.DeepEqual(p, tc.Expected) {
+				t.Errorf("payload, expected %+v, got %+v", tc.Expected, p)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"normalized": {
+			Body: `{"title":"  Hello \t  big\n world ","email":" Gopher@Example.COM ","tags":["go","Web"],"status":" Published ","raw":" As Is "}`,
+			Expected: payload{
+				Title:  "Hello big world",
+				Email:  &email,
+				Tags:   []string{"GO", "WEB"},
+				Status: "published",
+				Raw:    " As Is ",
+			},
+		},
+		"blank default": {
+			Body:     `{"status":"   "}`,
+			Expected: payload{Status: "draft"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestBindNormalizeUnknown(t *testing.T) {
+	type payload struct {
+		Title string `json:"title" normalize:"trim,shout"`
+		NilBinder
+	}
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"title":"hi"}`))
+	r.Header.Set("Content-Type", "application/json")
+
+	var p payload
+	if err := defaultCtrl.Bind(r, &p); err == nil || !strings.Contains(err.Error(), "shout") {
+		t.Errorf("error, expected unknown option error, got %v", err)
+	}
+}
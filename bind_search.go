@@ -0,0 +1,238 @@
+package render
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi"
+)
+
+// PathParam returns the value of the named path parameter of the request, for
+// fields tagged with `path:"name"`; it defaults to the URL parameters of chi.
+// Other routers can replace it.
+var PathParam = func(r *http.Request, name string) string { return chi.URLParam(r, name) }
+
+// BindSearch binds the payload from the URL, headers and cookies of the
+// request, and never reads the body; it is meant for search and list endpoints.
+// Fields are bound using their tags, and the same conversions as Convert:
+//
+//	type ArticleSearch struct {
+//		Query    string   `query:"q" normalize:"trim"`
+//		Tags     []string `query:"tag"`
+//		Page     int      `query:"page" default:"1"`
+//		AuthorID int64    `path:"authorID"`
+//		Locale   string   `header:"Accept-Language"`
+//		Theme    string   `cookie:"theme" default:"light"`
+//	}
+//
+// Slice fields get every value of a repeated query parameter or header. Like
+// Bind, the fields are then normalized, defaulted and validated, and the Bind
+// method of the payload is called.
+func (ctrl *Controller) BindSearch(r *http.Request, v Binder) error {
+	if ctrl == nil {
+		return defaultCtrl.BindSearch(r, v)
+	}
+	stats := statsFor(r)
+	protected := protectedFields(v)
+	if err := ctrl.bindURL(r, v, urlSources); err != nil {
+		return err
+	}
+	if err := ctrl.bindFields(v, protected); err != nil {
+		return err
+	}
+	start := time.Now()
+	err := binder(r, v)
+	stats.update(func(stats *RenderStats) { stats.BindDuration += time.Since(start) })
+	return err
+}
+
+// BindQuery binds the payload from the query string of the request only; the
+// body, path parameters and headers are not looked at. Fields are bound using
+// their query tags, and the same conversions as Convert; time.Time fields take
+// RFC 3339 times, or the layouts of a TimeConverter registered for the type.
+//
+//	type ArticleList struct {
+//		Tags      []string  `query:"tag,comma"`
+//		Published bool      `query:"published"`
+//		Since     time.Time `query:"since"`
+//		Limit     int       `query:"limit" default:"20"`
+//	}
+//
+// Slice fields get every value of a repeated parameter; with the comma option
+// the values are also split on commas, so ?tag=go,http&tag=chi gets all three.
+// Like Bind, the fields are then normalized, defaulted and validated, and the
+// Bind method of the payload is called.
+func (ctrl *Controller) BindQuery(r *http.Request, v Binder) error {
+	if ctrl == nil {
+		return defaultCtrl.BindQuery(r, v)
+	}
+	stats := statsFor(r)
+	protected := protectedFields(v)
+	if err := ctrl.bindURL(r, v, urlSources[:1]); err != nil {
+		return err
+	}
+	if err := ctrl.bindFields(v, protected); err != nil {
+		return err
+	}
+	start := time.Now()
+	err := binder(r, v)
+	stats.update(func(stats *RenderStats) { stats.BindDuration += time.Since(start) })
+	return err
+}
+
+// BindRequest binds the payload from the whole request, so a single struct
+// describes the input of an endpoint. The sources are bound in order, each one
+// overriding the fields the previous ones set:
+//
+//  1. the body, decoded like Bind; requests without a body, like most GET
+//     requests, skip it
+//  2. the query string, for fields tagged with query
+//  3. the path parameters, for fields tagged with path
+//  4. the headers, for fields tagged with header
+//  5. the cookies, for fields tagged with cookie
+//
+// Fields bound from the URL, headers and cookies should be tagged with
+// `json:"-"` when the body must not be able to set them:
+//
+//	type UpdateArticle struct {
+//		ID      int64  `json:"-" path:"articleID"`
+//		Title   string `json:"title" normalize:"trim"`
+//		Body    string `json:"body"`
+//		DryRun  bool   `json:"-" query:"dry_run"`
+//		IfMatch string `json:"-" header:"If-Match"`
+//	}
+//
+// Like Bind, the fields are then normalized, defaulted and validated, the
+// `json:"-"` fields with a query, path, header, cookie or form tag included,
+// and the Bind method of the payload is called.
+func (ctrl *Controller) BindRequest(r *http.Request, v Binder) error {
+	if ctrl == nil {
+		return defaultCtrl.BindRequest(r, v)
+	}
+	return ctrl.bind(r, v, false, urlSources)
+}
+
+// urlSource is a struct tag bindURL looks at, and how to get the values for it
+// from the request
+type urlSource struct {
+	tag    string
+	values func(r *http.Request, name string) []string
+}
+
+// urlSources are the sources BindSearch and BindRequest bind from, in order;
+// the query string is first
+var urlSources = []urlSource{
+	{"query", func(r *http.Request, name string) []string { return r.URL.Query()[name] }},
+	{"path", func(r *http.Request, name string) []string {
+		if value := PathParam(r, name); value != "" {
+			return []string{value}
+		}
+		return nil
+	}},
+	{"header", func(r *http.Request, name string) []string { return r.Header.Values(name) }},
+	cookieSource,
+}
+
+// cookieSource binds the fields tagged with cookie from the cookies of the
+// request with the name
+var cookieSource = urlSource{"cookie", func(r *http.Request, name string) []string {
+	var values []string
+	for _, cookie := range r.Cookies() {
+		if cookie.Name == name {
+			values = append(values, cookie.Value)
+		}
+	}
+	return values
+}}
+
+// cookieSources are the sources Bind binds from, besides the body
+var cookieSources = []urlSource{cookieSource}
+
+// bindURL sets the fields of the payload tagged for the sources from the
+// request.
+func (ctrl *Controller) bindURL(r *http.Request, v interface{}, sources []urlSource) error {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+	for _, sf := range boundFields(rv.Type()) {
+		fv, ok := fieldByIndex(rv, sf.index)
+		if !ok || !fv.CanSet() {
+			continue
+		}
+		for _, source := range sources {
+			tag, ok := sf.tag.Lookup(source.tag)
+			name, opts := tag, ""
+			if i := strings.IndexByte(tag, ','); i >= 0 {
+				name, opts = tag[:i], tag[i+1:]
+			}
+			if !ok || name == "" || name == "-" {
+				continue
+			}
+			values := source.values(r, name)
+			if hasTagOption(opts, "comma") {
+				values = splitComma(values)
+			}
+			if len(values) == 0 {
+				continue
+			}
+			if err := ctrl.convertValues(name, values, fv); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// hasTagOption reports whether option is one of the comma separated options
+func hasTagOption(opts, option string) bool {
+	for opts != "" {
+		var opt string
+		opt, opts = opts, ""
+		if i := strings.IndexByte(opt, ','); i >= 0 {
+			opt, opts = opt[:i], opt[i+1:]
+		}
+		if opt == option {
+			return true
+		}
+	}
+	return false
+}
+
+// splitComma splits the values on commas, leaving out empty values
+func splitComma(values []string) []string {
+	var split []string
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				split = append(split, item)
+			}
+		}
+	}
+	return split
+}
+
+// convertValues converts the values into dst; slices, other than []byte, get
+// every value, other types the first.
+func (ctrl *Controller) convertValues(field string, values []string, dst reflect.Value) error {
+	typ := dst.Type()
+	if _, ok := ctrl.converters()[typ]; ok || typ.Kind() != reflect.Slice || typ.Elem().Kind() == reflect.Uint8 {
+		return ctrl.convert(field, values[0], dst)
+	}
+	items := reflect.MakeSlice(typ, len(values), len(values))
+	for i, value := range values {
+		if err := ctrl.convert(field, value, items.Index(i)); err != nil {
+			return err
+		}
+	}
+	dst.Set(items)
+	return nil
+}
@@ -0,0 +1,355 @@
+package render
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+)
+
+func TestBindSearch(t *testing.T) {
+	type search struct {
+		Query    string     `query:"q" normalize:"trim"`
+		Tags     []string   `query:"tag"`
+		Page     int        `query:"page" default:"1"`
+		Status   testStatus `query:"status" default:"published"`
+		AuthorID int64      `path:"authorID"`
+		Locale   string     `header:"Accept-Language" normalize:"lower"`
+		Body     string     `json:"body"`
+		NilBinder
+	}
+	type tcase struct {
+		URL      string
+		Locale   string
+		Expected search
+		Err      error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			var (
+				got search
+				err error
+			)
+			router := chi.NewRouter()
+			router.Get("/authors/{authorID}/articles", func(w http.ResponseWriter, r *http.Request) {
+				err = defaultCtrl.BindSearch(r, &got)
+			})
+			r := httptest.NewRequest(http.MethodGet, tc.URL, strings.NewReader(`{"body":"ignored"}`))
+			r.Header.Set("Content-Type", "application/json")
+			if tc.Locale != "" {
+				r.Header.Set("Accept-Language", tc.Locale)
+			}
+			router.ServeHTTP(httptest.NewRecorder(), r)
+			if tc.Err != nil {
+				if !errors.Is(err, tc.Err) {
+					t.Errorf("error, expected %v, got %v", tc.Err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error, expected nil, got %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.Expected) {
+				t.Errorf("payload, expected %+v, got %+v", tc.Expected, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"all": {
+			URL:    "/authors/100/articles?q=+hello+&tag=go&tag=http&page=2&status=Draft",
+			Locale: "EN-GB",
+			Expected: search{
+				Query: "hello", Tags: []string{"go", "http"}, Page: 2, Status: "draft",
+				AuthorID: 100, Locale: "en-gb",
+			},
+		},
+		"defaults": {
+			URL:      "/authors/7/articles",
+			Expected: search{Page: 1, Status: "published", AuthorID: 7},
+		},
+		"invalid number": {
+			URL: "/authors/7/articles?page=last",
+			Err: ErrConversion,
+		},
+		"invalid path": {
+			URL: "/authors/someone/articles",
+			Err: ErrConversion,
+		},
+		"invalid enum": {
+			URL: "/authors/7/articles?status=deleted",
+			Err: ErrInvalidEnum,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestBindQuery(t *testing.T) {
+	type list struct {
+		Tags      []string   `query:"tag,comma"`
+		IDs       []int      `query:"id"`
+		Published bool       `query:"published"`
+		Since     time.Time  `query:"since"`
+		Until     *time.Time `query:"until"`
+		Limit     int        `query:"limit" default:"20"`
+		Locale    string     `header:"Accept-Language"`
+		NilBinder
+	}
+	type tcase struct {
+		URL      string
+		Expected list
+		Err      error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, tc.URL, nil)
+			r.Header.Set("Accept-Language", "en")
+			var got list
+			err := BindQuery(r, &got)
+			if tc.Err != nil {
+				if !errors.Is(err, tc.Err) {
+					t.Errorf("error, expected %v, got %v", tc.Err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error, expected nil, got %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.Expected) {
+				t.Errorf("payload, expected %+v, got %+v", tc.Expected, got)
+			}
+		}
+	}
+
+	since := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	tests := map[string]tcase{
+		"all": {
+			URL: "/articles?tag=go,http&tag=chi,&id=1&id=2&published=true&since=2021-03-04T05:06:07Z&until=2021-03-04T05:06:07Z&limit=5",
+			Expected: list{
+				Tags: []string{"go", "http", "chi"}, IDs: []int{1, 2}, Published: true,
+				Since: since, Until: &since, Limit: 5,
+			},
+		},
+		"defaults": {
+			URL:      "/articles",
+			Expected: list{Limit: 20},
+		},
+		"invalid bool": {
+			URL: "/articles?published=maybe",
+			Err: ErrConversion,
+		},
+		"invalid time": {
+			URL: "/articles?since=yesterday",
+			Err: ErrConversion,
+		},
+		"invalid slice item": {
+			URL: "/articles?id=1&id=two",
+			Err: ErrConversion,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestBindCookies(t *testing.T) {
+	type payload struct {
+		Title   string `json:"title"`
+		Session string `json:"-" cookie:"session"`
+		Theme   string `json:"theme" cookie:"theme" default:"light"`
+		Visits  int    `json:"-" cookie:"visits"`
+		NilBinder
+	}
+	type tcase struct {
+		Body     string
+		Cookies  []*http.Cookie
+		Expected payload
+		Err      error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.Body))
+			r.Header.Set("Content-Type", "application/json")
+			for _, cookie := range tc.Cookies {
+				r.AddCookie(cookie)
+			}
+			var got payload
+			err := Bind(r, &got)
+			if tc.Err != nil {
+				if !errors.Is(err, tc.Err) {
+					t.Errorf("error, expected %v, got %v", tc.Err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error, expected nil, got %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.Expected) {
+				t.Errorf("payload, expected %+v, got %+v", tc.Expected, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"cookies": {
+			Body:     `{"title":"hello"}`,
+			Cookies:  []*http.Cookie{{Name: "session", Value: "abc"}, {Name: "visits", Value: "3"}},
+			Expected: payload{Title: "hello", Session: "abc", Theme: "light", Visits: 3},
+		},
+		"cookie overrides body": {
+			Body:     `{"title":"hello","theme":"blue"}`,
+			Cookies:  []*http.Cookie{{Name: "theme", Value: "dark"}},
+			Expected: payload{Title: "hello", Theme: "dark"},
+		},
+		"no cookies": {
+			Body:     `{"title":"hello","theme":"blue"}`,
+			Expected: payload{Title: "hello", Theme: "blue"},
+		},
+		"invalid cookie": {
+			Body:    `{"title":"hello"}`,
+			Cookies: []*http.Cookie{{Name: "visits", Value: "many"}},
+			Err:     ErrConversion,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+
+	t.Run("search", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/?title=hello", nil)
+		r.AddCookie(&http.Cookie{Name: "session", Value: "abc"})
+		var got struct {
+			Title   string `query:"title"`
+			Session string `cookie:"session"`
+			NilBinder
+		}
+		if err := BindSearch(r, &got); err != nil {
+			t.Fatalf("error, expected nil, got %v", err)
+		}
+		if got.Title != "hello" || got.Session != "abc" {
+			t.Errorf("payload, expected hello and abc, got %+v", got)
+		}
+	})
+}
+
+func TestBindRequest(t *testing.T) {
+	type update struct {
+		ID      int64  `json:"-" path:"articleID"`
+		Title   string `json:"title" normalize:"trim"`
+		Status  string `json:"status" query:"status" default:"draft"`
+		DryRun  bool   `json:"-" query:"dry_run"`
+		IfMatch string `json:"-" header:"If-Match"`
+		Theme   string `json:"theme" header:"X-Theme" cookie:"theme"`
+		// bound from the URL only, with the tags Bind applies to the body
+		Sort string     `json:"-" query:"sort" default:"created" normalize:"lower"`
+		Mode testStatus `json:"-" query:"mode" default:"draft"`
+		NilBinder
+	}
+	type tcase struct {
+		Method   string
+		URL      string
+		Body     string
+		Headers  map[string]string
+		Cookies  []*http.Cookie
+		Expected update
+		Err      error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			var (
+				got update
+				err error
+			)
+			router := chi.NewRouter()
+			router.HandleFunc("/articles/{articleID}", func(w http.ResponseWriter, r *http.Request) {
+				err = BindRequest(r, &got)
+			})
+			var body io.Reader
+			if tc.Body != "" {
+				body = strings.NewReader(tc.Body)
+			}
+			r := httptest.NewRequest(tc.Method, tc.URL, body)
+			r.Header.Set("Content-Type", "application/json")
+			for name, value := range tc.Headers {
+				r.Header.Set(name, value)
+			}
+			for _, cookie := range tc.Cookies {
+				r.AddCookie(cookie)
+			}
+			router.ServeHTTP(httptest.NewRecorder(), r)
+			if tc.Err != nil {
+				if !errors.Is(err, tc.Err) {
+					t.Errorf("error, expected %v, got %v", tc.Err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error, expected nil, got %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.Expected) {
+				t.Errorf("payload, expected %+v, got %+v", tc.Expected, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"all": {
+			Method:   http.MethodPut,
+			URL:      "/articles/7?dry_run=true",
+			Body:     `{"title":" hello ","id":9,"dry_run":false}`,
+			Headers:  map[string]string{"If-Match": `"v1"`},
+			Expected: update{ID: 7, Title: "hello", Status: "draft", DryRun: true, IfMatch: `"v1"`, Sort: "created", Mode: "draft"},
+		},
+		"sources override the body in order": {
+			Method:   http.MethodPut,
+			URL:      "/articles/7?status=published",
+			Body:     `{"title":"hello","status":"review","theme":"blue"}`,
+			Headers:  map[string]string{"X-Theme": "light"},
+			Cookies:  []*http.Cookie{{Name: "theme", Value: "dark"}},
+			Expected: update{ID: 7, Title: "hello", Status: "published", Theme: "dark", Sort: "created", Mode: "draft"},
+		},
+		"no body": {
+			Method:   http.MethodGet,
+			URL:      "/articles/7?status=published",
+			Headers:  map[string]string{"X-Theme": "light"},
+			Expected: update{ID: 7, Status: "published", Theme: "light", Sort: "created", Mode: "draft"},
+		},
+		"url fields normalized and canonical": {
+			Method:   http.MethodGet,
+			URL:      "/articles/7?sort=TITLE&mode=Published",
+			Expected: update{ID: 7, Status: "draft", Sort: "title", Mode: "published"},
+		},
+		"invalid url enum": {
+			Method: http.MethodGet,
+			URL:    "/articles/7?mode=deleted",
+			Err:    ErrInvalidEnum,
+		},
+		"invalid body": {
+			Method: http.MethodPut,
+			URL:    "/articles/7",
+			Body:   `{"title":`,
+			Err:    io.ErrUnexpectedEOF,
+		},
+		"invalid path": {
+			Method: http.MethodPut,
+			URL:    "/articles/latest",
+			Body:   `{"title":"hello"}`,
+			Err:    ErrConversion,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
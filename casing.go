@@ -0,0 +1,90 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// KeyCasing is the casing applied to the JSON keys of struct fields by the
+// structured responders, so the same models can be exposed with different
+// conventions without retagging them. Map keys are left as is.
+type KeyCasing uint8
+
+const (
+	// KeyCasingAsIs uses the field names and json tags as they are
+	KeyCasingAsIs KeyCasing = iota
+	// KeyCasingSnake converts the keys to snake_case
+	KeyCasingSnake
+	// KeyCasingCamel converts the keys to camelCase
+	KeyCasingCamel
+)
+
+// String returns the name of the casing, as used by Describe
+func (casing KeyCasing) String() string {
+	switch casing {
+	case KeyCasingAsIs:
+		return "as_is"
+	case KeyCasingSnake:
+		return "snake"
+	case KeyCasingCamel:
+		return "camel"
+	default:
+		return fmt.Sprintf("KeyCasing(%d)", uint8(casing))
+	}
+}
+
+// Apply returns the key in the casing
+func (casing KeyCasing) Apply(key string) string {
+	switch casing {
+	case KeyCasingSnake:
+		words := splitWords(key)
+		for i := range words {
+			words[i] = strings.ToLower(words[i])
+		}
+		return strings.Join(words, "_")
+	case KeyCasingCamel:
+		words := splitWords(key)
+		for i := range words {
+			words[i] = strings.ToLower(words[i])
+			if i > 0 {
+				words[i] = strings.ToUpper(words[i][:1]) + words[i][1:]
+			}
+		}
+		return strings.Join(words, "")
+	default:
+		return key
+	}
+}
+
+// splitWords splits a key on underscores, dashes, spaces and changes in case;
+// runs of capitals are treated as one word, so "HTTPServer" is "HTTP", "Server".
+func splitWords(key string) []string {
+	var (
+		words []string
+		word  []rune
+	)
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, string(word))
+			word = word[:0]
+		}
+	}
+	runes := []rune(key)
+	for i, r := range runes {
+		switch {
+		case r == '_' || r == '-' || r == ' ':
+			flush()
+			continue
+		case unicode.IsUpper(r) && i > 0:
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				flush()
+			}
+		}
+		word = append(word, r)
+	}
+	flush()
+	return words
+}
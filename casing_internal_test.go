@@ -0,0 +1,60 @@
+package render
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestKeyCasing(t *testing.T) {
+	type tcase struct {
+		Key   string
+		Snake string
+		Camel string
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			if got := KeyCasingSnake.Apply(tc.Key); got != tc.Snake {
+				t.Errorf("snake, expected %v, got %v", tc.Snake, got)
+			}
+			if got := KeyCasingCamel.Apply(tc.Key); got != tc.Camel {
+				t.Errorf("camel, expected %v, got %v", tc.Camel, got)
+			}
+			if got := KeyCasingAsIs.Apply(tc.Key); got != tc.Key {
+				t.Errorf("as is, expected %v, got %v", tc.Key, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"snake":   {Key: "user_id", Snake: "user_id", Camel: "userId"},
+		"camel":   {Key: "userId", Snake: "user_id", Camel: "userId"},
+		"go name": {Key: "UserID", Snake: "user_id", Camel: "userId"},
+		"acronym": {Key: "HTTPServer", Snake: "http_server", Camel: "httpServer"},
+		"digits":  {Key: "address2Line", Snake: "address2_line", Camel: "address2Line"},
+		"single":  {Key: "name", Snake: "name", Camel: "name"},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestRespondKeyCasing(t *testing.T) {
+	type user struct {
+		UserID    int               `json:"user_id"`
+		FirstName string            `json:"firstName"`
+		Labels    map[string]string `json:"labels"`
+	}
+
+	ctrl := defaultCtrl.Clone()
+	ctrl.KeyCasing = KeyCasingCamel
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+	ctrl.respond(w, r, user{UserID: 1, FirstName: "Go", Labels: map[string]string{"team_name": "go"}})
+
+	want := `{"userId":1,"firstName":"Go","labels":{"team_name":"go"}}` + "\n"
+	if got := w.Body.String(); got != want {
+		t.Errorf("body, expected %s, got %s", want, got)
+	}
+}
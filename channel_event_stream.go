@@ -54,7 +54,12 @@ func ChannelEventStream(w http.ResponseWriter, r *http.Request, v interface{}) e
 				}
 			}
 
-			bytes, err := json.Marshal(v)
+			// apply the policies of the controller, like redaction
+			v, err := helpers.Project(r, v)
+			var bytes []byte
+			if err == nil {
+				bytes, err = json.Marshal(v)
+			}
 			if err != nil {
 				w.Write([]byte(fmt.Sprintf("event: error\ndata: {\"error\":\"%v\"}\n\n", err)))
 				if f, ok := w.(http.Flusher); ok {
@@ -0,0 +1,90 @@
+package render
+
+import (
+	"io"
+	"net/http"
+	"sort"
+
+	"github.com/gdey/chi-render/decoders"
+	"github.com/gdey/chi-render/responders"
+)
+
+// Codec describes a format that should be able to both decode request bodies
+// and encode responses; formats like msgpack, CBOR or protobuf are usually
+// symmetric in this way.
+type Codec interface {
+	Decode(r io.Reader, v interface{}) error
+	Respond(w http.ResponseWriter, r *http.Request, v interface{}) error
+}
+
+// CodecFuncs is a Codec made up of a decoder and responder function
+type CodecFuncs struct {
+	Decoder   decoders.Func
+	Responder responders.Func
+}
+
+// Decode calls the Decoder function
+func (c CodecFuncs) Decode(r io.Reader, v interface{}) error { return c.Decoder(r, v) }
+
+// Respond calls the Responder function
+func (c CodecFuncs) Respond(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	return c.Responder(w, r, v)
+}
+
+// SetCodec will set both the decoder and responder for the given content type.
+// Use nil functions to unset the content type.
+// Only error this function will return is ErrControllerIsNil; is returned
+// if the Controller object is nil.
+func (ctrl *Controller) SetCodec(contentType ContentType, decoder decoders.Func, responder responders.Func) error {
+	if ctrl == nil {
+		return ErrControllerIsNil
+	}
+	if err := ctrl.SetDecoder(contentType, decoder); err != nil {
+		return err
+	}
+	return ctrl.SetResponder(contentType, responder)
+}
+
+// RegisterCodec will set the decoder and responder for the given content type
+// to the methods of the codec. Use a nil codec to unset the content type.
+// Only error this function will return is ErrControllerIsNil; is returned
+// if the Controller object is nil.
+func (ctrl *Controller) RegisterCodec(contentType ContentType, codec Codec) error {
+	if codec == nil {
+		return ctrl.SetCodec(contentType, nil, nil)
+	}
+	return ctrl.SetCodec(contentType, codec.Decode, codec.Respond)
+}
+
+// SupportedCodecs returns a ContentTypeSet of the configured Content types
+// that have both a decoder and a responder
+func (ctrl *Controller) SupportedCodecs() *ContentTypeSet {
+	if ctrl == nil {
+		return defaultCtrl.SupportedCodecs()
+	}
+
+	ctrl.decoderLck.RLock()
+	ctrl.responderLck.RLock()
+	stringValues := make([]string, 0, len(ctrl.decoders))
+	for value, entry := range ctrl.decoders {
+		if entry.fn == nil || ctrl.responders[value].Func == nil {
+			continue
+		}
+		stringValues = append(stringValues, string(value))
+	}
+	ctrl.responderLck.RUnlock()
+	ctrl.decoderLck.RUnlock()
+
+	sort.Strings(stringValues)
+	return NewContentTypeSet(stringValues...)
+}
+
+// SetCodec will set both the decoder and responder for the given content type.
+// Use nil functions to unset the content type.
+func SetCodec(contentType ContentType, decoder decoders.Func, responder responders.Func) {
+	_ = defaultCtrl.SetCodec(contentType, decoder, responder)
+}
+
+// SupportedCodecs returns a ContentTypeSet of the configured Content types
+// that have both a decoder and a responder
+func SupportedCodecs() *ContentTypeSet { return defaultCtrl.SupportedCodecs() }
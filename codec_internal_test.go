@@ -0,0 +1,32 @@
+package render
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gdey/chi-render/decoders"
+	"github.com/gdey/chi-render/responders"
+)
+
+func TestSupportedCodecs(t *testing.T) {
+	ctrl := CloneDefault()
+	myType := ContentType("application/my-json")
+	if err := ctrl.RegisterCodec(myType, CodecFuncs{Decoder: decoders.JSON, Responder: responders.JSON}); err != nil {
+		t.Fatalf("error, expected nil, got %v", err)
+	}
+	// Only a decoder, should not be reported
+	_ = ctrl.SetDecoder(ContentType("application/only-decoder"), decoders.JSON)
+
+	expected := []ContentType{ContentTypeJSON, myType, ContentTypeXML}
+	got := ctrl.SupportedCodecs().Types()
+	if !reflect.DeepEqual(expected, got) {
+		t.Errorf("codecs, expected %v, got %v", expected, got)
+	}
+
+	_ = ctrl.SetCodec(myType, nil, nil)
+	expected = []ContentType{ContentTypeJSON, ContentTypeXML}
+	got = ctrl.SupportedCodecs().Types()
+	if !reflect.DeepEqual(expected, got) {
+		t.Errorf("codecs, expected %v, got %v", expected, got)
+	}
+}
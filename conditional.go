@@ -0,0 +1,147 @@
+package render
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Validators are the values used to evaluate conditional GET and HEAD requests
+type Validators struct {
+	// ETag is the entity tag of the payload, including the quotes and the W/
+	// prefix for weak tags. If the quotes are missing they will be added.
+	ETag string
+	// LastModified is the time the payload was last modified
+	LastModified time.Time
+}
+
+// ValidatorLookup returns the validators for the payload being rendered; ok
+// should be false if there are no validators for the payload. The lookup is
+// free to get the validators from somewhere other than the payload, like
+// the datastore.
+type ValidatorLookup func(r *http.Request, v interface{}) (validators Validators, ok bool)
+
+// Validated can be implemented by payloads that know their own validators,
+// use ValidatorsFromPayload as the controller's ValidatorLookup to use them.
+type Validated interface {
+	Validators() (validators Validators, ok bool)
+}
+
+// ValidatorsFromPayload is a ValidatorLookup that asks payloads that implement
+// Validated for their validators
+func ValidatorsFromPayload(_ *http.Request, v interface{}) (Validators, bool) {
+	if vv, ok := v.(Validated); ok {
+		return vv.Validators()
+	}
+	return Validators{}, false
+}
+
+// etag returns the entity tag with the quotes
+func (vals Validators) etag() string {
+	tag := vals.ETag
+	if tag == "" {
+		return ""
+	}
+	weak := strings.HasPrefix(tag, "W/")
+	tag = strings.TrimPrefix(tag, "W/")
+	if !strings.HasPrefix(tag, `"`) {
+		tag = `"` + tag + `"`
+	}
+	if weak {
+		tag = "W/" + tag
+	}
+	return tag
+}
+
+// forContentType returns the validators of the representation of the payload in
+// the content type. A strong entity tag identifies a single representation, so
+// the content type is mixed into it; weak tags are left as they are.
+func (vals Validators) forContentType(contentType ContentType) Validators {
+	tag := vals.etag()
+	if tag == "" || strings.HasPrefix(tag, "W/") {
+		return vals
+	}
+	h := fnv.New32a()
+	h.Write([]byte(contentType))
+	vals.ETag = fmt.Sprintf(`%s-%08x"`, strings.TrimSuffix(tag, `"`), h.Sum32())
+	return vals
+}
+
+// setHeaders sets the ETag and Last-Modified headers
+func (vals Validators) setHeaders(w http.ResponseWriter) {
+	if tag := vals.etag(); tag != "" {
+		w.Header().Set("ETag", tag)
+	}
+	if !vals.LastModified.IsZero() {
+		w.Header().Set("Last-Modified", vals.LastModified.UTC().Format(http.TimeFormat))
+	}
+}
+
+// notModified evaluates the If-None-Match and If-Modified-Since request headers,
+// following RFC 7232 section 6.
+func (vals Validators) notModified(r *http.Request) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		tag := vals.etag()
+		if tag == "" {
+			return false
+		}
+		for _, candidate := range strings.Split(inm, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" {
+				return true
+			}
+			// If-None-Match uses the weak comparison function
+			if strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(tag, "W/") {
+				return true
+			}
+		}
+		return false
+	}
+	ims := r.Header.Get("If-Modified-Since")
+	if ims == "" || vals.LastModified.IsZero() {
+		return false
+	}
+	t, err := http.ParseTime(ims)
+	if err != nil {
+		return false
+	}
+	// http dates only have a resolution of seconds
+	return !vals.LastModified.Truncate(time.Second).After(t)
+}
+
+// evaluateConditional sets the validator headers for the representation of the
+// payload in the negotiated content type, and reports if a 304 Not Modified
+// response was sent. It is only called once the content type has been
+// negotiated, so a request that is not acceptable gets a 406 instead.
+func (ctrl *Controller) evaluateConditional(w http.ResponseWriter, r *http.Request, contentType ContentType, v interface{}) bool {
+	if ctrl.Conditional == nil || (r.Method != http.MethodGet && r.Method != http.MethodHead) {
+		return false
+	}
+	vals, ok := ctrl.Conditional(r, v)
+	if !ok {
+		return false
+	}
+	// the representation, and so its validators, depends on the Accept header
+	addVary(w.Header(), "Accept")
+	vals = vals.forContentType(contentType)
+	vals.setHeaders(w)
+	if !vals.notModified(r) {
+		return false
+	}
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// addVary adds the header name to the Vary header, if it is not already listed
+func addVary(header http.Header, name string) {
+	for _, value := range header.Values("Vary") {
+		for _, field := range strings.Split(value, ",") {
+			if field = strings.TrimSpace(field); field == "*" || strings.EqualFold(field, name) {
+				return
+			}
+		}
+	}
+	header.Add("Vary", name)
+}
@@ -0,0 +1,125 @@
+package render
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type conditionalPayload struct {
+	Answer int `json:"answer"`
+}
+
+func TestConditional(t *testing.T) {
+	modified := time.Date(2021, 2, 25, 10, 0, 0, 0, time.UTC)
+	jsonTag := Validators{ETag: "v1"}.forContentType(ContentTypeJSON).ETag
+	xmlTag := Validators{ETag: "v1"}.forContentType(ContentTypeXML).ETag
+
+	type tcase struct {
+		Method  string
+		Headers map[string]string
+		Deny    ContentType
+		Status  int
+		ETag    string
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			ctrl := CloneDefault()
+			ctrl.Conditional = func(_ *http.Request, _ interface{}) (Validators, bool) {
+				// validators that would come from the datastore
+				return Validators{ETag: "v1", LastModified: modified}, true
+			}
+			if tc.Deny != "" {
+				ctrl.DeniedAccept = SetOfContentTypes(tc.Deny)
+			}
+
+			r := httptest.NewRequest(tc.Method, "/", nil)
+			r.Header.Set("Accept", "application/json")
+			for name, value := range tc.Headers {
+				r.Header.Set(name, value)
+			}
+			w := httptest.NewRecorder()
+			ctrl.respond(w, r, &conditionalPayload{Answer: 42})
+
+			if w.Code != tc.Status {
+				t.Errorf("status code, expected %v, got %v", tc.Status, w.Code)
+			}
+			if got := w.Header().Get("ETag"); got != tc.ETag {
+				t.Errorf("etag, expected %v, got %v", tc.ETag, got)
+			}
+			if tc.ETag != "" && w.Header().Get("Vary") != "Accept" {
+				t.Errorf("vary, expected Accept, got %q", w.Header().Get("Vary"))
+			}
+			if tc.Status == http.StatusNotModified && w.Body.Len() != 0 {
+				t.Errorf("body, expected empty, got %v", w.Body.String())
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"no conditional headers": {
+			Method: http.MethodGet,
+			Status: http.StatusOK,
+			ETag:   jsonTag,
+		},
+		"etag match": {
+			Method:  http.MethodGet,
+			Headers: map[string]string{"If-None-Match": `"v0", W/` + jsonTag},
+			Status:  http.StatusNotModified,
+			ETag:    jsonTag,
+		},
+		"etag of another representation": {
+			Method: http.MethodGet,
+			Headers: map[string]string{
+				"Accept":        "text/xml",
+				"If-None-Match": jsonTag,
+			},
+			Status: http.StatusOK,
+			ETag:   xmlTag,
+		},
+		"etag mismatch": {
+			Method: http.MethodGet,
+			Headers: map[string]string{
+				"If-None-Match": `"v0"`,
+				// ignored as If-None-Match is present
+				"If-Modified-Since": modified.Format(http.TimeFormat),
+			},
+			Status: http.StatusOK,
+			ETag:   jsonTag,
+		},
+		"not modified since": {
+			Method:  http.MethodHead,
+			Headers: map[string]string{"If-Modified-Since": modified.Format(http.TimeFormat)},
+			Status:  http.StatusNotModified,
+			ETag:    jsonTag,
+		},
+		"modified since": {
+			Method:  http.MethodGet,
+			Headers: map[string]string{"If-Modified-Since": modified.Add(-time.Hour).Format(http.TimeFormat)},
+			Status:  http.StatusOK,
+			ETag:    jsonTag,
+		},
+		"not acceptable before not modified": {
+			Method:  http.MethodGet,
+			Headers: map[string]string{"If-None-Match": "*"},
+			Deny:    ContentTypeJSON,
+			Status:  http.StatusNotAcceptable,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestValidatorsForContentType(t *testing.T) {
+	strong := Validators{ETag: "v1"}
+	if strong.forContentType(ContentTypeJSON).ETag == strong.forContentType(ContentTypeXML).ETag {
+		t.Errorf("strong etag, expected a tag per content type, got %v for both", strong.forContentType(ContentTypeJSON).ETag)
+	}
+	weak := Validators{ETag: `W/"v1"`}
+	if got := weak.forContentType(ContentTypeJSON).ETag; got != `W/"v1"` {
+		t.Errorf("weak etag, expected %v, got %v", `W/"v1"`, got)
+	}
+}
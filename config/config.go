@@ -0,0 +1,205 @@
+// Package config builds render Controllers from a declarative configuration,
+// so the formats a service supports can be changed, for example behind a
+// feature flag, without code changes.
+//
+// A configuration looks like the following:
+//
+//	{
+//	   "responders": ["application/json", "text/xml", "text/html"],
+//	   "error_responders": {"application/json": "application/problem+json"},
+//	   "decoders": ["application/json"],
+//	   "default_request": "application/json",
+//	   "default_response": "application/json",
+//	   "limits": {"application/json": 1048576}
+//	}
+//
+// The content types used must be known to the package; use RegisterResponder
+// and RegisterDecoder to make additional formats available.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	render "github.com/gdey/chi-render"
+	"github.com/gdey/chi-render/decoders"
+	"github.com/gdey/chi-render/responders"
+)
+
+// Config is the declarative configuration of a render.Controller
+type Config struct {
+	// Responders are the content types to enable responders for
+	Responders []string `json:"responders"`
+	// ErrorResponders maps a content type to the known responder to use for error payloads
+	ErrorResponders map[string]string `json:"error_responders,omitempty"`
+	// Decoders are the content types to enable decoders for
+	Decoders []string `json:"decoders"`
+	// DefaultRequest is the content type to assume if a request does not have one
+	DefaultRequest string `json:"default_request,omitempty"`
+	// DefaultResponse is the content type to respond with if nothing in
+	// the Accept header matches; it must be one of the Responders.
+	DefaultResponse string `json:"default_response"`
+	// Limits are the maximum request body sizes, in bytes, per content type
+	Limits map[string]int64 `json:"limits,omitempty"`
+	// AllowAccept, if set, are the only Accept header content types that are honored
+	AllowAccept []string `json:"allow_accept,omitempty"`
+	// DenyAccept are the Accept header content types that are not honored
+	DenyAccept []string `json:"deny_accept,omitempty"`
+	// MaxBodyBytes is the largest request body Bind reads for any content type, zero for no limit
+	MaxBodyBytes int64 `json:"max_body_bytes,omitempty"`
+	// MaxResponseBytes is the largest response body a responder may produce, zero for no limit
+	MaxResponseBytes int64 `json:"max_response_bytes,omitempty"`
+	// AcceptOverrideHeader is the request header that replaces the Accept header, if set
+	AcceptOverrideHeader string `json:"accept_override_header,omitempty"`
+}
+
+var (
+	lck sync.RWMutex
+
+	knownResponders = map[render.ContentType]responders.Func{
+		render.ContentTypeJSON:        responders.JSON,
+		render.ContentTypeXML:         responders.XML,
+		render.ContentTypeHTML:        responders.HTML,
+		render.ContentTypePlainText:   responders.PlainText,
+		render.ContentTypeProblemJSON: responders.ProblemJSON,
+		render.ContentTypeData:        responders.Data,
+		render.ContentTypeEventStream: render.ChannelEventStream,
+	}
+
+	knownDecoders = map[render.ContentType]decoders.Func{
+		render.ContentTypeJSON:       decoders.JSON,
+		render.ContentTypeXML:        decoders.XML,
+		render.ContentTypeForm:       decoders.Multipart,
+		render.ContentTypeURLEncoded: decoders.URLEncoded,
+		render.ContentTypeYAML:       decoders.YAML,
+		render.ContentTypeCBOR:       decoders.CBOR,
+		render.ContentTypeProtobuf:   decoders.Protobuf,
+		render.ContentTypeNDJSON:     decoders.NDJSON,
+	}
+)
+
+// RegisterResponder makes a responder available to configurations under the given content type
+func RegisterResponder(contentType render.ContentType, responder responders.Func) {
+	lck.Lock()
+	knownResponders[contentType] = responder
+	lck.Unlock()
+}
+
+// RegisterDecoder makes a decoder available to configurations under the given content type
+func RegisterDecoder(contentType render.ContentType, decoder decoders.Func) {
+	lck.Lock()
+	knownDecoders[contentType] = decoder
+	lck.Unlock()
+}
+
+// Load reads a JSON configuration
+func Load(r io.Reader) (cfg Config, err error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err = dec.Decode(&cfg); err != nil {
+		return cfg, fmt.Errorf("config: %w", err)
+	}
+	return cfg, nil
+}
+
+// LoadFile reads a JSON configuration from the named file
+func LoadFile(filename string) (Config, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return Config{}, fmt.Errorf("config: %w", err)
+	}
+	defer f.Close()
+	return Load(f)
+}
+
+// Build returns a new controller for the configuration
+func (cfg Config) Build() (*render.Controller, error) {
+	lck.RLock()
+	defer lck.RUnlock()
+
+	ctrl := new(render.Controller)
+	for _, str := range cfg.Responders {
+		ct, err := render.ContentTypeFromString(str)
+		if err != nil {
+			return nil, fmt.Errorf("config: responder %q: %w", str, err)
+		}
+		fn, ok := knownResponders[ct]
+		if !ok {
+			return nil, fmt.Errorf("config: unknown responder %q", str)
+		}
+		_ = ctrl.SetResponder(ct, fn)
+	}
+	for str, name := range cfg.ErrorResponders {
+		ct, err := render.ContentTypeFromString(str)
+		if err != nil {
+			return nil, fmt.Errorf("config: error responder %q: %w", str, err)
+		}
+		fn, ok := knownResponders[render.ContentType(name)]
+		if !ok {
+			return nil, fmt.Errorf("config: unknown error responder %q", name)
+		}
+		_ = ctrl.SetErrorResponder(ct, fn)
+	}
+	for _, str := range cfg.Decoders {
+		ct, err := render.ContentTypeFromString(str)
+		if err != nil {
+			return nil, fmt.Errorf("config: decoder %q: %w", str, err)
+		}
+		fn, ok := knownDecoders[ct]
+		if !ok {
+			return nil, fmt.Errorf("config: unknown decoder %q", str)
+		}
+		_ = ctrl.SetDecoder(ct, fn)
+	}
+	for str, limit := range cfg.Limits {
+		ct, err := render.ContentTypeFromString(str)
+		if err != nil {
+			return nil, fmt.Errorf("config: limit %q: %w", str, err)
+		}
+		_ = ctrl.SetDecoderLimit(ct, limit)
+	}
+
+	if cfg.DefaultRequest != "" {
+		ct, err := render.ContentTypeFromString(cfg.DefaultRequest)
+		if err != nil {
+			return nil, fmt.Errorf("config: default request %q: %w", cfg.DefaultRequest, err)
+		}
+		ctrl.DefaultRequest = ct
+	}
+
+	if len(cfg.AllowAccept) > 0 {
+		ctrl.AllowedAccept = render.NewContentTypeSet(cfg.AllowAccept...)
+	}
+	ctrl.DeniedAccept = render.NewContentTypeSet(cfg.DenyAccept...)
+	ctrl.MaxBodyBytes = cfg.MaxBodyBytes
+	ctrl.MaxResponseBytes = cfg.MaxResponseBytes
+	ctrl.AcceptOverrideHeader = cfg.AcceptOverrideHeader
+
+	ct, err := render.ContentTypeFromString(cfg.DefaultResponse)
+	if err != nil {
+		return nil, fmt.Errorf("config: default response %q: %w", cfg.DefaultResponse, err)
+	}
+	if !ctrl.SupportedResponders().Has(ct) {
+		return nil, fmt.Errorf("config: default response %q is not one of the responders", cfg.DefaultResponse)
+	}
+	ctrl.DefaultResponse = ct
+	return ctrl, nil
+}
+
+// Reload loads the configuration from the named file and, if it is valid,
+// atomically swaps it into ref. On error the current controller is left in place.
+func Reload(ref *render.SwappableController, filename string) error {
+	cfg, err := LoadFile(filename)
+	if err != nil {
+		return err
+	}
+	ctrl, err := cfg.Build()
+	if err != nil {
+		return err
+	}
+	ref.Store(ctrl)
+	return nil
+}
@@ -0,0 +1,80 @@
+package config_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	render "github.com/gdey/chi-render"
+	"github.com/gdey/chi-render/config"
+)
+
+func TestBuild(t *testing.T) {
+	type tcase struct {
+		Config string
+
+		Responders []render.ContentType
+		Decoders   []render.ContentType
+		Limit      int64
+		Err        bool
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			cfg, err := config.Load(strings.NewReader(tc.Config))
+			if err != nil {
+				t.Fatalf("load error, expected nil, got %v", err)
+			}
+			ctrl, err := cfg.Build()
+			if tc.Err {
+				if err == nil {
+					t.Errorf("error, expected an error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error, expected nil, got %v", err)
+			}
+			if got := ctrl.SupportedResponders().Types(); !reflect.DeepEqual(tc.Responders, got) {
+				t.Errorf("responders, expected %v, got %v", tc.Responders, got)
+			}
+			if got := ctrl.SupportedDecoders().Types(); !reflect.DeepEqual(tc.Decoders, got) {
+				t.Errorf("decoders, expected %v, got %v", tc.Decoders, got)
+			}
+			if got := ctrl.DecoderLimit(render.ContentTypeJSON); got != tc.Limit {
+				t.Errorf("limit, expected %v, got %v", tc.Limit, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"json only": {
+			Config:     `{"responders":["application/json"],"decoders":["application/json"],"default_response":"application/json","limits":{"application/json":1024}}`,
+			Responders: []render.ContentType{render.ContentTypeJSON},
+			Decoders:   []render.ContentType{render.ContentTypeJSON},
+			Limit:      1024,
+		},
+		"unknown responder": {
+			Config: `{"responders":["application/unknown"],"default_response":"application/unknown"}`,
+			Err:    true,
+		},
+		"default not a responder": {
+			Config: `{"responders":["application/json"],"default_response":"text/xml"}`,
+			Err:    true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestReloadKeepsControllerOnError(t *testing.T) {
+	ctrl := render.CloneDefault()
+	ref := render.NewSwappableController(ctrl)
+	if err := config.Reload(ref, "testdata/does-not-exist.json"); err == nil {
+		t.Errorf("error, expected an error, got %v", err)
+	}
+	if ref.Load() != ctrl {
+		t.Errorf("controller, expected the original controller to be kept")
+	}
+}
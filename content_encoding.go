@@ -0,0 +1,69 @@
+package render
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+// ErrUnsupportedEncoding is the error that UnsupportedEncodingError values match using errors.Is.
+var ErrUnsupportedEncoding = errors.New("render: unsupported request content encoding")
+
+// UnsupportedEncodingError is returned by Bind when the request body has a
+// Content-Encoding that can not be decompressed.
+type UnsupportedEncodingError struct {
+	// Encoding is the content coding that is not supported
+	Encoding string
+}
+
+func (err *UnsupportedEncodingError) Error() string {
+	return fmt.Sprintf("render: unsupported request content encoding '%s'", err.Encoding)
+}
+
+// Is reports whether target is ErrUnsupportedEncoding
+func (err *UnsupportedEncodingError) Is(target error) bool { return target == ErrUnsupportedEncoding }
+
+// StatusCode is the http status code that should be reported to the client
+func (err *UnsupportedEncodingError) StatusCode() int { return http.StatusUnsupportedMediaType }
+
+// decompressors are the content codings request bodies can be sent with
+var decompressors = map[string]func(io.Reader) (io.Reader, error){
+	"gzip":     func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) },
+	"x-gzip":   func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) },
+	"deflate":  func(r io.Reader) (io.Reader, error) { return zlib.NewReader(r) },
+	"br":       func(r io.Reader) (io.Reader, error) { return brotli.NewReader(r), nil },
+	"identity": func(r io.Reader) (io.Reader, error) { return r, nil },
+}
+
+// decompress wraps the body in the decompressors for the Content-Encoding of
+// the request, so decoders get the body as it was before it was compressed.
+// The encodings are listed in the order they were applied, so they are undone
+// from last to first.
+func decompress(r *http.Request, body io.Reader) (io.Reader, error) {
+	header := strings.Join(r.Header.Values("Content-Encoding"), ",")
+	if header == "" {
+		return body, nil
+	}
+	encodings := strings.Split(header, ",")
+	for i := len(encodings) - 1; i >= 0; i-- {
+		encoding := strings.ToLower(strings.TrimSpace(encodings[i]))
+		if encoding == "" {
+			continue
+		}
+		fn, ok := decompressors[encoding]
+		if !ok {
+			return nil, &UnsupportedEncodingError{Encoding: encoding}
+		}
+		var err error
+		if body, err = fn(body); err != nil {
+			return nil, fmt.Errorf("render: request body is not valid %s: %w", encoding, err)
+		}
+	}
+	return body, nil
+}
@@ -0,0 +1,136 @@
+package render
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+)
+
+func TestContentEncoding(t *testing.T) {
+	type tcase struct {
+		Encoding string
+		Body     []byte
+		Limit    int64
+		Name     string
+		Err      error
+	}
+
+	type payload struct {
+		Name string `json:"name"`
+		NilBinder
+	}
+
+	compress := func(body string, encodings ...string) []byte {
+		b := []byte(body)
+		for _, encoding := range encodings {
+			var buf bytes.Buffer
+			var w io.WriteCloser
+			switch encoding {
+			case "gzip":
+				w = gzip.NewWriter(&buf)
+			case "deflate":
+				w = zlib.NewWriter(&buf)
+			case "br":
+				w = brotli.NewWriter(&buf)
+			}
+			_, _ = w.Write(b)
+			_ = w.Close()
+			b = buf.Bytes()
+		}
+		return b
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			ctrl := CloneDefault()
+			_ = ctrl.SetDecoderLimit(ContentTypeJSON, tc.Limit)
+
+			r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(tc.Body))
+			r.Header.Set("Content-Type", "application/json")
+			r.Header.Set("Content-Encoding", tc.Encoding)
+
+			var p payload
+			err := ctrl.Bind(r, &p)
+			if tc.Err != nil {
+				if !errors.Is(err, tc.Err) {
+					t.Errorf("error, expected %v, got %v", tc.Err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error, expected nil, got %v", err)
+			}
+			if p.Name != tc.Name {
+				t.Errorf("name, expected %q, got %q", tc.Name, p.Name)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"none": {
+			Body: []byte(`{"name":"world"}`),
+			Name: "world",
+		},
+		"identity": {
+			Encoding: "identity",
+			Body:     []byte(`{"name":"world"}`),
+			Name:     "world",
+		},
+		"gzip": {
+			Encoding: "gzip",
+			Body:     compress(`{"name":"world"}`, "gzip"),
+			Name:     "world",
+		},
+		"deflate": {
+			Encoding: "deflate",
+			Body:     compress(`{"name":"world"}`, "deflate"),
+			Name:     "world",
+		},
+		"br": {
+			Encoding: "br",
+			Body:     compress(`{"name":"world"}`, "br"),
+			Name:     "world",
+		},
+		"stacked": {
+			Encoding: "deflate, GZIP",
+			Body:     compress(`{"name":"world"}`, "deflate", "gzip"),
+			Name:     "world",
+		},
+		"unsupported": {
+			Encoding: "compress",
+			Body:     []byte(`{"name":"world"}`),
+			Err:      ErrUnsupportedEncoding,
+		},
+		"not gzip": {
+			Encoding: "gzip",
+			Body:     []byte(`{"name":"world"}`),
+			Err:      gzip.ErrHeader,
+		},
+		"limit applies to decompressed body": {
+			Encoding: "gzip",
+			Body:     compress(`{"name":"`+strings.Repeat("a", 1000)+`"}`, "gzip"),
+			Limit:    100,
+			Err:      ErrBodyTooLarge,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+
+	var unsupported *UnsupportedEncodingError
+	if err := CloneDefault().Bind(&http.Request{
+		Header: http.Header{"Content-Type": {"application/json"}, "Content-Encoding": {"zstd"}},
+		Body:   ioutil.NopCloser(strings.NewReader("")),
+	}, &payload{}); !errors.As(err, &unsupported) || unsupported.StatusCode() != http.StatusUnsupportedMediaType {
+		t.Errorf("error, expected a 415 *UnsupportedEncodingError, got %v", err)
+	}
+}
@@ -93,6 +93,15 @@ func (set *ContentTypeSet) StringHas(mediaType string) bool {
 	return set.Has(ContentType(ct))
 }
 
+// clone returns a copy of the set, reset to its start; nil for a nil set, so an
+// empty set, which allows nothing, stays empty rather than becoming nil
+func (set *ContentTypeSet) clone() *ContentTypeSet {
+	if set == nil {
+		return nil
+	}
+	return &ContentTypeSet{set: append([]ContentType(nil), set.set...), pos: -1}
+}
+
 // SetOfContentTypes returns a set of the given ContentTypes
 func SetOfContentTypes(types ...ContentType) *ContentTypeSet {
 	if len(types) == 0 {
@@ -120,7 +129,8 @@ allTypes:
 }
 
 // NewContentTypeSet returns a new set of ContentTypes based on the set of strings passed in. mime.ParseMediaType is
-// used to parse each string. Empty strings and strings that do not parse are ignored.
+// used to parse each string. Empty strings, strings that do not parse and media types without a subtype are
+// ignored; media types with invalid parameters are kept.
 func NewContentTypeSet(types ...string) *ContentTypeSet {
 	if len(types) == 0 {
 		return nil
@@ -131,8 +141,8 @@ func NewContentTypeSet(types ...string) *ContentTypeSet {
 	}
 allTypes:
 	for _, t := range types {
-		mediaType, _, err := mime.ParseMediaType(t)
-		if err != nil {
+		mediaType, ok := lenientMediaType(t)
+		if !ok {
 			// skip types that can not be parsed
 			continue
 		}
@@ -185,10 +195,21 @@ const (
 	ContentTypeJSON        = ContentType("application/json")
 	ContentTypeData        = ContentType("application/octet-stream")
 	ContentTypeForm        = ContentType("multipart/form-data")
+	ContentTypeURLEncoded  = ContentType("application/x-www-form-urlencoded")
 	ContentTypeEventStream = ContentType("text/event-stream")
 	ContentTypeHTML        = ContentType("text/html")
 	ContentTypePlainText   = ContentType("text/plain")
 	ContentTypeXML         = ContentType("text/xml")
+	ContentTypeProblemJSON = ContentType("application/problem+json")
+	ContentTypeAppXML      = ContentType("application/xml")
+	ContentTypeVCard       = ContentType("text/vcard")
+	ContentTypeCalendar    = ContentType("text/calendar")
+	ContentTypeCSV         = ContentType("text/csv")
+	ContentTypeYAML        = ContentType("application/yaml")
+	ContentTypeCBOR        = ContentType("application/cbor")
+	ContentTypeProtobuf    = ContentType("application/x-protobuf")
+	ContentTypeNDJSON      = ContentType("application/x-ndjson")
+	ContentTypeAvro        = ContentType("avro/binary")
 )
 
 // SetContentType is a middleware that forces response Content-Type.
@@ -217,18 +238,47 @@ func AllowedContentTypes(contentTypes ContentTypeSet) func(next http.Handler) ht
 }
 
 // GetRequestContentType is a helper function that returns ContentType based on
-// context or "content-Type" request header.
+// context or "content-Type" request header. The parameters of either, like
+// "; charset=utf-8", are ignored; dflt is returned if the header is malformed.
 func GetRequestContentType(r *http.Request, dflt ContentType) ContentType {
 	if contentType, ok := r.Context().Value(ContentTypeCtxKey).(ContentType); ok && contentType != "" {
+		if ct, ok := lenientMediaType(string(contentType)); ok {
+			return ct
+		}
 		return contentType
 	}
-	ct, err := GetContentType(r.Header.Get("Content-Type"))
-	if err != nil {
+	ct, ok := lenientMediaType(r.Header.Get("Content-Type"))
+	if !ok {
 		return dflt
 	}
 	return ct
 }
 
+// DefaultAcceptOverrideHeader is the conventional name of the header used by
+// Controller.AcceptOverrideHeader
+const DefaultAcceptOverrideHeader = "X-Accept-Override"
+
+// acceptedContentTypes returns the content types accepted by the request,
+// honoring the controller's AcceptOverrideHeader and MalformedHeaders policy.
+func (ctrl *Controller) acceptedContentTypes(w http.ResponseWriter, r *http.Request) (*ContentTypeSet, error) {
+	name := "Accept"
+	if ctrl.AcceptOverrideHeader != "" {
+		// the response depends on the override header, so caches need to know
+		w.Header().Add("Vary", ctrl.AcceptOverrideHeader)
+		if strings.TrimSpace(r.Header.Get(ctrl.AcceptOverrideHeader)) != "" {
+			name = ctrl.AcceptOverrideHeader
+		}
+	}
+	if contentType, ok := r.Context().Value(ContentTypeCtxKey).(ContentType); ok {
+		return NewContentTypeSet(string(contentType)), nil
+	}
+	value := r.Header.Get(name)
+	if err := ctrl.MalformedHeaders.checkAcceptHeader(name, value); err != nil {
+		return nil, err
+	}
+	return NewContentTypeSet(strings.Split(value, ",")...), nil
+}
+
 // GetAcceptedContentType is a helper function that returns a set of ContentTypes based
 // on context or "Accept" request header.
 func GetAcceptedContentType(r *http.Request) *ContentTypeSet {
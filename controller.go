@@ -1,12 +1,15 @@
 package render
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 	"reflect"
-	"sort"
 	"sync"
+	"time"
 
 	"github.com/gdey/chi-render/responders/helpers"
 
@@ -20,18 +23,25 @@ var (
 	// to be not nil
 	ErrControllerIsNil = errors.New("controller is nil")
 
+	// ErrNotAcceptable is returned when none of the content types accepted by the
+	// request are honored by the controller
+	ErrNotAcceptable = errors.New("render: not acceptable")
+
 	// defaultCtrl is the default controller that is used if a controller is nil,
 	// or the package functions are used.
 	defaultCtrl = Controller{
-		responders: map[ContentType]responders.Func{
-			ContentTypeDefault:     responders.JSON,
-			ContentTypeJSON:        responders.JSON,
-			ContentTypeXML:         responders.XML,
-			ContentTypeEventStream: ChannelEventStream,
+		responders: map[ContentType]responders.Registration{
+			ContentTypeDefault:     {Func: responders.JSON, Structured: true},
+			ContentTypeJSON:        {Func: responders.JSON, CanEncode: responders.CanEncodeJSON, Structured: true},
+			ContentTypeXML:         {Func: responders.XML, CanEncode: responders.CanEncodeXML, Structured: true},
+			ContentTypeData:        {Func: responders.Data, CanEncode: responders.IsBinary},
+			ContentTypeEventStream: {Func: ChannelEventStream, Structured: true},
 		},
-		decoders: map[ContentType]decoders.Func{
-			ContentTypeJSON: decoders.JSON,
-			ContentTypeXML:  decoders.XML,
+		decoders: map[ContentType]decoderEntry{
+			ContentTypeJSON:       {fn: decoders.JSON},
+			ContentTypeXML:        {fn: decoders.XML},
+			ContentTypeForm:       {fn: decoders.Multipart},
+			ContentTypeURLEncoded: {fn: decoders.URLEncoded},
 		},
 		DefaultRequest:  ContentTypeNone,
 		DefaultResponse: ContentTypeDefault,
@@ -46,21 +56,167 @@ type Interface interface {
 }
 
 // Controller is responsible for managing the respond types that are available
+//
+// The zero value is an empty controller with no responders or decoders; use
+// CloneDefault to start with the default set of responders and decoders.
 type Controller struct {
 	responderLck sync.RWMutex
 	// responders is a mapping of content type to a function that can
 	//  marshal an object to that content type
-	responders map[ContentType]responders.Func
+	responders map[ContentType]responders.Registration
+	// errorResponders are used instead of responders for error payloads
+	errorResponders map[ContentType]responders.Registration
+	// profileResponders are used instead of responders when the client asks
+	// for a profile of the content type
+	profileResponders map[profileKey]responders.Registration
+
+	typeEncoderLck sync.RWMutex
+	// typeEncoders are used by the structured responders in place of
+	// the default encoding of a type
+	typeEncoders map[reflect.Type]TypeEncoder
+
+	payloadTypeLck sync.RWMutex
+	// payloadTypes are the content types preferred for payloads of a type
+	payloadTypes map[reflect.Type]ContentType
+
+	typeConverterLck sync.RWMutex
+	// typeConverters are used when binding strings to values of a type
+	typeConverters map[reflect.Type]TypeConverter
+
+	// metrics are the response counters exposed by DebugHandler
+	metrics responseMetrics
+	// decodeMetrics are the request body counters exposed by DebugHandler
+	decodeMetrics decodeMetrics
 
 	decoderLck sync.RWMutex
 	// decoders is a mapping content type to a function that can
-	// unmarshal a byte slice to an object
-	decoders map[ContentType]decoders.Func
+	// unmarshal a byte slice to an object, and the limits for that content type
+	decoders map[ContentType]decoderEntry
 
 	// If no content type matches, this content type will be used.
 	DefaultRequest ContentType
 	// If no Accept header match, this content type will be used to render the object
 	DefaultResponse ContentType
+
+	// AllowedAccept, if not nil, are the only content types from the Accept header
+	// that will be honored, regardless of which responders are registered.
+	AllowedAccept *ContentTypeSet
+	// DeniedAccept are content types from the Accept header that will not be
+	// honored, even if there is a responder registered for them.
+	//
+	// If every content type in the Accept header is denied or not allowed, the
+	// client will get a 406 Not Acceptable response.
+	DeniedAccept *ContentTypeSet
+
+	// AcceptOverrideHeader, if set, is the name of a request header, like
+	// DefaultAcceptOverrideHeader, whose value replaces the Accept header during
+	// content negotiation, for gateways and webhook callers that can not set
+	// the Accept header properly.
+	AcceptOverrideHeader string
+
+	// StrictAccept, if true, only lets the content type preferred for a payload
+	// type, see RegisterPayload, be used if the Accept header accepts it.
+	StrictAccept bool
+
+	// MalformedHeaders is how malformed Accept and Content-Type headers are
+	// handled; the zero value ignores them.
+	MalformedHeaders HeaderPolicy
+
+	// Fallback is what is done when the responder of the best match of the
+	// Accept header can not encode the payload; the zero value tries the next
+	// content types the request accepts, then the default responder.
+	Fallback FallbackStrategy
+
+	// Conditional, if set, is used to look up the validators of a payload once
+	// the content type has been negotiated, before the responder is run. The
+	// ETag and Last-Modified headers are set from the validators, with strong
+	// entity tags made specific to the content type, and GET and HEAD requests
+	// whose If-None-Match or If-Modified-Since headers match get a 304 Not
+	// Modified response.
+	Conditional ValidatorLookup
+
+	// Digest, if not DigestOff, is the checksum set on every buffered response
+	// before it is signed and sent to the client.
+	Digest DigestAlgorithm
+
+	// Signer, if set, signs every buffered response before it is sent
+	// to the client; see HMACSigner and JWSSigner.
+	Signer Signer
+
+	// Audit, if set, is called with a record of every response rendered
+	Audit Auditor
+
+	// CSRF, if set, provides the CSRF token the HTML responders put in the
+	// forms they render. If it is also a CSRFVerifier, Bind rejects form
+	// requests that do not send a valid token back.
+	CSRF CSRFProvider
+
+	// ServerTiming, if true, has the controller send the time spent decoding,
+	// binding, rendering and encoding, along with the spans added with
+	// AddTiming, to the client in the Server-Timing header of buffered responses.
+	ServerTiming bool
+
+	// CopyOnRender, if true, has the controller render and respond with a copy
+	// of the payload, so Render methods that mutate the payload do not race
+	// with other requests that share the same models. Payloads can implement
+	// Cloner to provide the copy; otherwise a deep copy is made using reflection.
+	CopyOnRender bool
+
+	// RaceCheck, if not RaceCheckOff, tracks the pointers in the payloads being
+	// rendered to find payloads that share models across concurrent requests.
+	RaceCheck RaceCheck
+
+	// MaxBodyBytes, if greater than zero, is the largest request body, once
+	// decompressed, Bind will read for any content type; larger bodies fail with
+	// a *BodyTooLargeError. The limits set with SetDecoderLimit still apply to
+	// their content types if they are smaller.
+	MaxBodyBytes int64
+
+	// MaxResponseBytes, if greater than zero, is the largest response body a
+	// responder may produce. Larger responses are discarded, logged and replaced
+	// with an ErrResponse wrapping a *ResponseTooLargeError. The JSON and XML
+	// responders stop encoding as soon as they go over the limit.
+	MaxResponseBytes int64
+
+	// Redact is how the structured responders sanitize the fields tagged as
+	// sensitive; see RedactMode and AllowRedacted.
+	Redact RedactMode
+
+	// IgnoreViews, if true, has the structured responders include the fields
+	// tagged with `view:"role,..."` for every viewer. Otherwise those fields are
+	// left out unless the viewer has one of the roles, see SetViewerRoles; a
+	// request with no roles set sees none of them.
+	IgnoreViews bool
+
+	// KeyCasing is the casing of the JSON keys of struct fields in structured responses
+	KeyCasing KeyCasing
+
+	// TimeFormat is how time.Time values are written in structured responses
+	TimeFormat TimeFormat
+	// DurationFormat is how time.Duration values are written in structured responses
+	DurationFormat DurationFormat
+
+	// Nulls is how null fields are handled in JSON structured responses
+	Nulls NullPolicy
+
+	// BigNumbersAsStrings, if true, has JSON structured responses write integers
+	// that JavaScript can not represent exactly, and decimal types, as strings.
+	// Such strings are read back as numbers by Bind for JSON request bodies.
+	BigNumbersAsStrings bool
+
+	// ProtoJSON, if true, has proto.Message payloads written with protojson for
+	// the JSON content type, see responders.ProtoJSON, and JSON request bodies
+	// bound to proto.Message payloads read with protojson, rather than with
+	// encoding/json.
+	ProtoJSON bool
+}
+
+// decoderEntry is a registered decoder along with the settings for its content type
+type decoderEntry struct {
+	fn decoders.Func
+	// limit is the maximum number of bytes of the request body the decoder
+	// will be allowed to read; zero or less means no limit.
+	limit int64
 }
 
 // Status sets a HTTP response status code hint into request context at any point
@@ -78,13 +234,54 @@ func (ctrl *Controller) Clone() *Controller {
 	child := new(Controller)
 	child.DefaultResponse = ctrl.DefaultResponse
 	child.DefaultRequest = ctrl.DefaultRequest
-	child.responders = make(map[ContentType]responders.Func, len(ctrl.responders))
-	child.decoders = make(map[ContentType]decoders.Func, len(ctrl.decoders))
+	child.AllowedAccept = ctrl.AllowedAccept.clone()
+	child.DeniedAccept = ctrl.DeniedAccept.clone()
+	child.AcceptOverrideHeader = ctrl.AcceptOverrideHeader
+	child.StrictAccept = ctrl.StrictAccept
+	child.Fallback = ctrl.Fallback
+	child.MalformedHeaders = ctrl.MalformedHeaders
+	child.Conditional = ctrl.Conditional
+	child.Digest = ctrl.Digest
+	child.Signer = ctrl.Signer
+	child.CSRF = ctrl.CSRF
+	child.Audit = ctrl.Audit
+	child.ServerTiming = ctrl.ServerTiming
+	child.CopyOnRender = ctrl.CopyOnRender
+	child.RaceCheck = ctrl.RaceCheck
+	child.MaxBodyBytes = ctrl.MaxBodyBytes
+	child.MaxResponseBytes = ctrl.MaxResponseBytes
+	child.Redact = ctrl.Redact
+	child.IgnoreViews = ctrl.IgnoreViews
+	child.KeyCasing = ctrl.KeyCasing
+	child.TimeFormat = ctrl.TimeFormat
+	child.DurationFormat = ctrl.DurationFormat
+	child.Nulls = ctrl.Nulls
+	child.BigNumbersAsStrings = ctrl.BigNumbersAsStrings
+	child.ProtoJSON = ctrl.ProtoJSON
+	child.responders = make(map[ContentType]responders.Registration, len(ctrl.responders))
+	child.decoders = make(map[ContentType]decoderEntry, len(ctrl.decoders))
 	ctrl.responderLck.RLock()
 	for name, val := range ctrl.responders {
 		child.responders[name] = val
 	}
+	if len(ctrl.errorResponders) > 0 {
+		child.errorResponders = make(map[ContentType]responders.Registration, len(ctrl.errorResponders))
+		for name, val := range ctrl.errorResponders {
+			child.errorResponders[name] = val
+		}
+	}
+	if len(ctrl.profileResponders) > 0 {
+		child.profileResponders = make(map[profileKey]responders.Registration, len(ctrl.profileResponders))
+		for key, val := range ctrl.profileResponders {
+			child.profileResponders[key] = val
+		}
+	}
 	ctrl.responderLck.RUnlock()
+	// the type encoders, converters and payload types maps are never
+	// modified, only replaced
+	child.typeEncoders = ctrl.encoders()
+	child.typeConverters = ctrl.converters()
+	child.payloadTypes = ctrl.preferredTypes()
 	ctrl.decoderLck.RLock()
 	for name, val := range ctrl.decoders {
 		child.decoders[name] = val
@@ -98,7 +295,19 @@ func (ctrl *Controller) Render(w http.ResponseWriter, r *http.Request, v Rendere
 	if ctrl == nil {
 		return defaultCtrl.Render(w, r, v)
 	}
-	if err := renderer(w, r, v); err != nil {
+	if ctrl.CopyOnRender {
+		v = copyForRender(v)
+	}
+	release, err := ctrl.trackRender(r, v)
+	if err != nil {
+		return err
+	}
+	defer release()
+	stats := statsFor(r)
+	start := time.Now()
+	err = renderer(w, r, v)
+	stats.update(func(stats *RenderStats) { stats.RenderDuration += time.Since(start) })
+	if err != nil {
 		return err
 	}
 	ctrl.respond(w, r, v)
@@ -110,11 +319,27 @@ func (ctrl *Controller) RenderList(w http.ResponseWriter, r *http.Request, l []R
 	if ctrl == nil {
 		return defaultCtrl.RenderList(w, r, l)
 	}
+	if ctrl.CopyOnRender {
+		copied := make([]Renderer, len(l))
+		for i := range l {
+			copied[i] = copyForRender(l[i])
+		}
+		l = copied
+	}
+	release, err := ctrl.trackRender(r, l)
+	if err != nil {
+		return err
+	}
+	defer release()
+	stats := statsFor(r)
+	start := time.Now()
 	for _, v := range l {
 		if err := renderer(w, r, v); err != nil {
+			stats.update(func(stats *RenderStats) { stats.RenderDuration += time.Since(start) })
 			return err
 		}
 	}
+	stats.update(func(stats *RenderStats) { stats.RenderDuration += time.Since(start) })
 	ctrl.respond(w, r, l)
 	return nil
 }
@@ -155,64 +380,281 @@ func channelIntoSlice(w http.ResponseWriter, r *http.Request, from interface{})
 }
 
 func (ctrl *Controller) respond(w http.ResponseWriter, r *http.Request, v interface{}) {
-	var err error
+	stats := statsFor(r)
+	start := time.Now()
+	ww := helpers.WrapWriter(w)
+	written := ww.BytesWritten()
+	w = ww
+	// fallback is set if the default responder was used
+	var fallback bool
+	var tees []io.Writer
+	var trail *auditTrail
+	if ctrl.Audit != nil {
+		trail = newAuditTrail(v, written)
+		tees = append(tees, trail.hash)
+	}
+	// capture is set if the response is to be replayed for the Idempotency-Key
+	// of the request; it is only stored if the response was rendered
+	capture := idempotencyCaptureFor(r)
+	var captured bytes.Buffer
+	var rendered bool
+	if capture != nil {
+		tees = append(tees, &captured)
+	}
+	if len(tees) > 0 {
+		ww.Tee(io.MultiWriter(tees...))
+	}
+	defer func() {
+		stats.update(func(stats *RenderStats) {
+			stats.RespondDuration += time.Since(start)
+			stats.BytesWritten += ww.BytesWritten() - written
+			stats.Status = ww.Status()
+			stats.ContentType = ww.Header().Get("Content-Type")
+		})
+		ctrl.metrics.record(ww.Header().Get("Content-Type"), ww.Status(), ww.BytesWritten()-written, fallback)
+		if len(tees) > 0 {
+			ww.Tee(nil)
+		}
+		if capture != nil && rendered {
+			capture.save(r, ww.Status(), ww.Header(), captured.Bytes())
+		}
+		if trail != nil {
+			ctrl.Audit(r, trail.record(r, ww.Status(), ww.BytesWritten(), ww.Header().Get("Content-Type")))
+		}
+	}()
+
+	fallback, err := ctrl.encode(w, r, v)
+	var tooLarge *ResponseTooLargeError
+	if errors.As(err, &tooLarge) {
+		fallback, err = ctrl.respondTooLarge(w, r, tooLarge)
+	}
+	rendered = err == nil
+	if err != nil {
+		if errors.Is(err, ErrNotAcceptable) {
+			http.Error(w, http.StatusText(http.StatusNotAcceptable), http.StatusNotAcceptable)
+			return
+		}
+		var malformed *MalformedHeaderError
+		if errors.As(err, &malformed) {
+			http.Error(w, err.Error(), malformed.StatusCode())
+			return
+		}
+		if errors.Is(err, ErrPanic) {
+			// the panic has been logged, don't leak its details to the client
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
 
-	acceptedTypes := GetAcceptedContentType(r)
+// respondTooLarge replaces a response that was larger than MaxResponseBytes
+// with an ErrResponse, making sure the error gets logged.
+func (ctrl *Controller) respondTooLarge(w http.ResponseWriter, r *http.Request, tooLarge *ResponseTooLargeError) (fallback bool, err error) {
+	logTo := ErrorLogTo
+	if logTo == nil {
+		logTo = ErrLogToStdOut
+	}
+	errResp := &ErrResponse{
+		Err:        tooLarge,
+		StatusCode: tooLarge.StatusCode(),
+		LogTo:      logTo,
+	}
+	if err := errResp.Render(w, r); err != nil {
+		return false, err
+	}
+	return ctrl.encode(w, r, errResp)
+}
+
+// encode negotiates the content type and writes the payload with the chosen
+// responder; fallback is true if it was the default responder. ErrNotAcceptable
+// is returned if every content type the client accepts is refused.
+func (ctrl *Controller) encode(w http.ResponseWriter, r *http.Request, v interface{}) (fallback bool, err error) {
+	acceptedTypes, err := ctrl.acceptedContentTypes(w, r)
+	if err != nil {
+		return false, err
+	}
 	if v != nil {
 		switch reflect.TypeOf(v).Kind() {
 		case reflect.Chan:
-			if acceptedTypes.Has(ContentTypeEventStream) {
-				ctrl.responderLck.RLock()
-				fn, ok := ctrl.responders[ContentTypeEventStream]
-				ctrl.responderLck.RUnlock()
-				if ok {
-					if err = fn(w, r, v); err != nil {
-						http.Error(w, err.Error(), http.StatusInternalServerError)
+			if acceptedTypes.Has(ContentTypeEventStream) && ctrl.honorsAccept(ContentTypeEventStream) {
+				if reg, ok := ctrl.responder(ContentTypeEventStream); ok {
+					if structured(reg) {
+						// the responder projects each item as it is sent
+						ctrl.withProjection(r)
+						return false, reg.Func(w, r, v)
+					}
+					if !ctrl.hidesType(r, reflect.TypeOf(v).Elem()) {
+						return false, reg.Func(w, r, v)
 					}
-					return
+					// the responder would see the fields the policies
+					// hide, so the items are rendered like a slice
 				}
 			}
 			v = channelIntoSlice(w, r, v)
 		}
 	}
 
+	acceptedTypes = ctrl.withPreferred(r, acceptedTypes, v)
+
+	withSizeHint(r, v)
+	ctrl.withResponseLimit(r)
+	ctrl.withCSRF(r)
+	projected, err := ctrl.project(r, v)
+	if err != nil {
+		return false, err
+	}
+	if routeOptionsFrom(r).envelope {
+		projected = Envelope{Data: projected}
+	}
+
+	profiles := ctrl.acceptedProfiles(r)
+	// unencodable is set if a responder could not encode the payload
+	var refused, acceptable, unencodable bool
+accepted:
 	for acceptedTypes.Next() {
+		if !ctrl.honorsAccept(acceptedTypes.Type()) {
+			refused = true
+			continue
+		}
+		acceptable = true
 		// Skip ContentTypeEventStream, handled up top.
 		if acceptedTypes.Type() == ContentTypeEventStream {
 			continue
 		}
-		ct := acceptedTypes.Type()
-		ctrl.responderLck.RLock()
-		fn, ok := ctrl.responders[ct]
-		ctrl.responderLck.RUnlock()
+		reg, ok := ctrl.responderFor(acceptedTypes.Type(), v)
+		profile := profiles[acceptedTypes.Type()]
+		if profiles != nil {
+			withProfile(r, profile)
+		}
+		var link string
+		if profile != "" {
+			if preg, pok := ctrl.profileResponder(acceptedTypes.Type(), profile); pok {
+				reg, ok = preg, true
+				link = "<" + profile + `>; rel="profile"`
+			}
+		}
 		if !ok {
 			continue
 		}
-
-		if err = fn(w, r, v); err != nil {
-
-			if errors.Is(err, responders.ErrCanNotEncodeObject) {
-				// Let's try the next content type
-				continue
+		if payload, ok := ctrl.payloadFor(r, reg, v, projected); ok && reg.Encodes(v) {
+			if ctrl.evaluateConditional(w, r, acceptedTypes.Type(), v) {
+				return false, nil
+			}
+			if link != "" {
+				w.Header().Add("Link", link)
+			}
+			err := ctrl.respondSafely(w, r, acceptedTypes.Type(), reg.Func, payload)
+			var pe *PanicError
+			switch {
+			case errors.Is(err, responders.ErrCanNotEncodeObject):
+				removeHeaderValue(w.Header(), "Link", link)
+			case errors.As(err, &pe):
+				// degrade to the default responder, rather than trusting
+				// the other responders the client accepts
+				removeHeaderValue(w.Header(), "Link", link)
+				logResponderPanic(r, pe)
+				break accepted
+			default:
+				return false, err
+			}
+		}
+		unencodable = true
+		if !ctrl.Fallback.triesNextAccepted() {
+			break
+		}
+		// Let's try the next content type
+	}
+	if refused && !acceptable {
+		return false, ErrNotAcceptable
+	}
+	if unencodable && ctrl.Fallback == FallbackNone {
+		return false, ErrNotAcceptable
+	}
+	if ctrl.Fallback == FallbackJSON && ctrl.DefaultResponse != ContentTypeJSON {
+		reg, ok := ctrl.responderFor(ContentTypeJSON, v)
+		payload, guarded := ctrl.payloadFor(r, reg, v, projected)
+		if ok && guarded && reg.Encodes(v) {
+			if ctrl.evaluateConditional(w, r, ContentTypeJSON, v) {
+				return true, nil
+			}
+			err := ctrl.respondSafely(w, r, ContentTypeJSON, reg.Func, payload)
+			var pe *PanicError
+			switch {
+			case errors.Is(err, responders.ErrCanNotEncodeObject):
+			case errors.As(err, &pe):
+				logResponderPanic(r, pe)
+			default:
+				return true, err
 			}
-
-			http.Error(w, err.Error(), http.StatusInternalServerError)
 		}
-		return
 	}
-	ctrl.responderLck.RLock()
 	if ctrl.DefaultResponse == "" {
 		ctrl.DefaultResponse = ContentTypeDefault
 	}
-	fn, ok := ctrl.responders[ctrl.DefaultResponse]
-	ctrl.responderLck.RUnlock()
-
+	// The default responder is the last resort, so it is not asked if it can encode
+	// the object.
+	reg, ok := ctrl.responderFor(ctrl.DefaultResponse, v)
 	if !ok {
 		panic("Default Controller Responder not set!")
 	}
-	if err = fn(w, r, v); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	payload, ok := ctrl.payloadFor(r, reg, v, projected)
+	if !ok {
+		return false, ErrNotAcceptable
+	}
+	if ctrl.evaluateConditional(w, r, ctrl.DefaultResponse, v) {
+		return true, nil
+	}
+	err = ctrl.respondSafely(w, r, ctrl.DefaultResponse, reg.Func, payload)
+	var pe *PanicError
+	if errors.As(err, &pe) {
+		logResponderPanic(r, pe)
+	}
+	return true, err
+}
+
+// RenderToBuffer renders the payload like Render, negotiating the content type
+// from the Accept header of the request, but returns the response instead of
+// writing it to a http.ResponseWriter. This allows the same payload types to be
+// used for emails, exports and cached artifacts.
+//
+// ErrNotAcceptable is returned if every content type the request accepts is refused.
+func (ctrl *Controller) RenderToBuffer(r *http.Request, v Renderer) (contentType string, body []byte, status int, err error) {
+	if ctrl == nil {
+		return defaultCtrl.RenderToBuffer(r, v)
+	}
+	if ctrl.CopyOnRender {
+		v = copyForRender(v)
+	}
+	release, err := ctrl.trackRender(r, v)
+	if err != nil {
+		return "", nil, 0, err
 	}
+	defer release()
+	rec := newResponseRecorder(nil)
+	if err = renderer(rec, r, v); err != nil {
+		return "", nil, 0, err
+	}
+	if _, err = ctrl.encode(rec, r, v); err != nil {
+		return "", nil, 0, err
+	}
+	status = rec.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	return rec.header.Get("Content-Type"), rec.body.Bytes(), status, nil
+}
+
+// honorsAccept reports whether the content type, from an Accept header, is
+// allowed and not denied.
+func (ctrl *Controller) honorsAccept(contentType ContentType) bool {
+	if ctrl.DeniedAccept.Has(contentType) {
+		return false
+	}
+	if ctrl.AllowedAccept == nil || contentType == ContentTypeDefault {
+		return true
+	}
+	return ctrl.AllowedAccept.Has(contentType)
 }
 
 // SetResponder will set the responder for the given content type.
@@ -220,56 +662,228 @@ func (ctrl *Controller) respond(w http.ResponseWriter, r *http.Request, v interf
 // Only error this function will return is ErrControllerIsNil; is returned
 // if the Controller object is nil.
 func (ctrl *Controller) SetResponder(contentType ContentType, responder responders.Func) error {
+	if ctrl == nil {
+		return ErrControllerIsNil
+	}
+	return ctrl.RegisterResponder(contentType, responders.Registration{Func: responder})
+}
+
+// RegisterResponder will set the responder registration for the given content type.
+// This allows the responder to provide capabilities, like CanEncode, that
+// the controller can use during content negotiation.
+// Use a registration with a nil Func to unset a content type
+// Only error this function will return is ErrControllerIsNil; is returned
+// if the Controller object is nil.
+func (ctrl *Controller) RegisterResponder(contentType ContentType, registration responders.Registration) error {
 	if ctrl == nil {
 		return ErrControllerIsNil
 	}
 	ctrl.responderLck.Lock()
-	ctrl.responders[contentType] = responder
+	if ctrl.responders == nil {
+		ctrl.responders = make(map[ContentType]responders.Registration)
+	}
+	ctrl.responders[contentType] = registration
 	ctrl.responderLck.Unlock()
 	return nil
 }
 
-// SupportedResponders returns a ContentTypeSet of the configured Content types with responders
-func (ctrl *Controller) SupportedResponders() *ContentTypeSet {
+// responder returns the registration for the given content type; ok will be false
+// if there is no responder for the content type.
+func (ctrl *Controller) responder(contentType ContentType) (reg responders.Registration, ok bool) {
+	ctrl.responderLck.RLock()
+	reg = ctrl.responders[contentType]
+	ctrl.responderLck.RUnlock()
+	return reg, reg.Func != nil
+}
+
+// SupportedResponders returns the descriptors of the content types with
+// responders, sorted by content type. Wildcard content types, like */*, that
+// use the same responder as another content type are listed as its aliases.
+func (ctrl *Controller) SupportedResponders() ContentTypeDescriptors {
 	if ctrl == nil {
 		return defaultCtrl.SupportedResponders()
 	}
 
 	ctrl.responderLck.RLock()
-	stringValues := make([]string, 0, len(ctrl.responders))
-	for value := range ctrl.responders {
-		stringValues = append(stringValues, string(value))
+	funcs := make(map[ContentType]interface{}, len(ctrl.responders))
+	for contentType, reg := range ctrl.responders {
+		if reg.Func == nil {
+			continue
+		}
+		funcs[contentType] = reg.Func
 	}
 	ctrl.responderLck.RUnlock()
 
-	sort.Strings(stringValues)
-	return NewContentTypeSet(stringValues...)
+	return describeContentTypes(funcs)
 }
 
 // Bind decodes a request body and executes the Binder method of the
-// payload structure.
+// payload structure. Fields tagged with `cookie:"name"` are then set from the
+// cookies of the request, overriding the body, so session tokens and
+// preferences go through the same defaults and validation as the body.
 func (ctrl *Controller) Bind(r *http.Request, v Binder) error {
 	if ctrl == nil {
 		return defaultCtrl.Bind(r, v)
 	}
-	if err := ctrl.decode(r, v); err != nil {
+	return ctrl.bind(r, v, true, cookieSources)
+}
+
+// bind decodes the body of the request, if it is required or there is one, and
+// then binds the fields tagged for the sources, in order.
+func (ctrl *Controller) bind(r *http.Request, v Binder, requireBody bool, sources []urlSource) error {
+	if err := ctrl.MalformedHeaders.checkContentType(r); err != nil {
 		return err
 	}
-	return binder(r, v)
+	stats := statsFor(r)
+	protected := protectedFields(v)
+	ct := GetRequestContentType(r, ctrl.DefaultRequest)
+	decodeBody := requireBody || (r.Body != nil && r.Body != http.NoBody)
+	verifier := ctrl.csrfVerifier(ct)
+	if verifier != nil && r.Header.Get(DefaultCSRFHeader) != "" {
+		// the body of a forged request is not decoded
+		if err := ctrl.verifyCSRF(r, verifier); err != nil {
+			return err
+		}
+		verifier = nil
+	}
+	if decodeBody {
+		if err := ctrl.decode(r, v); err != nil {
+			return wrapDecodeError(err)
+		}
+	}
+	if verifier != nil {
+		// the token is in a field of the form
+		if err := ctrl.verifyCSRF(r, verifier); err != nil {
+			ctrl.decodeMetrics.record(ctrl.decodeMetricsKey(ct), 0, failedValidation)
+			return err
+		}
+	}
+	if err := ctrl.bindURL(r, v, sources); err != nil {
+		ctrl.decodeMetrics.record(ctrl.decodeMetricsKey(ct), 0, failedValidation)
+		return err
+	}
+	if err := ctrl.bindFields(v, protected); err != nil {
+		ctrl.decodeMetrics.record(ctrl.decodeMetricsKey(ct), 0, failedValidation)
+		return err
+	}
+	start := time.Now()
+	err := binder(r, v)
+	stats.update(func(stats *RenderStats) { stats.BindDuration += time.Since(start) })
+	if err != nil {
+		ctrl.decodeMetrics.record(ctrl.decodeMetricsKey(ct), 0, failedValidation)
+	}
+	return err
 }
 
-func (ctrl *Controller) decode(r *http.Request, v interface{}) error {
-
+func (ctrl *Controller) decode(r *http.Request, v interface{}) (err error) {
+	stats := statsFor(r)
+	start := time.Now()
+	counter := &countingReader{r: r.Body}
 	ct := GetRequestContentType(r, ctrl.DefaultRequest)
+	failure := failedDecode
+	defer func() {
+		stats.update(func(stats *RenderStats) {
+			stats.DecodeDuration += time.Since(start)
+			stats.BytesRead += counter.n
+		})
+		if err == nil {
+			failure = failedNone
+		}
+		ctrl.decodeMetrics.record(ctrl.decodeMetricsKey(ct), counter.n, failure)
+	}()
 
+	// format is the content type the body is decoded as; vendor types, like
+	// application/vnd.acme.article+json, with no decoder of their own are
+	// decoded by the decoder of their suffix
+	format := ct
 	ctrl.decoderLck.RLock()
-	decoder := ctrl.decoders[ct]
+	entry := ctrl.decoders[ct]
+	if fallback, ok := suffixFallback(ct); ok && entry.fn == nil {
+		entry, format = ctrl.decoders[fallback], fallback
+	}
 	ctrl.decoderLck.RUnlock()
 
-	if decoder != nil {
-		return decoder(r.Body, v)
+	if ctrl.ProtoJSON && format == ContentTypeJSON && decoders.IsProtoMessage(v) {
+		entry.fn = decoders.ProtoJSON
+	}
+	if entry.fn == nil {
+		failure = failedUnsupported
+		return fmt.Errorf("render: unable to automatically decode the request content type: '%s'", ct)
+	}
+	limit := entry.limit
+	if ctrl.MaxBodyBytes > 0 && (limit <= 0 || ctrl.MaxBodyBytes < limit) {
+		limit = ctrl.MaxBodyBytes
+	}
+	if limit > 0 && r.ContentLength > limit && r.Header.Get("Content-Encoding") == "" {
+		// no need to read a body we know is too large
+		return &BodyTooLargeError{ContentType: ct, Limit: limit}
+	}
+	body, err := decompress(r, counter)
+	if err != nil {
+		if errors.Is(err, ErrUnsupportedEncoding) {
+			failure = failedUnsupported
+		}
+		return err
+	}
+	var limited *limitReader
+	if limit > 0 {
+		// the limit applies to the decompressed body
+		limited = newLimitReader(body, ct, limit)
+		body = limited
+	}
+	var mask *fieldMaskWriter
+	if format == ContentTypeJSON || format == ContentTypeMergePatch {
+		// record the fields that were sent as the body is read
+		mask = new(fieldMaskWriter)
+		body = io.TeeReader(body, mask)
+	}
+	if ctrl.BigNumbersAsStrings && format == ContentTypeJSON {
+		body, err = unquoteNumbers(body, v)
+	}
+	if err == nil {
+		if params := mediaTypeParams(r.Header.Get("Content-Type")); len(params) > 0 {
+			// decoders, like Multipart, may need the boundary or charset
+			body = decoders.WithParams(body, params)
+		}
+		if converters := ctrl.converters(); len(converters) > 0 {
+			// form decoders, like Multipart, convert values with the
+			// registered type converters
+			body = decoders.WithConverter(body, formConverter(converters))
+		}
+		// the temporary files of multipart forms are kept until the
+		// handler returns
+		body = decoders.WithCleanup(body, func(cleanup func() error) { cleanupAfter(r, cleanup) })
+		body = decoders.WithFormValues(body, func(values url.Values) { recordFormValues(r, values) })
+		// decoders that wait on the handler, like NDJSON sending on a
+		// channel, give up with the request
+		body = decoders.WithContext(body, r.Context())
+		err = entry.fn(body, v)
+	}
+	if err == nil && mask != nil {
+		if fields := mask.Fields(); fields != nil {
+			recordFieldMask(r, fields)
+		}
+	}
+	if limited != nil && limited.exceeded {
+		// the decoder may have wrapped or swallowed the error; either way
+		// we want to report a consistent error
+		return limited.err
+	}
+	return err
+}
+
+// cleanupAfter calls cleanup once the context of the request is done, which the
+// http server does when the handler returns. Requests with a context that is
+// never done, like the ones made with httptest.NewRequest, are not cleaned up.
+func cleanupAfter(r *http.Request, cleanup func() error) {
+	done := r.Context().Done()
+	if done == nil {
+		return
 	}
-	return fmt.Errorf("render: unable to automatically decode the request content type: '%s'", ct)
+	go func() {
+		<-done
+		_ = cleanup()
+	}()
 }
 
 // SetDecoder will set the decoder for the given content type.
@@ -281,23 +895,62 @@ func (ctrl *Controller) SetDecoder(contentType ContentType, decoder decoders.Fun
 		return ErrControllerIsNil
 	}
 	ctrl.decoderLck.Lock()
-	ctrl.decoders[contentType] = decoder
+	if ctrl.decoders == nil {
+		ctrl.decoders = make(map[ContentType]decoderEntry)
+	}
+	entry := ctrl.decoders[contentType]
+	entry.fn = decoder
+	ctrl.decoders[contentType] = entry
 	ctrl.decoderLck.Unlock()
 	return nil
 }
 
-// SupportedDecoders returns a ContentTypeSet of the configured Content types with decoders
-func (ctrl *Controller) SupportedDecoders() *ContentTypeSet {
+// SetDecoderLimit will set the maximum number of bytes that will be read from
+// a request body of the given content type. If the body is larger, Bind will return
+// a *BodyTooLargeError. Use a limit of zero to remove the limit.
+// Only error this function will return is ErrControllerIsNil; is returned
+// if the Controller object is nil.
+func (ctrl *Controller) SetDecoderLimit(contentType ContentType, limit int64) error {
+	if ctrl == nil {
+		return ErrControllerIsNil
+	}
+	ctrl.decoderLck.Lock()
+	if ctrl.decoders == nil {
+		ctrl.decoders = make(map[ContentType]decoderEntry)
+	}
+	entry := ctrl.decoders[contentType]
+	entry.limit = limit
+	ctrl.decoders[contentType] = entry
+	ctrl.decoderLck.Unlock()
+	return nil
+}
+
+// DecoderLimit returns the maximum number of bytes that will be read from a request
+// body of the given content type; zero means there is no limit.
+func (ctrl *Controller) DecoderLimit(contentType ContentType) int64 {
+	if ctrl == nil {
+		return defaultCtrl.DecoderLimit(contentType)
+	}
+	ctrl.decoderLck.RLock()
+	defer ctrl.decoderLck.RUnlock()
+	return ctrl.decoders[contentType].limit
+}
+
+// SupportedDecoders returns the descriptors of the content types with decoders,
+// sorted by content type.
+func (ctrl *Controller) SupportedDecoders() ContentTypeDescriptors {
 	if ctrl == nil {
 		return defaultCtrl.SupportedDecoders()
 	}
 
 	ctrl.decoderLck.RLock()
-	stringValues := make([]string, 0, len(ctrl.decoders))
-	for value := range ctrl.decoders {
-		stringValues = append(stringValues, string(value))
+	funcs := make(map[ContentType]interface{}, len(ctrl.decoders))
+	for contentType, entry := range ctrl.decoders {
+		if entry.fn == nil {
+			continue
+		}
+		funcs[contentType] = entry.fn
 	}
 	ctrl.decoderLck.RUnlock()
-	sort.Strings(stringValues)
-	return NewContentTypeSet(stringValues...)
+	return describeContentTypes(funcs)
 }
@@ -0,0 +1,484 @@
+package render
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gdey/chi-render/decoders"
+	"github.com/gdey/chi-render/responders"
+)
+
+func TestRespondCanEncode(t *testing.T) {
+	type tcase struct {
+		Accept      string
+		V           interface{}
+		ContentType string
+		Called      bool
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			var called bool
+			ctrl := CloneDefault()
+			_ = ctrl.RegisterResponder(ContentTypePlainText, responders.Registration{
+				Func: func(w http.ResponseWriter, r *http.Request, v interface{}) error {
+					called = true
+					return responders.PlainText(w, r, v)
+				},
+				CanEncode: func(v interface{}) bool {
+					_, ok := v.(string)
+					return ok
+				},
+			})
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("Accept", tc.Accept)
+			w := httptest.NewRecorder()
+			ctrl.respond(w, r, tc.V)
+
+			if called != tc.Called {
+				t.Errorf("called, expected %v, got %v", tc.Called, called)
+			}
+			if got := w.Header().Get("Content-Type"); got != tc.ContentType {
+				t.Errorf("content type, expected %v, got %v", tc.ContentType, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"can encode": {
+			Accept:      "text/plain, application/json",
+			V:           "hello",
+			ContentType: "text/plain; charset=utf-8",
+			Called:      true,
+		},
+		"can not encode": {
+			Accept:      "text/plain, application/json",
+			V:           map[string]int{"answer": 42},
+			ContentType: "application/json; charset=utf-8",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestRespondSafely(t *testing.T) {
+	var called bool
+	ctrl := CloneDefault()
+	_ = ctrl.SetResponder(ContentTypePlainText, func(w http.ResponseWriter, r *http.Request, v interface{}) error {
+		called = true
+		w.Header().Set("X-Half-Written", "true")
+		w.WriteHeader(http.StatusTeapot)
+		_, _ = w.Write([]byte("half written"))
+		return responders.ErrCanNotEncodeObject
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept", "text/plain, application/json")
+	w := httptest.NewRecorder()
+	ctrl.respond(w, r, map[string]int{"answer": 42})
+
+	if !called {
+		t.Fatalf("called, expected true, got false")
+	}
+	if got := w.Header().Get("X-Half-Written"); got != "" {
+		t.Errorf("X-Half-Written header, expected empty, got %v", got)
+	}
+	if w.Code != http.StatusOK {
+		t.Errorf("status code, expected %v, got %v", http.StatusOK, w.Code)
+	}
+	if got := w.Header().Get("Content-Type"); got != "application/json; charset=utf-8" {
+		t.Errorf("content type, expected application/json, got %v", got)
+	}
+	if got := w.Body.String(); got != "{\"answer\":42}\n" {
+		t.Errorf("body, expected {\"answer\":42}, got %v", got)
+	}
+}
+
+func TestErrorResponder(t *testing.T) {
+	ctrl := CloneDefault()
+	_ = ctrl.SetErrorResponder(ContentTypeJSON, responders.ProblemJSON)
+
+	type tcase struct {
+		V           Renderer
+		ContentType string
+		Status      int
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("Accept", "application/json")
+			w := httptest.NewRecorder()
+			if err := ctrl.Render(w, r, tc.V); err != nil {
+				t.Fatalf("error, expected nil, got %v", err)
+			}
+			if got := w.Header().Get("Content-Type"); got != tc.ContentType {
+				t.Errorf("content type, expected %v, got %v", tc.ContentType, got)
+			}
+			if w.Code != tc.Status {
+				t.Errorf("status code, expected %v, got %v", tc.Status, w.Code)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"error": {
+			V:           &ErrResponse{StatusCode: http.StatusNotFound},
+			ContentType: "application/problem+json; charset=utf-8",
+			Status:      http.StatusNotFound,
+		},
+		"payload": {
+			V: &struct {
+				NilRender
+				Name string
+			}{Name: "world"},
+			ContentType: "application/json; charset=utf-8",
+			Status:      http.StatusOK,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestRespondAcceptLists(t *testing.T) {
+	type tcase struct {
+		Allowed *ContentTypeSet
+		Denied  *ContentTypeSet
+		// Clone responds with a clone of the controller
+		Clone       bool
+		Accept      string
+		Status      int
+		ContentType string
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			ctrl := CloneDefault()
+			ctrl.AllowedAccept = tc.Allowed
+			ctrl.DeniedAccept = tc.Denied
+			if tc.Clone {
+				ctrl = ctrl.Clone()
+			}
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("Accept", tc.Accept)
+			w := httptest.NewRecorder()
+			ctrl.respond(w, r, map[string]int{"answer": 42})
+
+			if w.Code != tc.Status {
+				t.Errorf("status code, expected %v, got %v", tc.Status, w.Code)
+			}
+			if got := w.Header().Get("Content-Type"); got != tc.ContentType {
+				t.Errorf("content type, expected %v, got %v", tc.ContentType, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"denied only match": {
+			Denied:      SetOfContentTypes(ContentTypeXML),
+			Accept:      "text/xml",
+			Status:      http.StatusNotAcceptable,
+			ContentType: "text/plain; charset=utf-8",
+		},
+		"denied with wildcard": {
+			Denied:      SetOfContentTypes(ContentTypeXML),
+			Accept:      "text/xml, */*",
+			Status:      http.StatusOK,
+			ContentType: "application/json; charset=utf-8",
+		},
+		"not allowed": {
+			Allowed:     SetOfContentTypes(ContentTypeJSON),
+			Accept:      "text/xml",
+			Status:      http.StatusNotAcceptable,
+			ContentType: "text/plain; charset=utf-8",
+		},
+		"allowed": {
+			Allowed:     SetOfContentTypes(ContentTypeJSON),
+			Accept:      "text/xml, application/json",
+			Status:      http.StatusOK,
+			ContentType: "application/json; charset=utf-8",
+		},
+		"cloned allowed": {
+			Allowed:     SetOfContentTypes(ContentTypeJSON),
+			Clone:       true,
+			Accept:      "text/xml",
+			Status:      http.StatusNotAcceptable,
+			ContentType: "text/plain; charset=utf-8",
+		},
+		"cloned none allowed": {
+			Allowed:     &ContentTypeSet{},
+			Clone:       true,
+			Accept:      "application/json",
+			Status:      http.StatusNotAcceptable,
+			ContentType: "text/plain; charset=utf-8",
+		},
+		"unregistered is not refused": {
+			Denied:      SetOfContentTypes(ContentTypeXML),
+			Accept:      "image/png",
+			Status:      http.StatusOK,
+			ContentType: "application/json; charset=utf-8",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestRespondBinary(t *testing.T) {
+	type tcase struct {
+		V           interface{}
+		ContentType string
+		Body        string
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("Accept", "application/octet-stream")
+			w := httptest.NewRecorder()
+			defaultCtrl.respond(w, r, tc.V)
+
+			if got := w.Header().Get("Content-Type"); got != tc.ContentType {
+				t.Errorf("content type, expected %v, got %v", tc.ContentType, got)
+			}
+			if got := w.Body.String(); got != tc.Body {
+				t.Errorf("body, expected %q, got %q", tc.Body, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"bytes": {
+			V:           []byte("hello"),
+			ContentType: "application/octet-stream",
+			Body:        "hello",
+		},
+		"not binary": {
+			V:           map[string]int{"answer": 42},
+			ContentType: "application/json; charset=utf-8",
+			Body:        "{\"answer\":42}\n",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestRespondUnsupportedKind(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept", "text/xml, application/json;q=0.5")
+	w := httptest.NewRecorder()
+	defaultCtrl.respond(w, r, map[string]int{"answer": 42})
+
+	if got, want := w.Header().Get("Content-Type"), "application/json; charset=utf-8"; got != want {
+		t.Errorf("content type, expected %v, got %v", want, got)
+	}
+	if got, want := w.Body.String(), "{\"answer\":42}\n"; got != want {
+		t.Errorf("body, expected %q, got %q", want, got)
+	}
+}
+
+func TestAcceptOverrideHeader(t *testing.T) {
+	type tcase struct {
+		Header      string
+		Accept      string
+		Override    string
+		ContentType string
+		Vary        string
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			ctrl := defaultCtrl.Clone()
+			ctrl.AcceptOverrideHeader = tc.Header
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("Accept", tc.Accept)
+			if tc.Override != "" {
+				r.Header.Set(DefaultAcceptOverrideHeader, tc.Override)
+			}
+			w := httptest.NewRecorder()
+			ctrl.respond(w, r, map[string]int{"answer": 42})
+			if got := w.Header().Get("Content-Type"); got != tc.ContentType {
+				t.Errorf("content type, expected %v, got %v", tc.ContentType, got)
+			}
+			if got := w.Header().Get("Vary"); got != tc.Vary {
+				t.Errorf("vary, expected %q, got %q", tc.Vary, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"not configured": {
+			Accept:      "application/json",
+			Override:    "application/octet-stream",
+			ContentType: "application/json; charset=utf-8",
+		},
+		"override": {
+			Header:      DefaultAcceptOverrideHeader,
+			Accept:      "application/octet-stream",
+			Override:    "application/json",
+			ContentType: "application/json; charset=utf-8",
+			Vary:        DefaultAcceptOverrideHeader,
+		},
+		"no override sent": {
+			Header:      DefaultAcceptOverrideHeader,
+			Accept:      "application/json",
+			ContentType: "application/json; charset=utf-8",
+			Vary:        DefaultAcceptOverrideHeader,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestRenderToBuffer(t *testing.T) {
+	type payload struct {
+		NilRender
+		XMLName xml.Name `json:"-" xml:"payload"`
+		Name    string   `json:"name" xml:"name"`
+	}
+	type tcase struct {
+		Accept      string
+		Status      int
+		ContentType string
+		Body        string
+		Err         error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			ctrl := defaultCtrl.Clone()
+			ctrl.DeniedAccept = SetOfContentTypes(ContentTypeHTML)
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("Accept", tc.Accept)
+			if tc.Status != 0 {
+				Status(r, tc.Status)
+			}
+			contentType, body, status, err := ctrl.RenderToBuffer(r, &payload{Name: "world"})
+			if err != tc.Err {
+				t.Fatalf("error, expected %v, got %v", tc.Err, err)
+			}
+			if tc.Err != nil {
+				return
+			}
+			if tc.Status == 0 {
+				tc.Status = http.StatusOK
+			}
+			if status != tc.Status {
+				t.Errorf("status, expected %v, got %v", tc.Status, status)
+			}
+			if contentType != tc.ContentType {
+				t.Errorf("content type, expected %v, got %v", tc.ContentType, contentType)
+			}
+			if string(body) != tc.Body {
+				t.Errorf("body, expected %q, got %q", tc.Body, string(body))
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"json": {
+			Accept:      "application/json",
+			Status:      http.StatusCreated,
+			ContentType: "application/json; charset=utf-8",
+			Body:        "{\"name\":\"world\"}\n",
+		},
+		"xml": {
+			Accept:      "text/xml",
+			ContentType: "application/xml; charset=utf-8",
+			Body:        xml.Header + "<payload><name>world</name></payload>",
+		},
+		"not acceptable": {
+			Accept: "text/html",
+			Err:    ErrNotAcceptable,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestBindMultipart(t *testing.T) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	_ = mw.WriteField("title", "  Hello ")
+	fw, _ := mw.CreateFormFile("file", "hello.txt")
+	_, _ = fw.Write([]byte("hello"))
+	_ = mw.Close()
+
+	r := httptest.NewRequest(http.MethodPost, "/", &body)
+	r.Header.Set("Content-Type", mw.FormDataContentType())
+
+	var got struct {
+		Title string                `form:"title" normalize:"trim"`
+		File  *multipart.FileHeader `form:"file"`
+		NilBinder
+	}
+	if err := defaultCtrl.Bind(r, &got); err != nil {
+		t.Fatalf("error, expected nil, got %v", err)
+	}
+	if got.Title != "Hello" {
+		t.Errorf("title, expected Hello, got %q", got.Title)
+	}
+	if got.File == nil || got.File.Filename != "hello.txt" || got.File.Size != 5 {
+		t.Errorf("file, expected hello.txt of 5 bytes, got %+v", got.File)
+	}
+}
+
+// TestBindMultipartCleanup checks that the temporary files of the form are
+// removed once the context of the request is done
+func TestBindMultipartCleanup(t *testing.T) {
+	defer func(max int64) { decoders.MultipartMaxMemory = max }(decoders.MultipartMaxMemory)
+	// keep the file on disk
+	decoders.MultipartMaxMemory = 0
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	fw, _ := mw.CreateFormFile("file", "hello.txt")
+	_, _ = fw.Write([]byte("hello"))
+	_ = mw.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r := httptest.NewRequest(http.MethodPost, "/", &body).WithContext(ctx)
+	r.Header.Set("Content-Type", mw.FormDataContentType())
+
+	var got struct {
+		File *multipart.FileHeader `form:"file"`
+		NilBinder
+	}
+	if err := defaultCtrl.Bind(r, &got); err != nil {
+		t.Fatalf("error, expected nil, got %v", err)
+	}
+	f, err := got.File.Open()
+	if err != nil {
+		t.Fatalf("open, expected nil before the request is done, got %v", err)
+	}
+	f.Close()
+
+	cancel()
+	deadline := time.Now().Add(time.Second)
+	for {
+		f, err := got.File.Open()
+		if err != nil {
+			break
+		}
+		f.Close()
+		if time.Now().After(deadline) {
+			t.Fatalf("open, expected the temporary file to be removed once the request is done")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,212 @@
+package render
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/gdey/chi-render/decoders"
+)
+
+// ErrConversion is the error that ConversionError values match using errors.Is.
+var ErrConversion = errors.New("render: unable to convert value")
+
+// TypeConverter converts a string, from a query parameter, form field or header,
+// into a value of the type it was registered for; the returned value must be
+// convertible to that type.
+type TypeConverter func(s string) (interface{}, error)
+
+// ConversionError is returned when a string can not be converted into the
+// field it is being bound to.
+type ConversionError struct {
+	// Field is the name of the field that failed
+	Field string
+	// Value is the string that could not be converted
+	Value string
+	// Type is the type of the field
+	Type reflect.Type
+	// Err is the reason the conversion failed
+	Err error
+}
+
+func (err *ConversionError) Error() string {
+	return fmt.Sprintf("render: unable to convert %q for field '%s' to %v: %v", err.Value, err.Field, err.Type, err.Err)
+}
+
+// Unwrap returns the reason the conversion failed
+func (err *ConversionError) Unwrap() error { return err.Err }
+
+// Is reports whether target is ErrConversion
+func (err *ConversionError) Is(target error) bool { return target == ErrConversion }
+
+// StatusCode is the http status code that should be reported to the client
+func (err *ConversionError) StatusCode() int { return http.StatusBadRequest }
+
+// TimeConverter returns a TypeConverter for time.Time that accepts any of the
+// given layouts, tried in order.
+func TimeConverter(layouts ...string) TypeConverter {
+	return func(s string) (interface{}, error) {
+		var err error
+		for _, layout := range layouts {
+			var t time.Time
+			if t, err = time.Parse(layout, s); err == nil {
+				return t, nil
+			}
+		}
+		if err == nil {
+			err = errors.New("no time layouts")
+		}
+		return nil, err
+	}
+}
+
+// RegisterTypeConverter will set the converter used when binding strings, from
+// query parameters, form fields or headers, to values of the given type. Use a
+// nil converter to unset a type.
+// Only error this function will return is ErrControllerIsNil; is returned
+// if the Controller object is nil.
+func (ctrl *Controller) RegisterTypeConverter(typ reflect.Type, converter TypeConverter) error {
+	if ctrl == nil {
+		return ErrControllerIsNil
+	}
+	ctrl.typeConverterLck.Lock()
+	// the map is replaced, not modified, so bindings in flight can keep
+	// using the one they started with
+	converters := make(map[reflect.Type]TypeConverter, len(ctrl.typeConverters)+1)
+	for t, conv := range ctrl.typeConverters {
+		converters[t] = conv
+	}
+	if converter == nil {
+		delete(converters, typ)
+	} else {
+		converters[typ] = converter
+	}
+	ctrl.typeConverters = converters
+	ctrl.typeConverterLck.Unlock()
+	return nil
+}
+
+// converters returns the registered type converters; the map must not be modified
+func (ctrl *Controller) converters() map[reflect.Type]TypeConverter {
+	ctrl.typeConverterLck.RLock()
+	defer ctrl.typeConverterLck.RUnlock()
+	return ctrl.typeConverters
+}
+
+// formConverter returns the type converters as a decoders.ConvertFunc, for the
+// form decoders; types without a converter are left to the decoder.
+func formConverter(converters map[reflect.Type]TypeConverter) decoders.ConvertFunc {
+	return func(s string, dst reflect.Value) (bool, error) {
+		if _, ok := converters[dst.Type()]; !ok {
+			return false, nil
+		}
+		return true, convertString(converters, s, dst)
+	}
+}
+
+// Convert converts the string into v, which must be a pointer, using the
+// registered type converters, or for types without one, the built in
+// conversions for strings, bools, numbers, durations and
+// encoding.TextUnmarshalers. The field is used to name the value in the
+// *ConversionError returned on failure.
+func (ctrl *Controller) Convert(field, s string, v interface{}) error {
+	if ctrl == nil {
+		return defaultCtrl.Convert(field, s, v)
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("render: convert requires a non-nil pointer, got %T", v)
+	}
+	return ctrl.convert(field, s, rv.Elem())
+}
+
+// convert converts the string into the settable value dst
+func (ctrl *Controller) convert(field, s string, dst reflect.Value) error {
+	err := convertString(ctrl.converters(), s, dst)
+	if err == nil {
+		return nil
+	}
+	return &ConversionError{
+		Field: field,
+		Value: s,
+		Type:  dst.Type(),
+		Err:   err,
+	}
+}
+
+func convertString(converters map[reflect.Type]TypeConverter, s string, dst reflect.Value) error {
+	typ := dst.Type()
+	if converter, ok := converters[typ]; ok {
+		v, err := converter(s)
+		if err != nil {
+			return err
+		}
+		rv := reflect.ValueOf(v)
+		if !rv.IsValid() {
+			dst.Set(reflect.Zero(typ))
+			return nil
+		}
+		if !rv.Type().ConvertibleTo(typ) {
+			return fmt.Errorf("converter returned %T", v)
+		}
+		dst.Set(rv.Convert(typ))
+		return nil
+	}
+	if typ.Kind() == reflect.Ptr {
+		elem := reflect.New(typ.Elem())
+		if err := convertString(converters, s, elem.Elem()); err != nil {
+			return err
+		}
+		dst.Set(elem)
+		return nil
+	}
+	if dst.CanAddr() {
+		if unmarshaler, ok := dst.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			return unmarshaler.UnmarshalText([]byte(s))
+		}
+	}
+	if typ == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		dst.SetInt(int64(d))
+		return nil
+	}
+
+	switch typ.Kind() {
+	case reflect.String:
+		dst.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		dst.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, typ.Bits())
+		if err != nil {
+			return err
+		}
+		dst.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, err := strconv.ParseUint(s, 10, typ.Bits())
+		if err != nil {
+			return err
+		}
+		dst.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, typ.Bits())
+		if err != nil {
+			return err
+		}
+		dst.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported type %v", typ)
+	}
+	return nil
+}
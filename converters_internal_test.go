@@ -0,0 +1,177 @@
+package render
+
+import (
+	"bytes"
+	"errors"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testColor int
+
+func TestConvert(t *testing.T) {
+	type tcase struct {
+		Converters map[reflect.Type]TypeConverter
+		Value      string
+		// Into is a pointer to the type to convert into
+		Into     interface{}
+		Expected interface{}
+		Err      string
+	}
+
+	color := func(s string) (interface{}, error) {
+		switch s {
+		case "red":
+			return 1, nil
+		case "blue":
+			return 2, nil
+		}
+		return nil, errors.New("unknown color")
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			ctrl := defaultCtrl.Clone()
+			for typ, conv := range tc.Converters {
+				_ = ctrl.RegisterTypeConverter(typ, conv)
+			}
+			err := ctrl.Convert("field", tc.Value, tc.Into)
+			if tc.Err != "" {
+				var convErr *ConversionError
+				if !errors.As(err, &convErr) || !errors.Is(err, ErrConversion) {
+					t.Errorf("error, expected *ConversionError, got %v", err)
+					return
+				}
+				if convErr.Field != "field" {
+					t.Errorf("field, expected field, got %v", convErr.Field)
+				}
+				if !strings.Contains(err.Error(), tc.Err) {
+					t.Errorf("error, expected to contain %q, got %v", tc.Err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("error, expected nil, got %v", err)
+				return
+			}
+			if got := reflect.ValueOf(tc.Into).Elem().Interface(); !reflect.DeepEqual(got, tc.Expected) {
+				t.Errorf("value, expected %v, got %v", tc.Expected, got)
+			}
+		}
+	}
+
+	three := 3
+	tests := map[string]tcase{
+		"string":    {Value: "hi", Into: new(string), Expected: "hi"},
+		"int":       {Value: "-42", Into: new(int), Expected: -42},
+		"uint8":     {Value: "255", Into: new(uint8), Expected: uint8(255)},
+		"float":     {Value: "1.5", Into: new(float64), Expected: 1.5},
+		"bool":      {Value: "true", Into: new(bool), Expected: true},
+		"duration":  {Value: "1m30s", Into: new(time.Duration), Expected: 90 * time.Second},
+		"pointer":   {Value: "3", Into: new(*int), Expected: &three},
+		"text":      {Value: "127.0.0.1", Into: new(net.IP), Expected: net.ParseIP("127.0.0.1")},
+		"bad int":   {Value: "abc", Into: new(int), Err: `"abc" for field 'field' to int`},
+		"overflow":  {Value: "256", Into: new(uint8), Err: "out of range"},
+		"bad bool":  {Value: "yes please", Into: new(bool), Err: "invalid syntax"},
+		"bad ip":    {Value: "nope", Into: new(net.IP), Err: "invalid IP"},
+		"struct":    {Value: "x", Into: new(struct{}), Err: "unsupported type"},
+		"converter": {Converters: map[reflect.Type]TypeConverter{reflect.TypeOf(testColor(0)): color}, Value: "blue", Into: new(testColor), Expected: testColor(2)},
+		"converter error": {
+			Converters: map[reflect.Type]TypeConverter{reflect.TypeOf(testColor(0)): color},
+			Value:      "green",
+			Into:       new(testColor),
+			Err:        "unknown color",
+		},
+		"time layouts": {
+			Converters: map[reflect.Type]TypeConverter{timeType: TimeConverter("2006-01-02", time.RFC3339)},
+			Value:      "2020-05-06",
+			Into:       new(time.Time),
+			Expected:   time.Date(2020, 5, 6, 0, 0, 0, 0, time.UTC),
+		},
+		"time bad layout": {
+			Converters: map[reflect.Type]TypeConverter{timeType: TimeConverter("2006-01-02")},
+			Value:      "May 6",
+			Into:       new(time.Time),
+			Err:        "cannot parse",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+// TestConvertForm checks that the form decoders use the registered type converters
+func TestConvertForm(t *testing.T) {
+	type payload struct {
+		Color  testColor   `form:"color"`
+		Colors []testColor `form:"colors"`
+		Day    *time.Time  `form:"day"`
+	}
+	type tcase struct {
+		Fields   [][2]string
+		Expected payload
+		Err      string
+	}
+
+	ctrl := defaultCtrl.Clone()
+	_ = ctrl.RegisterTypeConverter(reflect.TypeOf(testColor(0)), func(s string) (interface{}, error) {
+		switch s {
+		case "red":
+			return 1, nil
+		case "blue":
+			return 2, nil
+		}
+		return nil, errors.New("unknown color")
+	})
+	_ = ctrl.RegisterTypeConverter(timeType, TimeConverter("2006-01-02"))
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			var body bytes.Buffer
+			mw := multipart.NewWriter(&body)
+			for _, field := range tc.Fields {
+				_ = mw.WriteField(field[0], field[1])
+			}
+			_ = mw.Close()
+			r := httptest.NewRequest(http.MethodPost, "/", &body)
+			r.Header.Set("Content-Type", mw.FormDataContentType())
+
+			var got payload
+			err := ctrl.decode(r, &got)
+			if tc.Err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.Err) {
+					t.Errorf("error, expected to contain %q, got %v", tc.Err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("error, expected nil, got %v", err)
+				return
+			}
+			if !reflect.DeepEqual(got, tc.Expected) {
+				t.Errorf("payload, expected %+v, got %+v", tc.Expected, got)
+			}
+		}
+	}
+
+	day := time.Date(2020, 5, 6, 0, 0, 0, 0, time.UTC)
+	tests := map[string]tcase{
+		"converted": {
+			Fields:   [][2]string{{"color", "blue"}, {"colors", "red"}, {"colors", "blue"}, {"day", "2020-05-06"}},
+			Expected: payload{Color: 2, Colors: []testColor{1, 2}, Day: &day},
+		},
+		"converter error": {
+			Fields: [][2]string{{"color", "green"}},
+			Err:    "unknown color",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
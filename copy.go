@@ -0,0 +1,101 @@
+package render
+
+import (
+	"reflect"
+)
+
+// Cloner can be implemented by payloads to provide their own copy when the
+// controller's CopyOnRender is set, instead of the reflection based deep copy.
+type Cloner interface {
+	Clone() Renderer
+}
+
+// copyForRender returns a copy of the payload that the Render tree can
+// safely mutate.
+func copyForRender(v Renderer) Renderer {
+	if v == nil {
+		return nil
+	}
+	if c, ok := v.(Cloner); ok {
+		return c.Clone()
+	}
+	c, _ := deepCopy(reflect.ValueOf(v), make(map[visited]reflect.Value)).Interface().(Renderer)
+	return c
+}
+
+// visited identifies a pointer that has already been copied, so shared
+// pointers stay shared and cycles terminate.
+type visited struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+// deepCopy copies pointers, slices, maps, arrays, interfaces and the exported
+// fields of structs. Unexported fields are copied as is, as are channels and
+// functions.
+func deepCopy(v reflect.Value, seen map[visited]reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		key := visited{ptr: v.Pointer(), typ: v.Type()}
+		if c, ok := seen[key]; ok {
+			return c
+		}
+		c := reflect.New(v.Type().Elem())
+		seen[key] = c
+		c.Elem().Set(deepCopy(v.Elem(), seen))
+		return c
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.New(v.Type()).Elem()
+		c.Set(deepCopy(v.Elem(), seen))
+		return c
+
+	case reflect.Struct:
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if !c.Field(i).CanSet() {
+				continue
+			}
+			c.Field(i).Set(deepCopy(v.Field(i), seen))
+		}
+		return c
+
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(deepCopy(v.Index(i), seen))
+		}
+		return c
+
+	case reflect.Array:
+		c := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(deepCopy(v.Index(i), seen))
+		}
+		return c
+
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			c.SetMapIndex(deepCopy(iter.Key(), seen), deepCopy(iter.Value(), seen))
+		}
+		return c
+
+	default:
+		return v
+	}
+}
@@ -0,0 +1,82 @@
+package render
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type CopyArticle struct {
+	Title string         `json:"title"`
+	Tags  []string       `json:"tags"`
+	Next  *CopyArticle   `json:"-"`
+	Meta  map[string]int `json:"meta"`
+}
+
+type copyResponse struct {
+	*CopyArticle
+}
+
+func (resp *copyResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	resp.Title = strings.ToUpper(resp.Title)
+	resp.Tags[0] = "rendered"
+	resp.Meta["renders"]++
+	return nil
+}
+
+type clonedResponse struct {
+	copyResponse
+	cloned bool
+}
+
+func (resp *clonedResponse) Clone() Renderer {
+	article := *resp.CopyArticle
+	article.Tags = append([]string(nil), article.Tags...)
+	article.Meta = map[string]int{}
+	return &clonedResponse{copyResponse: copyResponse{&article}, cloned: true}
+}
+
+func TestCopyOnRender(t *testing.T) {
+	type tcase struct {
+		V    func(shared *CopyArticle) Renderer
+		Body string
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			shared := &CopyArticle{Title: "hi", Tags: []string{"go"}, Meta: map[string]int{}}
+			shared.Next = shared
+			ctrl := defaultCtrl.Clone()
+			ctrl.CopyOnRender = true
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			w := httptest.NewRecorder()
+			if err := ctrl.Render(w, r, tc.V(shared)); err != nil {
+				t.Fatalf("error, expected nil, got %v", err)
+			}
+			if got := w.Body.String(); got != tc.Body {
+				t.Errorf("body, expected %q, got %q", tc.Body, got)
+			}
+			if shared.Title != "hi" || shared.Tags[0] != "go" || len(shared.Meta) != 0 {
+				t.Errorf("shared fixture, expected to be unchanged, got %+v", shared)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"deep copy": {
+			V:    func(shared *CopyArticle) Renderer { return &copyResponse{shared} },
+			Body: "{\"title\":\"HI\",\"tags\":[\"rendered\"],\"meta\":{\"renders\":1}}\n",
+		},
+		"cloner": {
+			V: func(shared *CopyArticle) Renderer {
+				return &clonedResponse{copyResponse: copyResponse{shared}}
+			},
+			Body: "{\"title\":\"HI\",\"tags\":[\"rendered\"],\"meta\":{\"renders\":1}}\n",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
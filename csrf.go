@@ -0,0 +1,175 @@
+package render
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gdey/chi-render/responders/helpers"
+)
+
+// CSRFCtxKey is a context key for the CSRF token of the forms in the response
+var CSRFCtxKey = helpers.CSRFCtxKey
+
+// DefaultCSRFField is the form field the CSRF token is sent back in, if the
+// provider does not name one
+const DefaultCSRFField = "csrf_token"
+
+// DefaultCSRFHeader is the header the CSRF token may be sent in instead of the
+// form field, for example by scripts posting forms
+const DefaultCSRFHeader = "X-CSRF-Token"
+
+// ErrInvalidCSRFToken is the error that CSRFError values match using errors.Is.
+var ErrInvalidCSRFToken = errors.New("render: invalid csrf token")
+
+// CSRFError is returned by Bind when a form request does not carry a valid
+// CSRF token.
+type CSRFError struct {
+	// Cause is the error returned by the CSRFVerifier
+	Cause error
+}
+
+func (err *CSRFError) Error() string {
+	return fmt.Sprintf("render: invalid csrf token: %v", err.Cause)
+}
+
+// Is reports whether target is ErrInvalidCSRFToken
+func (err *CSRFError) Is(target error) bool { return target == ErrInvalidCSRFToken }
+
+// Unwrap returns the error returned by the CSRFVerifier
+func (err *CSRFError) Unwrap() error { return err.Cause }
+
+// StatusCode is the http status code that should be reported to the client
+func (err *CSRFError) StatusCode() int { return http.StatusForbidden }
+
+// CSRFProvider provides the CSRF tokens the HTML responders put in the forms
+// they render, see responders.CSRFFuncs.
+type CSRFProvider interface {
+	// Token returns the token for the forms of the response to the request
+	Token(r *http.Request) string
+	// FieldName is the name of the form field the token is sent back in
+	FieldName() string
+}
+
+// CSRFVerifier is implemented by CSRFProviders that have Bind verify the token
+// sent with form requests, in the form field or the DefaultCSRFHeader header.
+// Providers whose tokens are verified by a middleware, like gorilla/csrf, do
+// not need to implement it.
+type CSRFVerifier interface {
+	VerifyCSRF(r *http.Request, token string) error
+}
+
+// CSRFTokenFunc is a CSRFProvider for tokens that are issued, and verified, by
+// a middleware, like gorilla/csrf:
+//
+//	ctrl.CSRF = render.CSRFTokenFunc{Func: csrf.Token, Field: "gorilla.csrf.Token"}
+type CSRFTokenFunc struct {
+	// Func returns the token for the request
+	Func func(r *http.Request) string
+	// Field is the name of the form field; DefaultCSRFField if empty
+	Field string
+}
+
+// Token returns the token for the request
+func (fn CSRFTokenFunc) Token(r *http.Request) string { return fn.Func(r) }
+
+// FieldName is the name of the form field the token is sent back in
+func (fn CSRFTokenFunc) FieldName() string {
+	if fn.Field == "" {
+		return DefaultCSRFField
+	}
+	return fn.Field
+}
+
+// SessionCSRF is a CSRFProvider, and CSRFVerifier, with stateless tokens that
+// are the HMAC of the session of the request; so a token is only valid for the
+// session it was issued for.
+type SessionCSRF struct {
+	// Key is the secret the tokens are signed with
+	Key []byte
+	// Session returns the id of the session of the request; requests without a
+	// session get no token, and fail verification.
+	Session func(r *http.Request) string
+	// Field is the name of the form field; DefaultCSRFField if empty
+	Field string
+}
+
+func (s SessionCSRF) sum(session string) []byte {
+	mac := hmac.New(sha256.New, s.Key)
+	_, _ = mac.Write([]byte(session))
+	return mac.Sum(nil)
+}
+
+// Token returns the token for the session of the request
+func (s SessionCSRF) Token(r *http.Request) string {
+	session := s.Session(r)
+	if session == "" {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(s.sum(session))
+}
+
+// FieldName is the name of the form field the token is sent back in
+func (s SessionCSRF) FieldName() string {
+	if s.Field == "" {
+		return DefaultCSRFField
+	}
+	return s.Field
+}
+
+// VerifyCSRF checks the token was issued for the session of the request
+func (s SessionCSRF) VerifyCSRF(r *http.Request, token string) error {
+	session := s.Session(r)
+	if session == "" {
+		return errors.New("no session")
+	}
+	sent, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || !hmac.Equal(sent, s.sum(session)) {
+		return errors.New("token does not match the session")
+	}
+	return nil
+}
+
+// withCSRF puts the CSRF token for the request in its context, for the HTML
+// responders to put in the forms they render
+func (ctrl *Controller) withCSRF(r *http.Request) {
+	if ctrl.CSRF == nil {
+		return
+	}
+	if csrf := helpers.CSRFToken(r); csrf.Token != "" {
+		return
+	}
+	csrf := helpers.CSRF{Field: ctrl.CSRF.FieldName(), Token: ctrl.CSRF.Token(r)}
+	*r = *r.WithContext(context.WithValue(r.Context(), CSRFCtxKey, csrf))
+}
+
+// csrfVerifier returns the verifier of the form request; nil if the request
+// does not need to be verified
+func (ctrl *Controller) csrfVerifier(ct ContentType) CSRFVerifier {
+	verifier, ok := ctrl.CSRF.(CSRFVerifier)
+	if !ok || (ct != ContentTypeForm && ct != ContentTypeURLEncoded) {
+		return nil
+	}
+	return verifier
+}
+
+// verifyCSRF checks the token sent with the form request, in the
+// DefaultCSRFHeader header or, once the body has been decoded, the form field
+// of the provider
+func (ctrl *Controller) verifyCSRF(r *http.Request, verifier CSRFVerifier) error {
+	token := r.Header.Get(DefaultCSRFHeader)
+	if token == "" {
+		token = formValues(r).Get(ctrl.CSRF.FieldName())
+	}
+	if token == "" {
+		return &CSRFError{Cause: errors.New("no token was sent")}
+	}
+	if err := verifier.VerifyCSRF(r, token); err != nil {
+		return &CSRFError{Cause: err}
+	}
+	return nil
+}
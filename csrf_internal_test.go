@@ -0,0 +1,134 @@
+package render
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gdey/chi-render/responders/helpers"
+)
+
+func TestCSRFBind(t *testing.T) {
+	type tcase struct {
+		Session string
+		Field   string
+		Header  string
+		Err     error
+	}
+
+	provider := SessionCSRF{
+		Key:     []byte("secret"),
+		Session: func(r *http.Request) string { return r.Header.Get("X-Session") },
+	}
+	ctrl := defaultCtrl.Clone()
+	ctrl.CSRF = provider
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			var body bytes.Buffer
+			mw := multipart.NewWriter(&body)
+			_ = mw.WriteField("title", "hello")
+			if tc.Field != "" {
+				_ = mw.WriteField(DefaultCSRFField, tc.Field)
+			}
+			_ = mw.Close()
+			r := httptest.NewRequest(http.MethodPost, "/", &body)
+			r.Header.Set("Content-Type", mw.FormDataContentType())
+			r.Header.Set("X-Session", tc.Session)
+			if tc.Header != "" {
+				r.Header.Set(DefaultCSRFHeader, tc.Header)
+			}
+
+			var article formArticle
+			err := ctrl.Bind(r, &article)
+			if tc.Err == nil {
+				if err != nil {
+					t.Fatalf("error, expected nil, got %v", err)
+				}
+				if article.Title != "hello" {
+					t.Errorf("title, expected %q, got %q", "hello", article.Title)
+				}
+				return
+			}
+			if !errors.Is(err, tc.Err) {
+				t.Fatalf("error, expected %v, got %v", tc.Err, err)
+			}
+			if tc.Header != "" && article.Title != "" {
+				t.Errorf("title, expected the body not to be decoded, got %q", article.Title)
+			}
+			var csrfErr *CSRFError
+			if errors.As(err, &csrfErr) && csrfErr.StatusCode() != http.StatusForbidden {
+				t.Errorf("status code, expected %v, got %v", http.StatusForbidden, csrfErr.StatusCode())
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"field": {
+			Session: "s1",
+			Field:   provider.Token(sessionRequest("s1")),
+		},
+		"header": {
+			Session: "s1",
+			Header:  provider.Token(sessionRequest("s1")),
+		},
+		"bad header": {
+			Session: "s1",
+			Header:  provider.Token(sessionRequest("s2")),
+			Field:   provider.Token(sessionRequest("s1")),
+			Err:     ErrInvalidCSRFToken,
+		},
+		"missing": {
+			Session: "s1",
+			Err:     ErrInvalidCSRFToken,
+		},
+		"other session": {
+			Session: "s1",
+			Field:   provider.Token(sessionRequest("s2")),
+			Err:     ErrInvalidCSRFToken,
+		},
+		"no session": {
+			Field: provider.Token(sessionRequest("s1")),
+			Err:   ErrInvalidCSRFToken,
+		},
+	}
+	if token := provider.Token(sessionRequest("")); token != "" {
+		t.Errorf("token without session, expected none, got %q", token)
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func sessionRequest(session string) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("X-Session", session)
+	return r
+}
+
+func TestCSRFRespond(t *testing.T) {
+	ctrl := defaultCtrl.Clone()
+	ctrl.CSRF = CSRFTokenFunc{Func: func(r *http.Request) string { return "token" }}
+	_ = ctrl.SetResponder(ContentTypeHTML, func(w http.ResponseWriter, r *http.Request, v interface{}) error {
+		csrf := helpers.CSRFToken(r)
+		_, err := fmt.Fprintf(w, "%s=%s", csrf.Field, csrf.Token)
+		return err
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept", "text/html")
+	w := httptest.NewRecorder()
+	if err := ctrl.Render(w, r, &formPage{}); err != nil {
+		t.Fatalf("error, expected nil, got %v", err)
+	}
+	if got, expected := w.Body.String(), DefaultCSRFField+"=token"; got != expected {
+		t.Errorf("body, expected %q, got %q", expected, got)
+	}
+	if info := ctrl.Describe(); len(info.Hooks) != 1 || info.Hooks[0] != "CSRF" {
+		t.Errorf("hooks, expected [CSRF], got %v", info.Hooks)
+	}
+}
@@ -0,0 +1,225 @@
+package render
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"sync"
+)
+
+// ContentTypeMetrics are the response counters of a controller for a content type
+type ContentTypeMetrics struct {
+	// Responses is the number of responses written
+	Responses int64 `json:"responses"`
+	// Fallbacks is the number of responses written by the default responder
+	// because none of the accepted content types could be served
+	Fallbacks int64 `json:"fallbacks"`
+	// Errors is the number of responses with a 4xx or 5xx status
+	Errors int64 `json:"errors"`
+	// Bytes is the total number of bytes written
+	Bytes int64 `json:"bytes"`
+
+	// FallbackRate is Fallbacks / Responses
+	FallbackRate float64 `json:"fallback_rate"`
+	// ErrorRate is Errors / Responses
+	ErrorRate float64 `json:"error_rate"`
+	// AverageBytes is Bytes / Responses
+	AverageBytes float64 `json:"average_bytes"`
+}
+
+// ResponseMetrics are the response counters of a controller, by the media type
+// of the response Content-Type header. Responses without a Content-Type are
+// counted under "none".
+type ResponseMetrics map[string]ContentTypeMetrics
+
+// responseMetrics collects the counters of a controller
+type responseMetrics struct {
+	lck    sync.Mutex
+	counts map[string]*ContentTypeMetrics
+}
+
+func (m *responseMetrics) record(contentType string, status int, bytes int64, fallback bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType == "" {
+		mediaType = "none"
+	}
+	m.lck.Lock()
+	defer m.lck.Unlock()
+	if m.counts == nil {
+		m.counts = make(map[string]*ContentTypeMetrics)
+	}
+	counts, ok := m.counts[mediaType]
+	if !ok {
+		counts = new(ContentTypeMetrics)
+		m.counts[mediaType] = counts
+	}
+	counts.Responses++
+	counts.Bytes += bytes
+	if fallback {
+		counts.Fallbacks++
+	}
+	if status >= http.StatusBadRequest {
+		counts.Errors++
+	}
+}
+
+// DecodeContentTypeMetrics are the request body counters of a controller for a
+// content type
+type DecodeContentTypeMetrics struct {
+	// Requests is the number of request bodies Bind was asked to decode
+	Requests int64 `json:"requests"`
+	// Bytes is the total number of bytes read from the request bodies
+	Bytes int64 `json:"bytes"`
+	// Unsupported is the number of requests without a decoder for the content type
+	Unsupported int64 `json:"unsupported"`
+	// DecodeErrors is the number of request bodies the decoder failed on
+	DecodeErrors int64 `json:"decode_errors"`
+	// ValidationErrors is the number of decoded request bodies that were
+	// rejected by the field binding, like enums and protected fields, or by
+	// the Bind methods of the payload
+	ValidationErrors int64 `json:"validation_errors"`
+
+	// ErrorRate is (Unsupported + DecodeErrors + ValidationErrors) / Requests
+	ErrorRate float64 `json:"error_rate"`
+	// AverageBytes is Bytes / Requests
+	AverageBytes float64 `json:"average_bytes"`
+}
+
+// DecodeMetrics are the request body counters of a controller, by the media
+// type of the request Content-Type. Requests without a Content-Type are counted
+// under "none", and requests with a content type the controller has no decoder
+// for under "unsupported", so clients can not add counters by making up content
+// types. Vendor types decoded by the decoder of their suffix, like
+// application/vnd.acme+json, are counted under the content type of the suffix.
+type DecodeMetrics map[string]DecodeContentTypeMetrics
+
+// decodeFailure is the class of failure of a request body
+type decodeFailure int
+
+const (
+	failedNone decodeFailure = iota
+	failedUnsupported
+	failedDecode
+	failedValidation
+)
+
+// decodeMetrics collects the request body counters of a controller
+type decodeMetrics struct {
+	lck    sync.Mutex
+	counts map[string]*DecodeContentTypeMetrics
+}
+
+// decodeMetricsKey returns the media type the request bodies of the content
+// type are counted under, see DecodeMetrics
+func (ctrl *Controller) decodeMetricsKey(contentType ContentType) string {
+	if contentType == "" {
+		return "none"
+	}
+	ctrl.decoderLck.RLock()
+	defer ctrl.decoderLck.RUnlock()
+	if ctrl.decoders[contentType].fn != nil {
+		return string(contentType)
+	}
+	if fallback, ok := suffixFallback(contentType); ok && ctrl.decoders[fallback].fn != nil {
+		return string(fallback)
+	}
+	return "unsupported"
+}
+
+// record counts a decoded request body, or the failure to bind one; a
+// validation failure is counted against a request that was already recorded.
+func (m *decodeMetrics) record(mediaType string, bytes int64, failure decodeFailure) {
+	m.lck.Lock()
+	defer m.lck.Unlock()
+	if m.counts == nil {
+		m.counts = make(map[string]*DecodeContentTypeMetrics)
+	}
+	counts, ok := m.counts[mediaType]
+	if !ok {
+		counts = new(DecodeContentTypeMetrics)
+		m.counts[mediaType] = counts
+	}
+	if failure == failedValidation {
+		counts.ValidationErrors++
+		return
+	}
+	counts.Requests++
+	counts.Bytes += bytes
+	switch failure {
+	case failedUnsupported:
+		counts.Unsupported++
+	case failedDecode:
+		counts.DecodeErrors++
+	}
+}
+
+// DecodeMetrics returns the request body counters of the controller since it
+// was created. Like the response counters, they are not copied by Clone.
+func (ctrl *Controller) DecodeMetrics() DecodeMetrics {
+	if ctrl == nil {
+		return defaultCtrl.DecodeMetrics()
+	}
+	ctrl.decodeMetrics.lck.Lock()
+	defer ctrl.decodeMetrics.lck.Unlock()
+	metrics := make(DecodeMetrics, len(ctrl.decodeMetrics.counts))
+	for mediaType, counts := range ctrl.decodeMetrics.counts {
+		m := *counts
+		if m.Requests > 0 {
+			m.ErrorRate = float64(m.Unsupported+m.DecodeErrors+m.ValidationErrors) / float64(m.Requests)
+			m.AverageBytes = float64(m.Bytes) / float64(m.Requests)
+		}
+		metrics[mediaType] = m
+	}
+	return metrics
+}
+
+// ResponseMetrics returns the response counters of the controller since it was
+// created. The counters are not copied by Clone. They can be published with
+// expvar:
+//
+//	expvar.Publish("render", expvar.Func(func() interface{} { return ctrl.ResponseMetrics() }))
+func (ctrl *Controller) ResponseMetrics() ResponseMetrics {
+	if ctrl == nil {
+		return defaultCtrl.ResponseMetrics()
+	}
+	ctrl.metrics.lck.Lock()
+	defer ctrl.metrics.lck.Unlock()
+	metrics := make(ResponseMetrics, len(ctrl.metrics.counts))
+	for mediaType, counts := range ctrl.metrics.counts {
+		m := *counts
+		if m.Responses > 0 {
+			m.FallbackRate = float64(m.Fallbacks) / float64(m.Responses)
+			m.ErrorRate = float64(m.Errors) / float64(m.Responses)
+			m.AverageBytes = float64(m.Bytes) / float64(m.Responses)
+		}
+		metrics[mediaType] = m
+	}
+	return metrics
+}
+
+// DebugHandler returns a handler that writes the configuration, response and
+// request body counters of the controller as JSON; it can be mounted under chi's /debug
+// route for quick inspection in production. A nil controller is the default
+// controller.
+//
+//	r.Mount("/debug/render", render.DebugHandler(ctrl))
+func DebugHandler(ctrl *Controller) http.Handler {
+	if ctrl == nil {
+		ctrl = &defaultCtrl
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// written directly, so the handler is not counted in its own metrics
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(struct {
+			Controller ControllerInfo  `json:"controller"`
+			Responses  ResponseMetrics `json:"responses"`
+			Requests   DecodeMetrics   `json:"requests"`
+		}{
+			Controller: ctrl.Describe(),
+			Responses:  ctrl.ResponseMetrics(),
+			Requests:   ctrl.DecodeMetrics(),
+		})
+	})
+}
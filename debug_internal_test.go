@@ -0,0 +1,114 @@
+package render
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestResponseMetrics(t *testing.T) {
+	ctrl := defaultCtrl.Clone()
+	type payload struct {
+		Name string `json:"name"`
+	}
+
+	respond := func(accept string, status int) {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Accept", accept)
+		if status != 0 {
+			Status(r, status)
+		}
+		ctrl.respond(httptest.NewRecorder(), r, payload{Name: "gopher"})
+	}
+	respond("application/json", 0)
+	respond("application/json", http.StatusBadRequest)
+	respond("text/html", 0)
+	respond("text/xml", 0)
+
+	metrics := ctrl.ResponseMetrics()
+	body := int64(len(`{"name":"gopher"}` + "\n"))
+	expected := ContentTypeMetrics{
+		Responses:    3,
+		Fallbacks:    1,
+		Errors:       1,
+		Bytes:        3 * body,
+		FallbackRate: 1.0 / 3,
+		ErrorRate:    1.0 / 3,
+		AverageBytes: float64(body),
+	}
+	if got := metrics["application/json"]; got != expected {
+		t.Errorf("json metrics, expected %+v, got %+v", expected, got)
+	}
+	if got := metrics["application/xml"]; got.Responses != 1 || got.Fallbacks != 0 || got.Errors != 0 {
+		t.Errorf("xml metrics, expected 1 response, got %+v", metrics)
+	}
+	if clone := ctrl.Clone().ResponseMetrics(); len(clone) != 0 {
+		t.Errorf("clone metrics, expected none, got %+v", clone)
+	}
+
+	w := httptest.NewRecorder()
+	DebugHandler(ctrl).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/render", nil))
+	var debug struct {
+		Controller ControllerInfo  `json:"controller"`
+		Responses  ResponseMetrics `json:"responses"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &debug); err != nil {
+		t.Fatalf("debug body, expected json, got %v: %s", err, w.Body.String())
+	}
+	if debug.Responses["application/json"].Responses != 3 {
+		t.Errorf("debug responses, expected 3, got %+v", debug.Responses)
+	}
+	if len(debug.Controller.Responders) == 0 {
+		t.Errorf("debug controller, expected responders, got %+v", debug.Controller)
+	}
+	if got := ctrl.ResponseMetrics()["application/json"].Responses; got != 3 {
+		t.Errorf("json responses after debug, expected 3, got %v", got)
+	}
+}
+
+func TestDecodeMetrics(t *testing.T) {
+	ctrl := defaultCtrl.Clone()
+	type payload struct {
+		Status testStatus `json:"status"`
+		NilBinder
+	}
+
+	bind := func(contentType, body string) {
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		r.Header.Set("Content-Type", contentType)
+		var p payload
+		_ = ctrl.Bind(r, &p)
+	}
+	bind("application/json", `{"status":"draft"}`)
+	bind("application/json", `{"status":`)
+	bind("application/json", `{"status":"deleted"}`)
+	bind("application/json; charset=utf-8", `{"status":"published"}`)
+	bind("application/yaml", `status: draft`)
+	bind("application/x-made-up-1", `status: draft`)
+	bind("application/vnd.acme.status+json", `{"status":"draft"}`)
+
+	metrics := ctrl.DecodeMetrics()
+	bytes := int64(len(`{"status":"draft"}` + `{"status":` + `{"status":"deleted"}` + `{"status":"published"}` + `{"status":"draft"}`))
+	expected := DecodeContentTypeMetrics{
+		Requests:         5,
+		Bytes:            bytes,
+		DecodeErrors:     1,
+		ValidationErrors: 1,
+		ErrorRate:        2.0 / 5,
+		AverageBytes:     float64(bytes) / 5,
+	}
+	if got := metrics["application/json"]; got != expected {
+		t.Errorf("json metrics, expected %+v, got %+v", expected, got)
+	}
+	if got := metrics["unsupported"]; got.Requests != 2 || got.Unsupported != 2 || got.Bytes != 0 {
+		t.Errorf("unsupported metrics, expected 2 unsupported requests, got %+v", got)
+	}
+	if len(metrics) != 2 {
+		t.Errorf("metrics, expected json and unsupported, got %+v", metrics)
+	}
+	if clone := ctrl.Clone().DecodeMetrics(); len(clone) != 0 {
+		t.Errorf("clone metrics, expected none, got %+v", clone)
+	}
+}
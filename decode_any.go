@@ -0,0 +1,59 @@
+package render
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// DecodeAny decodes the request body with the decoder for its Content-Type,
+// like Bind, but into a generic value rather than a payload struct, for proxy
+// and audit endpoints that do not have one: JSON objects and arrays become
+// map[string]interface{} and []interface{} values, and XML documents a
+// map[string]interface{} that keeps the attributes and repeated elements; see
+// decoders.XML. Decoders that decode maps with other key types, like CBOR,
+// have the keys formatted as strings. A request without a body decodes to nil.
+//
+// The body goes through the same limits, decompression and metrics as for
+// Bind; decoders that can only decode into specific types, like Protobuf,
+// return their error.
+func (ctrl *Controller) DecodeAny(r *http.Request) (interface{}, error) {
+	if ctrl == nil {
+		return defaultCtrl.DecodeAny(r)
+	}
+	if err := ctrl.MalformedHeaders.checkContentType(r); err != nil {
+		return nil, err
+	}
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, nil
+	}
+	var v interface{}
+	if err := ctrl.decode(r, &v); err != nil {
+		return nil, wrapDecodeError(err)
+	}
+	return genericValue(v), nil
+}
+
+// genericValue replaces the maps with keys that are not strings, recursively,
+// by map[string]interface{} values
+func genericValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, elem := range v {
+			v[key] = genericValue(elem)
+		}
+		return v
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, elem := range v {
+			m[fmt.Sprint(key)] = genericValue(elem)
+		}
+		return m
+	case []interface{}:
+		for i, elem := range v {
+			v[i] = genericValue(elem)
+		}
+		return v
+	default:
+		return v
+	}
+}
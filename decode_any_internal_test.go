@@ -0,0 +1,113 @@
+package render
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/gdey/chi-render/decoders"
+)
+
+func TestDecodeAny(t *testing.T) {
+	ctrl := CloneDefault()
+	ctrl.SetDecoder(ContentTypeCBOR, decoders.CBOR)
+
+	cborBody, err := cbor.Marshal(map[interface{}]interface{}{"id": 1, 2: []interface{}{"a"}})
+	if err != nil {
+		t.Fatalf("cbor, expected nil, got %v", err)
+	}
+	var form bytes.Buffer
+	mw := multipart.NewWriter(&form)
+	mw.WriteField("title", "hello")
+	mw.WriteField("tag", "a")
+	mw.WriteField("tag", "b")
+	mw.Close()
+
+	type tcase struct {
+		ContentType string
+		Body        []byte
+		Expected    interface{}
+		// Err is true if an error is expected
+		Err bool
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			var body io.Reader
+			if tc.Body != nil {
+				body = bytes.NewReader(tc.Body)
+			}
+			r := httptest.NewRequest(http.MethodPost, "/", body)
+			r.Header.Set("Content-Type", tc.ContentType)
+			got, err := ctrl.DecodeAny(r)
+			if tc.Err {
+				if err == nil {
+					t.Errorf("error, expected an error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error, expected nil, got %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.Expected) {
+				t.Errorf("value, expected %#v, got %#v", tc.Expected, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"json object": {
+			ContentType: "application/json",
+			Body:        []byte(`{"id":1,"tags":["a","b"],"author":{"name":"gdey"}}`),
+			Expected: map[string]interface{}{
+				"id":     1.0,
+				"tags":   []interface{}{"a", "b"},
+				"author": map[string]interface{}{"name": "gdey"},
+			},
+		},
+		"json array": {
+			ContentType: "application/json",
+			Body:        []byte(`[1,"a",null]`),
+			Expected:    []interface{}{1.0, "a", nil},
+		},
+		"multipart": {
+			ContentType: mw.FormDataContentType(),
+			Body:        form.Bytes(),
+			Expected:    map[string]interface{}{"title": "hello", "tag": []interface{}{"a", "b"}},
+		},
+		"xml": {
+			ContentType: "text/xml",
+			Body:        []byte(`<article id="1"><tag>a</tag><tag>b</tag></article>`),
+			Expected: map[string]interface{}{"article": map[string]interface{}{
+				"@id": "1",
+				"tag": []interface{}{"a", "b"},
+			}},
+		},
+		"cbor keys": {
+			ContentType: "application/cbor",
+			Body:        cborBody,
+			Expected:    map[string]interface{}{"id": uint64(1), "2": []interface{}{"a"}},
+		},
+		"no body": {
+			ContentType: "application/json",
+		},
+		"syntax error": {
+			ContentType: "application/json",
+			Body:        []byte(`{"id":`),
+			Err:         true,
+		},
+		"unsupported": {
+			ContentType: "application/x-unknown",
+			Body:        []byte(`data`),
+			Err:         true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
@@ -0,0 +1,470 @@
+package decoders
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/linkedin/goavro/v2"
+)
+
+// ErrNoAvroSchema is the error that NoAvroSchemaError values match using errors.Is.
+var ErrNoAvroSchema = errors.New("decoders: no avro schema")
+
+// NoAvroSchemaError is returned by AvroSchemas when it has no schema for the
+// type of the payload a request body is decoded into.
+type NoAvroSchemaError struct {
+	// Type is the type of the payload
+	Type reflect.Type
+}
+
+func (err *NoAvroSchemaError) Error() string {
+	return fmt.Sprintf("decoders: no avro schema for %v", err.Type)
+}
+
+// Is reports whether target is ErrNoAvroSchema
+func (err *NoAvroSchemaError) Is(target error) bool { return target == ErrNoAvroSchema }
+
+// StatusCode is the http status code that should be reported to the client
+func (err *NoAvroSchemaError) StatusCode() int { return http.StatusUnsupportedMediaType }
+
+// AvroSchemas are the Avro schemas of the payload types; its Decode method
+// decodes Avro binary bodies, and it is the encoder of the responders.Avro
+// responder:
+//
+//	var avro decoders.AvroSchemas
+//	if err := avro.Register(reflect.TypeOf(Event{}), eventSchema); err != nil {
+//		...
+//	}
+//	ctrl.SetCodec(render.ContentTypeAvro, avro.Decode, responders.Avro(&avro))
+//
+// Payloads are mapped to the records of their schema through their JSON
+// encoding, so the names of the fields are their json names; []byte fields are
+// Avro bytes, time.Time fields can be timestamp-millis or timestamp-micros
+// longs, and optional fields are unions with null. Responses for payloads
+// without a schema fall back to the other content types the client accepts.
+//
+// The zero value has no schemas.
+type AvroSchemas struct {
+	lck     sync.RWMutex
+	schemas map[reflect.Type]*avroSchema
+}
+
+// avroSchema is a compiled schema, along with its parsed JSON, which guides the
+// mapping between JSON values and goavro's native values
+type avroSchema struct {
+	codec  *goavro.Codec
+	schema interface{}
+	// named are the named types of the schema, by full and short name
+	named map[string]interface{}
+}
+
+// Register compiles the Avro schema and registers it for payloads of the type;
+// payloads are matched by their exact type, or for pointers, the type they
+// point to. Use an empty schema to unregister the type.
+func (c *AvroSchemas) Register(typ reflect.Type, schema string) error {
+	var compiled *avroSchema
+	if schema != "" {
+		codec, err := goavro.NewCodec(schema)
+		if err != nil {
+			return fmt.Errorf("decoders: avro schema for %v: %w", typ, err)
+		}
+		compiled = &avroSchema{codec: codec, named: make(map[string]interface{})}
+		if err := json.Unmarshal([]byte(schema), &compiled.schema); err != nil {
+			return fmt.Errorf("decoders: avro schema for %v: %w", typ, err)
+		}
+		compiled.collectNamed(compiled.schema, "")
+	}
+	c.lck.Lock()
+	defer c.lck.Unlock()
+	if compiled == nil {
+		delete(c.schemas, typ)
+		return nil
+	}
+	if c.schemas == nil {
+		c.schemas = make(map[reflect.Type]*avroSchema)
+	}
+	c.schemas[typ] = compiled
+	return nil
+}
+
+// lookup returns the schema registered for the type of v; nil if there is none
+func (c *AvroSchemas) lookup(v interface{}) *avroSchema {
+	if v == nil {
+		return nil
+	}
+	c.lck.RLock()
+	defer c.lck.RUnlock()
+	typ := reflect.TypeOf(v)
+	schema, ok := c.schemas[typ]
+	for !ok && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+		schema, ok = c.schemas[typ]
+	}
+	return schema
+}
+
+// Decode decodes the Avro binary body into v, with the schema registered for
+// its type.
+func (c *AvroSchemas) Decode(r io.Reader, v interface{}) error {
+	defer io.Copy(ioutil.Discard, r)
+	schema := c.lookup(v)
+	if schema == nil {
+		return &NoAvroSchemaError{Type: reflect.TypeOf(v)}
+	}
+	body, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	native, _, err := schema.codec.NativeFromBinary(body)
+	if err != nil {
+		return fmt.Errorf("decoders: avro decode: %w", err)
+	}
+	data, err := json.Marshal(schema.fromNative(schema.schema, "", native))
+	if err != nil {
+		return fmt.Errorf("decoders: avro decode: %w", err)
+	}
+	return json.Unmarshal(data, v)
+}
+
+// EncodeAvro returns v as Avro binary, with the schema registered for its
+// type; ok is false if there is none.
+func (c *AvroSchemas) EncodeAvro(v interface{}) (body []byte, ok bool, err error) {
+	schema := c.lookup(v)
+	if schema == nil {
+		return nil, false, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, true, fmt.Errorf("decoders: avro encode: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return nil, true, fmt.Errorf("decoders: avro encode: %w", err)
+	}
+	native, err := schema.toNative(schema.schema, "", doc)
+	if err != nil {
+		return nil, true, fmt.Errorf("decoders: avro encode: %w", err)
+	}
+	body, err = schema.codec.BinaryFromNative(nil, native)
+	if err != nil {
+		return nil, true, fmt.Errorf("decoders: avro encode: %w", err)
+	}
+	return body, true, nil
+}
+
+// avroFullName returns the full name of a named type in the namespace
+func avroFullName(name, namespace string) string {
+	if strings.Contains(name, ".") || namespace == "" {
+		return name
+	}
+	return namespace + "." + name
+}
+
+// collectNamed records the records, enums and fixed types of the schema
+func (s *avroSchema) collectNamed(schema interface{}, namespace string) {
+	switch schema := schema.(type) {
+	case []interface{}:
+		for _, member := range schema {
+			s.collectNamed(member, namespace)
+		}
+	case map[string]interface{}:
+		switch schema["type"] {
+		case "record", "error", "enum", "fixed":
+			name, _ := schema["name"].(string)
+			if ns, ok := schema["namespace"].(string); ok {
+				namespace = ns
+			}
+			fullName := avroFullName(name, namespace)
+			if i := strings.LastIndexByte(fullName, '.'); i >= 0 {
+				namespace = fullName[:i]
+			}
+			s.named[fullName] = schema
+			s.named[fullName[strings.LastIndexByte(fullName, '.')+1:]] = schema
+			fields, _ := schema["fields"].([]interface{})
+			for _, field := range fields {
+				if field, ok := field.(map[string]interface{}); ok {
+					s.collectNamed(field["type"], namespace)
+				}
+			}
+		case "array":
+			s.collectNamed(schema["items"], namespace)
+		case "map":
+			s.collectNamed(schema["values"], namespace)
+		default:
+			s.collectNamed(schema["type"], namespace)
+		}
+	}
+}
+
+// resolve returns the schema of a type name, and its namespace; primitive type
+// names are returned as they are.
+func (s *avroSchema) resolve(name, namespace string) (interface{}, string) {
+	schema, ok := s.named[avroFullName(name, namespace)]
+	if !ok {
+		if schema, ok = s.named[name]; !ok {
+			return name, namespace
+		}
+	}
+	fullName := avroFullName(name, namespace)
+	if ns, ok := schema.(map[string]interface{})["namespace"].(string); ok {
+		fullName = avroFullName(name, ns)
+	}
+	if i := strings.LastIndexByte(fullName, '.'); i >= 0 {
+		namespace = fullName[:i]
+	}
+	return schema, namespace
+}
+
+// unionName is the name goavro uses for the member of a union
+func (s *avroSchema) unionName(schema interface{}, namespace string) string {
+	switch schema := schema.(type) {
+	case string:
+		if named, ok := s.named[avroFullName(schema, namespace)]; ok {
+			return s.unionName(named, namespace)
+		}
+		if named, ok := s.named[schema]; ok {
+			return s.unionName(named, namespace)
+		}
+		return schema
+	case map[string]interface{}:
+		typ, _ := schema["type"].(string)
+		switch typ {
+		case "record", "error", "enum", "fixed":
+			name, _ := schema["name"].(string)
+			if ns, ok := schema["namespace"].(string); ok {
+				namespace = ns
+			}
+			return avroFullName(name, namespace)
+		}
+		if logicalType, ok := schema["logicalType"].(string); ok {
+			return typ + "." + logicalType
+		}
+		return typ
+	}
+	return ""
+}
+
+// toNative converts a JSON value to the native value goavro encodes for the
+// schema; union members are wrapped in a map keyed by their name.
+func (s *avroSchema) toNative(schema interface{}, namespace string, v interface{}) (interface{}, error) {
+	switch schema := schema.(type) {
+	case string:
+		if resolved, ns := s.resolve(schema, namespace); resolved != schema {
+			return s.toNative(resolved, ns, v)
+		}
+		return avroPrimitive(schema, v)
+
+	case []interface{}:
+		if v == nil {
+			return nil, nil
+		}
+		for _, member := range schema {
+			if member == "null" {
+				continue
+			}
+			if native, err := s.toNative(member, namespace, v); err == nil {
+				return goavro.Union(s.unionName(member, namespace), native), nil
+			}
+		}
+		return nil, fmt.Errorf("%v does not match any type of the union", v)
+
+	case map[string]interface{}:
+		switch typ := schema["type"]; typ {
+		case "record", "error":
+			obj, ok := v.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("expected an object for record %v, got %T", schema["name"], v)
+			}
+			if ns, ok := schema["namespace"].(string); ok {
+				namespace = ns
+			}
+			record := make(map[string]interface{}, len(obj))
+			fields, _ := schema["fields"].([]interface{})
+			for _, field := range fields {
+				field, _ := field.(map[string]interface{})
+				name, _ := field["name"].(string)
+				value, ok := obj[name]
+				if !ok {
+					// goavro uses the default of the field, if it has one
+					continue
+				}
+				native, err := s.toNative(field["type"], namespace, value)
+				if err != nil {
+					return nil, fmt.Errorf("field %s: %w", name, err)
+				}
+				record[name] = native
+			}
+			return record, nil
+		case "enum":
+			if _, ok := v.(string); !ok {
+				return nil, fmt.Errorf("expected a string for enum %v, got %T", schema["name"], v)
+			}
+			return v, nil
+		case "fixed":
+			return avroPrimitive("bytes", v)
+		case "array":
+			items, ok := v.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("expected an array, got %T", v)
+			}
+			natives := make([]interface{}, len(items))
+			for i, item := range items {
+				native, err := s.toNative(schema["items"], namespace, item)
+				if err != nil {
+					return nil, fmt.Errorf("item %d: %w", i, err)
+				}
+				natives[i] = native
+			}
+			return natives, nil
+		case "map":
+			obj, ok := v.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("expected an object, got %T", v)
+			}
+			natives := make(map[string]interface{}, len(obj))
+			for key, value := range obj {
+				native, err := s.toNative(schema["values"], namespace, value)
+				if err != nil {
+					return nil, fmt.Errorf("key %s: %w", key, err)
+				}
+				natives[key] = native
+			}
+			return natives, nil
+		default:
+			switch schema["logicalType"] {
+			case "timestamp-millis", "timestamp-micros":
+				str, ok := v.(string)
+				if !ok {
+					return nil, fmt.Errorf("expected a time, got %T", v)
+				}
+				return time.Parse(time.RFC3339Nano, str)
+			}
+			return s.toNative(typ, namespace, v)
+		}
+	}
+	return nil, fmt.Errorf("unsupported schema %v", schema)
+}
+
+// avroPrimitive converts a JSON value to the native value of a primitive type
+func avroPrimitive(typ string, v interface{}) (interface{}, error) {
+	switch typ {
+	case "null":
+		if v != nil {
+			return nil, fmt.Errorf("expected null, got %T", v)
+		}
+		return nil, nil
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return nil, fmt.Errorf("expected a boolean, got %T", v)
+		}
+		return v, nil
+	case "string":
+		if _, ok := v.(string); !ok {
+			return nil, fmt.Errorf("expected a string, got %T", v)
+		}
+		return v, nil
+	case "bytes":
+		// encoding/json writes []byte as base64
+		str, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected bytes, got %T", v)
+		}
+		return base64.StdEncoding.DecodeString(str)
+	case "int", "long", "float", "double":
+		num, ok := v.(json.Number)
+		if !ok {
+			return nil, fmt.Errorf("expected a number, got %T", v)
+		}
+		switch typ {
+		case "int":
+			i, err := num.Int64()
+			return int32(i), err
+		case "long":
+			return num.Int64()
+		case "float":
+			f, err := num.Float64()
+			return float32(f), err
+		default:
+			return num.Float64()
+		}
+	}
+	return nil, fmt.Errorf("unknown avro type %q", typ)
+}
+
+// fromNative converts a native value goavro decoded for the schema into a JSON
+// value, unwrapping the members of unions.
+func (s *avroSchema) fromNative(schema interface{}, namespace string, v interface{}) interface{} {
+	switch schema := schema.(type) {
+	case string:
+		if resolved, ns := s.resolve(schema, namespace); resolved != schema {
+			return s.fromNative(resolved, ns, v)
+		}
+		return v
+
+	case []interface{}:
+		wrapped, ok := v.(map[string]interface{})
+		if !ok || len(wrapped) != 1 {
+			return v
+		}
+		for name, value := range wrapped {
+			for _, member := range schema {
+				if s.unionName(member, namespace) == name {
+					return s.fromNative(member, namespace, value)
+				}
+			}
+		}
+		return v
+
+	case map[string]interface{}:
+		switch typ := schema["type"]; typ {
+		case "record", "error":
+			record, ok := v.(map[string]interface{})
+			if !ok {
+				return v
+			}
+			if ns, ok := schema["namespace"].(string); ok {
+				namespace = ns
+			}
+			fields, _ := schema["fields"].([]interface{})
+			for _, field := range fields {
+				field, _ := field.(map[string]interface{})
+				name, _ := field["name"].(string)
+				if value, ok := record[name]; ok {
+					record[name] = s.fromNative(field["type"], namespace, value)
+				}
+			}
+			return record
+		case "array":
+			if items, ok := v.([]interface{}); ok {
+				for i, item := range items {
+					items[i] = s.fromNative(schema["items"], namespace, item)
+				}
+			}
+			return v
+		case "map":
+			if values, ok := v.(map[string]interface{}); ok {
+				for key, value := range values {
+					values[key] = s.fromNative(schema["values"], namespace, value)
+				}
+			}
+			return v
+		case "enum", "fixed":
+			return v
+		default:
+			return s.fromNative(typ, namespace, v)
+		}
+	}
+	return v
+}
@@ -0,0 +1,112 @@
+package decoders_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gdey/chi-render/decoders"
+)
+
+type avroLocation struct {
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
+}
+
+type avroEvent struct {
+	ID       int64            `json:"id"`
+	Kind     string           `json:"kind"`
+	Tags     []string         `json:"tags"`
+	Attrs    map[string]int32 `json:"attrs"`
+	Payload  []byte           `json:"payload"`
+	At       time.Time        `json:"at"`
+	Note     *string          `json:"note"`
+	Location *avroLocation    `json:"location"`
+}
+
+const avroEventSchema = `{
+	"type": "record",
+	"name": "Event",
+	"namespace": "com.example",
+	"fields": [
+		{"name": "id", "type": "long"},
+		{"name": "kind", "type": {"type": "enum", "name": "Kind", "symbols": ["created", "deleted"]}},
+		{"name": "tags", "type": {"type": "array", "items": "string"}},
+		{"name": "attrs", "type": {"type": "map", "values": "int"}},
+		{"name": "payload", "type": "bytes"},
+		{"name": "at", "type": {"type": "long", "logicalType": "timestamp-millis"}},
+		{"name": "note", "type": ["null", "string"], "default": null},
+		{"name": "location", "type": ["null", {
+			"type": "record",
+			"name": "Location",
+			"fields": [{"name": "lat", "type": "double"}, {"name": "lng", "type": "double"}]
+		}], "default": null}
+	]
+}`
+
+func TestAvroSchemas(t *testing.T) {
+	var avro decoders.AvroSchemas
+	if err := avro.Register(reflect.TypeOf(avroEvent{}), avroEventSchema); err != nil {
+		t.Fatalf("register, expected nil, got %v", err)
+	}
+	note := "hello"
+
+	for name, event := range map[string]avroEvent{
+		"full": {
+			ID:       1,
+			Kind:     "created",
+			Tags:     []string{"a", "b"},
+			Attrs:    map[string]int32{"x": 1},
+			Payload:  []byte{0, 1, 2},
+			At:       time.Date(2021, 2, 25, 1, 2, 3, 4000000, time.UTC),
+			Note:     &note,
+			Location: &avroLocation{Lat: 1.5, Lng: -2.25},
+		},
+		"nulls": {
+			ID:      2,
+			Kind:    "deleted",
+			Tags:    []string{},
+			Attrs:   map[string]int32{},
+			Payload: []byte{},
+			At:      time.Date(2021, 2, 25, 0, 0, 0, 0, time.UTC),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			body, ok, err := avro.EncodeAvro(&event)
+			if !ok || err != nil {
+				t.Fatalf("encode, expected ok, got %v, %v", ok, err)
+			}
+			var got avroEvent
+			if err := avro.Decode(bytes.NewReader(body), &got); err != nil {
+				t.Fatalf("decode, expected nil, got %v", err)
+			}
+			if !reflect.DeepEqual(got, event) {
+				t.Errorf("event, expected %+v, got %+v", event, got)
+			}
+		})
+	}
+
+	t.Run("no schema", func(t *testing.T) {
+		type other struct {
+			Name string `json:"name"`
+		}
+		if _, ok, _ := avro.EncodeAvro(&other{Name: "gdey"}); ok {
+			t.Errorf("encode, expected no schema")
+		}
+		err := avro.Decode(bytes.NewReader([]byte{0}), &other{})
+		var noSchema *decoders.NoAvroSchemaError
+		if !errors.Is(err, decoders.ErrNoAvroSchema) || !errors.As(err, &noSchema) || noSchema.StatusCode() != http.StatusUnsupportedMediaType {
+			t.Errorf("error, expected %v, got %v", decoders.ErrNoAvroSchema, err)
+		}
+	})
+
+	t.Run("invalid schema", func(t *testing.T) {
+		var avro decoders.AvroSchemas
+		if err := avro.Register(reflect.TypeOf(avroEvent{}), `{"type": "record"}`); err == nil {
+			t.Errorf("error, expected an error for an invalid schema")
+		}
+	})
+}
@@ -0,0 +1,19 @@
+package decoders
+
+import (
+	"encoding/base64"
+	"io"
+)
+
+// Base64 returns a decoder that base64 decodes the body before passing it to
+// inner, for bodies wrapped by API gateways and webhook sources, like AWS Lambda
+// proxies. The body uses the standard encoding, with padding; line breaks are
+// ignored. The media type parameters of the body are kept for inner.
+//
+//	ctrl.SetDecoder(render.ContentTypeJSON, decoders.Base64(decoders.JSON))
+func Base64(inner Func) Func { return Chain(Base64Body)(inner) }
+
+// Base64Body is the Pre of Base64, to Chain with other Pre funcs.
+func Base64Body(r io.Reader) (io.Reader, error) {
+	return base64.NewDecoder(base64.StdEncoding, r), nil
+}
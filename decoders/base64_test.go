@@ -0,0 +1,59 @@
+package decoders_test
+
+import (
+	"encoding/base64"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gdey/chi-render/decoders"
+	"github.com/gdey/chi-render/decoders/test"
+)
+
+func TestBase64(t *testing.T) {
+	type payload struct {
+		Name string `json:"name"`
+	}
+	encode := func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) }
+	// wrap breaks s into lines of n characters, as MIME encoders do
+	wrap := func(s string, n int) string {
+		var lines []string
+		for ; len(s) > n; s = s[n:] {
+			lines = append(lines, s[:n])
+		}
+		return strings.Join(append(lines, s), "\r\n")
+	}
+
+	tests := map[string]test.Case{
+		"json":        test.NewStringCase(encode(`{"name":"gdey"}`), payload{Name: "gdey"}),
+		"line breaks": test.NewStringCase(wrap(encode(`{"name":"a longer name to wrap"}`), 16), payload{Name: "a longer name to wrap"}),
+		"corrupt": {
+			R:     strings.NewReader("not base64!"),
+			Value: payload{},
+			Err:   base64.CorruptInputError(0),
+			ErrComparator: func(_, got error) bool {
+				var corrupt base64.CorruptInputError
+				return reflect.TypeOf(got) == reflect.TypeOf(corrupt)
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, tc.Test(decoders.Base64(decoders.JSON)))
+	}
+
+	t.Run("params", func(t *testing.T) {
+		var params map[string]string
+		inner := func(r io.Reader, v interface{}) error {
+			params = decoders.Params(r)
+			return nil
+		}
+		body := decoders.WithParams(strings.NewReader(encode("x")), map[string]string{"charset": "utf-8"})
+		if err := decoders.Base64(inner)(body, nil); err != nil {
+			t.Fatalf("error, expected nil, got %v", err)
+		}
+		if params["charset"] != "utf-8" {
+			t.Errorf("params, expected the charset, got %v", params)
+		}
+	})
+}
@@ -0,0 +1,44 @@
+package decoders
+
+import (
+	"io"
+	"io/ioutil"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// CBOR decodes application/cbor bodies, like those of COSE and IoT clients.
+// Struct fields are matched using their cbor tags, falling back to their json
+// tags. It is the decoder NewCBOR returns without options.
+func CBOR(r io.Reader, v interface{}) error {
+	defer io.Copy(ioutil.Discard, r)
+	return cbor.NewDecoder(r).Decode(v)
+}
+
+// NewCBOR returns a CBOR decoder tuned by opts; see Strict.
+func NewCBOR(opts ...Option) Func {
+	o := newOptions(opts)
+	var decOpts cbor.DecOptions
+	if o.unknownFields {
+		decOpts.ExtraReturnErrors = cbor.ExtraDecErrorUnknownField
+	}
+	mode, err := decOpts.DecMode()
+	return func(r io.Reader, v interface{}) error {
+		defer io.Copy(ioutil.Discard, r)
+		if err != nil {
+			return err
+		}
+		dec := mode.NewDecoder(r)
+		if err := dec.Decode(v); err != nil {
+			return err
+		}
+		if !o.trailingData {
+			return nil
+		}
+		var trailing interface{}
+		if err := dec.Decode(&trailing); err != io.EOF {
+			return ErrTrailingData
+		}
+		return nil
+	}
+}
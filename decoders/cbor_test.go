@@ -0,0 +1,97 @@
+package decoders_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+
+	"github.com/gdey/chi-render/decoders"
+	"github.com/gdey/chi-render/decoders/test"
+)
+
+func TestCBOR(t *testing.T) {
+	type Reading struct {
+		Sensor string  `json:"sensor"`
+		Value  float64 `cbor:"v"`
+		Tags   []string
+	}
+
+	encode := func(v interface{}) *bytes.Reader {
+		b, err := cbor.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return bytes.NewReader(b)
+	}
+
+	tests := map[string]test.Case{
+		"reading": {
+			R:     encode(map[string]interface{}{"sensor": "t1", "v": 21.5, "Tags": []string{"room"}}),
+			Value: Reading{Sensor: "t1", Value: 21.5, Tags: []string{"room"}},
+		},
+		"truncated": {
+			R:     bytes.NewReader([]byte{0xa1, 0x66}),
+			Value: Reading{},
+			Err:   io.ErrUnexpectedEOF,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, tc.Test(decoders.CBOR))
+	}
+}
+
+func TestNewCBOR(t *testing.T) {
+	type Reading struct {
+		Sensor string `json:"sensor"`
+	}
+
+	encode := func(vs ...interface{}) *bytes.Reader {
+		var buf bytes.Buffer
+		for _, v := range vs {
+			b, err := cbor.Marshal(v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			buf.Write(b)
+		}
+		return bytes.NewReader(buf.Bytes())
+	}
+	isErr := func(_, got error) bool { return got != nil }
+
+	tests := map[string]struct {
+		Decoder decoders.Func
+		Case    test.Case
+	}{
+		"defaults": {
+			Decoder: decoders.NewCBOR(),
+			Case: test.Case{
+				R:     encode(map[string]interface{}{"sensor": "t1", "v": 1}, "trailing"),
+				Value: Reading{Sensor: "t1"},
+			},
+		},
+		"strict unknown field": {
+			Decoder: decoders.NewCBOR(decoders.Strict()),
+			Case: test.Case{
+				R:             encode(map[string]interface{}{"sensor": "t1", "v": 1}),
+				Value:         Reading{},
+				Err:           errors.New("unknown field"),
+				ErrComparator: isErr,
+			},
+		},
+		"strict trailing data": {
+			Decoder: decoders.NewCBOR(decoders.Strict()),
+			Case: test.Case{
+				R:             encode(map[string]interface{}{"sensor": "t1"}, "trailing"),
+				Value:         Reading{},
+				Err:           decoders.ErrTrailingData,
+				ErrComparator: func(expected, got error) bool { return errors.Is(got, expected) },
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, tc.Case.Test(tc.Decoder))
+	}
+}
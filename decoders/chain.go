@@ -0,0 +1,38 @@
+package decoders
+
+import (
+	"io"
+	"io/ioutil"
+)
+
+// Pre transforms a request body before a decoder reads it; it returns the
+// reader the decoder gets instead, or an error to fail the decoding with.
+type Pre func(r io.Reader) (io.Reader, error)
+
+// Chain returns a func that wraps a decoder so the body goes through the pre
+// funcs, in order, before the decoder reads it. Cross cutting concerns, like
+// decompression or checksum verification, can then wrap any decoder:
+//
+//	gunzip := func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) }
+//	wrap := decoders.Chain(gunzip)
+//	ctrl.SetDecoder(render.ContentTypeJSON, wrap(decoders.JSON))
+//	ctrl.SetDecoder(render.ContentTypeXML, wrap(decoders.XML))
+//
+// Everything attached to the body, like its media type parameters, converters,
+// cleanup, form values and context, is kept for the decoder, and the original
+// body is drained once the decoder returns.
+func Chain(pre ...Pre) func(Func) Func {
+	return func(inner Func) Func {
+		return func(r io.Reader, v interface{}) error {
+			defer io.Copy(ioutil.Discard, r)
+			body := r
+			for _, fn := range pre {
+				var err error
+				if body, err = fn(body); err != nil {
+					return err
+				}
+			}
+			return inner(withAttached(body, r), v)
+		}
+	}
+}
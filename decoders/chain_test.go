@@ -0,0 +1,139 @@
+package decoders_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gdey/chi-render/decoders"
+)
+
+func TestChain(t *testing.T) {
+	type payload struct {
+		Name string `json:"name"`
+	}
+	gunzip := func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) }
+	gzipped := func(s string) []byte {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		zw.Write([]byte(s))
+		zw.Close()
+		return buf.Bytes()
+	}
+	errRejected := errors.New("rejected")
+
+	type tcase struct {
+		Pre      []decoders.Pre
+		Body     []byte
+		Expected payload
+		Err      error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			var got payload
+			err := decoders.Chain(tc.Pre...)(decoders.JSON)(bytes.NewReader(tc.Body), &got)
+			if tc.Err != nil {
+				if !errors.Is(err, tc.Err) {
+					t.Errorf("error, expected %v, got %v", tc.Err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("error, expected nil, got %v", err)
+				return
+			}
+			if got != tc.Expected {
+				t.Errorf("value, expected %+v, got %+v", tc.Expected, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"no pre": {
+			Body:     []byte(`{"name":"gdey"}`),
+			Expected: payload{Name: "gdey"},
+		},
+		"gzip": {
+			Pre:      []decoders.Pre{gunzip},
+			Body:     gzipped(`{"name":"gdey"}`),
+			Expected: payload{Name: "gdey"},
+		},
+		"in order": {
+			// the body was gzipped, then base64 encoded
+			Pre:      []decoders.Pre{decoders.Base64Body, gunzip},
+			Body:     []byte(base64.StdEncoding.EncodeToString(gzipped(`{"name":"gdey"}`))),
+			Expected: payload{Name: "gdey"},
+		},
+		"not gzip": {
+			Pre:  []decoders.Pre{gunzip},
+			Body: []byte(`{"name":"gdey"}`),
+			Err:  gzip.ErrHeader,
+		},
+		"pre error": {
+			Pre: []decoders.Pre{
+				func(r io.Reader) (io.Reader, error) { return nil, errRejected },
+				func(r io.Reader) (io.Reader, error) { panic("not called") },
+			},
+			Body: []byte(`{"name":"gdey"}`),
+			Err:  errRejected,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+
+	t.Run("params and drain", func(t *testing.T) {
+		body := strings.NewReader(`{"name":"gdey"} trailing`)
+		params := map[string]string{"charset": "utf-8"}
+		var got map[string]string
+		inner := func(r io.Reader, v interface{}) error {
+			got = decoders.Params(r)
+			_, err := ioutil.ReadAll(io.LimitReader(r, 4))
+			return err
+		}
+		if err := decoders.Chain()(inner)(decoders.WithParams(body, params), nil); err != nil {
+			t.Fatalf("error, expected nil, got %v", err)
+		}
+		if !reflect.DeepEqual(got, params) {
+			t.Errorf("params, expected %v, got %v", params, got)
+		}
+		if body.Len() != 0 {
+			t.Errorf("body, expected drained, %d bytes left", body.Len())
+		}
+	})
+	t.Run("attached", func(t *testing.T) {
+		wrap := decoders.Chain(func(r io.Reader) (io.Reader, error) { return io.MultiReader(r), nil })
+
+		body, params := newMultipartCase(t, map[string][]string{"title": {"hello"}}, nil)
+		var (
+			cleanups int
+			values   url.Values
+		)
+		var r io.Reader = decoders.WithParams(body, params)
+		r = decoders.WithCleanup(r, func(cleanup func() error) { cleanups++ })
+		r = decoders.WithFormValues(r, func(v url.Values) { values = v })
+		var got upload
+		if err := wrap(decoders.Multipart)(r, &got); err != nil {
+			t.Fatalf("multipart error, expected nil, got %v", err)
+		}
+		if got.Title != "hello" || cleanups != 1 || values.Get("title") != "hello" {
+			t.Errorf("multipart, expected the title, a cleanup and the form values, got %q, %d and %v", got.Title, cleanups, values)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		r = decoders.WithContext(strings.NewReader("{\"id\":1}\n"), ctx)
+		if err := wrap(decoders.NDJSON)(r, make(chan ndjsonRow)); !errors.Is(err, context.Canceled) {
+			t.Errorf("ndjson error, expected %v, got %v", context.Canceled, err)
+		}
+	})
+}
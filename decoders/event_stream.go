@@ -0,0 +1,200 @@
+package decoders
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrNotEventChannel is returned by EventStream when the value to decode
+	// into is neither a channel that can be sent on nor an EventChannel.
+	ErrNotEventChannel = errors.New("decoders: event streams can only be decoded into a channel or an EventChannel")
+
+	// ErrErrorEvent is returned by EventStream, wrapped in an *EventError, when
+	// the stream sends an error event to a channel of typed events.
+	ErrErrorEvent = errors.New("decoders: the event stream sent an error event")
+)
+
+// SSEEvent is an event of a text/event-stream body, as written by the
+// render.ChannelEventStream responder: "event: data" events carry the JSON
+// encoding of a value, "event: error" events report an error, and the
+// "event: EOF" event ends the stream.
+type SSEEvent struct {
+	// ID is the last event id the stream set, it carries over to the events
+	// that do not set one
+	ID string
+	// Event is the name of the event; empty if the stream did not name it,
+	// which clients treat as "message"
+	Event string
+	// Data is the data lines of the event, joined by newlines
+	Data string
+	// Retry is the reconnection time the event set, if any
+	Retry time.Duration
+}
+
+// EventChannel is implemented by payloads that relay the events of a stream,
+// so they can be bound with render.Bind:
+//
+//	type Relay struct {
+//		Messages chan Message
+//	}
+//
+//	func (relay *Relay) EventChannel() interface{} { return relay.Messages }
+//	func (relay *Relay) Bind(r *http.Request) error { return nil }
+type EventChannel interface {
+	// EventChannel returns the channel the events are sent on
+	EventChannel() interface{}
+}
+
+// EventError is returned by EventStream when an event can not be decoded, or
+// when the stream sends an error event to a channel of typed events.
+type EventError struct {
+	Event SSEEvent
+	Err   error
+}
+
+func (err *EventError) Error() string {
+	return fmt.Sprintf("decoders: event %q: %v", err.Event.Event, err.Err)
+}
+
+func (err *EventError) Unwrap() error { return err.Err }
+
+// EventStream decodes text/event-stream bodies, sending every event on the
+// channel v is, or that v's EventChannel method returns, until the stream ends
+// or sends an EOF event. The send blocks, so the channel must be received from
+// while the body is decoded; the channel is not closed. A send is abandoned,
+// and the decoding fails, once the context attached with WithContext, the
+// context of the request for the controller, is done, or the receiver of an
+// EventChannel that is a RecordReceiver is. It is the decoder NewEventStream
+// returns without options.
+//
+// Channels of SSEEvent get the events as they are. For channels of any other
+// type, the data of each event is decoded as JSON into a new value of the
+// channel's element type, and error events end the decoding with an
+// *EventError wrapping ErrErrorEvent.
+//
+//	relay := &Relay{Messages: make(chan Message)}
+//	go func() {
+//		for msg := range relay.Messages {
+//			...
+//		}
+//	}()
+//	err := render.Bind(r, relay)
+//	close(relay.Messages)
+func EventStream(r io.Reader, v interface{}) error {
+	defer io.Copy(ioutil.Discard, r)
+	return decodeEventStream(r, v, options{})
+}
+
+// NewEventStream returns an EventStream decoder tuned by opts, which apply to
+// the data of the events of typed channels; see UseNumber and Strict.
+func NewEventStream(opts ...Option) Func {
+	o := newOptions(opts)
+	return func(r io.Reader, v interface{}) error {
+		defer io.Copy(ioutil.Discard, r)
+		return decodeEventStream(r, v, o)
+	}
+}
+
+var sseEventType = reflect.TypeOf(SSEEvent{})
+
+func decodeEventStream(r io.Reader, v interface{}, o options) error {
+	var receiverDone <-chan struct{}
+	if ec, ok := v.(EventChannel); ok {
+		if receiver, ok := v.(RecordReceiver); ok {
+			receiverDone = receiver.RecordsDone()
+		}
+		v = ec.EventChannel()
+	}
+	ch := reflect.ValueOf(v)
+	if ch.Kind() != reflect.Chan || ch.IsNil() || ch.Type().ChanDir()&reflect.SendDir == 0 {
+		return fmt.Errorf("%w; got %T", ErrNotEventChannel, v)
+	}
+	ctx := Context(r)
+	elemType := ch.Type().Elem()
+	return eachEvent(r, func(event SSEEvent) error {
+		if elemType == sseEventType {
+			return send(ctx, receiverDone, ch, reflect.ValueOf(event))
+		}
+		if event.Event == "error" {
+			return &EventError{Event: event, Err: ErrErrorEvent}
+		}
+		elem := reflect.New(elemType)
+		if err := unmarshalRecord([]byte(event.Data), elem.Interface(), o); err != nil {
+			return &EventError{Event: event, Err: err}
+		}
+		return send(ctx, receiverDone, ch, elem.Elem())
+	})
+}
+
+// eachEvent parses the event stream, calling fn for every event that has data,
+// until the end of the stream or an EOF event. Lines end with "\n" or "\r\n";
+// as with EventSource, an event the stream does not end with a blank line is
+// dropped.
+func eachEvent(r io.Reader, fn func(event SSEEvent) error) error {
+	var (
+		br      = bufio.NewReader(r)
+		event   SSEEvent
+		data    strings.Builder
+		hasData bool
+		lastID  string
+	)
+	for first := true; ; first = false {
+		line, err := br.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+		if err == io.EOF && !strings.HasSuffix(line, "\n") {
+			return nil
+		}
+		if first {
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+		line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+
+		field, value := line, ""
+		if i := strings.IndexByte(line, ':'); i >= 0 {
+			field, value = line[:i], strings.TrimPrefix(line[i+1:], " ")
+		}
+		switch {
+		case line == "":
+			// a blank line dispatches the event
+			if event.Event == "EOF" {
+				return nil
+			}
+			if hasData {
+				event.ID = lastID
+				event.Data = data.String()
+				if err := fn(event); err != nil {
+					return err
+				}
+			}
+			event, hasData = SSEEvent{}, false
+			data.Reset()
+		case field == "event":
+			event.Event = value
+		case field == "data":
+			if hasData {
+				data.WriteByte('\n')
+			}
+			data.WriteString(value)
+			hasData = true
+		case field == "id":
+			if !strings.ContainsRune(value, 0) {
+				lastID = value
+			}
+		case field == "retry":
+			if ms, err := strconv.ParseUint(value, 10, 32); err == nil {
+				event.Retry = time.Duration(ms) * time.Millisecond
+			}
+		}
+		// comments, lines starting with a colon, and unknown fields are ignored
+	}
+}
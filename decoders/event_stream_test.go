@@ -0,0 +1,178 @@
+package decoders_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdey/chi-render/decoders"
+)
+
+type sseMessage struct {
+	Text string `json:"text"`
+}
+
+// sseRelay is a payload that relays the messages of the stream
+type sseRelay struct {
+	Messages chan sseMessage
+}
+
+func (relay *sseRelay) EventChannel() interface{} { return relay.Messages }
+
+// sseReceiver relays the messages of the stream until Done is closed
+type sseReceiver struct {
+	Messages chan sseMessage
+	Done     chan struct{}
+}
+
+func (receiver *sseReceiver) EventChannel() interface{}    { return receiver.Messages }
+func (receiver *sseReceiver) RecordsDone() <-chan struct{} { return receiver.Done }
+
+func TestEventStream(t *testing.T) {
+	type tcase struct {
+		Body string
+		// Raw decodes into a channel of SSEEvent, rather than of sseMessage
+		Raw      bool
+		Events   []decoders.SSEEvent
+		Messages []sseMessage
+		// Err reports whether the error is the expected one; nil if no error
+		// is expected
+		Err func(err error) bool
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			var (
+				v      interface{}
+				events = make(chan decoders.SSEEvent)
+				relay  = &sseRelay{Messages: make(chan sseMessage)}
+				done   = make(chan struct{})
+
+				gotEvents   []decoders.SSEEvent
+				gotMessages []sseMessage
+			)
+			go func() {
+				defer close(done)
+				for {
+					select {
+					case event, ok := <-events:
+						if !ok {
+							return
+						}
+						gotEvents = append(gotEvents, event)
+					case msg := <-relay.Messages:
+						gotMessages = append(gotMessages, msg)
+					}
+				}
+			}()
+			if tc.Raw {
+				v = events
+			} else {
+				v = relay
+			}
+
+			err := decoders.EventStream(strings.NewReader(tc.Body), v)
+			close(events)
+			<-done
+
+			if tc.Err != nil {
+				if !tc.Err(err) {
+					t.Errorf("error, got unexpected %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("error, expected nil, got %v", err)
+				return
+			}
+			if !reflect.DeepEqual(gotEvents, tc.Events) {
+				t.Errorf("events, expected %+v, got %+v", tc.Events, gotEvents)
+			}
+			if !reflect.DeepEqual(gotMessages, tc.Messages) {
+				t.Errorf("messages, expected %+v, got %+v", tc.Messages, gotMessages)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"responder stream": {
+			Body:     "event: data\ndata: {\"text\":\"a\"}\n\nevent: data\ndata: {\"text\":\"b\"}\n\nevent: EOF\n\nevent: data\ndata: {\"text\":\"after\"}\n\n",
+			Messages: []sseMessage{{Text: "a"}, {Text: "b"}},
+		},
+		"raw events": {
+			Body: "\ufeff: a comment\r\nid: 1\r\nretry: 1500\r\ndata: first\r\ndata: second\r\n\r\nevent: ping\ndata\n\nid\ndata: third\n\n",
+			Raw:  true,
+			Events: []decoders.SSEEvent{
+				{ID: "1", Data: "first\nsecond", Retry: 1500 * time.Millisecond},
+				{ID: "1", Event: "ping"},
+				{Data: "third"},
+			},
+		},
+		"raw error event": {
+			Body:   "event: error\ndata: {\"error\":\"Server Timeout\"}\n\n",
+			Raw:    true,
+			Events: []decoders.SSEEvent{{Event: "error", Data: `{"error":"Server Timeout"}`}},
+		},
+		"events without data": {
+			Body: "event: data\n\nid: 2\n\n",
+			Raw:  true,
+		},
+		"unterminated event": {
+			Body:     "data: {\"text\":\"a\"}\n\ndata: {\"text\":\"b\"}",
+			Messages: []sseMessage{{Text: "a"}},
+		},
+		"error event": {
+			Body: "data: {\"text\":\"a\"}\n\nevent: error\ndata: {\"error\":\"Server Timeout\"}\n\n",
+			Err: func(err error) bool {
+				var eventErr *decoders.EventError
+				return errors.Is(err, decoders.ErrErrorEvent) && errors.As(err, &eventErr) && eventErr.Event.Event == "error"
+			},
+		},
+		"bad data": {
+			Body: "data: {\"text\":\n\n",
+			Err: func(err error) bool {
+				var syntaxErr *json.SyntaxError
+				return errors.As(err, &syntaxErr)
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+
+	for name, v := range map[string]interface{}{
+		"not a channel":   &sseMessage{},
+		"nil channel":     (chan sseMessage)(nil),
+		"receive channel": make(<-chan sseMessage),
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := decoders.EventStream(strings.NewReader("data: {}\n\n"), v)
+			if !errors.Is(err, decoders.ErrNotEventChannel) {
+				t.Errorf("error, expected %v, got %v", decoders.ErrNotEventChannel, err)
+			}
+		})
+	}
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		body := decoders.WithContext(strings.NewReader("data: {}\n\n"), ctx)
+		// nothing receives, so only the context stops the send
+		err := decoders.EventStream(body, make(chan decoders.SSEEvent))
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("error, expected %v, got %v", context.Canceled, err)
+		}
+	})
+
+	t.Run("receiver done", func(t *testing.T) {
+		receiver := &sseReceiver{Messages: make(chan sseMessage), Done: make(chan struct{})}
+		close(receiver.Done)
+		err := decoders.EventStream(strings.NewReader("data: {}\n\ndata: {}\n\n"), receiver)
+		if !errors.Is(err, decoders.ErrReceiverDone) {
+			t.Errorf("error, expected %v, got %v", decoders.ErrReceiverDone, err)
+		}
+	})
+}
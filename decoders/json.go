@@ -6,7 +6,43 @@ import (
 	"io/ioutil"
 )
 
+// JSON decodes application/json bodies; it is the decoder NewJSON returns
+// without options.
 func JSON(r io.Reader, v interface{}) error {
 	defer io.Copy(ioutil.Discard, r)
-	return json.NewDecoder(r).Decode(v)
+	return decodeJSON(r, v, options{})
+}
+
+// NewJSON returns a JSON decoder tuned by opts; see UseNumber and Strict.
+func NewJSON(opts ...Option) Func {
+	o := newOptions(opts)
+	return func(r io.Reader, v interface{}) error {
+		defer io.Copy(ioutil.Discard, r)
+		return decodeJSON(r, v, o)
+	}
+}
+
+func decodeJSON(r io.Reader, v interface{}, o options) error {
+	dec := json.NewDecoder(r)
+	if o.useNumber {
+		dec.UseNumber()
+	}
+	if o.unknownFields {
+		dec.DisallowUnknownFields()
+	}
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if !o.trailingData {
+		return nil
+	}
+	switch _, err := dec.Token(); err.(type) {
+	case nil, *json.SyntaxError:
+		return ErrTrailingData
+	default:
+		if err == io.EOF {
+			return nil
+		}
+		return err
+	}
 }
@@ -1,6 +1,9 @@
 package decoders_test
 
 import (
+	"encoding/json"
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/gdey/chi-render/decoders"
@@ -21,3 +24,60 @@ func TestJSON(t *testing.T) {
 		t.Run(name, tc.Test(decoders.JSON))
 	}
 }
+
+func TestNewJSON(t *testing.T) {
+	type Doc struct {
+		Name  string      `json:"name"`
+		Count interface{} `json:"count"`
+	}
+	errIs := func(expected, got error) bool { return errors.Is(got, expected) }
+	isErr := func(_, got error) bool { return got != nil }
+
+	tests := map[string]struct {
+		Decoder decoders.Func
+		Case    test.Case
+	}{
+		"defaults": {
+			Decoder: decoders.NewJSON(),
+			Case:    test.NewStringCase(`{"name":"a","count":12,"extra":true} {}`, Doc{Name: "a", Count: float64(12)}),
+		},
+		"use number": {
+			Decoder: decoders.NewJSON(decoders.UseNumber()),
+			Case:    test.NewStringCase(`{"name":"a","count":9007199254740993}`, Doc{Name: "a", Count: json.Number("9007199254740993")}),
+		},
+		"strict": {
+			Decoder: decoders.NewJSON(decoders.Strict()),
+			Case:    test.NewStringCase(`{"name":"a","count":1}`+"\n", Doc{Name: "a", Count: float64(1)}),
+		},
+		"strict unknown field": {
+			Decoder: decoders.NewJSON(decoders.Strict()),
+			Case: test.Case{
+				R:             strings.NewReader(`{"name":"a","extra":true}`),
+				Value:         Doc{},
+				Err:           errors.New("unknown field"),
+				ErrComparator: isErr,
+			},
+		},
+		"strict trailing data": {
+			Decoder: decoders.NewJSON(decoders.Strict()),
+			Case: test.Case{
+				R:             strings.NewReader(`{"name":"a"} {"name":"b"}`),
+				Value:         Doc{},
+				Err:           decoders.ErrTrailingData,
+				ErrComparator: errIs,
+			},
+		},
+		"strict trailing garbage": {
+			Decoder: decoders.NewJSON(decoders.Strict()),
+			Case: test.Case{
+				R:             strings.NewReader(`{"name":"a"}}`),
+				Value:         Doc{},
+				Err:           decoders.ErrTrailingData,
+				ErrComparator: errIs,
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, tc.Case.Test(tc.Decoder))
+	}
+}
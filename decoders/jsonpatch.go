@@ -0,0 +1,119 @@
+package decoders
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+)
+
+var (
+	// ErrNotPatchTarget is returned by JSONPatch when the value to decode into
+	// is not a PatchTarget.
+	ErrNotPatchTarget = errors.New("decoders: json patch bodies can only be decoded into a PatchTarget")
+
+	// ErrInvalidPatch is returned by JSONPatch when an operation of the patch
+	// is malformed; the error names the operation.
+	ErrInvalidPatch = errors.New("decoders: invalid json patch")
+)
+
+// The operations of a JSON Patch, RFC 6902
+const (
+	PatchAdd     = "add"
+	PatchRemove  = "remove"
+	PatchReplace = "replace"
+	PatchMove    = "move"
+	PatchCopy    = "copy"
+	PatchTest    = "test"
+)
+
+// PatchOperation is an operation of a JSON Patch; Path and From are JSON
+// Pointers, RFC 6901.
+type PatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// Patch is a JSON Patch document, the operations are applied in order.
+type Patch []PatchOperation
+
+// PatchOperations returns the patch, so a *Patch, and structs that embed a
+// Patch, are PatchTargets.
+func (patch *Patch) PatchOperations() *Patch { return patch }
+
+// PatchTarget is implemented by values that JSONPatch can decode into. Embed a
+// Patch in the payload to bind patches with render.Bind:
+//
+//	type ArticlePatch struct {
+//		decoders.Patch
+//	}
+//
+//	func (p *ArticlePatch) Bind(r *http.Request) error { return nil }
+type PatchTarget interface {
+	PatchOperations() *Patch
+}
+
+// JSONPatch decodes application/json-patch+json bodies, RFC 6902, into a
+// PatchTarget, checking every operation has the members its op requires.
+func JSONPatch(r io.Reader, v interface{}) error {
+	defer io.Copy(ioutil.Discard, r)
+	target, ok := v.(PatchTarget)
+	if !ok {
+		return fmt.Errorf("%w; got %T", ErrNotPatchTarget, v)
+	}
+	var patch Patch
+	if err := json.NewDecoder(r).Decode(&patch); err != nil {
+		return err
+	}
+	for i, op := range patch {
+		if err := op.validate(); err != nil {
+			return fmt.Errorf("%w: operation %d: %v", ErrInvalidPatch, i, err)
+		}
+	}
+	*target.PatchOperations() = patch
+	return nil
+}
+
+// validate checks the operation has the members its op requires
+func (op PatchOperation) validate() error {
+	if err := validPointer(op.Path); err != nil {
+		return fmt.Errorf("path: %v", err)
+	}
+	switch op.Op {
+	case PatchAdd, PatchReplace, PatchTest:
+		if len(op.Value) == 0 {
+			return fmt.Errorf("%s requires a value", op.Op)
+		}
+	case PatchMove, PatchCopy:
+		if op.From == "" {
+			return fmt.Errorf("%s requires a from", op.Op)
+		}
+		if err := validPointer(op.From); err != nil {
+			return fmt.Errorf("from: %v", err)
+		}
+	case PatchRemove:
+	case "":
+		return errors.New("missing op")
+	default:
+		return fmt.Errorf("unknown op %q", op.Op)
+	}
+	return nil
+}
+
+// validPointer checks pointer is a JSON Pointer; the empty pointer is the
+// whole document.
+func validPointer(pointer string) error {
+	if pointer != "" && !strings.HasPrefix(pointer, "/") {
+		return fmt.Errorf("%q is not a json pointer", pointer)
+	}
+	for i := 0; i < len(pointer); i++ {
+		if pointer[i] == '~' && (i+1 == len(pointer) || (pointer[i+1] != '0' && pointer[i+1] != '1')) {
+			return fmt.Errorf("%q has an invalid escape", pointer)
+		}
+	}
+	return nil
+}
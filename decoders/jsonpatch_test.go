@@ -0,0 +1,82 @@
+package decoders_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gdey/chi-render/decoders"
+	"github.com/gdey/chi-render/decoders/test"
+)
+
+// articlePatch is a payload that embeds the patch
+type articlePatch struct {
+	decoders.Patch
+}
+
+func TestJSONPatch(t *testing.T) {
+	errIs := func(expected, got error) bool { return errors.Is(got, expected) }
+	patch := decoders.Patch{
+		{Op: decoders.PatchReplace, Path: "/title", Value: json.RawMessage(`"hello"`)},
+		{Op: decoders.PatchAdd, Path: "/tags/-", Value: json.RawMessage(`null`)},
+		{Op: decoders.PatchRemove, Path: "/a~1b"},
+		{Op: decoders.PatchMove, From: "/draft", Path: "/published"},
+	}
+	body := `[
+		{"op":"replace","path":"/title","value":"hello"},
+		{"op":"add","path":"/tags/-","value":null},
+		{"op":"remove","path":"/a~1b"},
+		{"op":"move","from":"/draft","path":"/published"}
+	]`
+
+	tests := map[string]test.Case{
+		"patch":    test.NewStringCase(body, patch),
+		"embedded": test.NewStringCase(body, articlePatch{Patch: patch}),
+		"empty":    test.NewStringCase(`[]`, decoders.Patch{}),
+		"missing value": {
+			R:             strings.NewReader(`[{"op":"add","path":"/title"}]`),
+			Value:         decoders.Patch{},
+			Err:           decoders.ErrInvalidPatch,
+			ErrComparator: errIs,
+		},
+		"missing from": {
+			R:             strings.NewReader(`[{"op":"copy","path":"/title"}]`),
+			Value:         decoders.Patch{},
+			Err:           decoders.ErrInvalidPatch,
+			ErrComparator: errIs,
+		},
+		"unknown op": {
+			R:             strings.NewReader(`[{"op":"merge","path":"/title","value":1}]`),
+			Value:         decoders.Patch{},
+			Err:           decoders.ErrInvalidPatch,
+			ErrComparator: errIs,
+		},
+		"invalid pointer": {
+			R:             strings.NewReader(`[{"op":"remove","path":"title"}]`),
+			Value:         decoders.Patch{},
+			Err:           decoders.ErrInvalidPatch,
+			ErrComparator: errIs,
+		},
+		"invalid escape": {
+			R:             strings.NewReader(`[{"op":"remove","path":"/a~2"}]`),
+			Value:         decoders.Patch{},
+			Err:           decoders.ErrInvalidPatch,
+			ErrComparator: errIs,
+		},
+		"not an array": {
+			R:     strings.NewReader(`{"op":"remove","path":"/title"}`),
+			Value: decoders.Patch{},
+			Err:   &json.UnmarshalTypeError{},
+		},
+		"not a patch target": {
+			R:             strings.NewReader(`[]`),
+			Value:         []decoders.PatchOperation{},
+			Err:           decoders.ErrNotPatchTarget,
+			ErrComparator: errIs,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, tc.Test(decoders.JSONPatch))
+	}
+}
@@ -0,0 +1,490 @@
+package decoders
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrMissingBoundary is returned by Multipart if the body has no boundary parameter
+var ErrMissingBoundary = errors.New("decoders: multipart body has no boundary")
+
+// MultipartMaxMemory is the number of bytes of the file parts Multipart keeps in
+// memory; the rest are stored in temporary files.
+var MultipartMaxMemory int64 = 32 << 20
+
+var fileHeaderType = reflect.TypeOf((*multipart.FileHeader)(nil))
+
+// FieldError is returned by the form decoders when a value sent for a field can
+// not be set on it.
+type FieldError struct {
+	// Field is the name of the form field
+	Field string
+	Err   error
+}
+
+func (err *FieldError) Error() string {
+	return fmt.Sprintf("decoders: form field %q: %v", err.Field, err.Err)
+}
+
+func (err *FieldError) Unwrap() error { return err.Err }
+
+// Multipart decodes multipart/form-data bodies into a struct. The boundary is
+// taken from the media type parameters attached with WithParams, which the
+// controller does for every request body.
+//
+// Value parts are set on the fields named by the form tag, or the field name,
+// converting them to strings, bools, numbers and encoding.TextUnmarshalers;
+// slice fields get every value of a repeated part. The conversions attached
+// with WithConverter, like the type converters registered with the controller,
+// are tried first. File parts are set on *multipart.FileHeader, or
+// []*multipart.FileHeader, fields.
+//
+// Forms are decoded the way browsers send them. Bool fields take "on", sent
+// for checkboxes without a value, as true, and the last value of a repeated
+// part, so a hidden input can precede a checkbox. Bool fields, of the struct
+// and its embedded structs, with no part are set to false, as unchecked
+// checkboxes are left out; use a *bool field to tell them apart. Pointer
+// fields are set to nil for empty values.
+//
+//	type Upload struct {
+//		Title  string                `form:"title"`
+//		Tags   []string              `form:"tag"`
+//		Public bool                  `form:"public"`
+//		File   *multipart.FileHeader `form:"file"`
+//	}
+//
+// Part names can also be paths into nested structs, slices and maps, using the
+// form names of the fields along the way: author.address.city, tags[0],
+// items[1].name, and attrs[color] or attrs.color for map keys. Slices grow to
+// fit the indexes, up to an index of 1000.
+//
+// Decoding into an empty interface sets it to a map[string]interface{} of the
+// parts by name; a string, or *multipart.FileHeader, for a single part, and a
+// []interface{} of them for a repeated part.
+//
+// File parts larger than MultipartMaxMemory are stored in temporary files.
+// They are removed if decoding fails; otherwise with the cleanup attached by
+// WithCleanup, which the controller runs once the handler of the request has
+// returned. Without one, payloads implementing MultipartFormSetter are given
+// the form to remove the files themselves.
+func Multipart(r io.Reader, v interface{}) error {
+	defer io.Copy(ioutil.Discard, r)
+	boundary := Params(r)["boundary"]
+	if boundary == "" {
+		return ErrMissingBoundary
+	}
+	form, err := multipart.NewReader(r, boundary).ReadForm(MultipartMaxMemory)
+	if err != nil {
+		return err
+	}
+	recordFormValues(r, url.Values(form.Value))
+	if err := decodeMultipart(form, v, Converter(r)); err != nil {
+		form.RemoveAll()
+		return err
+	}
+	if !registerCleanup(r, form.RemoveAll) {
+		setMultipartForm(v, form)
+	}
+	return nil
+}
+
+// decodeMultipart sets the values and files of the form on v; URLEncoded
+// decodes its values with it too.
+func decodeMultipart(form *multipart.Form, v interface{}, convert ConvertFunc) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() && rv.Elem().Kind() == reflect.Interface && rv.Elem().NumMethod() == 0 {
+		rv.Elem().Set(reflect.ValueOf(formMap(form)))
+		return nil
+	}
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("decoders: form expects a pointer to a struct, not %T", v)
+	}
+	return decodeForm(form, rv.Elem(), convert)
+}
+
+// MultipartFormSetter is implemented by payloads that take the decoded
+// multipart form, to remove its temporary files with RemoveAll once they are
+// done with them.
+type MultipartFormSetter interface {
+	SetMultipartForm(form *multipart.Form)
+}
+
+// setMultipartForm hands the form to the payload if it takes it; otherwise
+// its temporary files are left behind.
+func setMultipartForm(v interface{}, form *multipart.Form) {
+	if setter, ok := v.(MultipartFormSetter); ok {
+		setter.SetMultipartForm(form)
+	}
+}
+
+// formMap returns the values and files of the form by name, for payloads
+// without a struct
+func formMap(form *multipart.Form) map[string]interface{} {
+	m := make(map[string]interface{}, len(form.Value)+len(form.File))
+	for name, values := range form.Value {
+		if len(values) == 1 {
+			m[name] = values[0]
+			continue
+		}
+		all := make([]interface{}, len(values))
+		for i, value := range values {
+			all[i] = value
+		}
+		m[name] = all
+	}
+	for name, files := range form.File {
+		if len(files) == 1 {
+			m[name] = files[0]
+			continue
+		}
+		all := make([]interface{}, len(files))
+		for i, file := range files {
+			all[i] = file
+		}
+		m[name] = all
+	}
+	return m
+}
+
+// maxFormIndex is the largest slice index a form field name may have, so a
+// field like tags[100000000] can not make the decoder allocate a huge slice
+const maxFormIndex = 1000
+
+// decodeForm sets the values and files of the form on the struct. Field names
+// are paths into the struct; a name that matches a field as a whole is set on
+// that field. Values are converted with convert, if it is not nil, ahead of
+// the built in conversions.
+func decodeForm(form *multipart.Form, rv reflect.Value, convert ConvertFunc) error {
+	for _, name := range sortedKeys(form.Value) {
+		values := form.Value[name]
+		if len(values) == 0 {
+			continue
+		}
+		err := setFormField(rv, name, func(fv reflect.Value) (bool, error) {
+			if fv.Type() == fileHeaderType || (fv.Kind() == reflect.Slice && fv.Type().Elem() == fileHeaderType) {
+				return false, nil
+			}
+			return true, setFormValues(fv, values, convert)
+		}, convert)
+		if err != nil {
+			return &FieldError{Field: name, Err: err}
+		}
+	}
+	for _, name := range sortedKeys(form.File) {
+		files := form.File[name]
+		if len(files) == 0 {
+			continue
+		}
+		err := setFormField(rv, name, func(fv reflect.Value) (bool, error) {
+			switch {
+			case fv.Type() == fileHeaderType:
+				fv.Set(reflect.ValueOf(files[0]))
+			case fv.Kind() == reflect.Slice && fv.Type().Elem() == fileHeaderType:
+				fv.Set(reflect.ValueOf(files))
+			default:
+				return false, nil
+			}
+			return true, nil
+		}, convert)
+		if err != nil {
+			return &FieldError{Field: name, Err: err}
+		}
+	}
+	uncheckBools(form, rv)
+	return nil
+}
+
+func sortedKeys(m interface{}) []string {
+	keys := reflect.ValueOf(m).MapKeys()
+	names := make([]string, len(keys))
+	for i, key := range keys {
+		names[i] = key.String()
+	}
+	sort.Strings(names)
+	return names
+}
+
+// setFormField calls set with the field of the struct the form field name
+// points to, if there is one
+func setFormField(rv reflect.Value, name string, set func(fv reflect.Value) (bool, error), convert ConvertFunc) error {
+	if fv, ok := formField(rv, name); ok {
+		_, err := set(fv)
+		return err
+	}
+	_, err := setFormPath(rv, formPath(name), set, convert)
+	return err
+}
+
+// formField returns the field of the struct with the form name; the fields of
+// embedded structs are promoted.
+func formField(rv reflect.Value, name string) (reflect.Value, bool) {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+			if fv, ok := formField(rv.Field(i), name); ok {
+				return fv, true
+			}
+			continue
+		}
+		if fieldName, ok := formName(sf); ok && fieldName == name {
+			return rv.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// formName returns the form name of the struct field, from its form tag or its
+// name; ok is false for unexported fields and fields tagged "-".
+func formName(sf reflect.StructField) (name string, ok bool) {
+	if sf.PkgPath != "" {
+		return "", false
+	}
+	name = sf.Name
+	if tag, ok := sf.Tag.Lookup("form"); ok {
+		if i := strings.IndexByte(tag, ','); i >= 0 {
+			tag = tag[:i]
+		}
+		if tag == "-" {
+			return "", false
+		}
+		if tag != "" {
+			name = tag
+		}
+	}
+	return name, true
+}
+
+// uncheckBools sets the bool fields of the struct, and of its embedded structs,
+// the form has no value for to false, as browsers leave unchecked checkboxes
+// out of the form
+func uncheckBools(form *multipart.Form, rv reflect.Value) {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+			uncheckBools(form, rv.Field(i))
+			continue
+		}
+		if sf.Type.Kind() != reflect.Bool {
+			continue
+		}
+		if name, ok := formName(sf); ok && len(form.Value[name]) == 0 {
+			rv.Field(i).SetBool(false)
+		}
+	}
+}
+
+// formPath splits a form field name into the field names, slice indexes and map
+// keys of its path: user.address.city, tags[0], items[1].name and attrs[color]
+// are all paths. Empty brackets, as in tags[], are dropped.
+func formPath(name string) []string {
+	var path []string
+	for name != "" {
+		i := strings.IndexAny(name, ".[")
+		if i < 0 {
+			return append(path, name)
+		}
+		if i > 0 {
+			path = append(path, name[:i])
+		}
+		if name[i] == '.' {
+			name = name[i+1:]
+			continue
+		}
+		end := strings.IndexByte(name[i:], ']')
+		if end < 0 {
+			// not a bracket, but part of the name
+			return append(path, name[i:])
+		}
+		if key := name[i+1 : i+end]; key != "" {
+			path = append(path, key)
+		}
+		name = name[i+end+1:]
+	}
+	return path
+}
+
+// setFormPath follows the path from v, allocating the pointers, growing the
+// slices and adding the map entries on the way, and calls set with the value
+// at its end. Nothing is allocated if set is not called, or does not set the
+// value; set reports whether it did. Map keys are converted with convert.
+func setFormPath(v reflect.Value, path []string, set func(fv reflect.Value) (bool, error), convert ConvertFunc) (bool, error) {
+	if len(path) == 0 {
+		return set(v)
+	}
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.Type() == fileHeaderType {
+			return false, nil
+		}
+		elem := v
+		if v.IsNil() {
+			elem = reflect.New(v.Type().Elem())
+		}
+		ok, err := setFormPath(elem.Elem(), path, set, convert)
+		if ok && err == nil && v.IsNil() {
+			v.Set(elem)
+		}
+		return ok, err
+
+	case reflect.Struct:
+		fv, ok := formField(v, path[0])
+		if !ok {
+			return false, nil
+		}
+		return setFormPath(fv, path[1:], set, convert)
+
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return false, nil
+		}
+		i, err := strconv.Atoi(path[0])
+		switch {
+		case err != nil || i < 0:
+			return false, fmt.Errorf("invalid index %q", path[0])
+		case i > maxFormIndex:
+			return false, fmt.Errorf("index %d exceeds the limit of %d", i, maxFormIndex)
+		case v.Kind() == reflect.Array && i >= v.Len():
+			return false, fmt.Errorf("index %d is out of bounds", i)
+		}
+		elem := reflect.New(v.Type().Elem()).Elem()
+		if i < v.Len() {
+			elem.Set(v.Index(i))
+		}
+		ok, err := setFormPath(elem, path[1:], set, convert)
+		if !ok || err != nil {
+			return ok, err
+		}
+		if i >= v.Len() {
+			grown := reflect.MakeSlice(v.Type(), i+1, i+1)
+			reflect.Copy(grown, v)
+			v.Set(grown)
+		}
+		v.Index(i).Set(elem)
+		return true, nil
+
+	case reflect.Map:
+		key := reflect.New(v.Type().Key()).Elem()
+		if err := setFormValue(key, path[0], convert); err != nil {
+			return false, fmt.Errorf("invalid key %q: %v", path[0], err)
+		}
+		elem := reflect.New(v.Type().Elem()).Elem()
+		if existing := v.MapIndex(key); existing.IsValid() {
+			elem.Set(existing)
+		}
+		ok, err := setFormPath(elem, path[1:], set, convert)
+		if !ok || err != nil {
+			return ok, err
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+		v.SetMapIndex(key, elem)
+		return true, nil
+
+	default:
+		return false, nil
+	}
+}
+
+// setFormValues sets the values on the field; slices, other than []byte, get
+// every value. Other fields get the first value, except for bools, which get
+// the last: a hidden input ahead of a checkbox of the same name is sent for
+// the box when it is not checked.
+func setFormValues(fv reflect.Value, values []string, convert ConvertFunc) error {
+	if fv.Kind() != reflect.Slice || fv.Type().Elem().Kind() == reflect.Uint8 {
+		rt := fv.Type()
+		for rt.Kind() == reflect.Ptr {
+			rt = rt.Elem()
+		}
+		if rt.Kind() == reflect.Bool {
+			return setFormValue(fv, values[len(values)-1], convert)
+		}
+		return setFormValue(fv, values[0], convert)
+	}
+	items := reflect.MakeSlice(fv.Type(), len(values), len(values))
+	for i, value := range values {
+		if err := setFormValue(items.Index(i), value, convert); err != nil {
+			return err
+		}
+	}
+	fv.Set(items)
+	return nil
+}
+
+// setFormValue sets the value on the field, with convert if it converts the
+// type of the field; pointers are set to nil for an empty value, as browsers
+// send one for empty inputs.
+func setFormValue(fv reflect.Value, s string, convert ConvertFunc) error {
+	if convert != nil {
+		if ok, err := convert(s, fv); ok {
+			return err
+		}
+	}
+	if fv.Kind() == reflect.Ptr {
+		if s == "" {
+			fv.Set(reflect.Zero(fv.Type()))
+			return nil
+		}
+		elem := reflect.New(fv.Type().Elem())
+		if err := setFormValue(elem.Elem(), s, convert); err != nil {
+			return err
+		}
+		fv.Set(elem)
+		return nil
+	}
+	if unmarshaler, ok := fv.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return unmarshaler.UnmarshalText([]byte(s))
+	}
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(s)
+	case reflect.Slice: // []byte
+		fv.SetBytes([]byte(s))
+	case reflect.Bool:
+		// "on" is what browsers send for checked checkboxes without a value
+		switch s {
+		case "on":
+			fv.SetBool(true)
+		case "off":
+			fv.SetBool(false)
+		default:
+			b, err := strconv.ParseBool(s)
+			if err != nil {
+				return err
+			}
+			fv.SetBool(b)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(s, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported type %v", fv.Type())
+	}
+	return nil
+}
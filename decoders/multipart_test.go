@@ -0,0 +1,343 @@
+package decoders_test
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"mime/multipart"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gdey/chi-render/decoders"
+	"github.com/gdey/chi-render/decoders/test"
+)
+
+type upload struct {
+	Title  string                  `form:"title"`
+	Tags   []string                `form:"tag"`
+	Count  int                     `form:"count"`
+	Public *bool                   `form:"public"`
+	File   *multipart.FileHeader   `form:"file"`
+	Extras []*multipart.FileHeader `form:"extra"`
+	Ignore string                  `form:"-"`
+}
+
+func newMultipartCase(t *testing.T, fields map[string][]string, files map[string][]string) (body *bytes.Buffer, params map[string]string) {
+	body = new(bytes.Buffer)
+	mw := multipart.NewWriter(body)
+	for name, values := range fields {
+		for _, value := range values {
+			if err := mw.WriteField(name, value); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	for name, contents := range files {
+		for i, content := range contents {
+			fw, err := mw.CreateFormFile(name, name+string(rune('a'+i))+".txt")
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, _ = fw.Write([]byte(content))
+		}
+	}
+	if err := mw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return body, map[string]string{"boundary": mw.Boundary()}
+}
+
+func readFile(t *testing.T, fh *multipart.FileHeader) string {
+	f, err := fh.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	b, _ := ioutil.ReadAll(f)
+	return string(b)
+}
+
+func TestMultipart(t *testing.T) {
+	body, params := newMultipartCase(t,
+		map[string][]string{"title": {"Hi"}, "tag": {"go", "http"}, "count": {"3"}, "public": {"true"}, "-": {"no"}},
+		map[string][]string{"file": {"hello"}, "extra": {"one", "two"}},
+	)
+	var got upload
+	if err := decoders.Multipart(decoders.WithParams(body, params), &got); err != nil {
+		t.Fatalf("error, expected nil, got %v", err)
+	}
+	if got.Title != "Hi" || strings.Join(got.Tags, ",") != "go,http" || got.Count != 3 || got.Public == nil || !*got.Public || got.Ignore != "" {
+		t.Errorf("values, got %+v", got)
+	}
+	if got.File == nil || got.File.Filename != "filea.txt" || readFile(t, got.File) != "hello" {
+		t.Fatalf("file, expected filea.txt, got %+v", got.File)
+	}
+	if len(got.Extras) != 2 || readFile(t, got.Extras[1]) != "two" {
+		t.Errorf("extras, expected 2 files, got %v", len(got.Extras))
+	}
+}
+
+func TestMultipartErrors(t *testing.T) {
+	body, params := newMultipartCase(t, map[string][]string{"count": {"many"}}, nil)
+	tests := map[string]test.Case{
+		"no boundary": {
+			R:     strings.NewReader("--x--"),
+			Value: upload{},
+			Err:   decoders.ErrMissingBoundary,
+		},
+		"bad value": {
+			R:     decoders.WithParams(body, params),
+			Value: upload{},
+			Err:   strconv.ErrSyntax,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, tc.Test(decoders.Multipart))
+	}
+}
+
+type formAddress struct {
+	City string `form:"city"`
+}
+
+type formItem struct {
+	Name  string                `form:"name"`
+	Count int                   `form:"count"`
+	File  *multipart.FileHeader `form:"file"`
+}
+
+type nestedForm struct {
+	Name    string                 `form:"name"`
+	Address *formAddress           `form:"address"`
+	Tags    []string               `form:"tags"`
+	Items   []formItem             `form:"items"`
+	Attrs   map[string]string      `form:"attrs"`
+	Places  map[string]formAddress `form:"places"`
+	Flat    string                 `form:"legacy.flat"`
+	Unused  *formAddress           `form:"unused"`
+}
+
+func TestMultipartNested(t *testing.T) {
+	type tcase struct {
+		Fields   map[string][]string
+		Files    map[string][]string
+		Expected nestedForm
+		// ItemFiles are the contents of the files of the items, by index
+		ItemFiles map[int]string
+		Err       string
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			body, params := newMultipartCase(t, tc.Fields, tc.Files)
+			var got nestedForm
+			err := decoders.Multipart(decoders.WithParams(body, params), &got)
+			if tc.Err != "" {
+				var fieldErr *decoders.FieldError
+				if !errors.As(err, &fieldErr) || !strings.Contains(err.Error(), tc.Err) {
+					t.Errorf("error, expected a field error with %q, got %v", tc.Err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error, expected nil, got %v", err)
+			}
+			var files map[int]string
+			for i := range got.Items {
+				if got.Items[i].File != nil {
+					if files == nil {
+						files = make(map[int]string)
+					}
+					files[i] = readFile(t, got.Items[i].File)
+					got.Items[i].File = nil
+				}
+			}
+			if !reflect.DeepEqual(got, tc.Expected) {
+				t.Errorf("values, expected %+v, got %+v", tc.Expected, got)
+			}
+			if !reflect.DeepEqual(files, tc.ItemFiles) {
+				t.Errorf("item files, expected %v, got %v", tc.ItemFiles, files)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"nested": {
+			Fields: map[string][]string{
+				"name":              {"gdey"},
+				"address.city":      {"Lisbon"},
+				"tags[1]":           {"http"},
+				"tags[0]":           {"go"},
+				"items[1].name":     {"b"},
+				"items[0].name":     {"a"},
+				"items[0].count":    {"2"},
+				"attrs[color]":      {"red"},
+				"attrs.size":        {"xl"},
+				"places[home].city": {"Porto"},
+				"legacy.flat":       {"kept"},
+				"unused.country":    {"none"},
+			},
+			Expected: nestedForm{
+				Name:    "gdey",
+				Address: &formAddress{City: "Lisbon"},
+				Tags:    []string{"go", "http"},
+				Items:   []formItem{{Name: "a", Count: 2}, {Name: "b"}},
+				Attrs:   map[string]string{"color": "red", "size": "xl"},
+				Places:  map[string]formAddress{"home": {City: "Porto"}},
+				Flat:    "kept",
+			},
+		},
+		"repeated and empty brackets": {
+			Fields:   map[string][]string{"tags[]": {"go", "http"}},
+			Expected: nestedForm{Tags: []string{"go", "http"}},
+		},
+		"nested files": {
+			Fields:    map[string][]string{"items[0].name": {"a"}},
+			Files:     map[string][]string{"items[1].file": {"hello"}},
+			Expected:  nestedForm{Items: []formItem{{Name: "a"}, {}}},
+			ItemFiles: map[int]string{1: "hello"},
+		},
+		"bad nested value": {
+			Fields: map[string][]string{"items[0].count": {"many"}},
+			Err:    `"items[0].count"`,
+		},
+		"bad index": {
+			Fields: map[string][]string{"items[first].name": {"a"}},
+			Err:    "invalid index",
+		},
+		"index too large": {
+			Fields: map[string][]string{"tags[100000]": {"a"}},
+			Err:    "exceeds the limit",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+type browserFlags struct {
+	Notify bool `form:"notify"`
+}
+
+type browserForm struct {
+	browserFlags
+	Subscribe bool     `form:"subscribe"`
+	Remember  bool     `form:"remember"`
+	Terms     *bool    `form:"terms"`
+	Age       *int     `form:"age"`
+	Nickname  *string  `form:"nickname"`
+	Colors    []string `form:"color"`
+	Ignored   bool     `form:"-"`
+}
+
+func TestMultipartBrowserForm(t *testing.T) {
+	type tcase struct {
+		Fields map[string][]string
+		// Initial is the value the form is decoded onto
+		Initial  browserForm
+		Expected browserForm
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			body, params := newMultipartCase(t, tc.Fields, nil)
+			got := tc.Initial
+			if err := decoders.Multipart(decoders.WithParams(body, params), &got); err != nil {
+				t.Fatalf("error, expected nil, got %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.Expected) {
+				t.Errorf("values, expected %+v, got %+v", tc.Expected, got)
+			}
+		}
+	}
+
+	yes, age, nickname := true, 30, "gd"
+	tests := map[string]tcase{
+		"checked boxes": {
+			Fields: map[string][]string{
+				"subscribe": {"on"},
+				"notify":    {"on"},
+				"remember":  {"false", "true"},
+				"terms":     {"on"},
+			},
+			Expected: browserForm{
+				browserFlags: browserFlags{Notify: true},
+				Subscribe:    true,
+				Remember:     true,
+				Terms:        &yes,
+			},
+		},
+		"unchecked boxes": {
+			Fields:   map[string][]string{"remember": {"false"}, "color": {"red"}},
+			Initial:  browserForm{browserFlags: browserFlags{Notify: true}, Subscribe: true, Remember: true, Terms: &yes, Ignored: true},
+			Expected: browserForm{Terms: &yes, Colors: []string{"red"}, Ignored: true},
+		},
+		"empty values": {
+			Fields:   map[string][]string{"age": {""}, "nickname": {""}, "terms": {""}},
+			Initial:  browserForm{Age: &age, Nickname: &nickname, Terms: &yes},
+			Expected: browserForm{},
+		},
+		"multiple inputs": {
+			Fields:   map[string][]string{"color": {"red", "green", "blue"}, "age": {"30"}, "nickname": {"gd"}},
+			Expected: browserForm{Colors: []string{"red", "green", "blue"}, Age: &age, Nickname: &nickname},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+// formUpload takes the multipart form, to remove its temporary files
+type formUpload struct {
+	File *multipart.FileHeader `form:"file"`
+	form *multipart.Form
+}
+
+func (u *formUpload) SetMultipartForm(form *multipart.Form) { u.form = form }
+
+func TestMultipartCleanup(t *testing.T) {
+	defer func(max int64) { decoders.MultipartMaxMemory = max }(decoders.MultipartMaxMemory)
+	// keep the files on disk
+	decoders.MultipartMaxMemory = 0
+
+	t.Run("registered", func(t *testing.T) {
+		body, params := newMultipartCase(t, nil, map[string][]string{"file": {"hello"}})
+		var cleanups []func() error
+		r := decoders.WithCleanup(decoders.WithParams(body, params), func(cleanup func() error) {
+			cleanups = append(cleanups, cleanup)
+		})
+		var got upload
+		if err := decoders.Multipart(r, &got); err != nil {
+			t.Fatalf("error, expected nil, got %v", err)
+		}
+		if len(cleanups) != 1 {
+			t.Fatalf("cleanups, expected 1, got %v", len(cleanups))
+		}
+		if readFile(t, got.File) != "hello" {
+			t.Errorf("file, expected hello before the cleanup")
+		}
+		if err := cleanups[0](); err != nil {
+			t.Errorf("cleanup, expected nil, got %v", err)
+		}
+		if f, err := got.File.Open(); err == nil {
+			f.Close()
+			t.Errorf("file, expected the temporary file to be removed")
+		}
+	})
+
+	t.Run("setter", func(t *testing.T) {
+		body, params := newMultipartCase(t, nil, map[string][]string{"file": {"hello"}})
+		var got formUpload
+		if err := decoders.Multipart(decoders.WithParams(body, params), &got); err != nil {
+			t.Fatalf("error, expected nil, got %v", err)
+		}
+		if got.form == nil || got.form.File["file"][0] != got.File {
+			t.Fatalf("form, expected the decoded form, got %+v", got.form)
+		}
+		if err := got.form.RemoveAll(); err != nil {
+			t.Errorf("remove, expected nil, got %v", err)
+		}
+	})
+}
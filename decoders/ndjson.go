@@ -0,0 +1,208 @@
+package decoders
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+)
+
+// ErrNotRecordTarget is returned by NDJSON when the value to decode into is
+// neither a pointer to a slice, a channel, a RecordChannel nor a RecordHandler.
+var ErrNotRecordTarget = errors.New("decoders: ndjson bodies can only be decoded into a slice, a channel, a RecordChannel or a RecordHandler")
+
+// RecordHandler is implemented by values that handle the records of a NDJSON
+// body one at a time, as they are read, so large uploads do not have to be held
+// in memory.
+//
+//	type Import struct{ Count int }
+//
+//	func (imp *Import) HandleRecord(decode func(v interface{}) error) error {
+//		var row Row
+//		if err := decode(&row); err != nil {
+//			return err
+//		}
+//		imp.Count++
+//		return store(row)
+//	}
+type RecordHandler interface {
+	// HandleRecord is called for every record in the body, with a func that
+	// decodes the record into its argument. Returning an error stops the
+	// decoding of the body.
+	HandleRecord(decode func(v interface{}) error) error
+}
+
+// RecordChannel is implemented by payloads that take the records of a NDJSON
+// body on a channel, as they are read, so they can be bound with render.Bind:
+//
+//	type Ingest struct {
+//		Rows chan Row
+//	}
+//
+//	func (ingest *Ingest) RecordChannel() interface{} { return ingest.Rows }
+//	func (ingest *Ingest) Bind(r *http.Request) error { return nil }
+type RecordChannel interface {
+	// RecordChannel returns the channel the records are sent on
+	RecordChannel() interface{}
+}
+
+// RecordReceiver can be implemented by RecordChannels, and EventChannels, whose
+// receiver may stop before the whole body has been read, for example on an
+// error; the decoder stops sending once the channel RecordsDone returns is
+// closed, and fails with ErrReceiverDone.
+type RecordReceiver interface {
+	RecordsDone() <-chan struct{}
+}
+
+// ErrReceiverDone is returned by NDJSON and EventStream when the receiver of a
+// RecordReceiver is done before the body has been read
+var ErrReceiverDone = errors.New("decoders: the receiver of the records is done")
+
+// RecordError is returned by NDJSON when a record can not be decoded or handled
+type RecordError struct {
+	// Line is the line of the record in the body, starting at 1
+	Line int
+	Err  error
+}
+
+func (err *RecordError) Error() string {
+	return fmt.Sprintf("decoders: ndjson record on line %d: %v", err.Line, err.Err)
+}
+
+func (err *RecordError) Unwrap() error { return err.Err }
+
+// NDJSON decodes application/x-ndjson bodies, one JSON value per line, either
+// appending every record to the slice v points to, passing them one at a time
+// to v's HandleRecord method if v is a RecordHandler, or sending them on the
+// channel v is, or that v's RecordChannel method returns. Blank lines are
+// skipped. It is the decoder NewNDJSON returns without options.
+//
+// Sends on the channel block, so the body is read no faster than the records
+// are received: the capacity of the channel is how many records the decoder
+// reads ahead of the receiver. A send is abandoned, and the decoding fails,
+// once the context attached with WithContext, the context of the request for
+// the controller, is done, or the receiver of a RecordReceiver is. The channel
+// is not closed.
+//
+//	ingest := &Ingest{Rows: make(chan Row, 64)}
+//	go func() {
+//		for row := range ingest.Rows {
+//			...
+//		}
+//	}()
+//	err := render.Bind(r, ingest)
+//	close(ingest.Rows)
+func NDJSON(r io.Reader, v interface{}) error {
+	defer io.Copy(ioutil.Discard, r)
+	return decodeNDJSON(r, v, options{})
+}
+
+// NewNDJSON returns a NDJSON decoder tuned by opts, which apply to every
+// record; see UseNumber and Strict.
+func NewNDJSON(opts ...Option) Func {
+	o := newOptions(opts)
+	return func(r io.Reader, v interface{}) error {
+		defer io.Copy(ioutil.Discard, r)
+		return decodeNDJSON(r, v, o)
+	}
+}
+
+func decodeNDJSON(r io.Reader, v interface{}, o options) error {
+	if handler, ok := v.(RecordHandler); ok {
+		return eachRecord(r, o, handler.HandleRecord)
+	}
+
+	var receiverDone <-chan struct{}
+	if rc, ok := v.(RecordChannel); ok {
+		if receiver, ok := v.(RecordReceiver); ok {
+			receiverDone = receiver.RecordsDone()
+		}
+		v = rc.RecordChannel()
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Chan {
+		if rv.IsNil() || rv.Type().ChanDir()&reflect.SendDir == 0 {
+			return fmt.Errorf("%w; got %T", ErrNotRecordTarget, v)
+		}
+		ctx := Context(r)
+		return eachRecord(r, o, func(decode func(v interface{}) error) error {
+			record := reflect.New(rv.Type().Elem())
+			if err := decode(record.Interface()); err != nil {
+				return err
+			}
+			return send(ctx, receiverDone, rv, record.Elem())
+		})
+	}
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("%w; got %T", ErrNotRecordTarget, v)
+	}
+	slice := rv.Elem()
+	records := reflect.MakeSlice(slice.Type(), 0, 0)
+	err := eachRecord(r, o, func(decode func(v interface{}) error) error {
+		record := reflect.New(slice.Type().Elem())
+		if err := decode(record.Interface()); err != nil {
+			return err
+		}
+		records = reflect.Append(records, record.Elem())
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	slice.Set(records)
+	return nil
+}
+
+// eachRecord calls fn for every non blank line of r
+func eachRecord(r io.Reader, o options, fn func(decode func(v interface{}) error) error) error {
+	br := bufio.NewReader(r)
+	for line := 1; ; line++ {
+		b, err := br.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+		if record := bytes.TrimSpace(b); len(record) > 0 {
+			decode := func(v interface{}) error { return unmarshalRecord(record, v, o) }
+			if ferr := fn(decode); ferr != nil {
+				return &RecordError{Line: line, Err: ferr}
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+	}
+}
+
+// unmarshalRecord decodes the record into v; like json.Unmarshal, data after
+// the value is an error.
+func unmarshalRecord(record []byte, v interface{}, o options) error {
+	if !o.useNumber && !o.unknownFields {
+		return json.Unmarshal(record, v)
+	}
+	o.trailingData = true
+	return decodeJSON(bytes.NewReader(record), v, o)
+}
+
+// send sends the value on the channel, unless the context, or the receiver, is
+// done first
+func send(ctx context.Context, receiverDone <-chan struct{}, ch, value reflect.Value) error {
+	// a nil channel is never selected
+	chosen, _, _ := reflect.Select([]reflect.SelectCase{
+		{Dir: reflect.SelectSend, Chan: ch, Send: value},
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())},
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(receiverDone)},
+	})
+	switch chosen {
+	case 1:
+		return ctx.Err()
+	case 2:
+		return ErrReceiverDone
+	}
+	return nil
+}
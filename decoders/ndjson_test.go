@@ -0,0 +1,223 @@
+package decoders_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gdey/chi-render/decoders"
+	"github.com/gdey/chi-render/decoders/test"
+)
+
+type ndjsonRow struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// ndjsonImport collects the names of the records it handles
+type ndjsonImport struct {
+	Names []string
+}
+
+func (imp *ndjsonImport) HandleRecord(decode func(v interface{}) error) error {
+	var row ndjsonRow
+	if err := decode(&row); err != nil {
+		return err
+	}
+	if row.ID == 0 {
+		return errors.New("missing id")
+	}
+	imp.Names = append(imp.Names, row.Name)
+	return nil
+}
+
+func TestNDJSON(t *testing.T) {
+	errIs := func(expected, got error) bool { return errors.Is(got, expected) }
+	var syntaxErr *json.SyntaxError
+
+	tests := map[string]test.Case{
+		"slice": test.NewStringCase(
+			"{\"id\":1,\"name\":\"a\"}\n\n{\"id\":2,\"name\":\"b\"}",
+			[]ndjsonRow{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}},
+		),
+		"empty": test.NewStringCase("", []ndjsonRow{}),
+		"handler": test.NewStringCase(
+			"{\"id\":1,\"name\":\"a\"}\r\n{\"id\":2,\"name\":\"b\"}\r\n",
+			ndjsonImport{Names: []string{"a", "b"}},
+		),
+		"syntax error": {
+			R:     strings.NewReader("{\"id\":1}\n{\"id\":\n"),
+			Value: []ndjsonRow{},
+			Err:   syntaxErr,
+			ErrComparator: func(_, got error) bool {
+				var recErr *decoders.RecordError
+				return errors.As(got, &recErr) && recErr.Line == 2 && errors.As(got, &syntaxErr)
+			},
+		},
+		"handler error": {
+			R:     strings.NewReader("{\"id\":1}\n{\"name\":\"b\"}\n"),
+			Value: ndjsonImport{},
+			Err:   errors.New("missing id"),
+			ErrComparator: func(_, got error) bool {
+				var recErr *decoders.RecordError
+				return errors.As(got, &recErr) && recErr.Line == 2
+			},
+		},
+		"not a slice": {
+			R:             strings.NewReader("{}\n"),
+			Value:         ndjsonRow{},
+			Err:           decoders.ErrNotRecordTarget,
+			ErrComparator: errIs,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, tc.Test(decoders.NDJSON))
+	}
+}
+
+func TestNewNDJSON(t *testing.T) {
+	tests := map[string]struct {
+		Decoder decoders.Func
+		Case    test.Case
+	}{
+		"use number": {
+			Decoder: decoders.NewNDJSON(decoders.UseNumber()),
+			Case:    test.NewStringCase("1\n2.5\n", []interface{}{json.Number("1"), json.Number("2.5")}),
+		},
+		"strict unknown field": {
+			Decoder: decoders.NewNDJSON(decoders.Strict()),
+			Case: test.Case{
+				R:     strings.NewReader("{\"id\":1}\n{\"id\":2,\"extra\":true}\n"),
+				Value: []ndjsonRow{},
+				Err:   errors.New("unknown field"),
+				ErrComparator: func(_, got error) bool {
+					var recErr *decoders.RecordError
+					return errors.As(got, &recErr) && recErr.Line == 2
+				},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, tc.Case.Test(tc.Decoder))
+	}
+}
+
+// ndjsonIngest takes the records on a channel
+type ndjsonIngest struct {
+	Rows chan ndjsonRow
+}
+
+func (ingest *ndjsonIngest) RecordChannel() interface{} { return ingest.Rows }
+
+// ndjsonReceiver takes the records on a channel until Done is closed
+type ndjsonReceiver struct {
+	Rows chan ndjsonRow
+	Done chan struct{}
+}
+
+func (receiver *ndjsonReceiver) RecordChannel() interface{}   { return receiver.Rows }
+func (receiver *ndjsonReceiver) RecordsDone() <-chan struct{} { return receiver.Done }
+
+func TestNDJSONChannel(t *testing.T) {
+	type tcase struct {
+		Body string
+		// Buffer is the capacity of the channel
+		Buffer int
+		// Raw decodes into the channel, rather than into a RecordChannel
+		Raw      bool
+		Expected []ndjsonRow
+		// ErrLine is the line of the expected *RecordError; zero if no error is
+		// expected
+		ErrLine int
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			ingest := &ndjsonIngest{Rows: make(chan ndjsonRow, tc.Buffer)}
+			done := make(chan []ndjsonRow)
+			go func() {
+				var rows []ndjsonRow
+				for row := range ingest.Rows {
+					rows = append(rows, row)
+				}
+				done <- rows
+			}()
+
+			var v interface{} = ingest
+			if tc.Raw {
+				v = (chan<- ndjsonRow)(ingest.Rows)
+			}
+			err := decoders.NDJSON(strings.NewReader(tc.Body), v)
+			close(ingest.Rows)
+			rows := <-done
+
+			if tc.ErrLine != 0 {
+				var recErr *decoders.RecordError
+				if !errors.As(err, &recErr) || recErr.Line != tc.ErrLine {
+					t.Errorf("error, expected a record error on line %d, got %v", tc.ErrLine, err)
+				}
+			} else if err != nil {
+				t.Errorf("error, expected nil, got %v", err)
+				return
+			}
+			if !reflect.DeepEqual(rows, tc.Expected) {
+				t.Errorf("rows, expected %+v, got %+v", tc.Expected, rows)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"unbuffered": {
+			Body:     "{\"id\":1,\"name\":\"a\"}\n\n{\"id\":2,\"name\":\"b\"}\n",
+			Expected: []ndjsonRow{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}},
+		},
+		"buffered": {
+			Body:     "{\"id\":1}\n{\"id\":2}\n{\"id\":3}\n",
+			Buffer:   2,
+			Expected: []ndjsonRow{{ID: 1}, {ID: 2}, {ID: 3}},
+		},
+		"send only channel": {
+			Body:     "{\"id\":1}\n",
+			Raw:      true,
+			Expected: []ndjsonRow{{ID: 1}},
+		},
+		"records before the error": {
+			Body:     "{\"id\":1}\n{\"id\":\n{\"id\":3}\n",
+			Expected: []ndjsonRow{{ID: 1}},
+			ErrLine:  2,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		body := decoders.WithContext(strings.NewReader("{\"id\":1}\n"), ctx)
+		// nothing receives, so only the context stops the send
+		err := decoders.NDJSON(body, make(chan ndjsonRow))
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("error, expected %v, got %v", context.Canceled, err)
+		}
+	})
+
+	t.Run("receiver done", func(t *testing.T) {
+		receiver := &ndjsonReceiver{Rows: make(chan ndjsonRow), Done: make(chan struct{})}
+		close(receiver.Done)
+		err := decoders.NDJSON(strings.NewReader("{\"id\":1}\n{\"id\":2}\n"), receiver)
+		if !errors.Is(err, decoders.ErrReceiverDone) {
+			t.Errorf("error, expected %v, got %v", decoders.ErrReceiverDone, err)
+		}
+	})
+
+	t.Run("receive only channel", func(t *testing.T) {
+		err := decoders.NDJSON(strings.NewReader("{}\n"), make(<-chan ndjsonRow))
+		if !errors.Is(err, decoders.ErrNotRecordTarget) {
+			t.Errorf("error, expected %v, got %v", decoders.ErrNotRecordTarget, err)
+		}
+	})
+}
@@ -0,0 +1,47 @@
+package decoders
+
+import "errors"
+
+// ErrTrailingData is returned by Strict decoders when the body has more data
+// after the value that was decoded.
+var ErrTrailingData = errors.New("decoders: unexpected data after the value")
+
+// Option tunes the decoders returned by the constructors, like NewJSON.
+// Options that do not apply to a format are ignored by its decoder.
+//
+//	ctrl.SetDecoder(render.ContentTypeJSON, decoders.NewJSON(decoders.UseNumber(), decoders.Strict()))
+type Option func(*options)
+
+type options struct {
+	// useNumber decodes numbers into interface{} values as json.Number
+	useNumber bool
+	// unknownFields rejects fields the value being decoded into does not have
+	unknownFields bool
+	// trailingData rejects bodies with data after the value
+	trailingData bool
+}
+
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// UseNumber has numbers decoded into interface{} values as json.Number,
+// rather than float64, so large integers do not lose their precision.
+// It applies to JSON, NDJSON and YAML.
+func UseNumber() Option {
+	return func(o *options) { o.useNumber = true }
+}
+
+// Strict has bodies rejected if they have fields the value being decoded into
+// does not have, or data after the value, like a second document. XML decoders
+// only reject the data after the value.
+func Strict() Option {
+	return func(o *options) {
+		o.unknownFields = true
+		o.trailingData = true
+	}
+}
@@ -0,0 +1,146 @@
+package decoders
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"reflect"
+)
+
+// ConvertFunc converts a string, like the value of a form field, into dst, a
+// settable value. ok is false if it has no conversion for the type of dst, in
+// which case the decoder converts the string itself.
+type ConvertFunc func(s string, dst reflect.Value) (ok bool, err error)
+
+// paramsReader is a request body along with the media type parameters of its
+// Content-Type header, and the conversions for the values it holds
+type paramsReader struct {
+	io.Reader
+	params  map[string]string
+	convert ConvertFunc
+	cleanup func(cleanup func() error)
+	values  func(values url.Values)
+	ctx     context.Context
+}
+
+// withAttached returns body along with everything attached to from, like its
+// params, conversions and context
+func withAttached(body, from io.Reader) io.Reader {
+	pr, ok := from.(paramsReader)
+	if !ok {
+		return body
+	}
+	if _, ok := body.(paramsReader); ok {
+		// already carries them
+		return body
+	}
+	pr.Reader = body
+	return pr
+}
+
+// WithParams returns r along with the media type parameters of the request's
+// Content-Type header, like the boundary of multipart bodies, so decoders that
+// need them can get them with Params.
+func WithParams(r io.Reader, params map[string]string) io.Reader {
+	pr, ok := r.(paramsReader)
+	if !ok {
+		pr = paramsReader{Reader: r}
+	}
+	pr.params = params
+	return pr
+}
+
+// Params returns the media type parameters attached to r by WithParams; nil if
+// there are none.
+func Params(r io.Reader) map[string]string {
+	if pr, ok := r.(paramsReader); ok {
+		return pr.params
+	}
+	return nil
+}
+
+// WithConverter returns r along with the conversions the form decoders, like
+// Multipart, use for the values of the fields, ahead of their own; the
+// controller attaches its registered type converters this way.
+func WithConverter(r io.Reader, convert ConvertFunc) io.Reader {
+	pr, ok := r.(paramsReader)
+	if !ok {
+		pr = paramsReader{Reader: r}
+	}
+	pr.convert = convert
+	return pr
+}
+
+// Converter returns the conversions attached to r by WithConverter; nil if
+// there are none.
+func Converter(r io.Reader) ConvertFunc {
+	if pr, ok := r.(paramsReader); ok {
+		return pr.convert
+	}
+	return nil
+}
+
+// WithCleanup returns r along with register, which decoders that leave
+// resources behind for the decoded value, like the temporary files of
+// Multipart, call with the function that releases them. The controller
+// registers them to run once the handler of the request returns.
+func WithCleanup(r io.Reader, register func(cleanup func() error)) io.Reader {
+	pr, ok := r.(paramsReader)
+	if !ok {
+		pr = paramsReader{Reader: r}
+	}
+	pr.cleanup = register
+	return pr
+}
+
+// registerCleanup hands cleanup to the function attached to r by WithCleanup;
+// ok is false if there is none.
+func registerCleanup(r io.Reader, cleanup func() error) (ok bool) {
+	pr, isParams := r.(paramsReader)
+	if !isParams || pr.cleanup == nil {
+		return false
+	}
+	pr.cleanup(cleanup)
+	return true
+}
+
+// WithFormValues returns r along with record, which the form decoders, like
+// Multipart, call with the values of the form fields they read, files left
+// out; the controller uses them to check the CSRF token of the form, and to
+// fill the form back in when it fails to bind.
+func WithFormValues(r io.Reader, record func(values url.Values)) io.Reader {
+	pr, ok := r.(paramsReader)
+	if !ok {
+		pr = paramsReader{Reader: r}
+	}
+	pr.values = record
+	return pr
+}
+
+// recordFormValues hands the values to the function attached to r by
+// WithFormValues, if there is one
+func recordFormValues(r io.Reader, values url.Values) {
+	if pr, ok := r.(paramsReader); ok && pr.values != nil {
+		pr.values(values)
+	}
+}
+
+// WithContext returns r along with the context of the request, so decoders
+// that wait, like NDJSON sending on a channel, stop once it is done.
+func WithContext(r io.Reader, ctx context.Context) io.Reader {
+	pr, ok := r.(paramsReader)
+	if !ok {
+		pr = paramsReader{Reader: r}
+	}
+	pr.ctx = ctx
+	return pr
+}
+
+// Context returns the context attached to r by WithContext; the background
+// context if there is none.
+func Context(r io.Reader) context.Context {
+	if pr, ok := r.(paramsReader); ok && pr.ctx != nil {
+		return pr.ctx
+	}
+	return context.Background()
+}
@@ -0,0 +1,65 @@
+package decoders
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// ErrNotProtoMessage is the error that NotProtoMessageError values match using
+// errors.Is.
+var ErrNotProtoMessage = errors.New("decoders: bind target is not a proto.Message")
+
+// NotProtoMessageError is returned by Protobuf when the value to decode into is
+// not a proto.Message, nor a pointer to one.
+type NotProtoMessageError struct {
+	// Type is the type of the value to decode into
+	Type reflect.Type
+}
+
+func (err *NotProtoMessageError) Error() string {
+	return fmt.Sprintf("decoders: can not decode protobuf into %v; it is not a proto.Message", err.Type)
+}
+
+// Is reports whether target is ErrNotProtoMessage
+func (err *NotProtoMessageError) Is(target error) bool { return target == ErrNotProtoMessage }
+
+// Protobuf decodes application/x-protobuf bodies into a proto.Message, or a
+// pointer to a nil proto.Message which will be allocated. A
+// *NotProtoMessageError is returned for any other value, so APIs that bind both
+// JSON and protobuf bodies can tell a client sending protobuf to a route that
+// only has JSON payloads apart from a malformed body.
+func Protobuf(r io.Reader, v interface{}) error {
+	defer io.Copy(ioutil.Discard, r)
+	msg, err := protoMessage(v)
+	if err != nil {
+		return err
+	}
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return proto.Unmarshal(b, msg)
+}
+
+// protoMessage returns the proto.Message to decode into for v
+func protoMessage(v interface{}) (proto.Message, error) {
+	if msg, ok := v.(proto.Message); ok {
+		return msg, nil
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() && rv.Elem().Kind() == reflect.Ptr {
+		elem := rv.Elem()
+		if _, ok := reflect.Zero(elem.Type()).Interface().(proto.Message); ok {
+			if elem.IsNil() {
+				elem.Set(reflect.New(elem.Type().Elem()))
+			}
+			return elem.Interface().(proto.Message), nil
+		}
+	}
+	return nil, &NotProtoMessageError{Type: reflect.TypeOf(v)}
+}
@@ -0,0 +1,55 @@
+package decoders_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/gdey/chi-render/decoders"
+	"github.com/gdey/chi-render/decoders/test"
+)
+
+func TestProtobuf(t *testing.T) {
+	encode := func(msg proto.Message) *bytes.Reader {
+		b, err := proto.Marshal(msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return bytes.NewReader(b)
+	}
+	protoEqual := func(expected, got interface{}) bool {
+		return proto.Equal(expected.(proto.Message), got.(proto.Message))
+	}
+
+	tests := map[string]test.Case{
+		"message": {
+			R:               encode(wrapperspb.String("hello")),
+			Value:           wrapperspb.String("hello"),
+			ValueComparator: protoEqual,
+		},
+		"empty": {
+			R:               bytes.NewReader(nil),
+			Value:           wrapperspb.Int64(0),
+			ValueComparator: protoEqual,
+		},
+		"malformed": {
+			R:     bytes.NewReader([]byte{0x0a, 0x05, 'h'}),
+			Value: wrapperspb.String(""),
+			Err:   proto.Error,
+		},
+		"not a message": {
+			R:     encode(wrapperspb.String("hello")),
+			Value: struct{ Value string }{},
+			Err:   decoders.ErrNotProtoMessage,
+			ErrComparator: func(expected, got error) bool {
+				return errors.Is(got, expected)
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, tc.Test(decoders.Protobuf))
+	}
+}
@@ -0,0 +1,43 @@
+package decoders
+
+import (
+	"io"
+	"io/ioutil"
+	"reflect"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// ProtoJSON is like JSON, but decodes into a proto.Message, or a pointer to a
+// nil proto.Message which will be allocated, with protojson, so bodies use the
+// canonical JSON mapping of protocol buffers. Other values are decoded with
+// encoding/json; use it for the JSON content type of APIs that bind both
+// messages and plain structs.
+func ProtoJSON(r io.Reader, v interface{}) error {
+	defer io.Copy(ioutil.Discard, r)
+	if !IsProtoMessage(v) {
+		return decodeJSON(r, v, options{})
+	}
+	msg, err := protoMessage(v)
+	if err != nil {
+		return err
+	}
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return protojson.Unmarshal(b, msg)
+}
+
+var protoMessageType = reflect.TypeOf((*proto.Message)(nil)).Elem()
+
+// IsProtoMessage reports whether v is a proto.Message, or a pointer to one, that
+// Protobuf and ProtoJSON decode with the protocol buffer runtime.
+func IsProtoMessage(v interface{}) bool {
+	if _, ok := v.(proto.Message); ok {
+		return true
+	}
+	typ := reflect.TypeOf(v)
+	return typ != nil && typ.Kind() == reflect.Ptr && typ.Elem().Implements(protoMessageType)
+}
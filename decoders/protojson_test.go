@@ -0,0 +1,45 @@
+package decoders_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/gdey/chi-render/decoders"
+	"github.com/gdey/chi-render/decoders/test"
+)
+
+func TestProtoJSON(t *testing.T) {
+	protoEqual := func(expected, got interface{}) bool {
+		return proto.Equal(expected.(proto.Message), got.(proto.Message))
+	}
+	type plain struct {
+		Value string `json:"value"`
+	}
+
+	tests := map[string]test.Case{
+		"timestamp": {
+			R:               strings.NewReader(`"2021-02-25T00:00:00Z"`),
+			Value:           timestamppb.New(time.Date(2021, 2, 25, 0, 0, 0, 0, time.UTC)),
+			ValueComparator: protoEqual,
+		},
+		"wrapper": {
+			R:               strings.NewReader(`"hello"`),
+			Value:           wrapperspb.String("hello"),
+			ValueComparator: protoEqual,
+		},
+		"not a message": test.NewStringCase(`{"value":"hello"}`, plain{Value: "hello"}),
+		"malformed": {
+			R:     strings.NewReader(`"yesterday"`),
+			Value: timestamppb.Now(),
+			Err:   proto.Error,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, tc.Test(decoders.ProtoJSON))
+	}
+}
@@ -0,0 +1,210 @@
+package decoders
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// ErrSchemaViolation is the error that SchemaError values match using errors.Is.
+var ErrSchemaViolation = errors.New("decoders: body does not match the json schema")
+
+// SchemaViolation is a value of the body that does not match the schema
+type SchemaViolation struct {
+	// Path is the path of the value in the body, like items[3].price; empty for
+	// the body as a whole
+	Path string
+	// Keyword is the location of the keyword of the schema the value failed,
+	// like /properties/items/items/properties/price/minimum
+	Keyword string
+	// Message describes the violation
+	Message string
+}
+
+// SchemaError is returned by the decoders NewSchemaJSON returns when the body
+// does not match the schema of the payload; it lists every violation, ordered
+// by path.
+type SchemaError struct {
+	Violations []SchemaViolation
+}
+
+func (err *SchemaError) Error() string {
+	msgs := make([]string, len(err.Violations))
+	for i, violation := range err.Violations {
+		msgs[i] = violation.Message
+		if violation.Path != "" {
+			msgs[i] = violation.Path + ": " + violation.Message
+		}
+	}
+	return ErrSchemaViolation.Error() + ": " + strings.Join(msgs, "; ")
+}
+
+// Is reports whether target is ErrSchemaViolation
+func (err *SchemaError) Is(target error) bool { return target == ErrSchemaViolation }
+
+// StatusCode is the http status code that should be reported to the client
+func (err *SchemaError) StatusCode() int { return http.StatusUnprocessableEntity }
+
+// Fields returns the messages of the violations by path, as render.FieldErrors
+// has them; the messages of a path with several violations are joined.
+func (err *SchemaError) Fields() map[string]string {
+	fields := make(map[string]string, len(err.Violations))
+	for _, violation := range err.Violations {
+		if msg, ok := fields[violation.Path]; ok {
+			fields[violation.Path] = msg + "; " + violation.Message
+			continue
+		}
+		fields[violation.Path] = violation.Message
+	}
+	return fields
+}
+
+// Schemas are the JSON Schemas request bodies are validated against, by the
+// type of the payload they are decoded into. The zero value has no schemas.
+type Schemas struct {
+	lck     sync.RWMutex
+	schemas map[reflect.Type]*jsonschema.Schema
+}
+
+// Register compiles the JSON Schema and registers it for payloads of the type;
+// payloads are matched by their exact type, or for pointers, the type they
+// point to. Use a nil schema to unregister the type.
+func (s *Schemas) Register(typ reflect.Type, schema []byte) error {
+	var compiled *jsonschema.Schema
+	if schema != nil {
+		compiler := jsonschema.NewCompiler()
+		if err := compiler.AddResource("schema.json", bytes.NewReader(schema)); err != nil {
+			return fmt.Errorf("decoders: json schema for %v: %w", typ, err)
+		}
+		var err error
+		if compiled, err = compiler.Compile("schema.json"); err != nil {
+			return fmt.Errorf("decoders: json schema for %v: %w", typ, err)
+		}
+	}
+	s.lck.Lock()
+	defer s.lck.Unlock()
+	if compiled == nil {
+		delete(s.schemas, typ)
+		return nil
+	}
+	if s.schemas == nil {
+		s.schemas = make(map[reflect.Type]*jsonschema.Schema)
+	}
+	s.schemas[typ] = compiled
+	return nil
+}
+
+// lookup returns the schema registered for the type of v; nil if there is none
+func (s *Schemas) lookup(v interface{}) *jsonschema.Schema {
+	if s == nil || v == nil {
+		return nil
+	}
+	s.lck.RLock()
+	defer s.lck.RUnlock()
+	typ := reflect.TypeOf(v)
+	schema, ok := s.schemas[typ]
+	for !ok && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+		schema, ok = s.schemas[typ]
+	}
+	return schema
+}
+
+// NewSchemaJSON returns a JSON decoder that validates the body against the
+// schema registered in schemas for the type of the payload before decoding
+// it, failing with a *SchemaError that lists the violations; payloads without
+// a schema are decoded as they are. The options tune the decoding; see
+// UseNumber and Strict.
+//
+//	var schemas decoders.Schemas
+//	if err := schemas.Register(reflect.TypeOf(ArticleRequest{}), articleSchema); err != nil {
+//		...
+//	}
+//	ctrl.SetDecoder(render.ContentTypeJSON, decoders.NewSchemaJSON(&schemas))
+func NewSchemaJSON(schemas *Schemas, opts ...Option) Func {
+	o := newOptions(opts)
+	return func(r io.Reader, v interface{}) error {
+		defer io.Copy(ioutil.Discard, r)
+		schema := schemas.lookup(v)
+		if schema == nil {
+			return decodeJSON(r, v, o)
+		}
+		body, err := ioutil.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		// the schema is checked against numbers as they were sent
+		var doc interface{}
+		dec := json.NewDecoder(bytes.NewReader(body))
+		dec.UseNumber()
+		if err := dec.Decode(&doc); err != nil {
+			return err
+		}
+		if err := schema.Validate(doc); err != nil {
+			var validationErr *jsonschema.ValidationError
+			if !errors.As(err, &validationErr) {
+				return err
+			}
+			return newSchemaError(validationErr)
+		}
+		return decodeJSON(bytes.NewReader(body), v, o)
+	}
+}
+
+// newSchemaError returns the violations of the validation error; the leaves of
+// the error tree are the keywords that failed.
+func newSchemaError(validationErr *jsonschema.ValidationError) *SchemaError {
+	var (
+		schemaErr = new(SchemaError)
+		walk      func(err *jsonschema.ValidationError)
+	)
+	walk = func(err *jsonschema.ValidationError) {
+		if len(err.Causes) == 0 {
+			schemaErr.Violations = append(schemaErr.Violations, SchemaViolation{
+				Path:    pointerPath(err.InstanceLocation),
+				Keyword: err.KeywordLocation,
+				Message: err.Message,
+			})
+			return
+		}
+		for _, cause := range err.Causes {
+			walk(cause)
+		}
+	}
+	walk(validationErr)
+	sort.SliceStable(schemaErr.Violations, func(i, j int) bool {
+		return schemaErr.Violations[i].Path < schemaErr.Violations[j].Path
+	})
+	return schemaErr
+}
+
+// pointerPath turns a JSON Pointer into a path like items[3].price
+func pointerPath(pointer string) string {
+	if pointer == "" {
+		return ""
+	}
+	var path strings.Builder
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		if _, err := strconv.Atoi(token); err == nil {
+			path.WriteString("[" + token + "]")
+			continue
+		}
+		if path.Len() > 0 {
+			path.WriteByte('.')
+		}
+		path.WriteString(token)
+	}
+	return path.String()
+}
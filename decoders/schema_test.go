@@ -0,0 +1,147 @@
+package decoders_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gdey/chi-render/decoders"
+)
+
+type schemaItem struct {
+	Name  string  `json:"name"`
+	Price float64 `json:"price"`
+}
+
+type schemaOrder struct {
+	ID    string       `json:"id"`
+	Items []schemaItem `json:"items"`
+}
+
+const orderSchema = `{
+	"type": "object",
+	"required": ["id", "items"],
+	"properties": {
+		"id": {"type": "string", "minLength": 1},
+		"items": {
+			"type": "array",
+			"items": {
+				"type": "object",
+				"properties": {
+					"name": {"type": "string"},
+					"price": {"type": "number", "minimum": 0}
+				}
+			}
+		}
+	}
+}`
+
+func TestSchemaJSON(t *testing.T) {
+	var schemas decoders.Schemas
+	if err := schemas.Register(reflect.TypeOf(schemaOrder{}), []byte(orderSchema)); err != nil {
+		t.Fatalf("register, expected nil, got %v", err)
+	}
+	decode := decoders.NewSchemaJSON(&schemas)
+
+	type tcase struct {
+		Body string
+		// V is the payload to decode into; a *schemaOrder if nil
+		V          interface{}
+		Expected   interface{}
+		Violations []decoders.SchemaViolation
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			v := tc.V
+			if v == nil {
+				v = new(schemaOrder)
+			}
+			err := decode(strings.NewReader(tc.Body), v)
+			if tc.Violations != nil {
+				var schemaErr *decoders.SchemaError
+				if !errors.As(err, &schemaErr) || !errors.Is(err, decoders.ErrSchemaViolation) {
+					t.Fatalf("error, expected a schema error, got %v", err)
+				}
+				if len(schemaErr.Violations) != len(tc.Violations) {
+					t.Fatalf("violations, expected %+v, got %+v", tc.Violations, schemaErr.Violations)
+				}
+				for i, violation := range schemaErr.Violations {
+					if expected := tc.Violations[i]; violation.Path != expected.Path || violation.Keyword != expected.Keyword {
+						t.Errorf("violation %d, expected %+v, got %+v", i, expected, violation)
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error, expected nil, got %v", err)
+			}
+			if got := reflect.ValueOf(v).Elem().Interface(); !reflect.DeepEqual(got, tc.Expected) {
+				t.Errorf("value, expected %+v, got %+v", tc.Expected, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"valid": {
+			Body:     `{"id":"o1","items":[{"name":"pen","price":1.5}]}`,
+			Expected: schemaOrder{ID: "o1", Items: []schemaItem{{Name: "pen", Price: 1.5}}},
+		},
+		"violations": {
+			Body: `{"id":"","items":[{"name":"pen","price":1},{"name":"ink","price":-2}]}`,
+			Violations: []decoders.SchemaViolation{
+				{Path: "id", Keyword: "/properties/id/minLength"},
+				{Path: "items[1].price", Keyword: "/properties/items/items/properties/price/minimum"},
+			},
+		},
+		"missing members": {
+			Body:       `{}`,
+			Violations: []decoders.SchemaViolation{{Path: "", Keyword: "/required"}},
+		},
+		"no schema": {
+			Body:     `{"name":"pen","price":-1}`,
+			V:        new(schemaItem),
+			Expected: schemaItem{Name: "pen", Price: -1},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+
+	t.Run("fields", func(t *testing.T) {
+		err := &decoders.SchemaError{Violations: []decoders.SchemaViolation{
+			{Path: "id", Message: "too short"},
+			{Path: "id", Message: "not a uuid"},
+			{Path: "items[1].price", Message: "below the minimum"},
+		}}
+		expected := map[string]string{"id": "too short; not a uuid", "items[1].price": "below the minimum"}
+		if got := err.Fields(); !reflect.DeepEqual(got, expected) {
+			t.Errorf("fields, expected %v, got %v", expected, got)
+		}
+		if got := err.StatusCode(); got != 422 {
+			t.Errorf("status code, expected 422, got %v", got)
+		}
+	})
+
+	t.Run("unregister", func(t *testing.T) {
+		var schemas decoders.Schemas
+		typ := reflect.TypeOf(schemaOrder{})
+		if err := schemas.Register(typ, []byte(orderSchema)); err != nil {
+			t.Fatalf("register, expected nil, got %v", err)
+		}
+		if err := schemas.Register(typ, nil); err != nil {
+			t.Fatalf("unregister, expected nil, got %v", err)
+		}
+		if err := decoders.NewSchemaJSON(&schemas)(strings.NewReader(`{}`), new(schemaOrder)); err != nil {
+			t.Errorf("error, expected nil, got %v", err)
+		}
+	})
+
+	t.Run("invalid schema", func(t *testing.T) {
+		var schemas decoders.Schemas
+		if err := schemas.Register(reflect.TypeOf(schemaOrder{}), []byte(`{"type": 7}`)); err == nil {
+			t.Errorf("error, expected an error for an invalid schema")
+		}
+	})
+}
@@ -0,0 +1,27 @@
+package decoders
+
+import (
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/url"
+)
+
+// URLEncoded decodes application/x-www-form-urlencoded bodies, which is what
+// browsers send for forms without an enctype, into a struct. The values are
+// set on the fields the way Multipart sets its value parts: nested names,
+// converters, checkboxes, empty values and repeated inputs alike. Decoding
+// into an empty interface sets it to a map[string]interface{} of the values
+// by name.
+func URLEncoded(r io.Reader, v interface{}) error {
+	body, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		return err
+	}
+	recordFormValues(r, values)
+	return decodeMultipart(&multipart.Form{Value: values}, v, Converter(r))
+}
@@ -0,0 +1,88 @@
+package decoders_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gdey/chi-render/decoders"
+)
+
+type urlEncodedForm struct {
+	browserForm
+	Title  string                `form:"title"`
+	Author struct{ Name string } `form:"author"`
+	Attrs  map[string]int        `form:"attrs"`
+}
+
+func TestURLEncoded(t *testing.T) {
+	type tcase struct {
+		Body string
+		// Initial is the value the form is decoded onto
+		Initial  urlEncodedForm
+		Expected urlEncodedForm
+		// Field is the field of the expected *FieldError; empty if no error is
+		// expected
+		Field string
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got := tc.Initial
+			err := decoders.URLEncoded(strings.NewReader(tc.Body), &got)
+			if tc.Field != "" {
+				var fieldErr *decoders.FieldError
+				if !errors.As(err, &fieldErr) || fieldErr.Field != tc.Field {
+					t.Errorf("error, expected a field error for %q, got %v", tc.Field, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error, expected nil, got %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.Expected) {
+				t.Errorf("values, expected %+v, got %+v", tc.Expected, got)
+			}
+		}
+	}
+
+	yes, age := true, 30
+	expectedNested := urlEncodedForm{Title: "a b", Attrs: map[string]int{"size": 2}}
+	expectedNested.Author.Name = "gopher"
+	tests := map[string]tcase{
+		"browser form": {
+			Body:    "subscribe=on&remember=false&remember=true&age=30&nickname=&color=red&color=blue",
+			Initial: urlEncodedForm{browserForm: browserForm{browserFlags: browserFlags{Notify: true}, Terms: &yes}},
+			Expected: urlEncodedForm{browserForm: browserForm{
+				Subscribe: true,
+				Remember:  true,
+				Terms:     &yes,
+				Age:       &age,
+				Colors:    []string{"red", "blue"},
+			}},
+		},
+		"nested names": {
+			Body:     "title=a+b&author.Name=gopher&attrs%5Bsize%5D=2",
+			Expected: expectedNested,
+		},
+		"bad value": {
+			Body:  "age=old",
+			Field: "age",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+
+	t.Run("empty interface", func(t *testing.T) {
+		var got interface{}
+		if err := decoders.URLEncoded(strings.NewReader("a=1&b=2&b=3"), &got); err != nil {
+			t.Fatalf("error, expected nil, got %v", err)
+		}
+		expected := map[string]interface{}{"a": "1", "b": []interface{}{"2", "3"}}
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("value, expected %v, got %v", expected, got)
+		}
+	})
+}
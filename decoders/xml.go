@@ -1,12 +1,57 @@
 package decoders
 
 import (
+	"bytes"
 	"encoding/xml"
 	"io"
 	"io/ioutil"
 )
 
+// XML decodes application/xml bodies; it is the decoder NewXML returns
+// without options. Bodies decoded into an interface{} or a
+// map[string]interface{} become a generic map that keeps the attributes and
+// repeated elements, for documents without a struct to decode into.
 func XML(r io.Reader, v interface{}) error {
 	defer io.Copy(ioutil.Discard, r)
-	return xml.NewDecoder(r).Decode(v)
+	return decodeXML(r, v, options{})
+}
+
+// NewXML returns an XML decoder tuned by opts; see Strict.
+func NewXML(opts ...Option) Func {
+	o := newOptions(opts)
+	return func(r io.Reader, v interface{}) error {
+		defer io.Copy(ioutil.Discard, r)
+		return decodeXML(r, v, o)
+	}
+}
+
+func decodeXML(r io.Reader, v interface{}, o options) error {
+	dec := xml.NewDecoder(r)
+	if err := decodeXMLValue(dec, v); err != nil {
+		return err
+	}
+	if !o.trailingData {
+		return nil
+	}
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if _, ok := err.(*xml.SyntaxError); ok {
+			return ErrTrailingData
+		}
+		if err != nil {
+			return err
+		}
+		switch tok := tok.(type) {
+		case xml.Comment, xml.ProcInst:
+		case xml.CharData:
+			if len(bytes.TrimSpace(tok)) != 0 {
+				return ErrTrailingData
+			}
+		default:
+			return ErrTrailingData
+		}
+	}
 }
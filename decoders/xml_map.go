@@ -0,0 +1,124 @@
+package decoders
+
+import (
+	"encoding/xml"
+	"errors"
+	"strings"
+)
+
+// maxXMLDepth is the deepest element nesting decoded into a generic map, as
+// for encoding/xml
+const maxXMLDepth = 10000
+
+var errXMLTooDeep = errors.New("decoders: xml elements nested too deeply")
+
+// decodeXMLValue decodes the next element into v. Values of *interface{} and
+// *map[string]interface{} get the element as a generic map, as structs can
+// not be defined up front for arbitrary documents: the map has the name of
+// the element as its only key, and the element as its value.
+//
+// An element with no attributes or child elements is its text. Others are a
+// map[string]interface{} of the attributes, by their name prefixed with "@",
+// the child elements, by their name, and the text, trimmed of spaces, under
+// "#text" if there is any. Repeated child elements are a []interface{}, in
+// the order of the document. Names are used without their namespace, and
+// namespace declarations are left out.
+//
+//	<article id="1"><tag>a</tag><tag>b</tag><title>Hi</title></article>
+//
+// decodes to
+//
+//	map[string]interface{}{"article": map[string]interface{}{
+//		"@id":   "1",
+//		"tag":   []interface{}{"a", "b"},
+//		"title": "Hi",
+//	}}
+func decodeXMLValue(dec *xml.Decoder, v interface{}) error {
+	switch v := v.(type) {
+	case *interface{}:
+		m, err := decodeXMLMap(dec)
+		if err != nil {
+			return err
+		}
+		*v = m
+		return nil
+	case *map[string]interface{}:
+		m, err := decodeXMLMap(dec)
+		if err != nil {
+			return err
+		}
+		*v = m
+		return nil
+	default:
+		return dec.Decode(v)
+	}
+}
+
+// decodeXMLMap decodes the next element of dec into a generic map
+func decodeXMLMap(dec *xml.Decoder) (map[string]interface{}, error) {
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			elem, err := xmlElement(dec, start, 1)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{start.Name.Local: elem}, nil
+		}
+		// like Decode, anything before the element is skipped
+	}
+}
+
+// xmlElement decodes the element that start starts; see decodeXMLValue
+func xmlElement(dec *xml.Decoder, start xml.StartElement, depth int) (interface{}, error) {
+	if depth > maxXMLDepth {
+		return nil, errXMLTooDeep
+	}
+	var (
+		elem = make(map[string]interface{})
+		text strings.Builder
+	)
+	for _, attr := range start.Attr {
+		if attr.Name.Space == "xmlns" || (attr.Name.Space == "" && attr.Name.Local == "xmlns") {
+			continue
+		}
+		elem["@"+attr.Name.Local] = attr.Value
+	}
+	for {
+		// the decoder reports unclosed elements as syntax errors
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			child, err := xmlElement(dec, tok, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			name := tok.Name.Local
+			switch prev := elem[name].(type) {
+			case nil:
+				elem[name] = child
+			case []interface{}:
+				elem[name] = append(prev, child)
+			default:
+				elem[name] = []interface{}{prev, child}
+			}
+		case xml.CharData:
+			text.Write(tok)
+		case xml.EndElement:
+			if len(elem) == 0 {
+				return text.String(), nil
+			}
+			if trimmed := strings.TrimSpace(text.String()); trimmed != "" {
+				elem["#text"] = trimmed
+			}
+			return elem, nil
+		}
+		// comments, processing instructions and directives are skipped
+	}
+}
@@ -2,6 +2,8 @@ package decoders_test
 
 import (
 	"encoding/xml"
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/gdey/chi-render/decoders"
@@ -44,3 +46,83 @@ func TestXML(t *testing.T) {
 		t.Run(name, tc.Test(decoders.XML))
 	}
 }
+
+func TestNewXML(t *testing.T) {
+	type Item struct {
+		XMLName xml.Name `xml:"item"`
+		Name    string   `xml:"name"`
+	}
+	errIs := func(expected, got error) bool { return errors.Is(got, expected) }
+
+	tests := map[string]struct {
+		Decoder decoders.Func
+		Case    test.Case
+	}{
+		"defaults": {
+			Decoder: decoders.NewXML(),
+			Case:    test.NewStringCase(`<item><name>a</name></item><item/>`, Item{XMLName: xml.Name{Local: "item"}, Name: "a"}),
+		},
+		"strict": {
+			Decoder: decoders.NewXML(decoders.Strict()),
+			Case:    test.NewStringCase("<item><name>a</name></item>\n<!-- end -->\n", Item{XMLName: xml.Name{Local: "item"}, Name: "a"}),
+		},
+		"strict trailing data": {
+			Decoder: decoders.NewXML(decoders.Strict()),
+			Case: test.Case{
+				R:             strings.NewReader(`<item><name>a</name></item><item/>`),
+				Value:         Item{},
+				Err:           decoders.ErrTrailingData,
+				ErrComparator: errIs,
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, tc.Case.Test(tc.Decoder))
+	}
+}
+
+func TestXMLMap(t *testing.T) {
+	tests := map[string]test.Case{
+		"attributes and repeated elements": test.NewStringCase(`<?xml version="1.0"?>
+<order id="7" xmlns="urn:acme" xmlns:x="urn:x">
+    <!-- items of the order -->
+    <item sku="a1">Widget</item>
+    <item sku="b2"><qty>2</qty></item>
+    <item>Gadget</item>
+    <note x:lang="en"/>
+    <total currency="USD">9.50</total>
+    <empty></empty>
+</order>`,
+			map[string]interface{}{"order": map[string]interface{}{
+				"@id": "7",
+				"item": []interface{}{
+					map[string]interface{}{"@sku": "a1", "#text": "Widget"},
+					map[string]interface{}{"@sku": "b2", "qty": "2"},
+					"Gadget",
+				},
+				"note":  map[string]interface{}{"@lang": "en"},
+				"total": map[string]interface{}{"@currency": "USD", "#text": "9.50"},
+				"empty": "",
+			}},
+		),
+		"text": test.NewStringCase(`<name> gdey </name>`, map[string]interface{}{"name": " gdey "}),
+		"unclosed": {
+			R:     strings.NewReader(`<order><item>a</item>`),
+			Value: map[string]interface{}{},
+			Err:   &xml.SyntaxError{},
+		},
+		"mismatched": {
+			R:     strings.NewReader(`<order><item>a</order>`),
+			Value: map[string]interface{}{},
+			Err:   &xml.SyntaxError{},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, tc.Test(decoders.XML))
+	}
+	t.Run("strict trailing data", test.Case{
+		R:     strings.NewReader(`<item/><item/>`),
+		Value: map[string]interface{}{},
+		Err:   decoders.ErrTrailingData,
+	}.Test(decoders.NewXML(decoders.Strict())))
+}
@@ -0,0 +1,99 @@
+package decoders
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"gopkg.in/yaml.v3"
+)
+
+// YAML decodes application/yaml and text/yaml bodies. The document is decoded
+// through JSON, so payloads use the same json struct tags, and get the same
+// field names, as they do for JSON bodies. It is the decoder NewYAML returns
+// without options.
+func YAML(r io.Reader, v interface{}) error {
+	defer io.Copy(ioutil.Discard, r)
+	return decodeYAML(r, v, options{})
+}
+
+// NewYAML returns a YAML decoder tuned by opts; see UseNumber and Strict.
+func NewYAML(opts ...Option) Func {
+	o := newOptions(opts)
+	return func(r io.Reader, v interface{}) error {
+		defer io.Copy(ioutil.Discard, r)
+		return decodeYAML(r, v, o)
+	}
+}
+
+func decodeYAML(r io.Reader, v interface{}, o options) error {
+	dec := yaml.NewDecoder(r)
+	var doc interface{}
+	if err := dec.Decode(&doc); err != nil {
+		if err == io.EOF {
+			// an empty document, like an empty JSON body
+			return err
+		}
+		return fmt.Errorf("decoders: yaml: %w", err)
+	}
+	if o.trailingData {
+		var trailing interface{}
+		if err := dec.Decode(&trailing); err != io.EOF {
+			return ErrTrailingData
+		}
+	}
+	doc, err := jsonCompatible(doc)
+	if err != nil {
+		return err
+	}
+	var buff bytes.Buffer
+	if err := json.NewEncoder(&buff).Encode(doc); err != nil {
+		return fmt.Errorf("decoders: yaml: %w", err)
+	}
+	o.trailingData = false
+	return decodeJSON(&buff, v, o)
+}
+
+// jsonCompatible converts the maps with non string keys, that YAML allows, into
+// maps with string keys
+func jsonCompatible(v interface{}) (interface{}, error) {
+	switch vv := v.(type) {
+	case map[string]interface{}:
+		for key, item := range vv {
+			item, err := jsonCompatible(item)
+			if err != nil {
+				return nil, err
+			}
+			vv[key] = item
+		}
+		return vv, nil
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(vv))
+		for key, item := range vv {
+			item, err := jsonCompatible(item)
+			if err != nil {
+				return nil, err
+			}
+			switch key.(type) {
+			case string, bool, int, int64, uint64, float64:
+				m[fmt.Sprint(key)] = item
+			default:
+				return nil, fmt.Errorf("decoders: yaml: unsupported map key %v", key)
+			}
+		}
+		return m, nil
+	case []interface{}:
+		for i, item := range vv {
+			item, err := jsonCompatible(item)
+			if err != nil {
+				return nil, err
+			}
+			vv[i] = item
+		}
+		return vv, nil
+	default:
+		return v, nil
+	}
+}
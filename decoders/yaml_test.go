@@ -0,0 +1,91 @@
+package decoders_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gdey/chi-render/decoders"
+	"github.com/gdey/chi-render/decoders/test"
+)
+
+func TestYAML(t *testing.T) {
+	type Service struct {
+		Name     string            `json:"name"`
+		Port     int               `json:"port"`
+		Enabled  bool              `json:"enabled"`
+		Tags     []string          `json:"tags"`
+		Settings map[string]string `json:"settings"`
+	}
+
+	tests := map[string]test.Case{
+		"service": test.NewStringCase(`
+name: api
+port: 8080
+enabled: true
+tags: [web, public]
+settings:
+  1: one
+  timeout: 5s
+`,
+			Service{
+				Name:     "api",
+				Port:     8080,
+				Enabled:  true,
+				Tags:     []string{"web", "public"},
+				Settings: map[string]string{"1": "one", "timeout": "5s"},
+			},
+		),
+		"invalid": {
+			R:     strings.NewReader("name: [api"),
+			Value: Service{},
+			Err:   errors.New("decoders: yaml"),
+			ErrComparator: func(expected, got error) bool {
+				return got != nil && strings.HasPrefix(got.Error(), expected.Error())
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, tc.Test(decoders.YAML))
+	}
+}
+
+func TestNewYAML(t *testing.T) {
+	type Service struct {
+		Name string      `json:"name"`
+		Port interface{} `json:"port"`
+	}
+	errIs := func(expected, got error) bool { return errors.Is(got, expected) }
+
+	tests := map[string]struct {
+		Decoder decoders.Func
+		Case    test.Case
+	}{
+		"use number": {
+			Decoder: decoders.NewYAML(decoders.UseNumber()),
+			Case:    test.NewStringCase("name: api\nport: 8080\n", Service{Name: "api", Port: json.Number("8080")}),
+		},
+		"strict unknown field": {
+			Decoder: decoders.NewYAML(decoders.Strict()),
+			Case: test.Case{
+				R:             strings.NewReader("name: api\nhost: example.com\n"),
+				Value:         Service{},
+				Err:           errors.New("unknown field"),
+				ErrComparator: func(_, got error) bool { return got != nil },
+			},
+		},
+		"strict second document": {
+			Decoder: decoders.NewYAML(decoders.Strict()),
+			Case: test.Case{
+				R:             strings.NewReader("name: api\n---\nname: web\n"),
+				Value:         Service{},
+				Err:           decoders.ErrTrailingData,
+				ErrComparator: errIs,
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, tc.Case.Test(tc.Decoder))
+	}
+}
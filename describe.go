@@ -0,0 +1,204 @@
+package render
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gdey/chi-render/responders"
+)
+
+// ResponderInfo describes a registered responder
+type ResponderInfo struct {
+	ContentType ContentType `json:"content_type"`
+	// Profile is the profile the responder is registered for, if any
+	Profile string `json:"profile,omitempty"`
+	// CanEncode is true if the responder checks the payloads it can encode
+	CanEncode bool `json:"can_encode,omitempty"`
+	// Structured is true if the responder gets the projected payload
+	Structured bool `json:"structured,omitempty"`
+}
+
+// DecoderInfo describes a registered decoder
+type DecoderInfo struct {
+	ContentType ContentType `json:"content_type"`
+	// Limit is the maximum number of bytes of the request body; zero for no limit
+	Limit int64 `json:"limit,omitempty"`
+}
+
+// ControllerInfo is the configuration of a controller in a structured form, so
+// services can expose it on a debug endpoint and tests can assert it.
+type ControllerInfo struct {
+	Responders      []ResponderInfo `json:"responders"`
+	ErrorResponders []ResponderInfo `json:"error_responders,omitempty"`
+	Decoders        []DecoderInfo   `json:"decoders"`
+
+	DefaultRequest  ContentType   `json:"default_request,omitempty"`
+	DefaultResponse ContentType   `json:"default_response,omitempty"`
+	AllowedAccept   []ContentType `json:"allowed_accept,omitempty"`
+	DeniedAccept    []ContentType `json:"denied_accept,omitempty"`
+	// AcceptOverrideHeader is the header that replaces the Accept header, if any
+	AcceptOverrideHeader string `json:"accept_override_header,omitempty"`
+	StrictAccept         bool   `json:"strict_accept,omitempty"`
+	Fallback             string `json:"fallback"`
+	MalformedHeaders     string `json:"malformed_headers"`
+	// PayloadTypes are the content types preferred for payloads, by type name
+	PayloadTypes map[string]ContentType `json:"payload_types,omitempty"`
+
+	// TypeEncoders are the types with a registered TypeEncoder
+	TypeEncoders []string `json:"type_encoders,omitempty"`
+	// TypeConverters are the types with a registered TypeConverter
+	TypeConverters []string `json:"type_converters,omitempty"`
+	// Hooks are the names of the hook fields that are set; Conditional, Signer, Audit and CSRF
+	Hooks []string `json:"hooks,omitempty"`
+
+	Digest              string `json:"digest"`
+	ServerTiming        bool   `json:"server_timing,omitempty"`
+	CopyOnRender        bool   `json:"copy_on_render,omitempty"`
+	RaceCheck           string `json:"race_check"`
+	MaxBodyBytes        int64  `json:"max_body_bytes,omitempty"`
+	MaxResponseBytes    int64  `json:"max_response_bytes,omitempty"`
+	Redact              string `json:"redact"`
+	IgnoreViews         bool   `json:"ignore_views,omitempty"`
+	KeyCasing           string `json:"key_casing"`
+	TimeFormat          string `json:"time_format,omitempty"`
+	DurationFormat      string `json:"duration_format"`
+	Nulls               string `json:"nulls"`
+	BigNumbersAsStrings bool   `json:"big_numbers_as_strings,omitempty"`
+	ProtoJSON           bool   `json:"proto_json,omitempty"`
+}
+
+// Describe returns the configuration of the controller. Content types and type
+// names are sorted, so the result can be compared in tests.
+func (ctrl *Controller) Describe() ControllerInfo {
+	if ctrl == nil {
+		return defaultCtrl.Describe()
+	}
+	info := ControllerInfo{
+		DefaultRequest:       ctrl.DefaultRequest,
+		DefaultResponse:      ctrl.DefaultResponse,
+		AcceptOverrideHeader: ctrl.AcceptOverrideHeader,
+		StrictAccept:         ctrl.StrictAccept,
+		Fallback:             ctrl.Fallback.String(),
+		MalformedHeaders:     ctrl.MalformedHeaders.String(),
+		Digest:               ctrl.Digest.String(),
+		ServerTiming:         ctrl.ServerTiming,
+		CopyOnRender:         ctrl.CopyOnRender,
+		RaceCheck:            ctrl.RaceCheck.String(),
+		MaxBodyBytes:         ctrl.MaxBodyBytes,
+		MaxResponseBytes:     ctrl.MaxResponseBytes,
+		Redact:               ctrl.Redact.String(),
+		IgnoreViews:          ctrl.IgnoreViews,
+		KeyCasing:            ctrl.KeyCasing.String(),
+		TimeFormat:           string(ctrl.TimeFormat),
+		DurationFormat:       ctrl.DurationFormat.String(),
+		Nulls:                ctrl.Nulls.String(),
+		BigNumbersAsStrings:  ctrl.BigNumbersAsStrings,
+		ProtoJSON:            ctrl.ProtoJSON,
+	}
+	if ctrl.AllowedAccept != nil {
+		info.AllowedAccept = ctrl.AllowedAccept.Types()
+	}
+	if ctrl.DeniedAccept != nil {
+		info.DeniedAccept = ctrl.DeniedAccept.Types()
+	}
+
+	ctrl.responderLck.RLock()
+	info.Responders = describeResponders(ctrl.responders)
+	for key, reg := range ctrl.profileResponders {
+		if reg.Func == nil {
+			continue
+		}
+		info.Responders = append(info.Responders, ResponderInfo{
+			ContentType: key.contentType,
+			Profile:     key.profile,
+			CanEncode:   reg.CanEncode != nil,
+			Structured:  structured(reg),
+		})
+	}
+	sortResponderInfos(info.Responders)
+	info.ErrorResponders = describeResponders(ctrl.errorResponders)
+	ctrl.responderLck.RUnlock()
+
+	ctrl.decoderLck.RLock()
+	for contentType, entry := range ctrl.decoders {
+		if entry.fn == nil {
+			continue
+		}
+		info.Decoders = append(info.Decoders, DecoderInfo{ContentType: contentType, Limit: entry.limit})
+	}
+	ctrl.decoderLck.RUnlock()
+	sort.Slice(info.Decoders, func(i, j int) bool { return info.Decoders[i].ContentType < info.Decoders[j].ContentType })
+
+	for typ := range ctrl.encoders() {
+		info.TypeEncoders = append(info.TypeEncoders, typ.String())
+	}
+	sort.Strings(info.TypeEncoders)
+	if payloadTypes := ctrl.preferredTypes(); len(payloadTypes) > 0 {
+		info.PayloadTypes = make(map[string]ContentType, len(payloadTypes))
+		for typ, ct := range payloadTypes {
+			info.PayloadTypes[typ.String()] = ct
+		}
+	}
+	for typ := range ctrl.converters() {
+		info.TypeConverters = append(info.TypeConverters, typ.String())
+	}
+	sort.Strings(info.TypeConverters)
+
+	if ctrl.Conditional != nil {
+		info.Hooks = append(info.Hooks, "Conditional")
+	}
+	if ctrl.Signer != nil {
+		info.Hooks = append(info.Hooks, "Signer")
+	}
+	if ctrl.Audit != nil {
+		info.Hooks = append(info.Hooks, "Audit")
+	}
+	if ctrl.CSRF != nil {
+		info.Hooks = append(info.Hooks, "CSRF")
+	}
+	return info
+}
+
+func describeResponders(registrations map[ContentType]responders.Registration) []ResponderInfo {
+	var infos []ResponderInfo
+	for contentType, reg := range registrations {
+		if reg.Func == nil {
+			continue
+		}
+		infos = append(infos, ResponderInfo{
+			ContentType: contentType,
+			CanEncode:   reg.CanEncode != nil,
+			Structured:  structured(reg),
+		})
+	}
+	sortResponderInfos(infos)
+	return infos
+}
+
+func sortResponderInfos(infos []ResponderInfo) {
+	sort.Slice(infos, func(i, j int) bool {
+		if infos[i].ContentType != infos[j].ContentType {
+			return infos[i].ContentType < infos[j].ContentType
+		}
+		return infos[i].Profile < infos[j].Profile
+	})
+}
+
+// String returns a short summary of the controller's content types
+func (ctrl *Controller) String() string {
+	if ctrl == nil {
+		return "render.Controller(nil)"
+	}
+	info := ctrl.Describe()
+	responders := make([]string, len(info.Responders))
+	for i := range info.Responders {
+		responders[i] = string(info.Responders[i].ContentType)
+	}
+	decoders := make([]string, len(info.Decoders))
+	for i := range info.Decoders {
+		decoders[i] = string(info.Decoders[i].ContentType)
+	}
+	return fmt.Sprintf("render.Controller{responders: [%s], decoders: [%s], default: %s}",
+		strings.Join(responders, " "), strings.Join(decoders, " "), info.DefaultResponse)
+}
@@ -0,0 +1,56 @@
+package render
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDescribe(t *testing.T) {
+	ctrl := defaultCtrl.Clone()
+	ctrl.DeniedAccept = SetOfContentTypes(ContentTypeHTML)
+	ctrl.KeyCasing = KeyCasingSnake
+	ctrl.Audit = func(*http.Request, AuditRecord) {}
+	_ = ctrl.SetDecoderLimit(ContentTypeJSON, 1024)
+	_ = ctrl.RegisterTypeEncoder(reflect.TypeOf(testPoint{}), func(v interface{}) (interface{}, error) { return v, nil })
+	_ = ctrl.RegisterTypeConverter(timeType, TimeConverter(time.RFC3339))
+
+	expected := ControllerInfo{
+		Responders: []ResponderInfo{
+			{ContentType: ContentTypeDefault, Structured: true},
+			{ContentType: ContentTypeJSON, CanEncode: true, Structured: true},
+			{ContentType: ContentTypeData, CanEncode: true},
+			{ContentType: ContentTypeEventStream, Structured: true},
+			{ContentType: ContentTypeXML, CanEncode: true, Structured: true},
+		},
+		Decoders: []DecoderInfo{
+			{ContentType: ContentTypeJSON, Limit: 1024},
+			{ContentType: ContentTypeURLEncoded},
+			{ContentType: ContentTypeForm},
+			{ContentType: ContentTypeXML},
+		},
+		DefaultRequest:   ctrl.DefaultRequest,
+		DefaultResponse:  ContentTypeDefault,
+		DeniedAccept:     []ContentType{ContentTypeHTML},
+		TypeEncoders:     []string{"render.testPoint"},
+		TypeConverters:   []string{"time.Time"},
+		Hooks:            []string{"Audit"},
+		Fallback:         "next_accepted",
+		MalformedHeaders: "lenient",
+		Digest:           "off",
+		RaceCheck:        "off",
+		Redact:           "off",
+		KeyCasing:        "snake",
+		DurationFormat:   "as_is",
+		Nulls:            "as_is",
+	}
+	got := ctrl.Describe()
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("describe, expected\n%+v\ngot\n%+v", expected, got)
+	}
+
+	if str, expected := ctrl.String(), "render.Controller{responders: [*/* application/json application/octet-stream text/event-stream text/xml], decoders: [application/json application/x-www-form-urlencoded multipart/form-data text/xml], default: */*}"; str != expected {
+		t.Errorf("string, expected %v, got %v", expected, str)
+	}
+}
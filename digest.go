@@ -0,0 +1,56 @@
+package render
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+)
+
+// DigestAlgorithm is the checksum the controller sets on buffered responses,
+// for partners that verify the integrity of the bodies they receive.
+type DigestAlgorithm uint8
+
+const (
+	// DigestOff sets no checksum header
+	DigestOff DigestAlgorithm = iota
+	// DigestSHA256 sets the Digest header (RFC 3230) to the sha-256 of the body
+	DigestSHA256
+	// DigestSHA512 sets the Digest header (RFC 3230) to the sha-512 of the body
+	DigestSHA512
+	// DigestMD5 sets the Content-MD5 header (RFC 1864) to the md5 of the body
+	DigestMD5
+)
+
+// String returns the name of the algorithm, as used by Describe
+func (alg DigestAlgorithm) String() string {
+	switch alg {
+	case DigestOff:
+		return "off"
+	case DigestSHA256:
+		return "sha-256"
+	case DigestSHA512:
+		return "sha-512"
+	case DigestMD5:
+		return "md5"
+	default:
+		return fmt.Sprintf("DigestAlgorithm(%d)", uint8(alg))
+	}
+}
+
+// Set sets the checksum header of the algorithm for the body
+func (alg DigestAlgorithm) Set(header http.Header, body []byte) {
+	switch alg {
+	case DigestSHA256:
+		sum := sha256.Sum256(body)
+		header.Set("Digest", "sha-256="+base64.StdEncoding.EncodeToString(sum[:]))
+	case DigestSHA512:
+		sum := sha512.Sum512(body)
+		header.Set("Digest", "sha-512="+base64.StdEncoding.EncodeToString(sum[:]))
+	case DigestMD5:
+		sum := md5.Sum(body)
+		header.Set("Content-MD5", base64.StdEncoding.EncodeToString(sum[:]))
+	}
+}
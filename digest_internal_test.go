@@ -0,0 +1,72 @@
+package render
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDigest(t *testing.T) {
+	type tcase struct {
+		Digest   DigestAlgorithm
+		Header   string
+		Expected func(body []byte) string
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			ctrl := defaultCtrl.Clone()
+			ctrl.Digest = tc.Digest
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("Accept", "application/json")
+			w := httptest.NewRecorder()
+			ctrl.respond(w, r, map[string]string{"hello": "world"})
+
+			for _, name := range []string{"Digest", "Content-MD5"} {
+				got := w.Header().Get(name)
+				var expected string
+				if name == tc.Header {
+					expected = tc.Expected(w.Body.Bytes())
+				}
+				if got != expected {
+					t.Errorf("%s, expected %q, got %q", name, expected, got)
+				}
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"off": {},
+		"sha-256": {
+			Digest: DigestSHA256,
+			Header: "Digest",
+			Expected: func(body []byte) string {
+				sum := sha256.Sum256(body)
+				return "sha-256=" + base64.StdEncoding.EncodeToString(sum[:])
+			},
+		},
+		"sha-512": {
+			Digest: DigestSHA512,
+			Header: "Digest",
+			Expected: func(body []byte) string {
+				sum := sha512.Sum512(body)
+				return "sha-512=" + base64.StdEncoding.EncodeToString(sum[:])
+			},
+		},
+		"md5": {
+			Digest: DigestMD5,
+			Header: "Content-MD5",
+			Expected: func(body []byte) string {
+				sum := md5.Sum(body)
+				return base64.StdEncoding.EncodeToString(sum[:])
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
@@ -0,0 +1,168 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// ErrInvalidEnum is the error that EnumError values match using errors.Is.
+var ErrInvalidEnum = errors.New("render: invalid enum value")
+
+// Enum is implemented by string types that have a fixed set of allowed values.
+//
+// Bind rejects values that are not one of the allowed values with an *EnumError,
+// matching them case insensitively and storing the canonical spelling, as
+// returned by EnumValues. The structured responders write the canonical spelling
+// as well.
+//
+//	type Status string
+//
+//	func (Status) EnumValues() []string { return []string{"draft", "published"} }
+type Enum interface {
+	EnumValues() []string
+}
+
+var enumType = reflect.TypeOf((*Enum)(nil)).Elem()
+
+// EnumError is returned by Bind when a payload has a value for an Enum that is
+// not one of the allowed values
+type EnumError struct {
+	// Field is the path of the field with the value
+	Field string
+	// Value is the value that was sent
+	Value string
+	// Allowed are the allowed values
+	Allowed []string
+}
+
+func (err *EnumError) Error() string {
+	return fmt.Sprintf("render: invalid value %q for field '%s'; allowed values are: %s",
+		err.Value, err.Field, strings.Join(err.Allowed, ", "))
+}
+
+// Is reports whether target is ErrInvalidEnum
+func (err *EnumError) Is(target error) bool { return target == ErrInvalidEnum }
+
+// StatusCode is the http status code that should be reported to the client
+func (err *EnumError) StatusCode() int { return http.StatusBadRequest }
+
+// CanonicalEnum returns the canonical spelling of value from the allowed values
+// of the enum; ok will be false if value is not allowed.
+func CanonicalEnum(enum Enum, value string) (canonical string, ok bool) {
+	for _, allowed := range enum.EnumValues() {
+		if strings.EqualFold(allowed, value) {
+			return allowed, true
+		}
+	}
+	return value, false
+}
+
+// isEnum reports whether v is a string that is an Enum
+func isEnum(v reflect.Value) (Enum, bool) {
+	if v.Kind() != reflect.String || !v.Type().Implements(enumType) {
+		return nil, false
+	}
+	return v.Interface().(Enum), true
+}
+
+// bindEnum validates the enum value, and replaces it with the canonical spelling
+func bindEnum(v reflect.Value, path string) error {
+	enum, ok := isEnum(v)
+	if !ok {
+		return nil
+	}
+	canonical, ok := CanonicalEnum(enum, v.String())
+	if !ok {
+		return &EnumError{
+			Field:   path,
+			Value:   v.String(),
+			Allowed: enum.EnumValues(),
+		}
+	}
+	if canonical != v.String() && v.CanSet() {
+		v.SetString(canonical)
+	}
+	return nil
+}
+
+// containsEnumCache caches whether a type can hold enums, so payloads with
+// enums can be projected to their canonical spelling
+var containsEnumCache sync.Map // map[reflect.Type]bool
+
+// containsEnum reports whether values of the type can hold enums; values held
+// by interfaces are not considered.
+func containsEnum(typ reflect.Type) bool {
+	if typ == nil {
+		return false
+	}
+	if has, ok := containsEnumCache.Load(typ); ok {
+		return has.(bool)
+	}
+	has := typeContains(typ, func(typ reflect.Type, _ *structField) bool {
+		return typ.Kind() == reflect.String && typ.Implements(enumType)
+	})
+	containsEnumCache.Store(typ, has)
+	return has
+}
+
+// canonicalEnums returns a copy of the payload with the enums it holds replaced
+// by their canonical spelling
+func canonicalEnums(v interface{}) interface{} {
+	rv := reflect.ValueOf(v)
+	c := reflect.New(rv.Type()).Elem()
+	c.Set(deepCopy(rv, make(map[visited]reflect.Value)))
+	canonicalizeEnums(c, make(map[visited]bool))
+	return c.Interface()
+}
+
+// canonicalizeEnums replaces the enums reachable from the settable value with
+// their canonical spelling; values held by interfaces are not considered.
+func canonicalizeEnums(v reflect.Value, seen map[visited]bool) {
+	if !containsEnum(v.Type()) {
+		return
+	}
+	if enum, ok := isEnum(v); ok {
+		if canonical, _ := CanonicalEnum(enum, v.String()); canonical != v.String() && v.CanSet() {
+			v.SetString(canonical)
+		}
+		return
+	}
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return
+		}
+		key := visited{ptr: v.Pointer(), typ: v.Type()}
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		canonicalizeEnums(v.Elem(), seen)
+
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Field(i).CanSet() {
+				canonicalizeEnums(v.Field(i), seen)
+			}
+		}
+
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			canonicalizeEnums(v.Index(i), seen)
+		}
+
+	case reflect.Map:
+		// map values can not be set in place
+		iter := v.MapRange()
+		for iter.Next() {
+			value := reflect.New(v.Type().Elem()).Elem()
+			value.Set(iter.Value())
+			canonicalizeEnums(value, seen)
+			v.SetMapIndex(iter.Key(), value)
+		}
+	}
+}
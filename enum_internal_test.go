@@ -0,0 +1,151 @@
+package render
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gdey/chi-render/responders"
+)
+
+type testStatus string
+
+func (testStatus) EnumValues() []string { return []string{"draft", "published"} }
+
+func TestBindEnum(t *testing.T) {
+	type item struct {
+		Status testStatus `json:"status"`
+	}
+	type payload struct {
+		Status  testStatus            `json:"status"`
+		Maybe   *testStatus           `json:"maybe"`
+		Items   []item                `json:"items"`
+		ByName  map[string]testStatus `json:"by_name"`
+		Ignored testStatus            `json:"-"`
+		NilBinder
+	}
+	type tcase struct {
+		Body     string
+		Expected payload
+		Err      *EnumError
+	}
+
+	published := testStatus("published")
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.Body))
+			r.Header.Set("Content-Type", "application/json")
+
+			var p payload
+			err := defaultCtrl.Bind(r, &p)
+			if tc.Err != nil {
+				var enumErr *EnumError
+				if !errors.As(err, &enumErr) || !errors.Is(err, ErrInvalidEnum) {
+					t.Errorf("error, expected *EnumError, got %v", err)
+					return
+				}
+				if !reflect.DeepEqual(enumErr, tc.Err) {
+					t.Errorf("error, expected %+v, got %+v", tc.Err, enumErr)
+				}
+				if enumErr.StatusCode() != http.StatusBadRequest {
+					t.Errorf("status code, expected %v, got %v", http.StatusBadRequest, enumErr.StatusCode())
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("error, expected nil, got %v", err)
+				return
+			}
+			if !reflect.DeepEqual(p, tc.Expected) {
+				t.Errorf("payload, expected %+v, got %+v", tc.Expected, p)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"valid": {
+			Body:     `{"status":"draft"}`,
+			Expected: payload{Status: "draft"},
+		},
+		"canonical": {
+			Body: `{"status":"DRAFT","maybe":"Published","items":[{"status":"Draft"}],"by_name":{"a":"PUBLISHED"}}`,
+			Expected: payload{
+				Status: "draft",
+				Maybe:  &published,
+				Items:  []item{{Status: "draft"}},
+				ByName: map[string]testStatus{"a": "published"},
+			},
+		},
+		"invalid": {
+			Body: `{"status":"deleted"}`,
+			Err:  &EnumError{Field: "status", Value: "deleted", Allowed: []string{"draft", "published"}},
+		},
+		"invalid nested": {
+			Body: `{"status":"draft","items":[{"status":"draft"},{"status":"gone"}]}`,
+			Err:  &EnumError{Field: "items[1].status", Value: "gone", Allowed: []string{"draft", "published"}},
+		},
+		"invalid map": {
+			Body: `{"status":"draft","by_name":{"a":"x"}}`,
+			Err:  &EnumError{Field: "by_name.a", Value: "x", Allowed: []string{"draft", "published"}},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestRespondEnum(t *testing.T) {
+	type article struct {
+		Title  string     `json:"title"`
+		Status testStatus `json:"status"`
+	}
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+	defaultCtrl.respond(w, r, []article{{Title: "a", Status: "PUBLISHED"}, {Title: "b", Status: "other"}})
+	expected := `[{"title":"a","status":"published"},{"title":"b","status":"other"}]` + "\n"
+	if got := w.Body.String(); got != expected {
+		t.Errorf("body, expected %s, got %s", expected, got)
+	}
+}
+
+// TestRespondEnumUnprojected checks that, with no policies in effect, the
+// responders get a copy of the payload, with the canonical spelling of its
+// enums, rather than a projection of it
+func TestRespondEnumUnprojected(t *testing.T) {
+	type article struct {
+		Title  string                `json:"title"`
+		Status *testStatus           `json:"status"`
+		ByTag  map[string]testStatus `json:"by_tag"`
+	}
+	ctrl := defaultCtrl.Clone()
+	var got interface{}
+	_ = ctrl.RegisterResponder(ContentTypeJSON, responders.Registration{
+		Func: func(w http.ResponseWriter, r *http.Request, v interface{}) error {
+			got = v
+			return nil
+		},
+		Structured: true,
+	})
+
+	status := testStatus("DRAFT")
+	payload := &article{Title: "a", Status: &status, ByTag: map[string]testStatus{"go": "Published"}}
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept", "application/json")
+	ctrl.respond(httptest.NewRecorder(), r, payload)
+
+	copied, ok := got.(*article)
+	if !ok {
+		t.Fatalf("payload, expected *article, got %T", got)
+	}
+	expected := &article{Title: "a", Status: new(testStatus), ByTag: map[string]testStatus{"go": "published"}}
+	*expected.Status = "draft"
+	if !reflect.DeepEqual(copied, expected) {
+		t.Errorf("payload, expected %+v, got %+v", expected, copied)
+	}
+	if status != "DRAFT" || payload.ByTag["go"] != "Published" {
+		t.Errorf("payload, expected the original to be left as is, got %+v", payload)
+	}
+}
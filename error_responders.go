@@ -0,0 +1,105 @@
+package render
+
+import (
+	"sort"
+
+	"github.com/gdey/chi-render/responders"
+	"google.golang.org/protobuf/proto"
+)
+
+// isErrorPayload reports whether the object being responded with should be
+// sent through the error channel
+func isErrorPayload(v interface{}) bool {
+	_, ok := v.(error)
+	return ok
+}
+
+// SetErrorResponder will set the responder used for error payloads for the given
+// content type. An error payload is any value that implements the error interface,
+// such as *ErrResponse. This allows errors to be rendered differently from
+// normal payloads for the same Accept header; for example, problem+json for errors
+// and plain JSON for everything else. If no error responder is registered for a
+// content type, the normal responder is used.
+// Use a nil RespondFunc to unset a content type
+// Only error this function will return is ErrControllerIsNil; is returned
+// if the Controller object is nil.
+func (ctrl *Controller) SetErrorResponder(contentType ContentType, responder responders.Func) error {
+	if ctrl == nil {
+		return ErrControllerIsNil
+	}
+	return ctrl.RegisterErrorResponder(contentType, responders.Registration{Func: responder})
+}
+
+// RegisterErrorResponder is like SetErrorResponder but takes a responder registration.
+// Use a registration with a nil Func to unset a content type
+// Only error this function will return is ErrControllerIsNil; is returned
+// if the Controller object is nil.
+func (ctrl *Controller) RegisterErrorResponder(contentType ContentType, registration responders.Registration) error {
+	if ctrl == nil {
+		return ErrControllerIsNil
+	}
+	ctrl.responderLck.Lock()
+	if ctrl.errorResponders == nil {
+		ctrl.errorResponders = make(map[ContentType]responders.Registration)
+	}
+	ctrl.errorResponders[contentType] = registration
+	ctrl.responderLck.Unlock()
+	return nil
+}
+
+// SupportedErrorResponders returns a ContentTypeSet of the configured Content types with error responders
+func (ctrl *Controller) SupportedErrorResponders() *ContentTypeSet {
+	if ctrl == nil {
+		return defaultCtrl.SupportedErrorResponders()
+	}
+
+	ctrl.responderLck.RLock()
+	stringValues := make([]string, 0, len(ctrl.errorResponders))
+	for value, reg := range ctrl.errorResponders {
+		if reg.Func == nil {
+			continue
+		}
+		stringValues = append(stringValues, string(value))
+	}
+	ctrl.responderLck.RUnlock()
+
+	sort.Strings(stringValues)
+	return NewContentTypeSet(stringValues...)
+}
+
+// responderFor returns the registration to use for the given content type and
+// object, preferring error responders for error payloads, and ProtoJSON for
+// proto.Message payloads of the JSON content types if the controller's
+// ProtoJSON is set. Content types with no responder fall back to the responder
+// for their +json or +xml suffix, labeling the response with the content type.
+func (ctrl *Controller) responderFor(contentType ContentType, v interface{}) (reg responders.Registration, ok bool) {
+	if isErrorPayload(v) {
+		ctrl.responderLck.RLock()
+		reg = ctrl.errorResponders[contentType]
+		ctrl.responderLck.RUnlock()
+		if reg.Func != nil {
+			return reg, true
+		}
+	}
+	reg, ok = ctrl.responder(contentType)
+	if ok && ctrl.ProtoJSON && (contentType == ContentTypeJSON || contentType == ContentTypeDefault) {
+		if _, isMessage := v.(proto.Message); isMessage {
+			// the message is encoded as it is, not projected
+			return responders.Registration{Func: responders.ProtoJSON}, true
+		}
+	}
+	if !ok {
+		if fallback, sok := suffixFallback(contentType); sok {
+			if reg, ok = ctrl.responderFor(fallback, v); ok {
+				reg.Func = suffixResponder(contentType, reg.Func)
+			}
+		}
+	}
+	return reg, ok
+}
+
+// SetErrorResponder will set the responder used for error payloads for the given content type.
+// Use a nil RespondFunc to unset a content type
+func SetErrorResponder(contentType ContentType, responder responders.Func) {
+	_ = defaultCtrl.SetErrorResponder(contentType, responder)
+}
@@ -2,8 +2,11 @@ package render
 
 import (
 	"crypto/rand"
+	"errors"
 	"log"
 	"net/http"
+
+	"github.com/gdey/chi-render/responders"
 )
 
 const (
@@ -70,6 +73,7 @@ type ErrResponse struct {
 	StatusText string `json:"status"`          // user-level status message
 	ErrorCode  string `json:"code"`            // application-specific error code
 	ErrorText  string `json:"error,omitempty"` // application-level error message, for debugging
+	Path       string `json:"path,omitempty"`  // path of the request field that failed, see BindError
 	// If you want to print out the issue set this the default ErrLogTo
 	LogTo func(*ErrResponse) `json:"-"`
 }
@@ -91,6 +95,13 @@ func (err *ErrResponse) Render(w http.ResponseWriter, r *http.Request) error {
 		}
 	}
 
+	if err.Path == "" {
+		var bindErr *BindError
+		if errors.As(err.Err, &bindErr) {
+			err.Path = bindErr.Path
+		}
+	}
+
 	// Set the http response status based on the error
 	Status(r, err.StatusCode)
 
@@ -108,3 +119,41 @@ func (err *ErrResponse) Render(w http.ResponseWriter, r *http.Request) error {
 
 	return nil
 }
+
+// Error returns the error text, this allows the ErrResponse to be recognized as an
+// error payload by the controller's error responders.
+func (err *ErrResponse) Error() string {
+	if err.ErrorText != "" {
+		return err.ErrorText
+	}
+	if err.Err != nil {
+		return err.Err.Error()
+	}
+	if err.StatusText != "" {
+		return err.StatusText
+	}
+	return http.StatusText(err.StatusCode)
+}
+
+// Unwrap returns the low-level runtime error
+func (err *ErrResponse) Unwrap() error { return err.Err }
+
+// ProblemDetails returns the ErrResponse as RFC 7807 problem details, allowing
+// it to be used with responders.ProblemJSON
+func (err *ErrResponse) ProblemDetails() responders.ProblemDetails {
+	pd := responders.ProblemDetails{
+		Title:  err.StatusText,
+		Status: err.StatusCode,
+		Detail: err.ErrorText,
+	}
+	if err.ErrorCode != "" {
+		pd.Extensions = map[string]interface{}{"code": err.ErrorCode}
+	}
+	if err.Path != "" {
+		if pd.Extensions == nil {
+			pd.Extensions = make(map[string]interface{})
+		}
+		pd.Extensions["path"] = err.Path
+	}
+	return pd
+}
@@ -0,0 +1,44 @@
+package render
+
+import "fmt"
+
+// FallbackStrategy is what the controller does when the responder of the best
+// match of the Accept header can not encode the payload, see Controller.Fallback.
+// Content types the controller has no responder for are always skipped.
+type FallbackStrategy uint8
+
+const (
+	// FallbackNextAccepted tries the next content types the request accepts,
+	// in order, and then the default responder
+	FallbackNextAccepted FallbackStrategy = iota
+	// FallbackJSON is like FallbackNextAccepted, but tries the JSON responder
+	// before the default responder
+	FallbackJSON
+	// FallbackDefault goes straight to the default responder, without trying
+	// the other content types the request accepts
+	FallbackDefault
+	// FallbackNone fails fast, with a 406 Not Acceptable response
+	FallbackNone
+)
+
+// String returns the name of the strategy, as used by Describe
+func (strategy FallbackStrategy) String() string {
+	switch strategy {
+	case FallbackNextAccepted:
+		return "next_accepted"
+	case FallbackJSON:
+		return "json"
+	case FallbackDefault:
+		return "default"
+	case FallbackNone:
+		return "none"
+	default:
+		return fmt.Sprintf("FallbackStrategy(%d)", uint8(strategy))
+	}
+}
+
+// triesNextAccepted reports whether the other content types the request
+// accepts are tried when a responder can not encode the payload
+func (strategy FallbackStrategy) triesNextAccepted() bool {
+	return strategy == FallbackNextAccepted || strategy == FallbackJSON
+}
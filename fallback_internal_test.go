@@ -0,0 +1,94 @@
+package render
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gdey/chi-render/responders"
+)
+
+type fallbackPayload struct {
+	Name string `json:"name" xml:"name"`
+}
+
+func (*fallbackPayload) Render(http.ResponseWriter, *http.Request) error { return nil }
+
+func TestFallback(t *testing.T) {
+	type tcase struct {
+		Fallback    FallbackStrategy
+		Accept      string
+		Status      int
+		ContentType string
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			ctrl := defaultCtrl.Clone()
+			ctrl.DefaultResponse = ContentTypeXML
+			ctrl.Fallback = tc.Fallback
+			// the plain text responder can not encode any payload
+			_ = ctrl.RegisterResponder(ContentTypePlainText, responders.Registration{
+				Func:      responders.PlainText,
+				CanEncode: func(interface{}) bool { return false },
+			})
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("Accept", tc.Accept)
+			w := httptest.NewRecorder()
+			if err := ctrl.Render(w, r, &fallbackPayload{Name: "fallback"}); err != nil {
+				t.Fatalf("error, expected nil, got %v", err)
+			}
+			if w.Code != tc.Status {
+				t.Errorf("status, expected %v, got %v", tc.Status, w.Code)
+			}
+			if got := w.Header().Get("Content-Type"); got != tc.ContentType {
+				t.Errorf("content type, expected %v, got %v", tc.ContentType, got)
+			}
+		}
+	}
+
+	const (
+		jsonType = "application/json; charset=utf-8"
+		xmlType  = "application/xml; charset=utf-8"
+	)
+	tests := map[string]tcase{
+		"next accepted": {
+			Accept:      "text/plain, application/json",
+			Status:      http.StatusOK,
+			ContentType: jsonType,
+		},
+		"next accepted then default": {
+			Accept:      "text/plain, application/octet-stream",
+			Status:      http.StatusOK,
+			ContentType: xmlType,
+		},
+		"json before default": {
+			Fallback:    FallbackJSON,
+			Accept:      "text/plain, application/octet-stream",
+			Status:      http.StatusOK,
+			ContentType: jsonType,
+		},
+		"default": {
+			Fallback:    FallbackDefault,
+			Accept:      "text/plain, application/json",
+			Status:      http.StatusOK,
+			ContentType: xmlType,
+		},
+		"none": {
+			Fallback:    FallbackNone,
+			Accept:      "text/plain, application/json",
+			Status:      http.StatusNotAcceptable,
+			ContentType: "text/plain; charset=utf-8",
+		},
+		"none without responder": {
+			Fallback:    FallbackNone,
+			Accept:      "text/html, application/json",
+			Status:      http.StatusOK,
+			ContentType: jsonType,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
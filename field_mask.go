@@ -0,0 +1,140 @@
+package render
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gdey/chi-render/responders/helpers"
+)
+
+var (
+	FieldMaskCtxKey = helpers.FieldMaskCtxKey
+)
+
+// Fields is the set of top level fields that were present in a request body
+type Fields map[string]struct{}
+
+// Has reports whether the field, by its JSON name, was present in the request body.
+// Like encoding/json, names are matched case insensitively.
+func (fields Fields) Has(name string) bool {
+	if _, ok := fields[name]; ok {
+		return true
+	}
+	for field := range fields {
+		if strings.EqualFold(field, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// FieldMask returns the top level fields that were present in the JSON, or
+// JSON Merge Patch, body decoded by Bind, so update handlers can tell a field
+// that was omitted from a field that was set to its zero value. It returns nil
+// if no JSON object has been bound for the request.
+//
+//	if render.FieldMask(r).Has("title") {
+//		article.Title = data.Title
+//	}
+func FieldMask(r *http.Request) Fields {
+	fields, _ := r.Context().Value(FieldMaskCtxKey).(Fields)
+	return fields
+}
+
+// recordFieldMask records the fields in the request context
+func recordFieldMask(r *http.Request, fields Fields) {
+	*r = *r.WithContext(context.WithValue(r.Context(), FieldMaskCtxKey, fields))
+}
+
+// fieldMaskWriter collects the names of the top level members of the JSON
+// object written to it as the body is decoded, so the body does not have to be
+// kept to record them. Only the name being read is buffered.
+type fieldMaskWriter struct {
+	fields Fields
+	// started is set once the first value has started, object if it is one
+	started, object bool
+	// done is set once the top level value has ended
+	done bool
+	// depth is the nesting of the objects and arrays
+	depth int
+	// key is set if the next string at the top level is a member name
+	key bool
+	// inString, escaped and name track the string being read; name is only
+	// kept for member names
+	inString, escaped, inName bool
+	name                      []byte
+}
+
+func (fm *fieldMaskWriter) Write(p []byte) (int, error) {
+	for _, c := range p {
+		if fm.done {
+			break
+		}
+		if fm.inString {
+			if fm.inName {
+				fm.name = append(fm.name, c)
+			}
+			switch {
+			case fm.escaped:
+				fm.escaped = false
+			case c == '\\':
+				fm.escaped = true
+			case c == '"':
+				fm.inString = false
+				if fm.inName {
+					fm.inName = false
+					fm.addName()
+				}
+			}
+			continue
+		}
+		switch c {
+		case ' ', '\t', '\r', '\n':
+			continue
+		case '"':
+			fm.inString = true
+			if fm.depth == 1 && fm.object && fm.key {
+				fm.inName, fm.key = true, false
+				fm.name = append(fm.name[:0], c)
+			}
+		case '{', '[':
+			if !fm.started {
+				fm.started, fm.object = true, c == '{'
+				fm.fields = make(Fields)
+				fm.key = fm.object
+			}
+			fm.depth++
+		case '}', ']':
+			fm.depth--
+			if fm.depth == 0 {
+				fm.done = true
+			}
+		case ',':
+			fm.key = fm.depth == 1
+		}
+		if !fm.started {
+			// the body is not a JSON object
+			fm.started, fm.done = true, true
+		}
+	}
+	return len(p), nil
+}
+
+// addName adds the member name that was read, unquoting it
+func (fm *fieldMaskWriter) addName() {
+	var name string
+	if err := json.Unmarshal(fm.name, &name); err == nil {
+		fm.fields[name] = struct{}{}
+	}
+}
+
+// Fields returns the names of the members of the object; nil if the body was
+// not a complete JSON object
+func (fm *fieldMaskWriter) Fields() Fields {
+	if !fm.object || !fm.done {
+		return nil
+	}
+	return fm.fields
+}
@@ -0,0 +1,88 @@
+package render
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestFieldMask(t *testing.T) {
+	type payload struct {
+		Title     string `json:"title"`
+		Published bool   `json:"published"`
+		Views     int    `json:"views"`
+		NilBinder
+	}
+	type tcase struct {
+		ContentType string
+		Body        string
+		Has         []string
+		Missing     []string
+		Nil         bool
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(tc.Body))
+			r.Header.Set("Content-Type", tc.ContentType)
+
+			var p payload
+			if err := defaultCtrl.Bind(r, &p); err != nil {
+				t.Errorf("error, expected nil, got %v", err)
+				return
+			}
+			mask := FieldMask(r)
+			if tc.Nil {
+				if mask != nil {
+					t.Errorf("mask, expected nil, got %v", mask)
+				}
+				return
+			}
+			for _, name := range tc.Has {
+				if !mask.Has(name) {
+					t.Errorf("has %v, expected true, got false", name)
+				}
+			}
+			for _, name := range tc.Missing {
+				if mask.Has(name) {
+					t.Errorf("has %v, expected false, got true", name)
+				}
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"zero values": {
+			ContentType: "application/json",
+			Body:        `{"title":"","published":false}`,
+			Has:         []string{"title", "published", "Title"},
+			Missing:     []string{"views"},
+		},
+		"nested fields are not top level": {
+			ContentType: "application/json",
+			Body:        `{"views":1,"extra":{"title":"x"}}` + "\n",
+			Has:         []string{"views", "extra"},
+			Missing:     []string{"title", "published"},
+		},
+		"escaped names": {
+			ContentType: "application/json",
+			Body:        `{"ti\u0074le":"a \"views\", b","extra":["published",{"views":1}]}`,
+			Has:         []string{"title", "extra"},
+			Missing:     []string{"views", "published"},
+		},
+		"not an object": {
+			ContentType: "application/json",
+			Body:        `null`,
+			Nil:         true,
+		},
+		"xml": {
+			ContentType: "text/xml",
+			Body:        `<payload><title>x</title></payload>`,
+			Nil:         true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
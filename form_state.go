@@ -0,0 +1,172 @@
+package render
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/gdey/chi-render/decoders"
+	"github.com/gdey/chi-render/responders/helpers"
+)
+
+// FormStateCtxKey is a context key for the state of a form that failed to bind
+var FormStateCtxKey = helpers.FormStateCtxKey
+
+// FieldErrors are the errors of several fields of a payload, by field name.
+// Binders can return them to report every invalid field of a form at once,
+// rather than just the first one.
+type FieldErrors map[string]string
+
+func (errs FieldErrors) Error() string {
+	names := make([]string, 0, len(errs))
+	for name := range errs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for i, name := range names {
+		names[i] = fmt.Sprintf("%s: %s", name, errs[name])
+	}
+	return "render: invalid fields: " + strings.Join(names, "; ")
+}
+
+// StatusCode is the http status code that should be reported to the client
+func (FieldErrors) StatusCode() int { return http.StatusUnprocessableEntity }
+
+// FormState is the state of an HTML form that failed to bind: the values that
+// were sent, and the errors of its fields, so the page with the form can be
+// rendered again for the user to correct. The methods of a nil *FormState are
+// safe to call, so the same template can render the empty form.
+//
+//	<input name="title" value="{{.Form.Value "title"}}">
+//	{{with .Form.Error "title"}}<p class="error">{{.}}</p>{{end}}
+type FormState struct {
+	// Values are the values of the form that was sent; files are left out
+	Values url.Values
+	// Errors are the messages of the invalid fields, by field name
+	Errors map[string]string
+	// Err is the error Bind returned
+	Err error
+}
+
+// Value returns the value that was sent for the field
+func (state *FormState) Value(name string) string {
+	if state == nil {
+		return ""
+	}
+	return state.Values.Get(name)
+}
+
+// Error returns the error message of the field; empty if it is valid
+func (state *FormState) Error(name string) string {
+	if state == nil {
+		return ""
+	}
+	return state.Errors[name]
+}
+
+// Invalid reports whether the form failed to bind
+func (state *FormState) Invalid() bool { return state != nil && state.Err != nil }
+
+// GetFormState returns the state of the form that failed to bind in BindForm;
+// nil if there is none. The Render method of the page with the form can use it
+// to fill the form back in.
+func GetFormState(r *http.Request) *FormState {
+	state, _ := r.Context().Value(FormStateCtxKey).(*FormState)
+	return state
+}
+
+// FormValuesCtxKey is a context key for the values of the form in the request body
+var FormValuesCtxKey = helpers.FormValuesCtxKey
+
+// recordFormValues puts the values of the form the request body was decoded
+// from in the request context
+func recordFormValues(r *http.Request, values url.Values) {
+	*r = *r.WithContext(context.WithValue(r.Context(), FormValuesCtxKey, values))
+}
+
+// formValues returns the values of the form the request body was decoded from;
+// files are left out. Nil if the body was not decoded by a form decoder.
+func formValues(r *http.Request) url.Values {
+	values, _ := r.Context().Value(FormValuesCtxKey).(url.Values)
+	return values
+}
+
+// BindForm binds the request like Bind. If binding fails, and the request is
+// an HTML form submission, the values that were sent and the errors of the
+// fields are put in the request context as a FormState, and form, usually the
+// page the form is on, is rendered with a 422 Unprocessable Entity status so
+// the user can correct it. Other requests, like those of JSON clients, get the
+// error rendered as an ErrResponse.
+//
+// Form requests that fail CSRF verification get a 403 Forbidden ErrResponse
+// rather than the form.
+//
+// BindForm returns false if binding failed, and the response has been written.
+//
+//	if !render.BindForm(w, r, &article, &ArticleFormPage{}) {
+//		return
+//	}
+func (ctrl *Controller) BindForm(w http.ResponseWriter, r *http.Request, v Binder, form Renderer) bool {
+	if ctrl == nil {
+		return defaultCtrl.BindForm(w, r, v, form)
+	}
+	ct := GetRequestContentType(r, ctrl.DefaultRequest)
+	isForm := ct == ContentTypeForm || ct == ContentTypeURLEncoded
+	err := ctrl.Bind(r, v)
+	if err == nil {
+		return true
+	}
+	if errors.Is(err, ErrInvalidCSRFToken) {
+		_ = ctrl.Render(w, r, &ErrResponse{Err: err, StatusCode: http.StatusForbidden})
+		return false
+	}
+	if !isForm {
+		_ = ctrl.Render(w, r, &ErrResponse{Err: err, StatusCode: (&BindError{Cause: err}).StatusCode()})
+		return false
+	}
+
+	state := &FormState{
+		Values: formValues(r),
+		Errors: fieldErrors(err),
+		Err:    err,
+	}
+	*r = *r.WithContext(context.WithValue(r.Context(), FormStateCtxKey, state))
+	Status(r, http.StatusUnprocessableEntity)
+	if err := ctrl.Render(w, r, form); err != nil {
+		_ = ctrl.Render(w, r, &ErrResponse{Err: err, StatusCode: http.StatusInternalServerError})
+	}
+	return false
+}
+
+// fieldErrors returns the error messages of the fields that failed to bind
+func fieldErrors(err error) map[string]string {
+	var (
+		fields    FieldErrors
+		fieldErr  *decoders.FieldError
+		schemaErr *decoders.SchemaError
+		enumErr   *EnumError
+		bindErr   *BindError
+	)
+	switch {
+	case errors.As(err, &fields):
+		errs := make(map[string]string, len(fields))
+		for name, msg := range fields {
+			errs[name] = msg
+		}
+		return errs
+	case errors.As(err, &fieldErr):
+		return map[string]string{fieldErr.Field: fieldErr.Err.Error()}
+	case errors.As(err, &schemaErr):
+		return schemaErr.Fields()
+	case errors.As(err, &enumErr):
+		return map[string]string{enumErr.Field: enumErr.Error()}
+	case errors.As(err, &bindErr) && bindErr.Path != "":
+		return map[string]string{bindErr.Path: bindErr.Cause.Error()}
+	default:
+		return nil
+	}
+}
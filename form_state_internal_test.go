@@ -0,0 +1,153 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gdey/chi-render/responders/helpers"
+)
+
+type formArticle struct {
+	Title string `form:"title" json:"title"`
+	Age   int    `form:"age" json:"age"`
+}
+
+func (a *formArticle) Bind(r *http.Request) error {
+	if a.Title == "" {
+		return FieldErrors{"title": "is required"}
+	}
+	return nil
+}
+
+type formPage struct {
+	Form *FormState
+}
+
+func (p *formPage) Render(w http.ResponseWriter, r *http.Request) error {
+	p.Form = GetFormState(r)
+	return nil
+}
+
+func TestBindForm(t *testing.T) {
+	type tcase struct {
+		Fields map[string]string
+		// URLEncoded sends the fields urlencoded, rather than multipart
+		URLEncoded  bool
+		JSON        string
+		OK          bool
+		Status      int
+		Body        string
+		ContentType string
+	}
+
+	ctrl := defaultCtrl.Clone()
+	_ = ctrl.SetResponder(ContentTypeHTML, func(w http.ResponseWriter, r *http.Request, v interface{}) error {
+		page, ok := v.(*formPage)
+		if !ok {
+			return fmt.Errorf("unexpected payload %T", v)
+		}
+		helpers.WriteStatus(w, r.Context())
+		_, err := fmt.Fprintf(w, "title=%q age=%q title error=%q age error=%q",
+			page.Form.Value("title"), page.Form.Value("age"), page.Form.Error("title"), page.Form.Error("age"))
+		return err
+	})
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			var r *http.Request
+			if tc.URLEncoded {
+				values := make(url.Values)
+				for name, value := range tc.Fields {
+					values.Set(name, value)
+				}
+				r = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(values.Encode()))
+				r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+				r.Header.Set("Accept", "text/html")
+			} else if tc.Fields != nil {
+				var body bytes.Buffer
+				mw := multipart.NewWriter(&body)
+				for name, value := range tc.Fields {
+					_ = mw.WriteField(name, value)
+				}
+				_ = mw.Close()
+				r = httptest.NewRequest(http.MethodPost, "/", &body)
+				r.Header.Set("Content-Type", mw.FormDataContentType())
+				r.Header.Set("Accept", "text/html")
+			} else {
+				r = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.JSON))
+				r.Header.Set("Content-Type", "application/json")
+				r.Header.Set("Accept", "application/json")
+			}
+			w := httptest.NewRecorder()
+
+			var article formArticle
+			if ok := ctrl.BindForm(w, r, &article, &formPage{}); ok != tc.OK {
+				t.Fatalf("ok, expected %v, got %v", tc.OK, ok)
+			}
+			if tc.OK {
+				if w.Body.Len() != 0 {
+					t.Errorf("body, expected none, got %q", w.Body.String())
+				}
+				return
+			}
+			if w.Code != tc.Status {
+				t.Errorf("status, expected %v, got %v", tc.Status, w.Code)
+			}
+			if !strings.Contains(w.Body.String(), tc.Body) {
+				t.Errorf("body, expected %q in %q", tc.Body, w.Body.String())
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"valid": {
+			Fields: map[string]string{"title": "hello", "age": "3"},
+			OK:     true,
+		},
+		"invalid value": {
+			Fields: map[string]string{"title": "hello", "age": "three"},
+			Status: http.StatusUnprocessableEntity,
+			Body:   `title="hello" age="three" title error="" age error="strconv.ParseInt: parsing \"three\": invalid syntax"`,
+		},
+		"field errors": {
+			Fields: map[string]string{"age": "3"},
+			Status: http.StatusUnprocessableEntity,
+			Body:   `title="" age="3" title error="is required" age error=""`,
+		},
+		"urlencoded valid": {
+			Fields:     map[string]string{"title": "hello", "age": "3"},
+			URLEncoded: true,
+			OK:         true,
+		},
+		"urlencoded field errors": {
+			Fields:     map[string]string{"age": "3"},
+			URLEncoded: true,
+			Status:     http.StatusUnprocessableEntity,
+			Body:       `title="" age="3" title error="is required" age error=""`,
+		},
+		"json": {
+			JSON:   `{"age":3}`,
+			Status: http.StatusUnprocessableEntity,
+			Body:   `"error":"render: invalid fields: title: is required"`,
+		},
+		"json syntax": {
+			JSON:   `{"age":`,
+			Status: http.StatusBadRequest,
+			Body:   `"status":"Bad Request"`,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+
+	var state *FormState
+	if state.Value("title") != "" || state.Error("title") != "" || state.Invalid() {
+		t.Errorf("nil form state, expected empty values")
+	}
+}
@@ -1,3 +1,13 @@
 module github.com/gdey/chi-render
 
-go 1.15
+go 1.16
+
+require (
+	github.com/andybalholm/brotli v1.0.5
+	github.com/fxamacker/cbor/v2 v2.4.0
+	github.com/go-chi/chi v1.5.5
+	github.com/linkedin/goavro/v2 v2.9.8
+	github.com/santhosh-tekuri/jsonschema/v5 v5.3.0
+	google.golang.org/protobuf v1.28.1
+	gopkg.in/yaml.v3 v3.0.1
+)
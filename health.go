@@ -0,0 +1,141 @@
+package render
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HealthState is the state of a service, or one of its checks
+type HealthState string
+
+const (
+	// HealthPass is a healthy service or check
+	HealthPass = HealthState("pass")
+	// HealthWarn is a service or check that is healthy, but with concerns
+	HealthWarn = HealthState("warn")
+	// HealthFail is an unhealthy service or check
+	HealthFail = HealthState("fail")
+)
+
+// severity orders the states from healthy to unhealthy
+func (state HealthState) severity() int {
+	switch state {
+	case HealthWarn:
+		return 1
+	case HealthFail:
+		return 2
+	default:
+		return 0
+	}
+}
+
+// HealthCheck is the result of checking one of the dependencies of a service
+type HealthCheck struct {
+	Status HealthState `json:"status"`
+	// Duration is how long the check took
+	Duration time.Duration `json:"duration"`
+	// Output is the reason a check did not pass
+	Output string `json:"output,omitempty"`
+}
+
+// HealthStatus is a renderer for health and readiness endpoints. It responds
+// with 200 OK, unless the status is HealthFail, in which case it responds
+// with 503 Service Unavailable, so load balancers can act on the status code
+// alone.
+//
+// It is written as JSON by the structured responders, and as a line per check
+// by responders.PlainText, if the controller has it registered.
+type HealthStatus struct {
+	// Status is the overall state; if empty it is the worst state of the checks
+	Status HealthState `json:"status"`
+	// Checks are the results of the checks, by name
+	Checks map[string]HealthCheck `json:"checks,omitempty"`
+	// Duration is how long all the checks took
+	Duration time.Duration `json:"duration"`
+}
+
+// Render sets the overall status, if not set, and the status code of the response
+func (health *HealthStatus) Render(_ http.ResponseWriter, r *http.Request) error {
+	if health.Status == "" {
+		health.Status = HealthPass
+		for _, check := range health.Checks {
+			if check.Status.severity() > health.Status.severity() {
+				health.Status = check.Status
+			}
+		}
+	}
+	if health.Status == HealthFail {
+		Status(r, http.StatusServiceUnavailable)
+	} else {
+		Status(r, http.StatusOK)
+	}
+	return nil
+}
+
+// String returns the plain text representation; the overall status followed by
+// a line for each check, sorted by name.
+func (health *HealthStatus) String() string {
+	names := make([]string, 0, len(health.Checks))
+	for name := range health.Checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s (%v)\n", health.Status, health.Duration)
+	for _, name := range names {
+		check := health.Checks[name]
+		fmt.Fprintf(&b, "%s: %s (%v)", name, check.Status, check.Duration)
+		if check.Output != "" {
+			fmt.Fprintf(&b, " %s", check.Output)
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// HealthChecker checks one of the dependencies of a service; a nil error
+// passes the check.
+type HealthChecker func(ctx context.Context) error
+
+// CheckHealth runs the checks concurrently and returns their results, with the
+// time each took. Failed checks have their error as the output.
+//
+//	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
+//		_ = render.Render(w, r, render.CheckHealth(r.Context(), checks))
+//	})
+func CheckHealth(ctx context.Context, checks map[string]HealthChecker) *HealthStatus {
+	var (
+		lck   sync.Mutex
+		wg    sync.WaitGroup
+		start = time.Now()
+	)
+	health := &HealthStatus{Checks: make(map[string]HealthCheck, len(checks))}
+	for name, checker := range checks {
+		wg.Add(1)
+		go func(name string, checker HealthChecker) {
+			defer wg.Done()
+			checkStart := time.Now()
+			err := checker(ctx)
+			check := HealthCheck{
+				Status:   HealthPass,
+				Duration: time.Since(checkStart),
+			}
+			if err != nil {
+				check.Status = HealthFail
+				check.Output = err.Error()
+			}
+			lck.Lock()
+			health.Checks[name] = check
+			lck.Unlock()
+		}(name, checker)
+	}
+	wg.Wait()
+	health.Duration = time.Since(start)
+	return health
+}
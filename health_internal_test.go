@@ -0,0 +1,118 @@
+package render
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gdey/chi-render/responders"
+)
+
+func TestHealthStatus(t *testing.T) {
+	type tcase struct {
+		Health *HealthStatus
+		Accept string
+		Status int
+		State  HealthState
+		Body   string
+	}
+
+	ctrl := defaultCtrl.Clone()
+	_ = ctrl.SetResponder(ContentTypePlainText, responders.PlainText)
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/health", nil)
+			r.Header.Set("Accept", tc.Accept)
+			w := httptest.NewRecorder()
+			if err := ctrl.Render(w, r, tc.Health); err != nil {
+				t.Fatalf("error, expected nil, got %v", err)
+			}
+			if w.Code != tc.Status {
+				t.Errorf("status, expected %v, got %v", tc.Status, w.Code)
+			}
+			if tc.Health.Status != tc.State {
+				t.Errorf("state, expected %v, got %v", tc.State, tc.Health.Status)
+			}
+			if got := w.Body.String(); got != tc.Body {
+				t.Errorf("body, expected %q, got %q", tc.Body, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"pass json": {
+			Health: &HealthStatus{
+				Checks:   map[string]HealthCheck{"db": {Status: HealthPass, Duration: time.Millisecond}},
+				Duration: time.Millisecond,
+			},
+			Accept: "application/json",
+			Status: http.StatusOK,
+			State:  HealthPass,
+			Body:   `{"status":"pass","checks":{"db":{"status":"pass","duration":1000000}},"duration":1000000}` + "\n",
+		},
+		"warn": {
+			Health: &HealthStatus{
+				Checks: map[string]HealthCheck{
+					"db":    {Status: HealthPass},
+					"cache": {Status: HealthWarn, Output: "slow"},
+				},
+			},
+			Accept: "text/plain",
+			Status: http.StatusOK,
+			State:  HealthWarn,
+			Body:   "warn (0s)\ncache: warn (0s) slow\ndb: pass (0s)\n",
+		},
+		"fail": {
+			Health: &HealthStatus{
+				Checks: map[string]HealthCheck{
+					"db":    {Status: HealthFail, Output: "connection refused"},
+					"cache": {Status: HealthWarn},
+				},
+			},
+			Accept: "text/plain",
+			Status: http.StatusServiceUnavailable,
+			State:  HealthFail,
+			Body:   "fail (0s)\ncache: warn (0s)\ndb: fail (0s) connection refused\n",
+		},
+		"explicit": {
+			Health: &HealthStatus{Status: HealthFail},
+			Accept: "application/json",
+			Status: http.StatusServiceUnavailable,
+			State:  HealthFail,
+			Body:   `{"status":"fail","duration":0}` + "\n",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestCheckHealth(t *testing.T) {
+	health := CheckHealth(context.Background(), map[string]HealthChecker{
+		"db":    func(context.Context) error { return nil },
+		"queue": func(context.Context) error { return errors.New("unreachable") },
+	})
+	if len(health.Checks) != 2 {
+		t.Fatalf("checks, expected 2, got %v", len(health.Checks))
+	}
+	if check := health.Checks["db"]; check.Status != HealthPass {
+		t.Errorf("db, expected pass, got %+v", check)
+	}
+	if check := health.Checks["queue"]; check.Status != HealthFail || check.Output != "unreachable" {
+		t.Errorf("queue, expected fail with output, got %+v", check)
+	}
+	if _, err := json.Marshal(health); err != nil {
+		t.Errorf("marshal error, expected nil, got %v", err)
+	}
+	r := httptest.NewRequest(http.MethodGet, "/health", nil)
+	w := httptest.NewRecorder()
+	_ = defaultCtrl.Render(w, r, health)
+	if w.Code != http.StatusServiceUnavailable || health.Status != HealthFail {
+		t.Errorf("status, expected 503 and fail, got %v and %v", w.Code, health.Status)
+	}
+}
@@ -0,0 +1,209 @@
+package render
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/gdey/chi-render/responders/helpers"
+)
+
+const (
+	// IdempotencyKeyHeader is the request header holding the idempotency key
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on responses that are replayed from the store
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+var (
+	IdempotencyCtxKey = helpers.IdempotencyCtxKey
+)
+
+// CapturedResponse is a rendered response as it was sent to the client
+type CapturedResponse struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+// IdempotencyStore stores the captured responses by idempotency key. The
+// store is responsible for scoping the keys (for example by user) and for
+// expiring them.
+type IdempotencyStore interface {
+	// Load returns the response captured for the key; ok is false if there is none.
+	Load(r *http.Request, key string) (resp CapturedResponse, ok bool, err error)
+	// Store saves the response captured for the key.
+	Store(r *http.Request, key string, resp CapturedResponse) error
+}
+
+// IdempotencyReserver can be implemented by an IdempotencyStore to reserve a
+// key while the request that sent it is being handled, so concurrent requests
+// with the same key are refused rather than handled twice. Stores shared by
+// several instances should implement it; otherwise the keys are only reserved
+// within the instance.
+type IdempotencyReserver interface {
+	// Reserve marks the key as in flight; ok is false if it already is.
+	// release is called once the response has been captured, or not.
+	Reserve(r *http.Request, key string) (release func(), ok bool, err error)
+}
+
+// MemoryIdempotencyStore is an IdempotencyStore that keeps the responses in memory,
+// forever. It is meant for tests and single instance deployments.
+type MemoryIdempotencyStore struct {
+	lck       sync.RWMutex
+	responses map[string]CapturedResponse
+	reserved  keyReservations
+}
+
+// Load returns the response captured for the key
+func (store *MemoryIdempotencyStore) Load(_ *http.Request, key string) (CapturedResponse, bool, error) {
+	store.lck.RLock()
+	resp, ok := store.responses[key]
+	store.lck.RUnlock()
+	return resp, ok, nil
+}
+
+// Store saves the response captured for the key
+func (store *MemoryIdempotencyStore) Store(_ *http.Request, key string, resp CapturedResponse) error {
+	store.lck.Lock()
+	if store.responses == nil {
+		store.responses = make(map[string]CapturedResponse)
+	}
+	store.responses[key] = resp
+	store.lck.Unlock()
+	return nil
+}
+
+// Reserve marks the key as in flight
+func (store *MemoryIdempotencyStore) Reserve(_ *http.Request, key string) (func(), bool, error) {
+	release, ok := store.reserved.reserve(key)
+	return release, ok, nil
+}
+
+// keyReservations are the keys of the requests in flight
+type keyReservations struct {
+	lck  sync.Mutex
+	keys map[string]bool
+}
+
+// reserve marks the key as in flight; ok is false if it already is
+func (res *keyReservations) reserve(key string) (release func(), ok bool) {
+	res.lck.Lock()
+	defer res.lck.Unlock()
+	if res.keys[key] {
+		return nil, false
+	}
+	if res.keys == nil {
+		res.keys = make(map[string]bool)
+	}
+	res.keys[key] = true
+	return func() {
+		res.lck.Lock()
+		delete(res.keys, key)
+		res.lck.Unlock()
+	}, true
+}
+
+// idempotencyCapture is placed on the request context by the Idempotency
+// middleware, so the controller can capture the response it renders.
+type idempotencyCapture struct {
+	store IdempotencyStore
+	key   string
+}
+
+// Idempotency is a middleware that replays the response previously rendered for
+// the Idempotency-Key header of the request, without calling the handler. If
+// there is no previous response, the next response the controller renders for
+// the request is captured into the store. Server errors (5xx) are not
+// captured so the request can be retried.
+//
+// The key is reserved while the request is handled, see IdempotencyReserver;
+// a request sent with a key that is in flight gets a 409 Conflict response.
+//
+// Only responses rendered by a Controller are captured; errors storing the
+// response are not reported to the client.
+func Idempotency(store IdempotencyStore) func(http.Handler) http.Handler {
+	// local reserves the keys if the store can not
+	local := new(keyReservations)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			release, ok, err := reserveIdempotent(r, store, local, key)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			if !ok {
+				http.Error(w, "idempotency key is in use by a request in flight", http.StatusConflict)
+				return
+			}
+			defer release()
+			// loaded after the key is reserved, so a request that finished
+			// in the mean time is replayed rather than handled again
+			resp, ok, err := store.Load(r, key)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			if ok {
+				replay(w, resp)
+				return
+			}
+			capture := &idempotencyCapture{store: store, key: key}
+			*r = *r.WithContext(context.WithValue(r.Context(), IdempotencyCtxKey, capture))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// replay writes the captured response
+func replay(w http.ResponseWriter, resp CapturedResponse) {
+	dst := w.Header()
+	for name, values := range resp.Header {
+		dst[name] = append([]string(nil), values...)
+	}
+	dst.Set(IdempotentReplayedHeader, "true")
+	status := resp.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.WriteHeader(status)
+	_, _ = w.Write(resp.Body)
+}
+
+// reserveIdempotent reserves the key with the store, if it is an
+// IdempotencyReserver, or in the local reservations otherwise
+func reserveIdempotent(r *http.Request, store IdempotencyStore, local *keyReservations, key string) (release func(), ok bool, err error) {
+	if reserver, isReserver := store.(IdempotencyReserver); isReserver {
+		return reserver.Reserve(r, key)
+	}
+	release, ok = local.reserve(key)
+	return release, ok, nil
+}
+
+// idempotencyCaptureFor returns the capture of the request, if the response
+// rendered for it is to be captured
+func idempotencyCaptureFor(r *http.Request) *idempotencyCapture {
+	capture, _ := r.Context().Value(IdempotencyCtxKey).(*idempotencyCapture)
+	return capture
+}
+
+// save stores the response that was sent to the client; server errors are not
+// stored so the request can be retried
+func (capture *idempotencyCapture) save(r *http.Request, status int, header http.Header, body []byte) {
+	if status == 0 {
+		status = http.StatusOK
+	}
+	if status >= http.StatusInternalServerError {
+		return
+	}
+	_ = capture.store.Store(r, capture.key, CapturedResponse{
+		Status: status,
+		Header: header.Clone(),
+		Body:   append([]byte(nil), body...),
+	})
+}
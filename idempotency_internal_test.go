@@ -0,0 +1,141 @@
+package render
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIdempotency(t *testing.T) {
+	type tcase struct {
+		Key     string
+		Status  int
+		Calls   int
+		Replays bool
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			calls := 0
+			handler := Idempotency(new(MemoryIdempotencyStore))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				Status(r, tc.Status)
+				defaultCtrl.respond(w, r, map[string]int{"call": calls})
+			}))
+
+			var bodies []string
+			for i := 0; i < 2; i++ {
+				r := httptest.NewRequest(http.MethodPost, "/", nil)
+				if tc.Key != "" {
+					r.Header.Set(IdempotencyKeyHeader, tc.Key)
+				}
+				w := httptest.NewRecorder()
+				handler.ServeHTTP(w, r)
+				if w.Code != tc.Status {
+					t.Errorf("status, expected %v, got %v", tc.Status, w.Code)
+				}
+				if i == 1 {
+					replayed := w.Header().Get(IdempotentReplayedHeader) == "true"
+					if replayed != tc.Replays {
+						t.Errorf("replayed, expected %v, got %v", tc.Replays, replayed)
+					}
+				}
+				bodies = append(bodies, w.Body.String())
+			}
+			if calls != tc.Calls {
+				t.Errorf("calls, expected %v, got %v", tc.Calls, calls)
+			}
+			if tc.Replays && bodies[0] != bodies[1] {
+				t.Errorf("replayed body, expected %q, got %q", bodies[0], bodies[1])
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"no key": {
+			Status: http.StatusCreated,
+			Calls:  2,
+		},
+		"replayed": {
+			Key:     "abc",
+			Status:  http.StatusCreated,
+			Calls:   1,
+			Replays: true,
+		},
+		"server error not captured": {
+			Key:    "abc",
+			Status: http.StatusServiceUnavailable,
+			Calls:  2,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestIdempotencyInFlight(t *testing.T) {
+	for name, store := range map[string]IdempotencyStore{
+		"reserver":     new(MemoryIdempotencyStore),
+		"not reserver": struct{ IdempotencyStore }{new(MemoryIdempotencyStore)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			entered, done := make(chan struct{}), make(chan struct{})
+			handler := Idempotency(store)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				close(entered)
+				<-done
+				defaultCtrl.respond(w, r, map[string]int{"call": 1})
+			}))
+			newRequest := func() *http.Request {
+				r := httptest.NewRequest(http.MethodPost, "/", nil)
+				r.Header.Set(IdempotencyKeyHeader, "abc")
+				return r
+			}
+
+			first := httptest.NewRecorder()
+			finished := make(chan struct{})
+			go func() {
+				handler.ServeHTTP(first, newRequest())
+				close(finished)
+			}()
+			<-entered
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, newRequest())
+			if w.Code != http.StatusConflict {
+				t.Errorf("in flight status, expected %v, got %v", http.StatusConflict, w.Code)
+			}
+
+			close(done)
+			<-finished
+			w = httptest.NewRecorder()
+			handler.ServeHTTP(w, newRequest())
+			if w.Header().Get(IdempotentReplayedHeader) != "true" || w.Body.String() != first.Body.String() {
+				t.Errorf("after, expected replay of %q, got %q", first.Body.String(), w.Body.String())
+			}
+		})
+	}
+}
+
+type idempotencyPayload struct {
+	NilRender
+	Name string `json:"name"`
+}
+
+func TestIdempotencyIgnoresBufferRenders(t *testing.T) {
+	store := new(MemoryIdempotencyStore)
+	handler := Idempotency(store)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// like the body of an email sent while handling the request
+		if _, _, _, err := defaultCtrl.RenderToBuffer(r, &idempotencyPayload{Name: "email"}); err != nil {
+			t.Errorf("render to buffer, unexpected error %v", err)
+		}
+		defaultCtrl.respond(w, r, &idempotencyPayload{Name: "response"})
+	}))
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	r.Header.Set(IdempotencyKeyHeader, "abc")
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+
+	resp, ok, _ := store.Load(r, "abc")
+	if want := "{\"name\":\"response\"}\n"; !ok || string(resp.Body) != want {
+		t.Errorf("captured body, expected %q, got %q", want, resp.Body)
+	}
+}
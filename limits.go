@@ -0,0 +1,112 @@
+package render
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gdey/chi-render/responders"
+	"github.com/gdey/chi-render/responders/helpers"
+)
+
+// ErrBodyTooLarge is the error that BodyTooLargeError values match using errors.Is.
+var ErrBodyTooLarge = errors.New("render: request body too large")
+
+// BodyTooLargeError is returned by Bind when the request body is larger than
+// the limit configured for the request content type, or the controller's
+// MaxBodyBytes.
+type BodyTooLargeError struct {
+	// ContentType is the content type of the request body
+	ContentType ContentType
+	// Limit is the maximum number of bytes that was allowed
+	Limit int64
+}
+
+func (err *BodyTooLargeError) Error() string {
+	return fmt.Sprintf("render: request body for '%s' exceeds limit of %d bytes", err.ContentType, err.Limit)
+}
+
+// Is reports whether target is ErrBodyTooLarge
+func (err *BodyTooLargeError) Is(target error) bool { return target == ErrBodyTooLarge }
+
+// StatusCode is the http status code that should be reported to the client
+func (err *BodyTooLargeError) StatusCode() int { return http.StatusRequestEntityTooLarge }
+
+// limitReader is like io.LimitedReader, but instead of returning io.EOF once
+// the limit is reached it returns a *BodyTooLargeError if there is more data.
+type limitReader struct {
+	r        io.Reader
+	err      *BodyTooLargeError
+	left     int64
+	exceeded bool
+}
+
+func newLimitReader(r io.Reader, contentType ContentType, limit int64) *limitReader {
+	return &limitReader{
+		r:    r,
+		left: limit,
+		err: &BodyTooLargeError{
+			ContentType: contentType,
+			Limit:       limit,
+		},
+	}
+}
+
+func (lr *limitReader) Read(p []byte) (n int, err error) {
+	if lr.exceeded {
+		return 0, lr.err
+	}
+	// read one byte past the limit so that we know if there is more data
+	if int64(len(p)) > lr.left+1 {
+		p = p[:lr.left+1]
+	}
+	n, err = lr.r.Read(p)
+	if int64(n) > lr.left {
+		lr.exceeded = true
+		n = int(lr.left)
+		lr.left = 0
+		return n, lr.err
+	}
+	lr.left -= int64(n)
+	return n, err
+}
+
+// ErrResponseTooLarge is the error that ResponseTooLargeError values match using
+// errors.Is; it is also returned by the responders that stop encoding once the
+// response is too large.
+var ErrResponseTooLarge = responders.ErrResponseTooLarge
+
+// ResponseTooLargeError is reported when a responder produces a response body
+// larger than the controller's MaxResponseBytes.
+type ResponseTooLargeError struct {
+	// ContentType is the content type the responder was producing
+	ContentType string
+	// Limit is the maximum number of bytes that was allowed
+	Limit int64
+}
+
+func (err *ResponseTooLargeError) Error() string {
+	return fmt.Sprintf("render: response body for '%s' exceeds limit of %d bytes", err.ContentType, err.Limit)
+}
+
+// Is reports whether target is ErrResponseTooLarge
+func (err *ResponseTooLargeError) Is(target error) bool { return target == ErrResponseTooLarge }
+
+// StatusCode is the http status code that should be reported to the client
+func (err *ResponseTooLargeError) StatusCode() int { return http.StatusInternalServerError }
+
+// ResponseLimitCtxKey is a context key for the maximum number of bytes the
+// responders may encode the payload to
+var ResponseLimitCtxKey = helpers.ResponseLimitCtxKey
+
+// withResponseLimit records the controller's MaxResponseBytes in the request, so
+// the responders that honor it, like JSON and XML, stop encoding once the
+// response is too large rather than after it has been encoded in full.
+func (ctrl *Controller) withResponseLimit(r *http.Request) {
+	if ctrl.MaxResponseBytes <= 0 {
+		return
+	}
+	*r = *r.WithContext(context.WithValue(r.Context(), ResponseLimitCtxKey, ctrl.MaxResponseBytes))
+}
@@ -0,0 +1,215 @@
+package render
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDecoderLimit(t *testing.T) {
+	type tcase struct {
+		Body  string
+		Limit int64
+		Err   error
+	}
+
+	type payload struct {
+		Name string `json:"name"`
+		NilBinder
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			ctrl := CloneDefault()
+			_ = ctrl.SetDecoderLimit(ContentTypeJSON, tc.Limit)
+
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.Body))
+			r.Header.Set("Content-Type", "application/json")
+
+			var p payload
+			err := ctrl.Bind(r, &p)
+			if tc.Err == nil {
+				if err != nil {
+					t.Errorf("error, expected nil, got %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tc.Err) {
+				t.Errorf("error, expected %v, got %v", tc.Err, err)
+				return
+			}
+			var tooLarge *BodyTooLargeError
+			if !errors.As(err, &tooLarge) {
+				t.Errorf("error, expected *BodyTooLargeError, got %T", err)
+				return
+			}
+			if tooLarge.StatusCode() != http.StatusRequestEntityTooLarge {
+				t.Errorf("status code, expected %v, got %v", http.StatusRequestEntityTooLarge, tooLarge.StatusCode())
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"no limit": {
+			Body: `{"name":"world"}`,
+		},
+		"under limit": {
+			Body:  `{"name":"world"}`,
+			Limit: 100,
+		},
+		"exactly limit": {
+			Body:  `{"name":"world"}`,
+			Limit: int64(len(`{"name":"world"}`)),
+		},
+		"over limit": {
+			Body:  `{"name":"` + strings.Repeat("a", 100) + `"}`,
+			Limit: 10,
+			Err:   ErrBodyTooLarge,
+		},
+		"trailing data over limit": {
+			Body:  `{"name":"world"}` + strings.Repeat(" ", 100),
+			Limit: 20,
+			Err:   ErrBodyTooLarge,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestMaxBodyBytes(t *testing.T) {
+	type tcase struct {
+		Body          string
+		ContentLength int64
+		MaxBodyBytes  int64
+		Limit         int64
+		Err           *BodyTooLargeError
+	}
+
+	type payload struct {
+		Name string `json:"name"`
+		NilBinder
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			ctrl := CloneDefault()
+			ctrl.MaxBodyBytes = tc.MaxBodyBytes
+			_ = ctrl.SetDecoderLimit(ContentTypeJSON, tc.Limit)
+
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.Body))
+			r.Header.Set("Content-Type", "application/json")
+			r.ContentLength = tc.ContentLength
+
+			var p payload
+			err := ctrl.Bind(r, &p)
+			if tc.Err == nil {
+				if err != nil {
+					t.Errorf("error, expected nil, got %v", err)
+				}
+				return
+			}
+			var tooLarge *BodyTooLargeError
+			if !errors.As(err, &tooLarge) {
+				t.Fatalf("error, expected *BodyTooLargeError, got %v", err)
+			}
+			if *tooLarge != *tc.Err {
+				t.Errorf("error, expected %v, got %v", tc.Err, tooLarge)
+			}
+		}
+	}
+
+	body := `{"name":"` + strings.Repeat("a", 100) + `"}`
+	tests := map[string]tcase{
+		"under": {
+			Body:         `{"name":"world"}`,
+			MaxBodyBytes: 100,
+		},
+		"over": {
+			Body:          body,
+			ContentLength: -1,
+			MaxBodyBytes:  50,
+			Err:           &BodyTooLargeError{ContentType: ContentTypeJSON, Limit: 50},
+		},
+		"content length over": {
+			Body:          body,
+			ContentLength: int64(len(body)),
+			MaxBodyBytes:  50,
+			Err:           &BodyTooLargeError{ContentType: ContentTypeJSON, Limit: 50},
+		},
+		"smaller decoder limit": {
+			Body:          body,
+			ContentLength: -1,
+			MaxBodyBytes:  50,
+			Limit:         20,
+			Err:           &BodyTooLargeError{ContentType: ContentTypeJSON, Limit: 20},
+		},
+		"larger decoder limit": {
+			Body:          body,
+			ContentLength: -1,
+			MaxBodyBytes:  50,
+			Limit:         200,
+			Err:           &BodyTooLargeError{ContentType: ContentTypeJSON, Limit: 50},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestMaxResponseBytes(t *testing.T) {
+	type tcase struct {
+		Max    int64
+		V      interface{}
+		Status int
+		Logged bool
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			var logged *ErrResponse
+			defer func(logTo func(*ErrResponse)) { ErrorLogTo = logTo }(ErrorLogTo)
+			ErrorLogTo = func(err *ErrResponse) { logged = err }
+
+			ctrl := defaultCtrl.Clone()
+			ctrl.MaxResponseBytes = tc.Max
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("Accept", "application/json")
+			w := httptest.NewRecorder()
+			ctrl.respond(w, r, tc.V)
+
+			if w.Code != tc.Status {
+				t.Errorf("status, expected %v, got %v", tc.Status, w.Code)
+			}
+			if (logged != nil) != tc.Logged {
+				t.Fatalf("logged, expected %v, got %v", tc.Logged, logged)
+			}
+			if logged != nil && !errors.Is(logged.Err, ErrResponseTooLarge) {
+				t.Errorf("logged error, expected %v, got %v", ErrResponseTooLarge, logged.Err)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"under": {
+			Max:    100,
+			V:      []string{"hello"},
+			Status: http.StatusOK,
+		},
+		"over": {
+			Max:    100,
+			V:      []string{strings.Repeat("hello", 100)},
+			Status: http.StatusInternalServerError,
+			Logged: true,
+		},
+		"no limit": {
+			V:      []string{strings.Repeat("hello", 100)},
+			Status: http.StatusOK,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
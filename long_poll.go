@@ -0,0 +1,50 @@
+package render
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/gdey/chi-render/responders/helpers"
+)
+
+// LongPoll waits for the first item from the channel, ch, and responds with
+// it, rendered like Render. If no item arrives within the timeout, or the
+// channel is closed, it responds with 204 No Content so the client polls again.
+// It is a simpler alternative to an event stream for clients that can only
+// make plain requests.
+//
+// If the client goes away first nothing is written, and the error of the
+// request context is returned.
+func (ctrl *Controller) LongPoll(w http.ResponseWriter, r *http.Request, ch interface{}, timeout time.Duration) error {
+	if ctrl == nil {
+		return defaultCtrl.LongPoll(w, r, ch, timeout)
+	}
+	chv := reflect.ValueOf(ch)
+	if chv.Kind() != reflect.Chan || chv.Type().ChanDir()&reflect.RecvDir == 0 {
+		return fmt.Errorf("render: long poll expects a receivable channel, not %T", ch)
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	ctx := r.Context()
+	chosen, recv, ok := reflect.Select([]reflect.SelectCase{
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())},
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(timer.C)},
+		{Dir: reflect.SelectRecv, Chan: chv},
+	})
+	switch {
+	case chosen == 0: // equivalent to: case <-ctx.Done()
+		return ctx.Err()
+	case chosen == 1 || !ok: // timed out, or the channel was closed
+		helpers.NoContent(w)
+		return nil
+	}
+	v := recv.Interface()
+	if rv, ok := v.(Renderer); ok {
+		return ctrl.Render(w, r, rv)
+	}
+	ctrl.respond(w, r, v)
+	return nil
+}
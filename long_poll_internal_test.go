@@ -0,0 +1,86 @@
+package render
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdey/chi-render/responders"
+)
+
+func TestLongPoll(t *testing.T) {
+	type tcase struct {
+		Channel func() interface{}
+		Status  int
+		Body    string
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/poll", nil)
+			r.Header.Set("Accept", "application/json")
+			w := httptest.NewRecorder()
+			if err := defaultCtrl.LongPoll(w, r, tc.Channel(), 10*time.Millisecond); err != nil {
+				t.Fatalf("error, expected nil, got %v", err)
+			}
+			if w.Code != tc.Status {
+				t.Errorf("status, expected %v, got %v", tc.Status, w.Code)
+			}
+			if got := strings.TrimSpace(w.Body.String()); !strings.HasPrefix(got, tc.Body) {
+				t.Errorf("body, expected %s, got %s", tc.Body, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"item": {
+			Channel: func() interface{} {
+				ch := make(chan responders.M, 1)
+				ch <- responders.M{"event": "created"}
+				return ch
+			},
+			Status: http.StatusOK,
+			Body:   `{"event":"created"}`,
+		},
+		"renderer": {
+			Channel: func() interface{} {
+				ch := make(chan Renderer, 1)
+				ch <- &ErrResponse{StatusCode: http.StatusConflict}
+				return ch
+			},
+			Status: http.StatusConflict,
+			Body:   `{"status":"Conflict","code":`,
+		},
+		"timeout": {
+			Channel: func() interface{} { return make(chan responders.M) },
+			Status:  http.StatusNoContent,
+		},
+		"closed": {
+			Channel: func() interface{} {
+				ch := make(chan responders.M)
+				close(ch)
+				return ch
+			},
+			Status: http.StatusNoContent,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestLongPollCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	r := httptest.NewRequest(http.MethodGet, "/poll", nil).WithContext(ctx)
+	w := httptest.NewRecorder()
+	if err := defaultCtrl.LongPoll(w, r, make(chan int), time.Second); err != context.Canceled {
+		t.Errorf("error, expected %v, got %v", context.Canceled, err)
+	}
+	if err := defaultCtrl.LongPoll(w, r, 5, time.Second); err == nil {
+		t.Errorf("error, expected an error for a non channel, got nil")
+	}
+}
@@ -0,0 +1,162 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// HeaderPolicy is how the controller handles malformed Accept and Content-Type
+// request headers: media types that do not parse, that have no subtype, like
+// "application", or whose parameters are invalid.
+type HeaderPolicy uint8
+
+const (
+	// HeaderLenient ignores the malformed media types of the Accept header,
+	// and decodes request bodies with a malformed Content-Type as
+	// DefaultRequest. Media types with invalid parameters are used without
+	// their parameters.
+	HeaderLenient HeaderPolicy = iota
+	// HeaderStrict responds 406 Not Acceptable to requests with a malformed
+	// Accept header, and fails Bind with a 400 Bad Request *MalformedHeaderError
+	// for a malformed Content-Type header.
+	HeaderStrict
+)
+
+// String returns the name of the policy, as used by Describe
+func (policy HeaderPolicy) String() string {
+	switch policy {
+	case HeaderLenient:
+		return "lenient"
+	case HeaderStrict:
+		return "strict"
+	default:
+		return fmt.Sprintf("HeaderPolicy(%d)", uint8(policy))
+	}
+}
+
+// ErrMalformedHeader is returned, when the controller's MalformedHeaders policy
+// is HeaderStrict, for requests with a malformed Accept or Content-Type header
+var ErrMalformedHeader = errors.New("render: malformed header")
+
+// MalformedHeaderError is the error for a malformed Accept or Content-Type
+// header
+type MalformedHeaderError struct {
+	// Header is the name of the header; it is the AcceptOverrideHeader of the
+	// controller if the media types were negotiated from it
+	Header string
+	Value  string
+	Cause  error
+}
+
+func (err *MalformedHeaderError) Error() string {
+	return fmt.Sprintf("render: malformed %s header %q: %v", err.Header, err.Value, err.Cause)
+}
+
+// Is reports whether target is ErrMalformedHeader
+func (err *MalformedHeaderError) Is(target error) bool { return target == ErrMalformedHeader }
+
+func (err *MalformedHeaderError) Unwrap() error { return err.Cause }
+
+// StatusCode is the http status code that should be reported to the client;
+// 400 Bad Request for the Content-Type header, 406 Not Acceptable otherwise.
+func (err *MalformedHeaderError) StatusCode() int {
+	if http.CanonicalHeaderKey(err.Header) == "Content-Type" {
+		return http.StatusBadRequest
+	}
+	return http.StatusNotAcceptable
+}
+
+// parseMediaType parses the media type, checking it has a type and a subtype.
+// If only the parameters are invalid, the media type is returned along with
+// mime.ErrInvalidMediaParameter.
+func parseMediaType(s string) (ContentType, map[string]string, error) {
+	mediaType, params, err := mime.ParseMediaType(s)
+	if err != nil && !errors.Is(err, mime.ErrInvalidMediaParameter) {
+		return "", nil, err
+	}
+	i := strings.IndexByte(mediaType, '/')
+	switch {
+	case i <= 0 || i == len(mediaType)-1:
+		return "", nil, fmt.Errorf("media type %q has no subtype", mediaType)
+	case mediaType[:i] == "*" && mediaType[i+1:] != "*":
+		return "", nil, fmt.Errorf("media type %q has a wildcard type and a specific subtype", mediaType)
+	}
+	return ContentType(mediaType), params, err
+}
+
+// lenientMediaType returns the media type of s, ignoring invalid parameters;
+// ok is false if s is malformed otherwise.
+func lenientMediaType(s string) (contentType ContentType, ok bool) {
+	contentType, _, err := parseMediaType(s)
+	return contentType, err == nil || errors.Is(err, mime.ErrInvalidMediaParameter)
+}
+
+// mediaTypeParams returns the parameters of the media type s. Unlike
+// mime.ParseMediaType, which drops all of them if one is invalid or repeated,
+// it keeps the parameters that parse, the first of repeated ones, so a stray
+// "; charset" does not lose the boundary of a multipart body.
+func mediaTypeParams(s string) map[string]string {
+	_, params, err := mime.ParseMediaType(s)
+	if err == nil {
+		return params
+	}
+	params = make(map[string]string)
+	segments := strings.Split(s, ";")
+	for i := 1; i < len(segments); i++ {
+		// quoted values may hold semicolons; try the longest segments first
+		for j := len(segments); j > i; j-- {
+			_, param, err := mime.ParseMediaType("x/x;" + strings.Join(segments[i:j], ";"))
+			if err != nil || len(param) != 1 {
+				continue
+			}
+			for k, v := range param {
+				if _, dup := params[k]; !dup {
+					params[k] = v
+				}
+			}
+			i = j - 1
+			break
+		}
+	}
+	return params
+}
+
+// checkAcceptHeader returns a *MalformedHeaderError, if the policy is
+// HeaderStrict, and one of the media types of the header's value is malformed
+func (policy HeaderPolicy) checkAcceptHeader(name, value string) error {
+	if policy != HeaderStrict {
+		return nil
+	}
+	for _, field := range strings.Split(value, ",") {
+		if strings.TrimSpace(field) == "" {
+			continue
+		}
+		if _, _, err := parseMediaType(field); err != nil {
+			return &MalformedHeaderError{Header: name, Value: value, Cause: err}
+		}
+	}
+	return nil
+}
+
+// checkContentType returns a *MalformedHeaderError, if the policy is
+// HeaderStrict, and the Content-Type of the request is malformed; the content
+// type set in the request context, with SetContentType, is not checked.
+func (policy HeaderPolicy) checkContentType(r *http.Request) error {
+	if policy != HeaderStrict {
+		return nil
+	}
+	if contentType, ok := r.Context().Value(ContentTypeCtxKey).(ContentType); ok && contentType != "" {
+		return nil
+	}
+	value := r.Header.Get("Content-Type")
+	if value == "" {
+		return nil
+	}
+	if _, _, err := parseMediaType(value); err != nil {
+		return &MalformedHeaderError{Header: "Content-Type", Value: value, Cause: err}
+	}
+	return nil
+}
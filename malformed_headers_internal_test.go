@@ -0,0 +1,245 @@
+package render
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMalformedAccept(t *testing.T) {
+	type tcase struct {
+		Policy      HeaderPolicy
+		Accept      string
+		Status      int
+		ContentType string
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			ctrl := defaultCtrl.Clone()
+			ctrl.MalformedHeaders = tc.Policy
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("Accept", tc.Accept)
+			w := httptest.NewRecorder()
+			if err := ctrl.Render(w, r, &fallbackPayload{Name: "accept"}); err != nil {
+				t.Fatalf("error, expected nil, got %v", err)
+			}
+			if w.Code != tc.Status {
+				t.Errorf("status, expected %v, got %v", tc.Status, w.Code)
+			}
+			if got := w.Header().Get("Content-Type"); got != tc.ContentType {
+				t.Errorf("content type, expected %v, got %v", tc.ContentType, got)
+			}
+		}
+	}
+
+	const (
+		jsonType = "application/json; charset=utf-8"
+		xmlType  = "application/xml; charset=utf-8"
+	)
+	tests := map[string]tcase{
+		"lenient garbage": {
+			Accept:      "garbage, text/xml",
+			Status:      http.StatusOK,
+			ContentType: xmlType,
+		},
+		"lenient missing subtype": {
+			Accept:      "text, text/xml",
+			Status:      http.StatusOK,
+			ContentType: xmlType,
+		},
+		"lenient bad parameters": {
+			Accept:      "text/xml; q",
+			Status:      http.StatusOK,
+			ContentType: xmlType,
+		},
+		"lenient all malformed": {
+			Accept:      "text, */xml",
+			Status:      http.StatusOK,
+			ContentType: jsonType,
+		},
+		"strict": {
+			Policy:      HeaderStrict,
+			Accept:      "application/json;q=0.9, text/xml",
+			Status:      http.StatusOK,
+			ContentType: jsonType,
+		},
+		"strict garbage": {
+			Policy:      HeaderStrict,
+			Accept:      "garbage, text/xml",
+			Status:      http.StatusNotAcceptable,
+			ContentType: "text/plain; charset=utf-8",
+		},
+		"strict bad parameters": {
+			Policy:      HeaderStrict,
+			Accept:      "text/xml; q",
+			Status:      http.StatusNotAcceptable,
+			ContentType: "text/plain; charset=utf-8",
+		},
+		"strict no accept": {
+			Policy:      HeaderStrict,
+			Status:      http.StatusOK,
+			ContentType: jsonType,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestMalformedContentType(t *testing.T) {
+	type tcase struct {
+		Policy      HeaderPolicy
+		ContentType string
+		Default     ContentType
+		Err         error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			ctrl := defaultCtrl.Clone()
+			ctrl.MalformedHeaders = tc.Policy
+			ctrl.DefaultRequest = tc.Default
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"gdey"}`))
+			r.Header.Set("Content-Type", tc.ContentType)
+			var got struct {
+				Name string `json:"name"`
+				NilBinder
+			}
+			err := ctrl.Bind(r, &got)
+			if tc.Err != nil {
+				if !errors.Is(err, tc.Err) {
+					t.Errorf("error, expected %v, got %v", tc.Err, err)
+				}
+				var malformed *MalformedHeaderError
+				if errors.As(err, &malformed) && malformed.StatusCode() != http.StatusBadRequest {
+					t.Errorf("status, expected %v, got %v", http.StatusBadRequest, malformed.StatusCode())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error, expected nil, got %v", err)
+			}
+			if got.Name != "gdey" {
+				t.Errorf("name, expected gdey, got %q", got.Name)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"lenient bad parameters": {
+			ContentType: "application/json; charset",
+		},
+		"lenient missing subtype": {
+			ContentType: "application",
+			Default:     ContentTypeJSON,
+		},
+		"strict": {
+			Policy:      HeaderStrict,
+			ContentType: "application/json; charset=utf-8",
+		},
+		"strict bad parameters": {
+			Policy:      HeaderStrict,
+			ContentType: "application/json; charset",
+			Err:         ErrMalformedHeader,
+		},
+		"strict missing subtype": {
+			Policy:      HeaderStrict,
+			ContentType: "application",
+			Default:     ContentTypeJSON,
+			Err:         ErrMalformedHeader,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestContentTypeParameters(t *testing.T) {
+	var form bytes.Buffer
+	mw := multipart.NewWriter(&form)
+	mw.WriteField("name", "gdey")
+	mw.Close()
+
+	type tcase struct {
+		ContentType string
+		// Forced is the content type set in the request context
+		Forced ContentType
+		Body   string
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.Body))
+			r.Header.Set("Content-Type", tc.ContentType)
+			if tc.Forced != "" {
+				r = r.WithContext(context.WithValue(r.Context(), ContentTypeCtxKey, tc.Forced))
+			}
+			var got struct {
+				Name string `json:"name" form:"name"`
+				NilBinder
+			}
+			if err := defaultCtrl.Bind(r, &got); err != nil {
+				t.Fatalf("error, expected nil, got %v", err)
+			}
+			if got.Name != "gdey" {
+				t.Errorf("name, expected gdey, got %q", got.Name)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"charset": {
+			ContentType: "application/json; charset=utf-8",
+			Body:        `{"name":"gdey"}`,
+		},
+		"upper case": {
+			ContentType: "Application/JSON; Charset=UTF-8",
+			Body:        `{"name":"gdey"}`,
+		},
+		"boundary": {
+			ContentType: mw.FormDataContentType(),
+			Body:        form.String(),
+		},
+		"boundary and bad parameter": {
+			ContentType: mw.FormDataContentType() + "; charset",
+			Body:        form.String(),
+		},
+		"boundary after bad parameter": {
+			ContentType: "multipart/form-data; charset; boundary=\"" + mw.Boundary() + "\"",
+			Body:        form.String(),
+		},
+		"forced with parameters": {
+			ContentType: "text/plain",
+			Forced:      ContentType("application/json; charset=utf-8"),
+			Body:        `{"name":"gdey"}`,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestMediaTypeParams(t *testing.T) {
+	tests := map[string]map[string]string{
+		"text/plain":                               {},
+		"text/plain; charset=utf-8":                {"charset": "utf-8"},
+		"text/plain; charset":                      {},
+		"multipart/form-data; charset; boundary=a": {"boundary": "a"},
+		`multipart/form-data; boundary="a;b"; x`:   {"boundary": "a;b"},
+		"text/plain; a=1; a=2; b=3":                {"a": "1", "b": "3"},
+	}
+	for value, expected := range tests {
+		t.Run(value, func(t *testing.T) {
+			if got := mediaTypeParams(value); !reflect.DeepEqual(got, expected) {
+				t.Errorf("params, expected %v, got %v", expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+)
+
+// ContentTypeMergePatch is the content type of JSON Merge Patch documents,
+// RFC 7386; it is not decoded by default:
+//
+//	ctrl.SetDecoder(render.ContentTypeMergePatch, render.DecodeMergePatch)
+const ContentTypeMergePatch = ContentType("application/merge-patch+json")
+
+// MergePatch merges the JSON Merge Patch onto target, which must be a pointer.
+// Members of the patch replace the members of the JSON encoding of target,
+// objects are merged recursively, and null members are removed, which zeroes
+// their fields. Fields that are not encoded as JSON are kept, and fields tagged
+// with protect are handled as Bind does. If the patch can not be decoded into
+// target, target is left untouched.
+func MergePatch(target interface{}, patch []byte) error {
+	rv := reflect.ValueOf(target)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("render: patch target must be a non nil pointer, got %T", target)
+	}
+	changes, err := decodeJSONValue(patch)
+	if err != nil {
+		return err
+	}
+	protected := protectedFields(target)
+	doc, err := jsonDocument(target)
+	if err != nil {
+		return err
+	}
+	return decodeDocument(rv, mergeJSONValue(doc, changes), protected)
+}
+
+// DecodeMergePatch is the decoder of application/merge-patch+json bodies; the
+// body is merged onto the payload with MergePatch, so the payload should hold
+// the current state of the resource before Bind is called:
+//
+//	data := &ArticleRequest{Article: article}
+//	if err := render.Bind(r, data); err != nil {
+//		...
+//	}
+//
+// As for JSON bodies, FieldMask returns the top level members of the patch,
+// so the Bind method of the payload can tell which fields the client changed.
+func DecodeMergePatch(r io.Reader, v interface{}) error {
+	defer io.Copy(ioutil.Discard, r)
+	patch, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return MergePatch(v, patch)
+}
+
+// mergeJSONValue merges the patch onto the target, RFC 7386
+func mergeJSONValue(target, patch interface{}) interface{} {
+	changes, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	merged, ok := target.(map[string]interface{})
+	if !ok {
+		merged = make(map[string]interface{}, len(changes))
+	}
+	for name, change := range changes {
+		if change == nil {
+			delete(merged, name)
+			continue
+		}
+		merged[name] = mergeJSONValue(merged[name], change)
+	}
+	return merged
+}
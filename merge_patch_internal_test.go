@@ -0,0 +1,131 @@
+package render
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMergePatch(t *testing.T) {
+	type tcase struct {
+		Patch    string
+		Expected patchArticle
+		Err      bool
+	}
+
+	original := func() patchArticle {
+		return patchArticle{
+			Title:    "hello",
+			Tags:     []string{"go", "http"},
+			Rating:   4,
+			Meta:     map[string]string{"a": "b", "c": "d"},
+			Author:   &patchAuthor{Name: "gdey"},
+			Internal: "kept",
+			version:  3,
+		}
+	}
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			article := original()
+			err := MergePatch(&article, []byte(tc.Patch))
+			if tc.Err {
+				if err == nil {
+					t.Errorf("error, expected one, got nil")
+				}
+				if !reflect.DeepEqual(article, original()) {
+					t.Errorf("target, expected it untouched, got %+v", article)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error, expected nil, got %v", err)
+			}
+			if !reflect.DeepEqual(article, tc.Expected) {
+				t.Errorf("target, expected %+v, got %+v", tc.Expected, article)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"replace": {
+			Patch: `{"title":"bye","tags":["chi"]}`,
+			Expected: patchArticle{
+				Title: "bye", Tags: []string{"chi"}, Rating: 4,
+				Meta: map[string]string{"a": "b", "c": "d"}, Author: &patchAuthor{Name: "gdey"},
+				Internal: "kept", version: 3,
+			},
+		},
+		"merge objects": {
+			Patch: `{"meta":{"a":null,"e":"f"},"author":{"name":"someone"}}`,
+			Expected: patchArticle{
+				Title: "hello", Tags: []string{"go", "http"}, Rating: 4,
+				Meta: map[string]string{"c": "d", "e": "f"}, Author: &patchAuthor{Name: "someone"},
+				Internal: "kept", version: 3,
+			},
+		},
+		"remove": {
+			Patch: `{"title":null,"rating":null,"author":null,"internal":"ignored"}`,
+			Expected: patchArticle{
+				Tags: []string{"go", "http"}, Meta: map[string]string{"a": "b", "c": "d"},
+				Internal: "kept", version: 3,
+			},
+		},
+		"empty": {
+			Patch:    `{}`,
+			Expected: original(),
+		},
+		"wrong type": {
+			Patch: `{"title":"bye","rating":"high"}`,
+			Err:   true,
+		},
+		"not an object": {
+			Patch: `["title"]`,
+			Err:   true,
+		},
+		"invalid json": {
+			Patch: `{"title":`,
+			Err:   true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+// mergePatchRequest records the fields the client sent
+type mergePatchRequest struct {
+	*patchArticle
+	present Fields
+}
+
+func (req *mergePatchRequest) Bind(r *http.Request) error {
+	req.present = FieldMask(r)
+	return nil
+}
+
+func TestBindMergePatch(t *testing.T) {
+	ctrl := CloneDefault()
+	_ = ctrl.SetDecoder(ContentTypeMergePatch, DecodeMergePatch)
+
+	r := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(`{"title":"bye","author":null}`))
+	r.Header.Set("Content-Type", "application/merge-patch+json")
+	article := &patchArticle{Title: "hello", Rating: 4, Author: &patchAuthor{Name: "gdey"}, Internal: "kept"}
+	req := &mergePatchRequest{patchArticle: article}
+	if err := ctrl.Bind(r, req); err != nil {
+		t.Fatalf("error, expected nil, got %v", err)
+	}
+	expected := patchArticle{Title: "bye", Rating: 4, Internal: "kept"}
+	if !reflect.DeepEqual(*req.patchArticle, expected) {
+		t.Errorf("payload, expected %+v, got %+v", expected, *req.patchArticle)
+	}
+	for _, name := range []string{"title", "author"} {
+		if !req.present.Has(name) {
+			t.Errorf("field mask, expected %v, got %v", name, req.present)
+		}
+	}
+	if req.present.Has("rating") {
+		t.Errorf("field mask, expected no rating, got %v", req.present)
+	}
+}
@@ -0,0 +1,78 @@
+package render
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// normalizers are the options of the normalize struct tag
+var normalizers = map[string]func(string) string{
+	"trim":           strings.TrimSpace,
+	"lower":          strings.ToLower,
+	"upper":          strings.ToUpper,
+	"collapse_space": collapseSpace,
+}
+
+// collapseSpace replaces runs of white space with a single space
+func collapseSpace(s string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range s {
+		if unicode.IsSpace(r) {
+			if !space {
+				b.WriteByte(' ')
+			}
+			space = true
+			continue
+		}
+		space = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// bindNormalize applies the options of the normalize tag, in order, to a
+// string field, a pointer to a string or a slice of strings.
+//
+//	Title string `json:"title" normalize:"trim,collapse_space"`
+//	Email string `json:"email" normalize:"trim,lower"`
+func bindNormalize(sf structField, v reflect.Value, path string) error {
+	tag, ok := sf.tag.Lookup("normalize")
+	if !ok || tag == "" {
+		return nil
+	}
+	var fns []func(string) string
+	for _, name := range strings.Split(tag, ",") {
+		fn, ok := normalizers[strings.TrimSpace(name)]
+		if !ok {
+			return fmt.Errorf("render: unknown normalize option '%s' for field '%s'", name, path)
+		}
+		fns = append(fns, fn)
+	}
+	normalize := func(v reflect.Value) {
+		if v.Kind() != reflect.String || !v.CanSet() {
+			return
+		}
+		s := v.String()
+		for _, fn := range fns {
+			s = fn(s)
+		}
+		v.SetString(s)
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			normalize(v.Elem())
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			normalize(v.Index(i))
+		}
+	default:
+		normalize(v)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package render
+
+import "fmt"
+
+// NullPolicy is how struct fields that encode as null, like nil pointers, are
+// handled by the JSON encoding of structured responses.
+type NullPolicy uint8
+
+const (
+	// NullsAsIs follows the omitempty option of the json tag of the field
+	NullsAsIs NullPolicy = iota
+	// NullsOmit leaves out null fields, as if they were all tagged omitempty
+	NullsOmit
+	// NullsEmit writes null fields, even if they are tagged omitempty
+	NullsEmit
+)
+
+// String returns the name of the policy, as used by Describe
+func (policy NullPolicy) String() string {
+	switch policy {
+	case NullsAsIs:
+		return "as_is"
+	case NullsOmit:
+		return "omit"
+	case NullsEmit:
+		return "emit"
+	default:
+		return fmt.Sprintf("NullPolicy(%d)", uint8(policy))
+	}
+}
+
+// omitNull returns whether a null field should be left out of the JSON object
+func (policy NullPolicy) omitNull(sf structField, omit bool) bool {
+	switch policy {
+	case NullsOmit:
+		return true
+	case NullsEmit:
+		return sf.json.skip
+	default:
+		return omit
+	}
+}
@@ -0,0 +1,52 @@
+package render
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNulls(t *testing.T) {
+	type profile struct {
+		Name     string            `json:"name"`
+		Nickname *string           `json:"nickname"`
+		Avatar   *string           `json:"avatar,omitempty"`
+		Labels   map[string]string `json:"labels,omitempty"`
+		Hidden   *string           `json:"-"`
+	}
+	type tcase struct {
+		Policy NullPolicy
+		Body   string
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			ctrl := defaultCtrl.Clone()
+			ctrl.Nulls = tc.Policy
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			w := httptest.NewRecorder()
+			ctrl.respond(w, r, profile{Name: "gopher"})
+			if got := w.Body.String(); got != tc.Body {
+				t.Errorf("body, expected %s, got %s", tc.Body, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"as is": {
+			Body: `{"name":"gopher","nickname":null}` + "\n",
+		},
+		"omit": {
+			Policy: NullsOmit,
+			Body:   `{"name":"gopher"}` + "\n",
+		},
+		"emit": {
+			Policy: NullsEmit,
+			Body:   `{"name":"gopher","nickname":null,"avatar":null,"labels":null}` + "\n",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
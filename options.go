@@ -0,0 +1,106 @@
+package render
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+
+	"github.com/gdey/chi-render/responders/helpers"
+)
+
+var (
+	// OptionsCtxKey is a context key for the render options of a route
+	OptionsCtxKey = helpers.OptionsCtxKey
+	// IndentCtxKey is a context key for the indent the structured responders
+	// pretty print with
+	IndentCtxKey = helpers.IndentCtxKey
+)
+
+// DefaultIndent is the indent used by PrettyPrint
+const DefaultIndent = "  "
+
+// Envelope wraps the payload of the structured responders for routes with
+// enveloping turned on (see WithEnvelope).
+type Envelope struct {
+	XMLName xml.Name    `json:"-" xml:"response"`
+	Data    interface{} `json:"data" xml:"data"`
+}
+
+// routeOptions are the render options of a route
+type routeOptions struct {
+	indent   string
+	envelope bool
+	redact   RedactMode
+	// redactSet is true if the route overrides the redaction of the controller
+	redactSet bool
+}
+
+// Option is a render option for the routes wrapped by Options.
+type Option func(*routeOptions)
+
+// PrettyPrint turns pretty printing of the JSON and XML responses on or off.
+func PrettyPrint(on bool) Option {
+	if !on {
+		return WithIndent("")
+	}
+	return WithIndent(DefaultIndent)
+}
+
+// WithIndent pretty prints the JSON and XML responses with the given indent; an
+// empty indent turns pretty printing off.
+func WithIndent(indent string) Option {
+	return func(opts *routeOptions) { opts.indent = indent }
+}
+
+// WithEnvelope turns wrapping the payload of the structured responders in an
+// Envelope on or off.
+func WithEnvelope(on bool) Option {
+	return func(opts *routeOptions) { opts.envelope = on }
+}
+
+// WithRedaction overrides the Redact mode of the controller.
+func WithRedaction(mode RedactMode) Option {
+	return func(opts *routeOptions) {
+		opts.redact = mode
+		opts.redactSet = true
+	}
+}
+
+// Options is a middleware that sets the render options of the routes it wraps,
+// so the behavior of the responses can vary by route without a controller per
+// variation. Options nest; the options of an inner Options override the ones of
+// the outer ones.
+//
+//	r.Route("/admin", func(r chi.Router) {
+//		r.Use(render.Options(render.PrettyPrint(true), render.WithRedaction(render.RedactOff)))
+//		…
+//	})
+func Options(options ...Option) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			opts := routeOptionsFrom(r)
+			for _, opt := range options {
+				opt(&opts)
+			}
+			ctx := context.WithValue(r.Context(), OptionsCtxKey, opts)
+			ctx = context.WithValue(ctx, IndentCtxKey, opts.indent)
+			*r = *r.WithContext(ctx)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// routeOptionsFrom returns the render options of the route of the request
+func routeOptionsFrom(r *http.Request) routeOptions {
+	opts, _ := r.Context().Value(OptionsCtxKey).(routeOptions)
+	return opts
+}
+
+// redactMode returns how the sensitive fields are sanitized for the route of
+// the request
+func (ctrl *Controller) redactMode(r *http.Request) RedactMode {
+	if opts := routeOptionsFrom(r); opts.redactSet {
+		return opts.redact
+	}
+	return ctrl.Redact
+}
@@ -0,0 +1,82 @@
+package render
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOptions(t *testing.T) {
+	type tcase struct {
+		Mode    RedactMode
+		Options [][]Option
+		Accept  string
+		Body    string
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			ctrl := defaultCtrl.Clone()
+			ctrl.Redact = tc.Mode
+			var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ctrl.respond(w, r, &projectionUser{ID: 1, Name: "gopher", Email: "g@example.org", Password: "pw", Token: "t", SSN: "123"})
+			})
+			for i := len(tc.Options) - 1; i >= 0; i-- {
+				handler = Options(tc.Options[i]...)(handler)
+			}
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("Accept", tc.Accept)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			if got := w.Body.String(); got != tc.Body {
+				t.Errorf("body, expected %s, got %s", tc.Body, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"none": {
+			Accept: "application/json",
+			Body:   `{"id":1,"name":"gopher","email":"g@example.org","token":"t","ssn":"123"}` + "\n",
+		},
+		"redact": {
+			Options: [][]Option{{WithRedaction(RedactRemove)}},
+			Accept:  "application/json",
+			Body:    `{"id":1,"name":"gopher"}` + "\n",
+		},
+		"redact off": {
+			Mode:    RedactMask,
+			Options: [][]Option{{WithRedaction(RedactOff)}},
+			Accept:  "application/json",
+			Body:    `{"id":1,"name":"gopher","email":"g@example.org","token":"t","ssn":"123"}` + "\n",
+		},
+		"pretty": {
+			Options: [][]Option{{PrettyPrint(true), WithRedaction(RedactRemove)}},
+			Accept:  "application/json",
+			Body:    "{\n  \"id\": 1,\n  \"name\": \"gopher\"\n}\n",
+		},
+		"envelope": {
+			Options: [][]Option{{WithEnvelope(true), WithRedaction(RedactRemove)}},
+			Accept:  "application/json",
+			Body:    `{"data":{"id":1,"name":"gopher"}}` + "\n",
+		},
+		"nested": {
+			Options: [][]Option{
+				{WithEnvelope(true), WithRedaction(RedactRemove)},
+				{WithEnvelope(false), WithIndent("\t")},
+			},
+			Accept: "application/json",
+			Body:   "{\n\t\"id\": 1,\n\t\"name\": \"gopher\"\n}\n",
+		},
+		"xml": {
+			Options: [][]Option{{WithEnvelope(true), WithRedaction(RedactRemove)}},
+			Accept:  "text/xml",
+			Body:    xml.Header + `<response><data id="1"><Name>gopher</Name><Password>pw</Password></data></response>`,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
@@ -0,0 +1,117 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"reflect"
+	"runtime/debug"
+
+	"github.com/gdey/chi-render/responders"
+)
+
+// ErrPanic is the error that PanicError values match using errors.Is.
+var ErrPanic = errors.New("render: panic")
+
+// PanicError is returned by Render, RenderList and Bind when the Render or Bind
+// method of a payload panics, instead of the panic unwinding through the
+// handler with no context. It is also how a panicking responder is reported.
+type PanicError struct {
+	// Type is the type of the payload whose method panicked
+	Type reflect.Type
+	// Method is the method that panicked; Render, Bind or Respond for a responder
+	Method string
+	// ContentType is the content type of the responder that panicked; empty
+	// if the panic was not in a responder
+	ContentType ContentType
+	// Path is the path of the payload within the top level payload; empty
+	// for the top level payload
+	Path string
+	// Value is the value passed to panic
+	Value interface{}
+	// Stack is the stack trace of the goroutine when it panicked
+	Stack []byte
+}
+
+func (err *PanicError) Error() string {
+	if err.ContentType != "" {
+		return fmt.Sprintf("render: panic in '%s' responder for %v: %v", err.ContentType, err.Type, err.Value)
+	}
+	if err.Path == "" {
+		return fmt.Sprintf("render: panic in %v.%s: %v", err.Type, err.Method, err.Value)
+	}
+	return fmt.Sprintf("render: panic in %v.%s at '%s': %v", err.Type, err.Method, err.Path, err.Value)
+}
+
+// Is reports whether target is ErrPanic
+func (err *PanicError) Is(target error) bool { return target == ErrPanic }
+
+// Unwrap returns the value passed to panic, if it was an error
+func (err *PanicError) Unwrap() error {
+	cause, _ := err.Value.(error)
+	return cause
+}
+
+// StatusCode is the http status code that should be reported to the client
+func (err *PanicError) StatusCode() int { return http.StatusInternalServerError }
+
+// recoverPanic turns a panic in the method of the payload into a *PanicError.
+// http.ErrAbortHandler is used to abort the response on purpose, so it is
+// left to unwind.
+func recoverPanic(method string, v interface{}, err *error) {
+	p := recover()
+	if p == nil {
+		return
+	}
+	if p == http.ErrAbortHandler {
+		panic(p)
+	}
+	*err = &PanicError{
+		Type:   reflect.TypeOf(v),
+		Method: method,
+		Value:  p,
+		Stack:  debug.Stack(),
+	}
+}
+
+// callRender calls the Render method of the payload, recovering any panic
+func callRender(w http.ResponseWriter, r *http.Request, v Renderer) (err error) {
+	defer recoverPanic("Render", v, &err)
+	return v.Render(w, r)
+}
+
+// callBind calls the Bind method of the payload, recovering any panic
+func callBind(r *http.Request, v Binder) (err error) {
+	defer recoverPanic("Bind", v, &err)
+	return v.Bind(r)
+}
+
+// callResponder calls the responder for the content type, recovering any panic
+func callResponder(contentType ContentType, fn responders.Func, w http.ResponseWriter, r *http.Request, v interface{}) (err error) {
+	defer func() {
+		if pe, ok := err.(*PanicError); ok {
+			pe.ContentType = contentType
+		}
+	}()
+	defer recoverPanic("Respond", v, &err)
+	return fn(w, r, v)
+}
+
+// logResponderPanic logs a panicking responder along with the details of the
+// content negotiation that picked it.
+func logResponderPanic(r *http.Request, err *PanicError) {
+	log.Printf("[render] %v %v: responder '%s' panicked rendering %v (Accept: %q): %v\n%s",
+		r.Method, r.URL.Path, err.ContentType, err.Type, r.Header.Get("Accept"), err.Value, err.Stack)
+}
+
+// wrapPanicError prepends the field name to the path of a *PanicError; other
+// errors are returned as is.
+func wrapPanicError(name string, err error) error {
+	if pe, ok := err.(*PanicError); ok {
+		wrapped := *pe
+		wrapped.Path = joinBindPath(name, pe.Path)
+		return &wrapped
+	}
+	return err
+}
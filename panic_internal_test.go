@@ -0,0 +1,174 @@
+package render
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testPanicky struct {
+	Name string `json:"name"`
+}
+
+func (p *testPanicky) Render(http.ResponseWriter, *http.Request) error {
+	if p.Name == "boom" {
+		panic("render boom")
+	}
+	return nil
+}
+
+func (p *testPanicky) Bind(*http.Request) error {
+	if p.Name == "boom" {
+		panic(errors.New("bind boom"))
+	}
+	return nil
+}
+
+type testPanickyList struct {
+	Items []*testPanicky `json:"items"`
+	Owner *testPanicky   `json:"owner"`
+}
+
+func (*testPanickyList) Render(http.ResponseWriter, *http.Request) error { return nil }
+func (*testPanickyList) Bind(*http.Request) error                        { return nil }
+
+func TestRenderPanic(t *testing.T) {
+	type tcase struct {
+		Payload Renderer
+		Path    string
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			w := httptest.NewRecorder()
+			err := defaultCtrl.Render(w, r, tc.Payload)
+			var pe *PanicError
+			if !errors.As(err, &pe) || !errors.Is(err, ErrPanic) {
+				t.Fatalf("error, expected *PanicError, got %v", err)
+			}
+			if pe.Path != tc.Path {
+				t.Errorf("path, expected %q, got %q", tc.Path, pe.Path)
+			}
+			if pe.Type != reflect.TypeOf(&testPanicky{}) || pe.Method != "Render" {
+				t.Errorf("method, expected (*testPanicky).Render, got (%v).%v", pe.Type, pe.Method)
+			}
+			if pe.Value != "render boom" || len(pe.Stack) == 0 {
+				t.Errorf("value, expected render boom with a stack, got %v", pe.Value)
+			}
+			if w.Body.Len() != 0 {
+				t.Errorf("body, expected nothing written, got %s", w.Body.String())
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"top level": {
+			Payload: &testPanicky{Name: "boom"},
+		},
+		"nested": {
+			Payload: &testPanickyList{Items: []*testPanicky{{Name: "ok"}, {Name: "boom"}}},
+			Path:    "items[1]",
+		},
+		"field": {
+			Payload: &testPanickyList{Owner: &testPanicky{Name: "boom"}},
+			Path:    "owner",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestBindPanic(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"items":[{"name":"ok"},{"name":"boom"}]}`))
+	r.Header.Set("Content-Type", "application/json")
+
+	var list testPanickyList
+	err := defaultCtrl.Bind(r, &list)
+	var pe *PanicError
+	if !errors.As(err, &pe) {
+		t.Fatalf("error, expected *PanicError, got %v", err)
+	}
+	if pe.Path != "items[1]" || pe.Method != "Bind" {
+		t.Errorf("panic, expected Bind at items[1], got %v at %v", pe.Method, pe.Path)
+	}
+	if pe.Unwrap() == nil || pe.Unwrap().Error() != "bind boom" {
+		t.Errorf("unwrap, expected bind boom, got %v", pe.Unwrap())
+	}
+}
+
+func TestRenderPanicAbort(t *testing.T) {
+	defer func() {
+		if p := recover(); p != http.ErrAbortHandler {
+			t.Errorf("panic, expected http.ErrAbortHandler, got %v", p)
+		}
+	}()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	_ = callRender(httptest.NewRecorder(), r, renderFunc(func() { panic(http.ErrAbortHandler) }))
+}
+
+type renderFunc func()
+
+func (fn renderFunc) Render(http.ResponseWriter, *http.Request) error {
+	fn()
+	return nil
+}
+
+func TestResponderPanic(t *testing.T) {
+	type tcase struct {
+		ContentType ContentType
+		Status      int
+		Body        string
+	}
+
+	panicky := func(w http.ResponseWriter, r *http.Request, v interface{}) error {
+		w.Header().Set("Content-Type", "text/csv")
+		_, _ = w.Write([]byte("half,a,"))
+		panic("responder boom")
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			ctrl := defaultCtrl.Clone()
+			_ = ctrl.SetResponder(tc.ContentType, panicky)
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("Accept", "text/csv")
+			w := httptest.NewRecorder()
+			if err := ctrl.Render(w, r, &testPanicky{Name: "ok"}); err != nil {
+				t.Fatalf("error, expected nil, got %v", err)
+			}
+			if w.Code != tc.Status {
+				t.Errorf("status, expected %v, got %v", tc.Status, w.Code)
+			}
+			if got := strings.TrimSpace(w.Body.String()); got != tc.Body {
+				t.Errorf("body, expected %s, got %s", tc.Body, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"default responder": {
+			ContentType: ContentType("text/csv"),
+			Status:      http.StatusOK,
+			Body:        `{"name":"ok"}`,
+		},
+		"minimal 500": {
+			ContentType: ContentTypeDefault,
+			Status:      http.StatusInternalServerError,
+			Body:        http.StatusText(http.StatusInternalServerError),
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+
+	err := callResponder(ContentTypeJSON, panicky, httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil), &testPanicky{})
+	var pe *PanicError
+	if !errors.As(err, &pe) || pe.ContentType != ContentTypeJSON || pe.Method != "Respond" {
+		t.Errorf("error, expected a responder *PanicError, got %v", err)
+	}
+}
@@ -0,0 +1,457 @@
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gdey/chi-render/decoders"
+)
+
+// ContentTypeJSONPatch is the content type of JSON Patch documents, RFC 6902;
+// it is not decoded by default:
+//
+//	ctrl.SetDecoder(render.ContentTypeJSONPatch, decoders.JSONPatch)
+const ContentTypeJSONPatch = ContentType("application/json-patch+json")
+
+var (
+	// ErrPatchFailed is returned by ApplyPatch when the patch can not be
+	// applied to the target
+	ErrPatchFailed = errors.New("render: patch can not be applied")
+
+	// ErrPatchTestFailed is the cause of the *PatchError of a test operation
+	// that did not match the target
+	ErrPatchTestFailed = errors.New("render: patch test failed")
+)
+
+// PatchError is returned by ApplyPatch when an operation of the patch fails
+type PatchError struct {
+	// Index of the operation in the patch; it is -1 if the patched document
+	// could not be decoded back into the target
+	Index int
+	Op    decoders.PatchOperation
+	Cause error
+}
+
+func (err *PatchError) Error() string {
+	if err.Index < 0 {
+		return fmt.Sprintf("render: patched document: %v", err.Cause)
+	}
+	return fmt.Sprintf("render: patch operation %d, %s %s: %v", err.Index, err.Op.Op, err.Op.Path, err.Cause)
+}
+
+// Is reports whether target is ErrPatchFailed
+func (err *PatchError) Is(target error) bool { return target == ErrPatchFailed }
+
+func (err *PatchError) Unwrap() error { return err.Cause }
+
+// StatusCode is the http status code that should be reported to the client; a
+// failed test is a conflict with the current state of the resource.
+func (err *PatchError) StatusCode() int {
+	if errors.Is(err.Cause, ErrPatchTestFailed) {
+		return http.StatusConflict
+	}
+	return http.StatusUnprocessableEntity
+}
+
+// ApplyPatch applies the JSON Patch to target, which must be a pointer. The
+// operations are applied to the JSON encoding of target, and the result is
+// decoded back into it; fields that are not encoded as JSON are kept, and
+// fields tagged with protect are handled as Bind does. If an operation fails
+// target is left untouched, and a *PatchError is returned.
+//
+//	func (h *Handlers) PatchArticle(w http.ResponseWriter, r *http.Request) {
+//		var patch ArticlePatch // embeds a decoders.Patch
+//		if err := render.Bind(r, &patch); err != nil {
+//			...
+//		}
+//		article := h.Articles.Get(chi.URLParam(r, "articleID"))
+//		if err := render.ApplyPatch(article, patch.Patch); err != nil {
+//			render.Render(w, r, &render.ErrResponse{Err: err, StatusCode: (&render.BindError{Cause: err}).StatusCode()})
+//			return
+//		}
+//		...
+//	}
+func ApplyPatch(target interface{}, patch decoders.Patch) error {
+	rv := reflect.ValueOf(target)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("render: patch target must be a non nil pointer, got %T", target)
+	}
+	protected := protectedFields(target)
+	doc, err := jsonDocument(target)
+	if err != nil {
+		return err
+	}
+	for i, op := range patch {
+		if doc, err = applyPatchOperation(doc, op); err != nil {
+			return &PatchError{Index: i, Op: op, Cause: err}
+		}
+	}
+	if err := decodeDocument(rv, doc, protected); err != nil {
+		return &PatchError{Index: -1, Cause: err}
+	}
+	return nil
+}
+
+// decodeDocument decodes the document into the value the pointer points to.
+// It is decoded into a copy without the JSON fields, so members missing from
+// the document are zeroed, and the value is only changed if it works. The
+// protected fields are put back, or rejected, as Bind does, from the values
+// they had before the patch.
+func decodeDocument(ptr reflect.Value, doc interface{}, protected map[string]reflect.Value) error {
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	patched := reflect.New(ptr.Elem().Type())
+	patched.Elem().Set(deepCopy(ptr.Elem(), make(map[visited]reflect.Value)))
+	zeroJSONFields(patched.Elem())
+	if err := json.Unmarshal(b, patched.Interface()); err != nil {
+		return err
+	}
+	if err := restoreProtected(patched, protected); err != nil {
+		return err
+	}
+	ptr.Elem().Set(patched.Elem())
+	return nil
+}
+
+// jsonDocument returns the JSON encoding of v as maps, slices and values, with
+// the numbers as json.Number
+func jsonDocument(v interface{}) (interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return decodeJSONValue(b)
+}
+
+func decodeJSONValue(b []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var value interface{}
+	err := dec.Decode(&value)
+	return value, err
+}
+
+// zeroJSONFields zeroes the fields of v that encoding/json decodes into
+func zeroJSONFields(v reflect.Value) {
+	if v.Kind() != reflect.Struct {
+		v.Set(reflect.Zero(v.Type()))
+		return
+	}
+	for _, sf := range structFields(v.Type()) {
+		fv, ok := fieldByIndex(v, sf.index)
+		if !ok || sf.json.skip || !fv.CanSet() {
+			continue
+		}
+		fv.Set(reflect.Zero(fv.Type()))
+	}
+}
+
+// applyPatchOperation applies the operation to the document, returning the
+// new document
+func applyPatchOperation(doc interface{}, op decoders.PatchOperation) (interface{}, error) {
+	if _, err := pointerTokens(op.Path); err != nil {
+		return nil, err
+	}
+	var value interface{}
+	if len(op.Value) > 0 {
+		var err error
+		if value, err = decodeJSONValue(op.Value); err != nil {
+			return nil, err
+		}
+	}
+	switch op.Op {
+	case decoders.PatchAdd:
+		return patchAdd(doc, op.Path, value)
+	case decoders.PatchRemove:
+		return patchRemove(doc, op.Path)
+	case decoders.PatchReplace:
+		return patchReplace(doc, op.Path, value)
+	case decoders.PatchMove:
+		if op.From == op.Path {
+			return doc, nil
+		}
+		if strings.HasPrefix(op.Path, op.From+"/") {
+			return nil, fmt.Errorf("can not move %q into itself", op.From)
+		}
+		value, err := patchGet(doc, op.From)
+		if err != nil {
+			return nil, err
+		}
+		if doc, err = patchRemove(doc, op.From); err != nil {
+			return nil, err
+		}
+		return patchAdd(doc, op.Path, value)
+	case decoders.PatchCopy:
+		value, err := patchGet(doc, op.From)
+		if err != nil {
+			return nil, err
+		}
+		return patchAdd(doc, op.Path, copyJSONValue(value))
+	case decoders.PatchTest:
+		current, err := patchGet(doc, op.Path)
+		if err != nil {
+			return nil, err
+		}
+		if !jsonEqual(current, value) {
+			return nil, ErrPatchTestFailed
+		}
+		return doc, nil
+	default:
+		return nil, fmt.Errorf("unknown op %q", op.Op)
+	}
+}
+
+// pointerTokens splits the JSON Pointer into its unescaped reference tokens;
+// pointers other than the empty one, for the whole document, start with "/".
+func pointerTokens(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("%q is not a json pointer, it does not start with /", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+	return tokens, nil
+}
+
+// arrayIndex parses the index of an array of length n; the index may be n if
+// appending, which "-" stands for.
+func arrayIndex(token string, n int, appending bool) (int, error) {
+	if token == "-" && appending {
+		return n, nil
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || (token != "0" && strings.HasPrefix(token, "0")) {
+		return 0, fmt.Errorf("%q is not an array index", token)
+	}
+	if i > n || (i == n && !appending) {
+		return 0, fmt.Errorf("array index %d is out of bounds", i)
+	}
+	return i, nil
+}
+
+// patchAt calls fn with the container of the value the path points to, and the
+// last token of the path; the containers on the way are replaced by the ones fn
+// returns.
+func patchAt(node interface{}, tokens []string, fn func(container interface{}, token string) (interface{}, error)) (interface{}, error) {
+	if len(tokens) == 1 {
+		return fn(node, tokens[0])
+	}
+	switch n := node.(type) {
+	case map[string]interface{}:
+		child, ok := n[tokens[0]]
+		if !ok {
+			return nil, fmt.Errorf("member %q does not exist", tokens[0])
+		}
+		child, err := patchAt(child, tokens[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		n[tokens[0]] = child
+		return n, nil
+	case []interface{}:
+		i, err := arrayIndex(tokens[0], len(n), false)
+		if err != nil {
+			return nil, err
+		}
+		child, err := patchAt(n[i], tokens[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		n[i] = child
+		return n, nil
+	default:
+		return nil, fmt.Errorf("%q is not an object or an array", tokens[0])
+	}
+}
+
+func patchGet(doc interface{}, path string) (interface{}, error) {
+	tokens, err := pointerTokens(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return doc, nil
+	}
+	var value interface{}
+	_, err = patchAt(doc, tokens, func(container interface{}, token string) (interface{}, error) {
+		switch c := container.(type) {
+		case map[string]interface{}:
+			v, ok := c[token]
+			if !ok {
+				return nil, fmt.Errorf("member %q does not exist", token)
+			}
+			value = v
+		case []interface{}:
+			i, err := arrayIndex(token, len(c), false)
+			if err != nil {
+				return nil, err
+			}
+			value = c[i]
+		default:
+			return nil, fmt.Errorf("%q is not in an object or an array", token)
+		}
+		return container, nil
+	})
+	return value, err
+}
+
+func patchAdd(doc interface{}, path string, value interface{}) (interface{}, error) {
+	tokens, err := pointerTokens(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	return patchAt(doc, tokens, func(container interface{}, token string) (interface{}, error) {
+		switch c := container.(type) {
+		case map[string]interface{}:
+			c[token] = value
+			return c, nil
+		case []interface{}:
+			i, err := arrayIndex(token, len(c), true)
+			if err != nil {
+				return nil, err
+			}
+			c = append(c, nil)
+			copy(c[i+1:], c[i:])
+			c[i] = value
+			return c, nil
+		default:
+			return nil, fmt.Errorf("%q is not in an object or an array", token)
+		}
+	})
+}
+
+func patchReplace(doc interface{}, path string, value interface{}) (interface{}, error) {
+	tokens, err := pointerTokens(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	return patchAt(doc, tokens, func(container interface{}, token string) (interface{}, error) {
+		switch c := container.(type) {
+		case map[string]interface{}:
+			if _, ok := c[token]; !ok {
+				return nil, fmt.Errorf("member %q does not exist", token)
+			}
+			c[token] = value
+			return c, nil
+		case []interface{}:
+			i, err := arrayIndex(token, len(c), false)
+			if err != nil {
+				return nil, err
+			}
+			c[i] = value
+			return c, nil
+		default:
+			return nil, fmt.Errorf("%q is not in an object or an array", token)
+		}
+	})
+}
+
+func patchRemove(doc interface{}, path string) (interface{}, error) {
+	tokens, err := pointerTokens(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, errors.New("can not remove the whole document")
+	}
+	return patchAt(doc, tokens, func(container interface{}, token string) (interface{}, error) {
+		switch c := container.(type) {
+		case map[string]interface{}:
+			if _, ok := c[token]; !ok {
+				return nil, fmt.Errorf("member %q does not exist", token)
+			}
+			delete(c, token)
+			return c, nil
+		case []interface{}:
+			i, err := arrayIndex(token, len(c), false)
+			if err != nil {
+				return nil, err
+			}
+			return append(c[:i], c[i+1:]...), nil
+		default:
+			return nil, fmt.Errorf("%q is not in an object or an array", token)
+		}
+	})
+}
+
+// copyJSONValue deep copies the objects and arrays of the value
+func copyJSONValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		c := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			c[key] = copyJSONValue(item)
+		}
+		return c
+	case []interface{}:
+		c := make([]interface{}, len(v))
+		for i, item := range v {
+			c[i] = copyJSONValue(item)
+		}
+		return c
+	default:
+		return value
+	}
+}
+
+// jsonEqual reports whether the values are equal, as a test operation defines
+// it: numbers are compared by value, objects regardless of the order of their
+// members.
+func jsonEqual(a, b interface{}) bool {
+	switch a := a.(type) {
+	case json.Number:
+		b, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		af, aerr := a.Float64()
+		bf, berr := b.Float64()
+		if aerr != nil || berr != nil {
+			return a == b
+		}
+		return af == bf
+	case map[string]interface{}:
+		b, ok := b.(map[string]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for key, item := range a {
+			other, ok := b[key]
+			if !ok || !jsonEqual(item, other) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		b, ok := b.([]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !jsonEqual(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return a == b
+	}
+}
@@ -0,0 +1,193 @@
+package render
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gdey/chi-render/decoders"
+)
+
+type patchArticle struct {
+	ID       string            `json:"id" protect:"true"`
+	Owner    string            `json:"owner" protect:"reject"`
+	Title    string            `json:"title"`
+	Tags     []string          `json:"tags,omitempty"`
+	Rating   float64           `json:"rating"`
+	Meta     map[string]string `json:"meta,omitempty"`
+	Author   *patchAuthor      `json:"author,omitempty"`
+	Internal string            `json:"-"`
+	version  int
+}
+
+type patchAuthor struct {
+	Name string `json:"name"`
+}
+
+func TestApplyPatch(t *testing.T) {
+	type tcase struct {
+		Patch    string
+		Expected patchArticle
+		Err      error
+		Status   int
+	}
+
+	original := func() patchArticle {
+		return patchArticle{
+			ID:       "a1",
+			Owner:    "gdey",
+			Title:    "hello",
+			Tags:     []string{"go", "http"},
+			Rating:   4,
+			Meta:     map[string]string{"a/b": "c"},
+			Author:   &patchAuthor{Name: "gdey"},
+			Internal: "kept",
+			version:  3,
+		}
+	}
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			var patch decoders.Patch
+			if err := json.Unmarshal([]byte(tc.Patch), &patch); err != nil {
+				t.Fatal(err)
+			}
+			article := original()
+			err := ApplyPatch(&article, patch)
+			if tc.Err != nil {
+				if !errors.Is(err, tc.Err) {
+					t.Errorf("error, expected %v, got %v", tc.Err, err)
+				}
+				var patchErr *PatchError
+				if errors.As(err, &patchErr) && patchErr.StatusCode() != tc.Status {
+					t.Errorf("status, expected %v, got %v", tc.Status, patchErr.StatusCode())
+				}
+				if !reflect.DeepEqual(article, original()) {
+					t.Errorf("target, expected it untouched, got %+v", article)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error, expected nil, got %v", err)
+			}
+			if !reflect.DeepEqual(article, tc.Expected) {
+				t.Errorf("target, expected %+v, got %+v", tc.Expected, article)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"replace and add": {
+			Patch: `[
+				{"op":"test","path":"/rating","value":4.0},
+				{"op":"replace","path":"/title","value":"bye"},
+				{"op":"add","path":"/tags/1","value":"chi"},
+				{"op":"add","path":"/tags/-","value":"rest"},
+				{"op":"add","path":"/meta/d","value":"e"}
+			]`,
+			Expected: patchArticle{
+				ID: "a1", Owner: "gdey",
+				Title: "bye", Tags: []string{"go", "chi", "http", "rest"}, Rating: 4,
+				Meta: map[string]string{"a/b": "c", "d": "e"}, Author: &patchAuthor{Name: "gdey"},
+				Internal: "kept", version: 3,
+			},
+		},
+		"remove": {
+			Patch: `[
+				{"op":"remove","path":"/tags/0"},
+				{"op":"remove","path":"/meta/a~1b"},
+				{"op":"remove","path":"/author"}
+			]`,
+			Expected: patchArticle{
+				ID: "a1", Owner: "gdey",
+				Title: "hello", Tags: []string{"http"}, Rating: 4, Meta: map[string]string{},
+				Internal: "kept", version: 3,
+			},
+		},
+		"move and copy": {
+			Patch: `[
+				{"op":"copy","from":"/author/name","path":"/meta/author"},
+				{"op":"move","from":"/tags/1","path":"/title"}
+			]`,
+			Expected: patchArticle{
+				ID: "a1", Owner: "gdey",
+				Title: "http", Tags: []string{"go"}, Rating: 4,
+				Meta:   map[string]string{"a/b": "c", "author": "gdey"},
+				Author: &patchAuthor{Name: "gdey"}, Internal: "kept", version: 3,
+			},
+		},
+		"failed test": {
+			Patch:  `[{"op":"replace","path":"/title","value":"bye"},{"op":"test","path":"/title","value":"hello"}]`,
+			Err:    ErrPatchTestFailed,
+			Status: http.StatusConflict,
+		},
+		"missing member": {
+			Patch:  `[{"op":"replace","path":"/author/email","value":"x"}]`,
+			Err:    ErrPatchFailed,
+			Status: http.StatusUnprocessableEntity,
+		},
+		"index out of bounds": {
+			Patch:  `[{"op":"add","path":"/tags/3","value":"x"}]`,
+			Err:    ErrPatchFailed,
+			Status: http.StatusUnprocessableEntity,
+		},
+		"move into itself": {
+			Patch:  `[{"op":"move","from":"/author","path":"/author/name"}]`,
+			Err:    ErrPatchFailed,
+			Status: http.StatusUnprocessableEntity,
+		},
+		"protected": {
+			Patch: `[{"op":"replace","path":"/id","value":"b2"},{"op":"replace","path":"/title","value":"bye"}]`,
+			Expected: patchArticle{
+				ID: "a1", Owner: "gdey",
+				Title: "bye", Tags: []string{"go", "http"}, Rating: 4,
+				Meta: map[string]string{"a/b": "c"}, Author: &patchAuthor{Name: "gdey"},
+				Internal: "kept", version: 3,
+			},
+		},
+		"protected reject": {
+			Patch:  `[{"op":"replace","path":"/owner","value":"mallory"}]`,
+			Err:    ErrProtectedField,
+			Status: http.StatusUnprocessableEntity,
+		},
+		"pointer without a slash": {
+			Patch:  `[{"op":"replace","path":"title","value":"bye"}]`,
+			Err:    ErrPatchFailed,
+			Status: http.StatusUnprocessableEntity,
+		},
+		"move to itself without a slash": {
+			Patch:  `[{"op":"move","from":"title","path":"title"}]`,
+			Err:    ErrPatchFailed,
+			Status: http.StatusUnprocessableEntity,
+		},
+		"wrong type": {
+			Patch:  `[{"op":"replace","path":"/rating","value":"high"}]`,
+			Err:    ErrPatchFailed,
+			Status: http.StatusUnprocessableEntity,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+
+	t.Run("bind", func(t *testing.T) {
+		ctrl := CloneDefault()
+		ctrl.SetDecoder(ContentTypeJSONPatch, decoders.JSONPatch)
+		r := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(`[{"op":"replace","path":"/title","value":"bye"}]`))
+		r.Header.Set("Content-Type", "application/json-patch+json")
+		var payload struct {
+			decoders.Patch
+			NilBinder
+		}
+		if err := ctrl.Bind(r, &payload); err != nil {
+			t.Fatalf("error, expected nil, got %v", err)
+		}
+		article := original()
+		if err := ApplyPatch(&article, payload.Patch); err != nil || article.Title != "bye" {
+			t.Errorf("patch, expected title bye, got %q and %v", article.Title, err)
+		}
+	})
+}
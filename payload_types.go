@@ -0,0 +1,96 @@
+package render
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// RegisterPayload will set the content type preferred for payloads of the
+// given type, like binary reports that should be sent as application/pdf. The
+// responder for the preferred content type is tried before the content types
+// of the Accept header, unless StrictAccept is set and the Accept header does
+// not accept it. Use ContentTypeNone to unset a type.
+//
+// Payloads are matched by their exact type, or for pointers, the type they
+// point to.
+// Only error this function will return is ErrControllerIsNil; is returned
+// if the Controller object is nil.
+func (ctrl *Controller) RegisterPayload(typ reflect.Type, preferred ContentType) error {
+	if ctrl == nil {
+		return ErrControllerIsNil
+	}
+	ctrl.payloadTypeLck.Lock()
+	// the map is replaced, not modified, so responses in flight can keep
+	// using the one they started with
+	payloadTypes := make(map[reflect.Type]ContentType, len(ctrl.payloadTypes)+1)
+	for t, ct := range ctrl.payloadTypes {
+		payloadTypes[t] = ct
+	}
+	if preferred == ContentTypeNone {
+		delete(payloadTypes, typ)
+	} else {
+		payloadTypes[typ] = preferred
+	}
+	ctrl.payloadTypes = payloadTypes
+	ctrl.payloadTypeLck.Unlock()
+	return nil
+}
+
+// preferredTypes returns the registered payload types; the map must not be modified
+func (ctrl *Controller) preferredTypes() map[reflect.Type]ContentType {
+	ctrl.payloadTypeLck.RLock()
+	defer ctrl.payloadTypeLck.RUnlock()
+	return ctrl.payloadTypes
+}
+
+// preferredContentType returns the content type registered for the type of the
+// payload; ok is false if there is none.
+func (ctrl *Controller) preferredContentType(v interface{}) (preferred ContentType, ok bool) {
+	payloadTypes := ctrl.preferredTypes()
+	if len(payloadTypes) == 0 || v == nil {
+		return ContentTypeNone, false
+	}
+	typ := reflect.TypeOf(v)
+	if preferred, ok = payloadTypes[typ]; !ok && typ.Kind() == reflect.Ptr {
+		preferred, ok = payloadTypes[typ.Elem()]
+	}
+	return preferred, ok
+}
+
+// withPreferred puts the preferred content type of the payload in front of the
+// accepted content types, if it should be tried first.
+func (ctrl *Controller) withPreferred(r *http.Request, accepted *ContentTypeSet, v interface{}) *ContentTypeSet {
+	preferred, ok := ctrl.preferredContentType(v)
+	if !ok || !ctrl.honorsAccept(preferred) {
+		return accepted
+	}
+	if _, forced := r.Context().Value(ContentTypeCtxKey).(ContentType); forced {
+		// the route forced the content type
+		return accepted
+	}
+	if ctrl.StrictAccept && !acceptsContentType(accepted, preferred) {
+		return accepted
+	}
+	return SetOfContentTypes(append([]ContentType{preferred}, accepted.Types()...)...)
+}
+
+// acceptsContentType reports whether the accepted content types include the
+// content type, directly or through a media range; no accepted content types
+// accept everything.
+func acceptsContentType(accepted *ContentTypeSet, contentType ContentType) bool {
+	types := accepted.Types()
+	if len(types) == 0 {
+		return true
+	}
+	mainType := string(contentType)
+	if i := strings.IndexByte(mainType, '/'); i >= 0 {
+		mainType = mainType[:i]
+	}
+	for _, ct := range types {
+		if ct == contentType || ct == ContentTypeDefault || ct == ContentType(mainType+"/*") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+package render
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gdey/chi-render/responders"
+)
+
+type testReport []byte
+
+func (testReport) Render(http.ResponseWriter, *http.Request) error { return nil }
+func (report testReport) MarshalBinary() ([]byte, error)           { return report, nil }
+
+func TestRegisterPayload(t *testing.T) {
+	type tcase struct {
+		Strict      bool
+		Denied      bool
+		Accept      string
+		ContentType string
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			ctrl := defaultCtrl.Clone()
+			_ = ctrl.RegisterResponder(ContentTypeData, responders.Registration{Func: responders.Data, CanEncode: responders.IsBinary})
+			_ = ctrl.RegisterPayload(reflect.TypeOf(testReport{}), ContentTypeData)
+			ctrl.StrictAccept = tc.Strict
+			if tc.Denied {
+				ctrl.DeniedAccept = SetOfContentTypes(ContentTypeData)
+			}
+
+			r := httptest.NewRequest(http.MethodGet, "/report", nil)
+			if tc.Accept != "" {
+				r.Header.Set("Accept", tc.Accept)
+			}
+			w := httptest.NewRecorder()
+			if err := ctrl.Render(w, r, testReport("%PDF")); err != nil {
+				t.Fatalf("error, expected nil, got %v", err)
+			}
+			if got := w.Header().Get("Content-Type"); got != tc.ContentType {
+				t.Errorf("content type, expected %v, got %v", tc.ContentType, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"no accept": {
+			ContentType: "application/octet-stream",
+		},
+		"generic accept": {
+			Accept:      "*/*",
+			ContentType: "application/octet-stream",
+		},
+		"specific accept": {
+			Accept:      "application/json",
+			ContentType: "application/octet-stream",
+		},
+		"strict generic accept": {
+			Strict:      true,
+			Accept:      "application/*",
+			ContentType: "application/octet-stream",
+		},
+		"strict veto": {
+			Strict:      true,
+			Accept:      "application/json",
+			ContentType: "application/json; charset=utf-8",
+		},
+		"denied": {
+			Denied:      true,
+			ContentType: "application/json; charset=utf-8",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+
+	ctrl := defaultCtrl.Clone()
+	_ = ctrl.RegisterPayload(reflect.TypeOf(testReport{}), ContentTypeData)
+	_ = ctrl.RegisterPayload(reflect.TypeOf(testReport{}), ContentTypeNone)
+	if _, ok := ctrl.preferredContentType(testReport{}); ok {
+		t.Errorf("preferred, expected the payload type to be unset")
+	}
+}
@@ -0,0 +1,117 @@
+package render
+
+import (
+	"context"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/gdey/chi-render/responders"
+	"github.com/gdey/chi-render/responders/helpers"
+)
+
+var (
+	ProfileCtxKey = helpers.ProfileCtxKey
+)
+
+// profileKey identifies a responder registered for a profile of a content type
+type profileKey struct {
+	contentType ContentType
+	profile     string
+}
+
+// RequestedProfile returns the profile media type parameter the client
+// requested for the content type being responded with, e.g. the schema URI in
+// `application/json;profile="https://example.com/schemas/article"`. Responders
+// outside this package can use helpers.Profile.
+func RequestedProfile(r *http.Request) string { return helpers.Profile(r) }
+
+// RegisterProfileResponder will set the responder registration used instead of
+// the content type's responder when the client asks for the given profile of
+// the content type in the Accept header, for schema versioned APIs:
+//
+//	Accept: application/json;profile="https://example.com/schemas/article/v2"
+//
+// The response gets a `Link: <profile>; rel="profile"` header. Use a
+// registration with a nil Func to unset the profile.
+// Only error this function will return is ErrControllerIsNil; is returned
+// if the Controller object is nil.
+func (ctrl *Controller) RegisterProfileResponder(contentType ContentType, profile string, registration responders.Registration) error {
+	if ctrl == nil {
+		return ErrControllerIsNil
+	}
+	ctrl.responderLck.Lock()
+	if ctrl.profileResponders == nil {
+		ctrl.profileResponders = make(map[profileKey]responders.Registration)
+	}
+	ctrl.profileResponders[profileKey{contentType: contentType, profile: profile}] = registration
+	ctrl.responderLck.Unlock()
+	return nil
+}
+
+// profileResponder returns the registration for the profile of the content
+// type; ok will be false if there is no responder for the profile.
+func (ctrl *Controller) profileResponder(contentType ContentType, profile string) (reg responders.Registration, ok bool) {
+	ctrl.responderLck.RLock()
+	reg = ctrl.profileResponders[profileKey{contentType: contentType, profile: profile}]
+	ctrl.responderLck.RUnlock()
+	return reg, reg.Func != nil
+}
+
+// acceptedProfiles returns the profile parameters of the accepted content types
+// of the request; nil if there are none.
+func (ctrl *Controller) acceptedProfiles(r *http.Request) map[ContentType]string {
+	if _, ok := r.Context().Value(ContentTypeCtxKey).(ContentType); ok {
+		return nil
+	}
+	accept := r.Header.Get("Accept")
+	if ctrl.AcceptOverrideHeader != "" {
+		if override := r.Header.Get(ctrl.AcceptOverrideHeader); strings.TrimSpace(override) != "" {
+			accept = override
+		}
+	}
+	if !strings.Contains(accept, "profile") {
+		return nil
+	}
+	var profiles map[ContentType]string
+	for _, field := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(field)
+		if err != nil || params["profile"] == "" {
+			continue
+		}
+		if profiles == nil {
+			profiles = make(map[ContentType]string)
+		}
+		if _, ok := profiles[ContentType(mediaType)]; !ok {
+			profiles[ContentType(mediaType)] = params["profile"]
+		}
+	}
+	return profiles
+}
+
+// withProfile records the requested profile in the request context, for the
+// responders to see.
+func withProfile(r *http.Request, profile string) {
+	if helpers.Profile(r) == profile {
+		return
+	}
+	*r = *r.WithContext(context.WithValue(r.Context(), ProfileCtxKey, profile))
+}
+
+// removeHeaderValue removes a value added to the header
+func removeHeaderValue(header http.Header, name, value string) {
+	if value == "" {
+		return
+	}
+	values := header.Values(name)
+	kept := values[:0]
+	for _, v := range values {
+		if v != value {
+			kept = append(kept, v)
+		}
+	}
+	header.Del(name)
+	for _, v := range kept {
+		header.Add(name, v)
+	}
+}
@@ -0,0 +1,74 @@
+package render
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gdey/chi-render/responders"
+	"github.com/gdey/chi-render/responders/helpers"
+)
+
+func TestProfile(t *testing.T) {
+	const (
+		v1 = "https://example.com/schemas/article/v1"
+		v2 = "https://example.com/schemas/article/v2"
+	)
+	type tcase struct {
+		Accept string
+		Body   string
+		Link   string
+	}
+
+	// echo writes the profile the responder was asked for
+	echo := func(name string) responders.Func {
+		return func(w http.ResponseWriter, r *http.Request, v interface{}) error {
+			w.Header().Set("Content-Type", "application/json")
+			_, err := fmt.Fprintf(w, "%s:%s", name, helpers.Profile(r))
+			return err
+		}
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			ctrl := defaultCtrl.Clone()
+			_ = ctrl.SetResponder(ContentTypeJSON, echo("json"))
+			_ = ctrl.RegisterProfileResponder(ContentTypeJSON, v2, responders.Registration{Func: echo("v2")})
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("Accept", tc.Accept)
+			w := httptest.NewRecorder()
+			ctrl.respond(w, r, map[string]string{"title": "hi"})
+			if got := w.Body.String(); got != tc.Body {
+				t.Errorf("body, expected %v, got %v", tc.Body, got)
+			}
+			if got := w.Header().Get("Link"); got != tc.Link {
+				t.Errorf("link, expected %q, got %q", tc.Link, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"no profile": {
+			Accept: "application/json",
+			Body:   "json:",
+		},
+		"registered profile": {
+			Accept: `application/json;profile="` + v2 + `"`,
+			Body:   "v2:" + v2,
+			Link:   "<" + v2 + `>; rel="profile"`,
+		},
+		"unregistered profile": {
+			Accept: `application/json; profile="` + v1 + `"`,
+			Body:   "json:" + v1,
+		},
+		"profile of another type": {
+			Accept: `text/xml;profile="` + v2 + `";q=0.1, application/json`,
+			Body:   "json:",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
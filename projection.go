@@ -0,0 +1,713 @@
+package render
+
+import (
+	"bytes"
+	"context"
+	"encoding"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gdey/chi-render/responders"
+	"github.com/gdey/chi-render/responders/helpers"
+)
+
+// ProjectCtxKey is a context key for the projection the structured responders
+// of channel payloads, like ChannelEventStream, apply to each item
+var ProjectCtxKey = helpers.ProjectCtxKey
+
+// maxProjectionDepth guards against cyclic payloads
+const maxProjectionDepth = 1000
+
+var (
+	// ErrProjectionTooDeep is returned when a payload is nested too deeply to be
+	// projected; usually because it contains a cycle.
+	ErrProjectionTooDeep = errors.New("render: payload nested too deeply to project")
+
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	xmlMarshalerType  = reflect.TypeOf((*xml.Marshaler)(nil)).Elem()
+	xmlNameType       = reflect.TypeOf(xml.Name{})
+)
+
+// projects reports whether the payload needs to be projected before it is
+// handed to the structured responders
+func (ctrl *Controller) projects(r *http.Request) bool {
+	return ctrl.redactMode(r) != RedactOff ||
+		ctrl.KeyCasing != KeyCasingAsIs || ctrl.TimeFormat != TimeAsIs || ctrl.DurationFormat != DurationAsIs ||
+		ctrl.Nulls != NullsAsIs || ctrl.BigNumbersAsStrings || len(ctrl.encoders()) > 0
+}
+
+// structured reports whether the responder gets the projected payload; the
+// JSON, XML, ChannelEventStream and BatchMultipart responders of this module
+// always do, however they are registered, so redaction and views can not be
+// bypassed by leaving out the Structured flag.
+func structured(reg responders.Registration) bool {
+	if reg.Structured || reg.Func == nil {
+		return reg.Structured
+	}
+	fn := reflect.ValueOf(reg.Func).Pointer()
+	return fn == reflect.ValueOf(responders.JSON).Pointer() || fn == reflect.ValueOf(responders.XML).Pointer() ||
+		fn == reflect.ValueOf(ChannelEventStream).Pointer() || fn == reflect.ValueOf(BatchMultipart).Pointer()
+}
+
+// withProjection puts the projection of the controller in the request context,
+// for the structured responders of channel payloads to apply to each item
+func (ctrl *Controller) withProjection(r *http.Request) {
+	project := helpers.ProjectFunc(func(v interface{}) (interface{}, error) { return ctrl.project(r, v) })
+	*r = *r.WithContext(context.WithValue(r.Context(), ProjectCtxKey, project))
+}
+
+// hides reports whether the policies of the controller hide, mask or remove
+// fields of the payload for the request; the values held by its interfaces
+// are walked to find out.
+func (ctrl *Controller) hides(r *http.Request, v interface{}) bool {
+	hidden, ok := ctrl.hiddenFields(r)
+	if v == nil || !ok {
+		return false
+	}
+	return holds(reflect.ValueOf(v), 0, hidden)
+}
+
+// hidesType reports whether the policies of the controller can hide fields of
+// values of the type for the request; types that can hold interfaces are
+// assumed to.
+func (ctrl *Controller) hidesType(r *http.Request, typ reflect.Type) bool {
+	hidden, ok := ctrl.hiddenFields(r)
+	return ok && (hidden(typ) || containsInterface(typ))
+}
+
+// hiddenFields returns whether values of a type have fields the policies of the
+// controller hide for the request, without looking into interfaces; ok is
+// false if no policy hides fields.
+func (ctrl *Controller) hiddenFields(r *http.Request) (hidden func(typ reflect.Type) bool, ok bool) {
+	mode := ctrl.redactMode(r)
+	views := !ctrl.IgnoreViews
+	if mode == RedactOff && !views {
+		return nil, false
+	}
+	roles := make(map[string]bool)
+	for _, role := range ViewerRoles(r) {
+		roles[role] = true
+	}
+	return func(typ reflect.Type) bool {
+		return typeContains(typ, func(_ reflect.Type, sf *structField) bool {
+			if sf == nil {
+				return false
+			}
+			return redaction(*sf, mode) != RedactOff || (views && !viewable(*sf, roles))
+		})
+	}, true
+}
+
+// holds reports whether the value holds a value of a type that contains
+// matches; the values held by interfaces are walked to find out.
+func holds(v reflect.Value, depth int, contains func(typ reflect.Type) bool) bool {
+	if depth > maxProjectionDepth {
+		// too deep to tell, so assume it does
+		return true
+	}
+	if !v.IsValid() {
+		return false
+	}
+	if contains(v.Type()) {
+		return true
+	}
+	if !containsInterface(v.Type()) {
+		return false
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return !v.IsNil() && holds(v.Elem(), depth+1, contains)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if holds(v.Field(i), depth+1, contains) {
+				return true
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if holds(v.Index(i), depth+1, contains) {
+				return true
+			}
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			if holds(iter.Value(), depth+1, contains) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// payloadFor returns the payload to hand to the responder: the projection for
+// structured responders, and the payload itself for the others. ok is false if
+// the responder is not structured and would see fields the policies of the
+// controller hide from the request; such a responder is skipped.
+func (ctrl *Controller) payloadFor(r *http.Request, reg responders.Registration, v, projected interface{}) (payload interface{}, ok bool) {
+	if structured(reg) {
+		return projected, true
+	}
+	if ctrl.hides(r, v) {
+		return nil, false
+	}
+	return v, true
+}
+
+// project returns the payload as it should be seen by the structured responders
+// (see responders.Registration.Structured), with the controller's policies, like
+// type encoders, redaction, views, key casing, time formats, nulls and big
+// numbers, applied to it as it is encoded. If none of the policies are in
+// effect the payload is returned as is, or as a copy with the canonical
+// spelling of its enums if it holds any.
+func (ctrl *Controller) project(r *http.Request, v interface{}) (interface{}, error) {
+	if v == nil {
+		return v, nil
+	}
+	typ := reflect.TypeOf(v)
+	if !ctrl.projects(r) && (ctrl.IgnoreViews || !holdsView(reflect.ValueOf(v), 0)) {
+		if containsEnum(typ) {
+			// the responders encode the payload as they would; only
+			// the spelling of the enums changes
+			return canonicalEnums(v), nil
+		}
+		return v, nil
+	}
+	p := projector{
+		ctrl:     ctrl,
+		redact:   ctrl.redactMode(r),
+		encoders: ctrl.encoders(),
+		allowed:  allowedRedacted(r),
+		views:    !ctrl.IgnoreViews,
+		roles:    make(map[string]bool),
+	}
+	for _, role := range ViewerRoles(r) {
+		p.roles[role] = true
+	}
+	return &projection{p: &p, v: reflect.ValueOf(v)}, nil
+}
+
+// projection is the payload handed to the structured responders in place of the
+// original one. It is projected for the encoder that asks for it: JSON gets
+// ordered objects, and XML a copy of the payload that encoding/xml encodes as
+// it would the payload, so the xml struct tags keep their meaning.
+type projection struct {
+	p *projector
+	v reflect.Value
+}
+
+// MarshalJSON writes the payload with the policies applied
+func (pr *projection) MarshalJSON() ([]byte, error) {
+	value, err := pr.p.value(pr.v, "", 0)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
+}
+
+// MarshalXML writes the payload with the policies applied
+func (pr *projection) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	value, err := pr.p.xmlValue(pr.v, "", 0, true)
+	if err != nil {
+		return err
+	}
+	if value == nil {
+		return nil
+	}
+	if start.Name.Local == "projection" {
+		// not the field of another struct, the copy names itself
+		return e.Encode(value)
+	}
+	return e.EncodeElement(value, start)
+}
+
+// projector walks a payload applying the policies of the controller
+type projector struct {
+	ctrl *Controller
+	// redact is how the sensitive fields are sanitized for the route
+	redact RedactMode
+	// encoders are the registered type encoders
+	encoders map[reflect.Type]TypeEncoder
+	// allowed are the paths of the redacted fields the route is allowed to see
+	allowed map[string]bool
+	// views is true if the fields are left out by the roles of the viewer
+	views bool
+	// roles are the roles of the viewer
+	roles map[string]bool
+}
+
+func (p *projector) value(v reflect.Value, path string, depth int) (interface{}, error) {
+	if depth > maxProjectionDepth {
+		return nil, ErrProjectionTooDeep
+	}
+	if !v.IsValid() {
+		return nil, nil
+	}
+	if encoded, ok, err := p.encodeType(v, path, depth); ok {
+		return encoded, err
+	}
+	if enum, ok := isEnum(v); ok {
+		canonical, _ := CanonicalEnum(enum, v.String())
+		return canonical, nil
+	}
+	if formatted, ok := p.ctrl.formatTime(v); ok {
+		return formatted, nil
+	}
+	if leaf, ok := asLeaf(v); ok {
+		if p.ctrl.BigNumbersAsStrings {
+			if num, ok, err := bigNumberLeaf(leaf); ok || err != nil {
+				return num, err
+			}
+		}
+		return leaf, nil
+	}
+	if p.ctrl.BigNumbersAsStrings {
+		if num, ok := bigNumber(v); ok {
+			return num, nil
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return p.value(v.Elem(), path, depth+1)
+
+	case reflect.Struct:
+		return p.object(v, path, depth)
+
+	case reflect.Map:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return p.mapObject(v, path, depth)
+
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil, nil
+		}
+		fallthrough
+	case reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			// byte slices are encoded as strings by the encoders
+			return v.Interface(), nil
+		}
+		list := make([]interface{}, v.Len())
+		for i := range list {
+			item, err := p.value(v.Index(i), path, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = item
+		}
+		return list, nil
+
+	default:
+		return v.Interface(), nil
+	}
+}
+
+// asLeaf returns the value if it encodes itself, and should not be walked
+func asLeaf(v reflect.Value) (interface{}, bool) {
+	typ := v.Type()
+	if typ.Kind() == reflect.Interface {
+		return nil, false
+	}
+	for _, marshaler := range []reflect.Type{jsonMarshalerType, textMarshalerType, xmlMarshalerType} {
+		if typ.Implements(marshaler) {
+			return v.Interface(), true
+		}
+		if v.CanAddr() && reflect.PtrTo(typ).Implements(marshaler) {
+			return v.Addr().Interface(), true
+		}
+	}
+	return nil, false
+}
+
+// object projects the fields of a struct
+func (p *projector) object(v reflect.Value, path string, depth int) (interface{}, error) {
+	obj := new(projectedObject)
+	for _, sf := range structFields(v.Type()) {
+		if sf.json.skip {
+			continue
+		}
+		fv, ok := fieldByIndex(v, sf.index)
+		if !ok {
+			continue
+		}
+
+		if p.views && !viewable(sf, p.roles) {
+			continue
+		}
+
+		fieldPath := joinPath(path, sf.json.name)
+		out := projectedField{
+			key:  p.ctrl.KeyCasing.Apply(sf.json.name),
+			omit: sf.json.omitEmpty && isEmptyValue(fv),
+		}
+
+		switch mode := redaction(sf, p.redact); {
+		case mode == RedactOff || p.allowed[fieldPath]:
+		case mode == RedactRemove:
+			continue
+		default:
+			out.value = RedactedValue
+			obj.fields = append(obj.fields, out)
+			continue
+		}
+
+		value, err := p.value(fv, fieldPath, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		if value == nil {
+			out.omit = p.ctrl.Nulls.omitNull(sf, out.omit)
+		}
+		if sf.json.quoted {
+			value = quote(value)
+		}
+		out.value = value
+		obj.fields = append(obj.fields, out)
+	}
+	return obj, nil
+}
+
+// mapObject projects a map into an object with the keys sorted, as encoding/json would
+func (p *projector) mapObject(v reflect.Value, path string, depth int) (interface{}, error) {
+	type entry struct {
+		key   string
+		value reflect.Value
+	}
+	entries := make([]entry, 0, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		key, err := mapKey(iter.Key())
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry{key: key, value: iter.Value()})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].key < entries[j].key })
+
+	obj := &projectedObject{fields: make([]projectedField, 0, len(entries))}
+	for _, e := range entries {
+		value, err := p.value(e.value, joinPath(path, e.key), depth+1)
+		if err != nil {
+			return nil, err
+		}
+		obj.fields = append(obj.fields, projectedField{
+			key:   e.key,
+			value: value,
+		})
+	}
+	return obj, nil
+}
+
+// mapKey returns the string used for a map key, following encoding/json
+func mapKey(k reflect.Value) (string, error) {
+	if k.Kind() == reflect.String {
+		return k.String(), nil
+	}
+	if tm, ok := k.Interface().(encoding.TextMarshaler); ok {
+		if k.Kind() == reflect.Ptr && k.IsNil() {
+			return "", nil
+		}
+		b, err := tm.MarshalText()
+		return string(b), err
+	}
+	switch k.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(k.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(k.Uint(), 10), nil
+	}
+	return "", fmt.Errorf("render: unsupported map key type %v", k.Type())
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// quote implements the json ",string" option
+func quote(v interface{}) interface{} {
+	switch v.(type) {
+	case string:
+		b, _ := json.Marshal(v)
+		return string(b)
+	case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, uintptr, float32, float64:
+		return fmt.Sprint(v)
+	default:
+		return v
+	}
+}
+
+// fieldByIndex is like reflect.Value.FieldByIndex, but returns false instead of
+// panicking on nil embedded pointers
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// isEmptyValue is the definition of empty used by encoding/json for omitempty
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
+
+// tagOptions are the parsed json or xml struct tag of a field
+type tagOptions struct {
+	name      string
+	skip      bool
+	omitEmpty bool
+	quoted    bool
+	attr      bool
+	chardata  bool
+}
+
+func parseTag(tag string) (opts tagOptions) {
+	if tag == "-" {
+		opts.skip = true
+		return opts
+	}
+	parts := strings.Split(tag, ",")
+	opts.name = parts[0]
+	for _, opt := range parts[1:] {
+		switch opt {
+		case "omitempty":
+			opts.omitEmpty = true
+		case "string":
+			opts.quoted = true
+		case "attr":
+			opts.attr = true
+		case "chardata":
+			opts.chardata = true
+		}
+	}
+	return opts
+}
+
+// structField is an exported field of a struct, or of a struct embedded in it
+type structField struct {
+	tag   reflect.StructTag
+	index []int
+	typ   reflect.Type
+	json  tagOptions
+	xml   tagOptions
+	depth int
+}
+
+// structFieldsCache caches the fields of the struct types, so payloads are not
+// walked with reflection on every request
+var structFieldsCache sync.Map // map[reflect.Type][]structField
+
+// structFields returns the fields of the struct as encoding/json sees them;
+// fields of embedded structs are promoted, and shallower fields win. The
+// fields are cached and shared, they must not be modified.
+func structFields(typ reflect.Type) []structField {
+	if fields, ok := structFieldsCache.Load(typ); ok {
+		return fields.([]structField)
+	}
+	fields, _ := structFieldsCache.LoadOrStore(typ, buildStructFields(typ))
+	return fields.([]structField)
+}
+
+func buildStructFields(typ reflect.Type) []structField {
+	fields := walkStructFields(typ)
+
+	// the shallowest field with a name wins; if there is more than one at the
+	// same depth, none of them do. JSON and XML name fields differently, so
+	// each gets to pick.
+	jsonHidden := dominated(fields, func(f structField) string { return f.json.name })
+	xmlHidden := dominated(fields, func(f structField) string { return f.xml.name })
+	visible := fields[:0]
+	for i, f := range fields {
+		f.json.skip = f.json.skip || jsonHidden[i]
+		f.xml.skip = f.xml.skip || xmlHidden[i]
+		if f.json.skip && f.xml.skip {
+			continue
+		}
+		visible = append(visible, f)
+	}
+	return visible
+}
+
+// walkStructFields returns every exported field of the struct, and of the
+// structs embedded in it, that are not named by their json tag
+func walkStructFields(typ reflect.Type) []structField {
+	var fields []structField
+	var walk func(typ reflect.Type, index []int, depth int, visited map[reflect.Type]bool)
+	walk = func(typ reflect.Type, index []int, depth int, visited map[reflect.Type]bool) {
+		if visited[typ] {
+			return
+		}
+		visited[typ] = true
+		for i := 0; i < typ.NumField(); i++ {
+			sf := typ.Field(i)
+			ft := sf.Type
+			if sf.Anonymous && ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if sf.PkgPath != "" && !(sf.Anonymous && ft.Kind() == reflect.Struct) {
+				// unexported
+				continue
+			}
+			jsonTag := parseTag(sf.Tag.Get("json"))
+			idx := append(append([]int(nil), index...), i)
+			if sf.Anonymous && ft.Kind() == reflect.Struct && jsonTag.name == "" && !jsonTag.skip {
+				walk(ft, idx, depth+1, visited)
+				continue
+			}
+			if sf.PkgPath != "" {
+				continue
+			}
+			xmlTag := parseTag(sf.Tag.Get("xml"))
+			if jsonTag.name == "" {
+				jsonTag.name = sf.Name
+			}
+			if xmlTag.name == "" {
+				xmlTag.name = sf.Name
+			}
+			fields = append(fields, structField{
+				tag:   sf.Tag,
+				index: idx,
+				typ:   sf.Type,
+				json:  jsonTag,
+				xml:   xmlTag,
+				depth: depth,
+			})
+		}
+		visited[typ] = false
+	}
+	walk(typ, nil, 0, make(map[reflect.Type]bool))
+	return fields
+}
+
+// dominated reports, for each field, whether another field with the same name
+// is as shallow or shallower, in which case the field is hidden
+func dominated(fields []structField, name func(f structField) string) []bool {
+	byName := make(map[string][]int)
+	for i, f := range fields {
+		byName[name(f)] = append(byName[name(f)], i)
+	}
+	hidden := make([]bool, len(fields))
+	for i, f := range fields {
+		for _, j := range byName[name(f)] {
+			if j != i && fields[j].depth <= f.depth {
+				hidden[i] = true
+				break
+			}
+		}
+	}
+	return hidden
+}
+
+// typeContains reports whether the type, or a type or struct field reachable from
+// it through pointers, slices, arrays, maps and structs, matches; values held by
+// interfaces are not considered. sf is nil for types that are not struct fields.
+func typeContains(typ reflect.Type, match func(typ reflect.Type, sf *structField) bool) bool {
+	return typeContainsIn(typ, structFields, match)
+}
+
+// typeContainsIn is typeContains, with the fields of the structs given by fields
+func typeContainsIn(typ reflect.Type, fields func(typ reflect.Type) []structField, match func(typ reflect.Type, sf *structField) bool) bool {
+	visited := make(map[reflect.Type]bool)
+	var walk func(typ reflect.Type, sf *structField) bool
+	walk = func(typ reflect.Type, sf *structField) bool {
+		if match(typ, sf) {
+			return true
+		}
+		if visited[typ] {
+			return false
+		}
+		visited[typ] = true
+		switch typ.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+			return walk(typ.Elem(), nil)
+		case reflect.Struct:
+			for _, f := range fields(typ) {
+				f := f
+				if walk(f.typ, &f) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	return walk(typ, nil)
+}
+
+// projectedField is a field of a projectedObject
+type projectedField struct {
+	key   string
+	omit  bool
+	value interface{}
+}
+
+// projectedObject is a struct or map that has been projected; it keeps the
+// order of the fields and knows how to encode itself as JSON
+type projectedObject struct {
+	fields []projectedField
+}
+
+// MarshalJSON writes the fields as a JSON object
+func (obj *projectedObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	first := true
+	for _, f := range obj.fields {
+		if f.omit {
+			continue
+		}
+		if !first {
+			buf.WriteByte(',')
+		}
+		first = false
+		key, err := json.Marshal(f.key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(f.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,327 @@
+package render
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdey/chi-render/responders"
+)
+
+type projectionUser struct {
+	ID       int64  `json:"id" xml:"id,attr"`
+	Name     string `json:"name"`
+	Email    string `json:"email,omitempty" redact:"true"`
+	Password string `json:"-"`
+	Token    string `json:"token" sensitive:"true"`
+	SSN      string `json:"ssn" redact:"remove"`
+}
+
+type projectionArticle struct {
+	XMLName xml.Name        `json:"-" xml:"article"`
+	Title   string          `json:"title" xml:"title"`
+	Author  *projectionUser `json:"author,omitempty" xml:"author"`
+	Tags    []string        `json:"tags" xml:"tag"`
+	Created time.Time       `json:"created" xml:"created"`
+	Count   int             `json:"count,string" xml:"-"`
+	Meta    map[string]int  `json:"meta,omitempty" xml:"-"`
+	Body    []byte          `json:"body" xml:"-"`
+	*projectionExtra
+	Extra json.RawMessage `json:"extra,omitempty" xml:"-"`
+}
+
+type projectionXMLTags struct {
+	XMLName xml.Name `xml:"urn:test tags"`
+	ID      string   `xml:"id,attr"`
+	Street  string   `xml:"address>street"`
+	City    string   `xml:"address>city"`
+	Inner   string   `xml:",innerxml"`
+	Comment string   `xml:",comment"`
+	Text    string   `xml:",chardata"`
+	Empty   string   `xml:"empty,omitempty"`
+}
+
+type projectionExtra struct {
+	Views int    `json:"views"`
+	Title string `json:"title"`
+}
+
+// TestProjectionMatchesEncoders checks that with no policies to apply the
+// projection encodes the same as the original payload.
+func TestProjectionMatchesEncoders(t *testing.T) {
+	created := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	tests := map[string]interface{}{
+		"article": &projectionArticle{
+			Title:           "hello",
+			Author:          &projectionUser{ID: 1, Name: "gopher", Email: "g@example.org"},
+			Tags:            []string{"go", "<html>"},
+			Created:         created,
+			Count:           3,
+			Meta:            map[string]int{"b": 2, "a": 1},
+			Body:            []byte("body"),
+			projectionExtra: &projectionExtra{Views: 10, Title: "shadowed"},
+			Extra:           json.RawMessage(`{"raw":true}`),
+		},
+		"list": []*projectionArticle{{Title: "one"}, {Title: "two", Tags: []string{}}},
+		"map":  map[int]interface{}{2: "two", 1: []int{1}},
+		"nil":  (*projectionArticle)(nil),
+		"xml tags": &projectionXMLTags{
+			ID:      "1",
+			Street:  "main",
+			City:    "gotham",
+			Inner:   "<b>raw</b>",
+			Comment: "note",
+			Text:    "text",
+			Empty:   "",
+		},
+		"anonymous": struct {
+			Name string
+		}{Name: "anon"},
+		"map field": &struct {
+			XMLName xml.Name          `xml:"holder"`
+			Values  map[string]string `json:"values"`
+		}{Values: map[string]string{"<evil x='1'>": "v"}},
+	}
+	p := projector{ctrl: &Controller{}}
+	for name, v := range tests {
+		t.Run(name, func(t *testing.T) {
+			projected := &projection{p: &p, v: reflect.ValueOf(v)}
+			want, _ := json.Marshal(v)
+			got, err := json.Marshal(projected)
+			if err != nil {
+				t.Fatalf("json error, expected nil, got %v", err)
+			}
+			if string(got) != string(want) {
+				t.Errorf("json, expected %s, got %s", want, got)
+			}
+			want, wantErr := xml.Marshal(v)
+			got, err = xml.Marshal(projected)
+			if (err != nil) != (wantErr != nil) {
+				t.Fatalf("xml error, expected %v, got %v", wantErr, err)
+			}
+			if string(got) != string(want) {
+				t.Errorf("xml, expected %s, got %s", want, got)
+			}
+		})
+	}
+}
+
+func TestRedact(t *testing.T) {
+	type tcase struct {
+		Mode    RedactMode
+		Allowed []string
+		Accept  string
+		Body    string
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			ctrl := defaultCtrl.Clone()
+			ctrl.Redact = tc.Mode
+			handler := AllowRedacted(tc.Allowed...)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ctrl.respond(w, r, &projectionUser{ID: 1, Name: "gopher", Email: "g@example.org", Password: "pw", Token: "t", SSN: "123"})
+			}))
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("Accept", tc.Accept)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			if got := w.Body.String(); got != tc.Body {
+				t.Errorf("body, expected %s, got %s", tc.Body, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"off": {
+			Accept: "application/json",
+			Body:   `{"id":1,"name":"gopher","email":"g@example.org","token":"t","ssn":"123"}` + "\n",
+		},
+		"remove": {
+			Mode:   RedactRemove,
+			Accept: "application/json",
+			Body:   `{"id":1,"name":"gopher"}` + "\n",
+		},
+		"mask": {
+			Mode:   RedactMask,
+			Accept: "application/json",
+			Body:   `{"id":1,"name":"gopher","email":"[REDACTED]","token":"[REDACTED]"}` + "\n",
+		},
+		"allowed": {
+			Mode:    RedactMask,
+			Allowed: []string{"email", "ssn"},
+			Accept:  "application/json",
+			Body:    `{"id":1,"name":"gopher","email":"g@example.org","token":"[REDACTED]","ssn":"123"}` + "\n",
+		},
+		"xml": {
+			Mode:   RedactRemove,
+			Accept: "text/xml",
+			Body:   xml.Header + `<projectionUser id="1"><Name>gopher</Name><Password>pw</Password></projectionUser>`,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+// TestProjectionXMLMapKeys checks that map keys never become XML element names
+func TestProjectionXMLMapKeys(t *testing.T) {
+	ctrl := defaultCtrl.Clone()
+	ctrl.Redact = RedactMask
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept", "text/xml")
+	w := httptest.NewRecorder()
+	ctrl.respond(w, r, &struct {
+		XMLName xml.Name          `xml:"holder"`
+		Values  map[string]string `json:"values"`
+	}{Values: map[string]string{"<evil x='1'>": "v"}})
+	if strings.Contains(w.Body.String(), "<evil") {
+		t.Errorf("body, expected the map key to be escaped, got %s", w.Body.String())
+	}
+	if got, want := w.Header().Get("Content-Type"), "application/json; charset=utf-8"; got != want {
+		t.Errorf("content type, expected %v, got %v", want, got)
+	}
+}
+
+// TestRedactUnstructured checks that the policies are applied however the
+// responders are registered, and that responders that do not get the projection
+// are skipped rather than handed the hidden fields.
+func TestRedactUnstructured(t *testing.T) {
+	type tcase struct {
+		Register func(ctrl *Controller)
+		// Payload is responded with; the user if nil
+		Payload interface{}
+		Accept  string
+		Status  int
+		Body    string
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			ctrl := defaultCtrl.Clone()
+			ctrl.Redact = RedactRemove
+			tc.Register(ctrl)
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("Accept", tc.Accept)
+			w := httptest.NewRecorder()
+			payload := tc.Payload
+			if payload == nil {
+				payload = &projectionUser{ID: 1, Name: "gopher", Email: "g@example.org", SSN: "123"}
+			}
+			ctrl.respond(w, r, payload)
+			if w.Code != tc.Status {
+				t.Errorf("status, expected %v, got %v", tc.Status, w.Code)
+			}
+			if tc.Body != "" && w.Body.String() != tc.Body {
+				t.Errorf("body, expected %s, got %s", tc.Body, w.Body.String())
+			}
+			if strings.Contains(w.Body.String(), "g@example.org") {
+				t.Errorf("body, expected the email to be redacted, got %s", w.Body.String())
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"set responder json": {
+			Register: func(ctrl *Controller) { _ = ctrl.SetResponder(ContentTypeJSON, responders.JSON) },
+			Accept:   "application/json",
+			Status:   http.StatusOK,
+			Body:     `{"id":1,"name":"gopher"}` + "\n",
+		},
+		"unstructured skipped": {
+			Register: func(ctrl *Controller) {
+				_ = ctrl.SetResponder(ContentTypePlainText, func(w http.ResponseWriter, r *http.Request, v interface{}) error {
+					_, err := fmt.Fprintf(w, "%+v", v)
+					return err
+				})
+			},
+			Accept: "text/plain",
+			Status: http.StatusOK,
+			Body:   `{"id":1,"name":"gopher"}` + "\n",
+		},
+		"unstructured interface": {
+			Register: func(ctrl *Controller) {
+				_ = ctrl.SetResponder(ContentTypePlainText, func(w http.ResponseWriter, r *http.Request, v interface{}) error {
+					_, err := fmt.Fprintf(w, "%+v", v)
+					return err
+				})
+			},
+			Payload: &struct {
+				Data interface{} `json:"data"`
+			}{Data: &projectionUser{ID: 1, Name: "gopher", Email: "g@example.org"}},
+			Accept: "text/plain",
+			Status: http.StatusOK,
+			Body:   `{"data":{"id":1,"name":"gopher"}}` + "\n",
+		},
+		"unstructured default": {
+			Register: func(ctrl *Controller) {
+				ctrl.DefaultResponse = ContentTypePlainText
+				_ = ctrl.SetResponder(ContentTypeJSON, nil)
+				_ = ctrl.SetResponder(ContentTypePlainText, func(w http.ResponseWriter, r *http.Request, v interface{}) error {
+					_, err := fmt.Fprintf(w, "%+v", v)
+					return err
+				})
+			},
+			Accept: "text/plain",
+			Status: http.StatusNotAcceptable,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+// TestRedactChannel checks that the items of channel payloads streamed as
+// events have the policies applied
+func TestRedactChannel(t *testing.T) {
+	type tcase struct {
+		// Responder replaces ChannelEventStream, if not nil
+		Responder responders.Func
+		Expected  string
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			ctrl := defaultCtrl.Clone()
+			ctrl.Redact = RedactMask
+			if tc.Responder != nil {
+				_ = ctrl.SetResponder(ContentTypeEventStream, tc.Responder)
+			}
+			items := make(chan *projectionUser, 1)
+			items <- &projectionUser{ID: 1, Name: "gopher", Email: "g@example.org"}
+			close(items)
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("Accept", "text/event-stream")
+			w := httptest.NewRecorder()
+			ctrl.respond(w, r, items)
+			if !strings.Contains(w.Body.String(), tc.Expected) {
+				t.Errorf("body, expected %q in %q", tc.Expected, w.Body.String())
+			}
+			if strings.Contains(w.Body.String(), "g@example.org") {
+				t.Errorf("body, expected the email to be redacted, got %s", w.Body.String())
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"event stream": {
+			Expected: `data: {"id":1,"name":"gopher","email":"[REDACTED]","token":"[REDACTED]"}`,
+		},
+		"unstructured": {
+			Responder: func(w http.ResponseWriter, r *http.Request, v interface{}) error {
+				return ChannelEventStream(w, r, v)
+			},
+			Expected: `[{"id":1,"name":"gopher","email":"[REDACTED]","token":"[REDACTED]"}]`,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
@@ -0,0 +1,197 @@
+package render
+
+import (
+	"encoding/xml"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	interfaceType        = reflect.TypeOf((*interface{})(nil)).Elem()
+	xmlMarshalerAttrType = reflect.TypeOf((*xml.MarshalerAttr)(nil)).Elem()
+)
+
+// xmlValue returns a copy of the value, with the policies of the controller
+// applied, that encoding/xml encodes as it would the value itself. Structs are
+// copied into struct types, made with reflect.StructOf, that have the fields
+// that were kept along with their xml tags; so names, attributes, parents
+// ("a>b"), chardata, innerxml and comments keep their meaning. Maps are left
+// for encoding/xml to refuse.
+//
+// named is true if the value is not the field of a struct, in which case
+// encoding/xml names its element after the type, which the copy does not have.
+func (p *projector) xmlValue(v reflect.Value, path string, depth int, named bool) (interface{}, error) {
+	if depth > maxProjectionDepth {
+		return nil, ErrProjectionTooDeep
+	}
+	if !v.IsValid() {
+		return nil, nil
+	}
+	if encoded, ok, err := p.encodeType(v, path, depth); ok {
+		return encoded, err
+	}
+	if enum, ok := isEnum(v); ok {
+		canonical, _ := CanonicalEnum(enum, v.String())
+		return canonical, nil
+	}
+	if formatted, ok := p.ctrl.formatTime(v); ok {
+		return formatted, nil
+	}
+	if leaf, ok := asXMLLeaf(v); ok {
+		return leaf, nil
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return p.xmlValue(v.Elem(), path, depth+1, named)
+
+	case reflect.Struct:
+		return p.xmlObject(v, path, depth, named)
+
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			// byte slices are encoded as text by encoding/xml
+			return v.Interface(), nil
+		}
+		list := make([]interface{}, v.Len())
+		for i := range list {
+			item, err := p.xmlValue(v.Index(i), path, depth+1, named)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = item
+		}
+		return list, nil
+
+	default:
+		return v.Interface(), nil
+	}
+}
+
+// asXMLLeaf returns the value if it encodes itself as XML, and should not be walked
+func asXMLLeaf(v reflect.Value) (interface{}, bool) {
+	typ := v.Type()
+	if typ.Kind() == reflect.Interface {
+		return nil, false
+	}
+	for _, marshaler := range []reflect.Type{xmlMarshalerType, xmlMarshalerAttrType, textMarshalerType} {
+		if typ.Implements(marshaler) {
+			return v.Interface(), true
+		}
+		if v.CanAddr() && reflect.PtrTo(typ).Implements(marshaler) {
+			return v.Addr().Interface(), true
+		}
+	}
+	return nil, false
+}
+
+// xmlObject copies the fields of a struct that are to be encoded into a new struct
+func (p *projector) xmlObject(v reflect.Value, path string, depth int, named bool) (interface{}, error) {
+	var (
+		fields  []reflect.StructField
+		values  []interface{}
+		hasName bool
+	)
+	for _, sf := range structFields(v.Type()) {
+		if sf.xml.skip {
+			continue
+		}
+		fv, ok := fieldByIndex(v, sf.index)
+		if !ok {
+			continue
+		}
+		if sf.typ == xmlNameType {
+			hasName = true
+			fields = append(fields, reflect.StructField{Name: "XMLName", Type: xmlNameType, Tag: xmlFieldTag(sf)})
+			values = append(values, fv.Interface())
+			continue
+		}
+		if p.views && !viewable(sf, p.roles) {
+			continue
+		}
+
+		fieldPath := joinPath(path, sf.json.name)
+		var value interface{}
+		switch mode := redaction(sf, p.redact); {
+		case mode == RedactOff || p.allowed[fieldPath]:
+			if sf.xml.omitEmpty && isEmptyValue(fv) {
+				// encoding/xml only sees the interface, which is not empty
+				break
+			}
+			var err error
+			if value, err = p.xmlValue(fv, fieldPath, depth+1, false); err != nil {
+				return nil, err
+			}
+		case mode == RedactRemove:
+			continue
+		default:
+			value = RedactedValue
+		}
+		if value == nil && strings.Contains(sf.tag.Get("xml"), ",comment") {
+			// encoding/xml refuses comments that are not strings
+			continue
+		}
+		fields = append(fields, reflect.StructField{
+			Name: fmt.Sprintf("F%d", len(fields)),
+			Type: interfaceType,
+			Tag:  xmlFieldTag(sf),
+		})
+		values = append(values, value)
+	}
+	if named && !hasName && v.Type().Name() != "" {
+		fields = append([]reflect.StructField{{Name: "XMLName", Type: xmlNameType}}, fields...)
+		values = append([]interface{}{xml.Name{Local: v.Type().Name()}}, values...)
+	}
+
+	obj := reflect.New(reflect.StructOf(fields)).Elem()
+	for i, value := range values {
+		if value != nil {
+			obj.Field(i).Set(reflect.ValueOf(value))
+		}
+	}
+	return obj.Interface(), nil
+}
+
+// xmlFieldTag returns the xml tag of the field, with the name encoding/xml
+// would give the element if the tag does not name it
+func xmlFieldTag(sf structField) reflect.StructTag {
+	tag := sf.tag.Get("xml")
+	parts := strings.Split(tag, ",")
+	if parts[0] != "" {
+		return reflect.StructTag(`xml:` + strconv.Quote(tag))
+	}
+	for _, flag := range parts[1:] {
+		switch flag {
+		case "chardata", "cdata", "innerxml", "comment":
+			// these can not be named
+			return reflect.StructTag(`xml:` + strconv.Quote(tag))
+		}
+	}
+	name := sf.xml.name
+	if typeName, ok := xmlTypeName(sf.typ); ok {
+		name = typeName
+	}
+	return reflect.StructTag(`xml:` + strconv.Quote(name+tag))
+}
+
+// xmlTypeName returns the name given to the type by the tag of its XMLName field
+func xmlTypeName(typ reflect.Type) (string, bool) {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return "", false
+	}
+	for _, f := range structFields(typ) {
+		if f.typ == xmlNameType {
+			name := strings.SplitN(f.tag.Get("xml"), ",", 2)[0]
+			return name, name != ""
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,128 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"sync"
+)
+
+// ErrProtectedField is the error that ProtectedFieldError values match using errors.Is.
+var ErrProtectedField = errors.New("render: protected field set by client")
+
+// ProtectedFieldError is returned by Bind when the request body sets a field
+// tagged with `protect:"reject"`
+type ProtectedFieldError struct {
+	// Field is the path of the field
+	Field string
+}
+
+func (err *ProtectedFieldError) Error() string {
+	return fmt.Sprintf("render: field '%s' can not be set", err.Field)
+}
+
+// Is reports whether target is ErrProtectedField
+func (err *ProtectedFieldError) Is(target error) bool { return target == ErrProtectedField }
+
+// StatusCode is the http status code that should be reported to the client
+func (err *ProtectedFieldError) StatusCode() int { return http.StatusBadRequest }
+
+// protectedCache caches whether a type has protected fields
+var protectedCache sync.Map // map[reflect.Type]bool
+
+func isProtected(sf structField) bool {
+	_, ok := sf.tag.Lookup("protect")
+	return ok
+}
+
+// protectedFields returns copies of the values of the protected fields, by path,
+// before the request body is decoded into v. Fields are protected with a
+// `protect:"true"` tag, which puts back any value the client sent, or a
+// `protect:"reject"` tag, which fails the Bind with a *ProtectedFieldError.
+//
+//	ID        string    `json:"id" protect:"true"`
+//	CreatedAt time.Time `json:"created_at" protect:"reject"`
+func protectedFields(v interface{}) map[string]reflect.Value {
+	typ := reflect.TypeOf(v)
+	if typ == nil {
+		return nil
+	}
+	has, ok := protectedCache.Load(typ)
+	if !ok {
+		has = typeContainsIn(typ, boundFields, func(_ reflect.Type, sf *structField) bool {
+			return sf != nil && isProtected(*sf)
+		})
+		protectedCache.Store(typ, has)
+	}
+	if !has.(bool) {
+		return nil
+	}
+	values := make(map[string]reflect.Value)
+	collectProtected(reflect.ValueOf(v), "", 0, values)
+	return values
+}
+
+func collectProtected(v reflect.Value, path string, depth int, values map[string]reflect.Value) {
+	if !v.IsValid() || depth > maxProjectionDepth {
+		return
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			collectProtected(v.Elem(), path, depth+1, values)
+		}
+
+	case reflect.Struct:
+		for _, sf := range boundFields(v.Type()) {
+			fv, ok := fieldByIndex(v, sf.index)
+			if !ok {
+				continue
+			}
+			fieldPath := joinPath(path, sf.json.name)
+			if isProtected(sf) {
+				// deepCopy returns basic values as is, which would still
+				// point at the field
+				value := reflect.New(fv.Type()).Elem()
+				value.Set(deepCopy(fv, make(map[visited]reflect.Value)))
+				values[fieldPath] = value
+				continue
+			}
+			collectProtected(fv, fieldPath, depth+1, values)
+		}
+
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			collectProtected(v.Index(i), path+"["+strconv.Itoa(i)+"]", depth+1, values)
+		}
+
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			key, _ := mapKey(iter.Key())
+			collectProtected(iter.Value(), joinPath(path, key), depth+1, values)
+		}
+	}
+}
+
+// bindProtected puts back the value a protected field had before the body was
+// decoded, or rejects the body if the field is tagged with `protect:"reject"`
+func (b *fieldBinder) bindProtected(sf structField, v reflect.Value, path string) error {
+	mode, ok := sf.tag.Lookup("protect")
+	if !ok || mode == "false" || !v.CanSet() {
+		return nil
+	}
+	original, ok := b.protected[path]
+	if !ok {
+		original = reflect.Zero(v.Type())
+	}
+	if reflect.DeepEqual(v.Interface(), original.Interface()) {
+		return nil
+	}
+	if mode == "reject" {
+		return &ProtectedFieldError{Field: path}
+	}
+	v.Set(original)
+	return nil
+}
@@ -0,0 +1,94 @@
+package render
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBindProtected(t *testing.T) {
+	type comment struct {
+		ID   string `json:"id" protect:"true"`
+		Text string `json:"text"`
+	}
+	type payload struct {
+		ID        string     `json:"id" protect:"true"`
+		Owner     string     `json:"owner" protect:"true"`
+		CreatedAt *time.Time `json:"created_at" protect:"reject"`
+		Title     string     `json:"title"`
+		Comments  []comment  `json:"comments"`
+		NilBinder
+	}
+	type tcase struct {
+		Existing payload
+		Body     string
+		Expected payload
+		Err      *ProtectedFieldError
+	}
+
+	// decoding into an existing pointer writes through it, so each case gets its own
+	created := func() *time.Time {
+		at := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+		return &at
+	}
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(tc.Body))
+			r.Header.Set("Content-Type", "application/json")
+
+			p := tc.Existing
+			err := defaultCtrl.Bind(r, &p)
+			if tc.Err != nil {
+				var protErr *ProtectedFieldError
+				if !errors.As(err, &protErr) || !errors.Is(err, ErrProtectedField) {
+					t.Errorf("error, expected *ProtectedFieldError, got %v", err)
+					return
+				}
+				if protErr.Field != tc.Err.Field {
+					t.Errorf("field, expected %v, got %v", tc.Err.Field, protErr.Field)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("error, expected nil, got %v", err)
+				return
+			}
+			if !reflect.DeepEqual(p, tc.Expected) {
+				t.Errorf("payload, expected %+v, got %+v", tc.Expected, p)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"cleared": {
+			Body:     `{"id":"evil","owner":"mallory","title":"hi","comments":[{"id":"x","text":"a"}]}`,
+			Expected: payload{Title: "hi", Comments: []comment{{Text: "a"}}},
+		},
+		"kept": {
+			Existing: payload{ID: "7", Owner: "alice", CreatedAt: created()},
+			Body:     `{"id":"evil","owner":"mallory","title":"hi"}`,
+			Expected: payload{ID: "7", Owner: "alice", CreatedAt: created(), Title: "hi"},
+		},
+		"same value": {
+			Existing: payload{CreatedAt: created()},
+			Body:     `{"created_at":"2020-01-02T03:04:05Z"}`,
+			Expected: payload{CreatedAt: created()},
+		},
+		"rejected": {
+			Body: `{"title":"hi","created_at":"2021-01-01T00:00:00Z"}`,
+			Err:  &ProtectedFieldError{Field: "created_at"},
+		},
+		"rejected existing": {
+			Existing: payload{CreatedAt: created()},
+			Body:     `{"created_at":"2021-01-01T00:00:00Z"}`,
+			Err:      &ProtectedFieldError{Field: "created_at"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
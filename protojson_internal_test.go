@@ -0,0 +1,55 @@
+package render
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestProtoJSON(t *testing.T) {
+	ts := timestamppb.New(time.Date(2021, 2, 25, 0, 0, 0, 0, time.UTC))
+
+	t.Run("respond", func(t *testing.T) {
+		for name, tc := range map[string]struct {
+			ProtoJSON bool
+			Accept    string
+			Expected  string
+		}{
+			"json":           {ProtoJSON: true, Accept: "application/json", Expected: `"2021-02-25T00:00:00Z"`},
+			"default":        {ProtoJSON: true, Accept: "*/*", Expected: `"2021-02-25T00:00:00Z"`},
+			"encoding/json":  {Accept: "application/json", Expected: `"seconds":1614211200`},
+			"other encoding": {ProtoJSON: true, Accept: "text/xml", Expected: "<Timestamp>"},
+		} {
+			t.Run(name, func(t *testing.T) {
+				ctrl := CloneDefault()
+				ctrl.ProtoJSON = tc.ProtoJSON
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(http.MethodGet, "/", nil)
+				r.Header.Set("Accept", tc.Accept)
+				ctrl.respond(w, r, ts)
+				if body := w.Body.String(); !strings.Contains(body, tc.Expected) {
+					t.Errorf("body, expected %s, got %s", tc.Expected, body)
+				}
+			})
+		}
+	})
+
+	t.Run("decode", func(t *testing.T) {
+		ctrl := CloneDefault()
+		ctrl.ProtoJSON = true
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`"2021-02-25T00:00:00Z"`))
+		r.Header.Set("Content-Type", "application/json")
+		got := new(timestamppb.Timestamp)
+		if err := ctrl.decode(r, got); err != nil {
+			t.Fatalf("error, expected nil, got %v", err)
+		}
+		if !proto.Equal(got, ts) {
+			t.Errorf("value, expected %v, got %v", ts, got)
+		}
+	})
+}
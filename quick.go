@@ -0,0 +1,42 @@
+package render
+
+import (
+	"net/http"
+)
+
+// requestController returns the controller attached to the request context, or
+// the default controller if there is none.
+func requestController(r *http.Request) *Controller {
+	if ctrl := FromContext(r); ctrl != nil {
+		return ctrl
+	}
+	return &defaultCtrl
+}
+
+// OK responds with v and a 200 OK status, using the controller attached to the
+// request context, or the default controller. It is meant for small endpoints,
+// like ping and health checks, that have no payload types of their own:
+//
+//	render.OK(w, r, responders.M{"status": "pong"})
+//
+// v does not have to be a Renderer; if it is, it is rendered like Render.
+func OK(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	ctrl := requestController(r)
+	Status(r, http.StatusOK)
+	if renderer, ok := v.(Renderer); ok {
+		return ctrl.Render(w, r, renderer)
+	}
+	ctrl.respond(w, r, v)
+	return nil
+}
+
+// Error responds with an ErrResponse for the status and message, using the
+// controller attached to the request context, or the default controller.
+//
+//	render.Error(w, r, http.StatusServiceUnavailable, "database is down")
+func Error(w http.ResponseWriter, r *http.Request, status int, msg string) error {
+	return requestController(r).Render(w, r, &ErrResponse{
+		StatusCode: status,
+		ErrorText:  msg,
+	})
+}
@@ -0,0 +1,75 @@
+package render
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gdey/chi-render/responders"
+)
+
+func TestQuickResponses(t *testing.T) {
+	type tcase struct {
+		Handler http.HandlerFunc
+		Accept  string
+		Status  int
+		Body    string
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("Accept", tc.Accept)
+			w := httptest.NewRecorder()
+			tc.Handler(w, r)
+			if w.Code != tc.Status {
+				t.Errorf("status, expected %v, got %v", tc.Status, w.Code)
+			}
+			if got := strings.TrimSpace(w.Body.String()); !strings.Contains(got, tc.Body) {
+				t.Errorf("body, expected %s, got %s", tc.Body, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"ok map": {
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				_ = OK(w, r, responders.M{"ping": "pong"})
+			},
+			Accept: "application/json",
+			Status: http.StatusOK,
+			Body:   `{"ping":"pong"}`,
+		},
+		"ok renderer": {
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				_ = OK(w, r, &testPanicky{Name: "ok"})
+			},
+			Accept: "text/xml",
+			Status: http.StatusOK,
+			Body:   `<testPanicky><Name>ok</Name></testPanicky>`,
+		},
+		"error": {
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				_ = Error(w, r, http.StatusServiceUnavailable, "database is down")
+			},
+			Accept: "application/json",
+			Status: http.StatusServiceUnavailable,
+			Body:   `"error":"database is down"`,
+		},
+		"context controller": {
+			Handler: WithCtx(&Controller{
+				responders:      map[ContentType]responders.Registration{ContentTypePlainText: {Func: responders.PlainText}},
+				DefaultResponse: ContentTypePlainText,
+			})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = OK(w, r, "pong")
+			})).ServeHTTP,
+			Accept: "application/json",
+			Status: http.StatusOK,
+			Body:   "pong",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
@@ -0,0 +1,126 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"reflect"
+	"sync"
+)
+
+// RaceCheck is how the controller reacts to a payload pointer being rendered by
+// more than one request at the same time; this is meant for debugging, as it
+// walks the payload on every render.
+type RaceCheck uint8
+
+const (
+	// RaceCheckOff does not track the payloads being rendered
+	RaceCheckOff RaceCheck = iota
+	// RaceCheckLog logs the payload type when a pointer is rendered concurrently
+	RaceCheckLog
+	// RaceCheckError logs and returns ErrConcurrentRender from Render instead of
+	// rendering the payload
+	RaceCheckError
+)
+
+// String returns the name of the check, as used by Describe
+func (check RaceCheck) String() string {
+	switch check {
+	case RaceCheckOff:
+		return "off"
+	case RaceCheckLog:
+		return "log"
+	case RaceCheckError:
+		return "error"
+	default:
+		return fmt.Sprintf("RaceCheck(%d)", uint8(check))
+	}
+}
+
+// ErrConcurrentRender is returned, when the controller's RaceCheck is
+// RaceCheckError, if part of a payload is already being rendered by another request.
+var ErrConcurrentRender = errors.New("render: payload is being rendered concurrently")
+
+// rendering tracks the pointers of the payloads currently being rendered
+var rendering = struct {
+	lck  sync.Mutex
+	ptrs map[visited]int
+}{ptrs: make(map[visited]int)}
+
+// trackRender registers the pointers reachable from the payload as being
+// rendered; the returned func must be called once the payload is rendered.
+func (ctrl *Controller) trackRender(r *http.Request, v interface{}) (release func(), err error) {
+	if ctrl.RaceCheck == RaceCheckOff || v == nil {
+		return func() {}, nil
+	}
+	seen := make(map[visited]bool)
+	collectPointers(reflect.ValueOf(v), seen)
+
+	var shared []reflect.Type
+	rendering.lck.Lock()
+	for key := range seen {
+		if rendering.ptrs[key] > 0 {
+			shared = append(shared, key.typ)
+		}
+	}
+	if len(shared) == 0 || ctrl.RaceCheck != RaceCheckError {
+		for key := range seen {
+			rendering.ptrs[key]++
+		}
+	}
+	rendering.lck.Unlock()
+
+	if len(shared) > 0 {
+		log.Printf("[render] %v %v: %v rendered concurrently by another request", r.Method, r.URL.Path, shared)
+		if ctrl.RaceCheck == RaceCheckError {
+			return nil, fmt.Errorf("%w: %v", ErrConcurrentRender, shared)
+		}
+	}
+	return func() {
+		rendering.lck.Lock()
+		for key := range seen {
+			if rendering.ptrs[key]--; rendering.ptrs[key] <= 0 {
+				delete(rendering.ptrs, key)
+			}
+		}
+		rendering.lck.Unlock()
+	}, nil
+}
+
+// collectPointers records the pointers reachable through the exported fields,
+// slices, arrays, maps and interfaces of v
+func collectPointers(v reflect.Value, seen map[visited]bool) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return
+		}
+		key := visited{ptr: v.Pointer(), typ: v.Type()}
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		collectPointers(v.Elem(), seen)
+	case reflect.Interface:
+		if !v.IsNil() {
+			collectPointers(v.Elem(), seen)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath != "" && !v.Type().Field(i).Anonymous {
+				continue
+			}
+			collectPointers(v.Field(i), seen)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			collectPointers(v.Index(i), seen)
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			collectPointers(iter.Value(), seen)
+		}
+	}
+}
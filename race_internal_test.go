@@ -0,0 +1,62 @@
+package render
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRaceCheck(t *testing.T) {
+	type tcase struct {
+		Check RaceCheck
+		Err   error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			shared := &CopyArticle{Title: "hi"}
+			ctrl := defaultCtrl.Clone()
+			ctrl.RaceCheck = tc.Check
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+			release, err := ctrl.trackRender(r, &CopyArticle{Next: shared})
+			if err != nil {
+				t.Fatalf("first render error, expected nil, got %v", err)
+			}
+
+			// a second request rendering a different payload sharing the article
+			err = ctrl.Render(httptest.NewRecorder(), r, struct {
+				NilRender
+				*CopyArticle
+			}{CopyArticle: shared})
+			if !errors.Is(err, tc.Err) {
+				t.Errorf("concurrent render error, expected %v, got %v", tc.Err, err)
+			}
+
+			release()
+			err = ctrl.Render(httptest.NewRecorder(), r, struct {
+				NilRender
+				*CopyArticle
+			}{CopyArticle: shared})
+			if err != nil {
+				t.Errorf("render after release error, expected nil, got %v", err)
+			}
+			if len(rendering.ptrs) != 0 {
+				t.Errorf("tracked pointers, expected none, got %v", len(rendering.ptrs))
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"off": {},
+		"log": {Check: RaceCheckLog},
+		"error": {
+			Check: RaceCheckError,
+			Err:   ErrConcurrentRender,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
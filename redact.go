@@ -0,0 +1,96 @@
+package render
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gdey/chi-render/responders/helpers"
+)
+
+var (
+	RedactAllowCtxKey = helpers.RedactAllowCtxKey
+)
+
+// RedactedValue replaces the value of masked fields
+const RedactedValue = "[REDACTED]"
+
+// RedactMode is how fields tagged as sensitive are sanitized by the structured
+// responders. Fields are marked with a `redact:"true"` or `sensitive:"true"`
+// tag; the tag may also be `redact:"mask"` or `redact:"remove"` to pick the
+// mode for the field.
+type RedactMode uint8
+
+const (
+	// RedactOff leaves the sensitive fields in the response
+	RedactOff RedactMode = iota
+	// RedactRemove removes the sensitive fields from the response
+	RedactRemove
+	// RedactMask replaces the value of the sensitive fields with RedactedValue
+	RedactMask
+)
+
+// String returns the name of the mode, as used by Describe
+func (mode RedactMode) String() string {
+	switch mode {
+	case RedactOff:
+		return "off"
+	case RedactRemove:
+		return "remove"
+	case RedactMask:
+		return "mask"
+	default:
+		return fmt.Sprintf("RedactMode(%d)", uint8(mode))
+	}
+}
+
+// redaction returns how the field should be redacted, when sensitive fields are
+// sanitized with mode
+func redaction(sf structField, mode RedactMode) RedactMode {
+	if mode == RedactOff {
+		return RedactOff
+	}
+	tag, ok := sf.tag.Lookup("redact")
+	if !ok {
+		if sensitive, _ := sf.tag.Lookup("sensitive"); sensitive != "true" {
+			return RedactOff
+		}
+		tag = "true"
+	}
+	switch strings.ToLower(tag) {
+	case "mask":
+		return RedactMask
+	case "remove":
+		return RedactRemove
+	case "true":
+		return mode
+	default:
+		return RedactOff
+	}
+}
+
+// AllowRedacted is a middleware that lets the routes it wraps see the given
+// sensitive fields. Fields are named by their path of JSON names from the
+// payload, ignoring lists; for example "email" or "user.email".
+func AllowRedacted(fields ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			allowed := make(map[string]bool, len(fields))
+			for name := range allowedRedacted(r) {
+				allowed[name] = true
+			}
+			for _, name := range fields {
+				allowed[name] = true
+			}
+			*r = *r.WithContext(context.WithValue(r.Context(), RedactAllowCtxKey, allowed))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// allowedRedacted returns the sensitive fields the request is allowed to see
+func allowedRedacted(r *http.Request) map[string]bool {
+	allowed, _ := r.Context().Value(RedactAllowCtxKey).(map[string]bool)
+	return allowed
+}
@@ -0,0 +1,131 @@
+package render
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gdey/chi-render/responders/helpers"
+)
+
+// TenantFunc returns the key of the tenant, or app, a request is for; an
+// empty key means the tenant is unknown.
+type TenantFunc func(r *http.Request) string
+
+// Registry maps tenant keys to controllers, so multi-tenant gateways can
+// customize the formats per tenant. Use the Middleware to attach the
+// controller of the tenant to each request, and FromContext to get it.
+//
+// The zero value is an empty registry that falls back to the default controller.
+type Registry struct {
+	lck         sync.RWMutex
+	controllers map[string]*Controller
+	fallback    *Controller
+}
+
+// NewRegistry returns a registry that uses the fallback controller for
+// requests from unknown tenants; a nil fallback is the default controller.
+func NewRegistry(fallback *Controller) *Registry {
+	return &Registry{fallback: fallback}
+}
+
+// Register sets the controller for the tenant; use a nil controller to remove
+// the tenant.
+func (reg *Registry) Register(tenant string, ctrl *Controller) {
+	reg.lck.Lock()
+	defer reg.lck.Unlock()
+	if ctrl == nil {
+		delete(reg.controllers, tenant)
+		return
+	}
+	if reg.controllers == nil {
+		reg.controllers = make(map[string]*Controller)
+	}
+	reg.controllers[tenant] = ctrl
+}
+
+// Lookup returns the controller registered for the tenant; ok will be false if
+// there isn't one.
+func (reg *Registry) Lookup(tenant string) (ctrl *Controller, ok bool) {
+	reg.lck.RLock()
+	defer reg.lck.RUnlock()
+	ctrl, ok = reg.controllers[tenant]
+	return ctrl, ok
+}
+
+// Controller returns the controller for the tenant, or the fallback controller
+// if the tenant is unknown.
+func (reg *Registry) Controller(tenant string) *Controller {
+	if ctrl, ok := reg.Lookup(tenant); ok {
+		return ctrl
+	}
+	if reg.fallback != nil {
+		return reg.fallback
+	}
+	return &defaultCtrl
+}
+
+// Tenants returns the sorted keys of the registered tenants
+func (reg *Registry) Tenants() []string {
+	reg.lck.RLock()
+	tenants := make([]string, 0, len(reg.controllers))
+	for tenant := range reg.controllers {
+		tenants = append(tenants, tenant)
+	}
+	reg.lck.RUnlock()
+	sort.Strings(tenants)
+	return tenants
+}
+
+// Middleware attaches the controller of the request's tenant, as returned by
+// the tenant func, to the request context, like WithCtx.
+//
+//	registry.Register("acme", acmeCtrl)
+//	r.Use(registry.Middleware(render.TenantFromHost))
+func (reg *Registry) Middleware(tenant TenantFunc) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctrl := reg.Controller(tenant(r))
+			*r = *r.WithContext(context.WithValue(r.Context(), helpers.RenderCtxKey, ctrl))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// TenantFromHost uses the host of the request, without the port, as the tenant
+func TenantFromHost(r *http.Request) string {
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.ToLower(host)
+}
+
+// TenantFromPathPrefix uses the first segment of the request path as the
+// tenant; `/acme/articles` is for the `acme` tenant.
+func TenantFromPathPrefix(r *http.Request) string {
+	path := strings.TrimPrefix(r.URL.Path, "/")
+	if i := strings.IndexByte(path, '/'); i >= 0 {
+		path = path[:i]
+	}
+	return path
+}
+
+// TenantFromHeader returns a TenantFunc that uses the value of the named header
+// as the tenant.
+func TenantFromHeader(name string) TenantFunc {
+	return func(r *http.Request) string { return r.Header.Get(name) }
+}
+
+// TenantFromClaim returns a TenantFunc that uses a string claim of the
+// request's token as the tenant; claims returns the claims of the token
+// verified by an earlier authentication middleware.
+func TenantFromClaim(claims func(r *http.Request) map[string]interface{}, name string) TenantFunc {
+	return func(r *http.Request) string {
+		tenant, _ := claims(r)[name].(string)
+		return tenant
+	}
+}
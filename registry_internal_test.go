@@ -0,0 +1,91 @@
+package render
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	acme, globex, fallback := defaultCtrl.Clone(), defaultCtrl.Clone(), defaultCtrl.Clone()
+
+	registry := NewRegistry(fallback)
+	registry.Register("acme", acme)
+	registry.Register("globex", globex)
+	registry.Register("initech", defaultCtrl.Clone())
+	registry.Register("initech", nil)
+
+	if got, expected := registry.Tenants(), []string{"acme", "globex"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("tenants, expected %v, got %v", expected, got)
+	}
+
+	type tcase struct {
+		Tenant   TenantFunc
+		Host     string
+		Path     string
+		Header   string
+		Expected *Controller
+	}
+
+	claims := func(r *http.Request) map[string]interface{} {
+		return map[string]interface{}{"tenant": r.Header.Get("X-Test-Claim")}
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, tc.Path, nil)
+			r.Host = tc.Host
+			r.Header.Set("X-Tenant", tc.Header)
+			r.Header.Set("X-Test-Claim", tc.Header)
+
+			var got *Controller
+			h := registry.Middleware(tc.Tenant)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = FromContext(r)
+			}))
+			h.ServeHTTP(httptest.NewRecorder(), r)
+			if got != tc.Expected {
+				t.Errorf("controller, expected %p, got %p", tc.Expected, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"host": {
+			Tenant:   TenantFromHost,
+			Host:     "ACME:8080",
+			Path:     "/",
+			Expected: acme,
+		},
+		"path prefix": {
+			Tenant:   TenantFromPathPrefix,
+			Path:     "/globex/articles/1",
+			Expected: globex,
+		},
+		"header": {
+			Tenant:   TenantFromHeader("X-Tenant"),
+			Path:     "/",
+			Header:   "acme",
+			Expected: acme,
+		},
+		"claim": {
+			Tenant:   TenantFromClaim(claims, "tenant"),
+			Path:     "/",
+			Header:   "globex",
+			Expected: globex,
+		},
+		"unknown": {
+			Tenant:   TenantFromPathPrefix,
+			Path:     "/initech/",
+			Expected: fallback,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+
+	var empty Registry
+	if got := empty.Controller("acme"); got != &defaultCtrl {
+		t.Errorf("zero registry, expected the default controller, got %p", got)
+	}
+}
@@ -60,6 +60,8 @@ import (
 	"context"
 	"net/http"
 	"reflect"
+	"strconv"
+	"time"
 
 	"github.com/gdey/chi-render/responders/helpers"
 
@@ -128,6 +130,22 @@ func (NilBinder) Bind(_ *http.Request) error { return nil }
 // payload structure.
 func Bind(r *http.Request, v Binder) error { return defaultCtrl.Bind(r, v) }
 
+// BindSearch binds the payload from the URL, headers and cookies of the
+// request, using the default controller; see Controller.BindSearch.
+func BindSearch(r *http.Request, v Binder) error { return defaultCtrl.BindSearch(r, v) }
+
+// BindQuery binds the payload from the query string of the request, using the
+// default controller; see Controller.BindQuery.
+func BindQuery(r *http.Request, v Binder) error { return defaultCtrl.BindQuery(r, v) }
+
+// BindRequest binds the payload from the body, URL, headers and cookies of the
+// request, using the default controller; see Controller.BindRequest.
+func BindRequest(r *http.Request, v Binder) error { return defaultCtrl.BindRequest(r, v) }
+
+// DecodeAny decodes the request body into a generic value, using the default
+// controller; see Controller.DecodeAny.
+func DecodeAny(r *http.Request) (interface{}, error) { return defaultCtrl.DecodeAny(r) }
+
 // Render renders a single payload and respond to the client request.
 func Render(w http.ResponseWriter, r *http.Request, v Renderer) error {
 	return defaultCtrl.Render(w, r, v)
@@ -138,14 +156,31 @@ func RenderList(w http.ResponseWriter, r *http.Request, l []Renderer) error {
 	return defaultCtrl.RenderList(w, r, l)
 }
 
+// LongPoll responds with the first item from the channel, or 204 No Content
+// after the timeout, using the default controller; see Controller.LongPoll.
+func LongPoll(w http.ResponseWriter, r *http.Request, ch interface{}, timeout time.Duration) error {
+	return defaultCtrl.LongPoll(w, r, ch, timeout)
+}
+
+// RenderToBuffer renders a single payload and returns the response instead of writing it.
+func RenderToBuffer(r *http.Request, v Renderer) (contentType string, body []byte, status int, err error) {
+	return defaultCtrl.RenderToBuffer(r, v)
+}
+
 // SetDecoder will set the decoder for the given content type.
 // Use a nil DecodeFunc to unset a content type
 func SetDecoder(contentType ContentType, decoder decoders.Func) {
 	_ = defaultCtrl.SetDecoder(contentType, decoder)
 }
 
-// SupportedDecoders returns a ContentTypeSet of the configured Content types with decoders
-func SupportedDecoders() *ContentTypeSet { return defaultCtrl.SupportedDecoders() }
+// SetDecoderLimit will set the maximum number of bytes that will be read from
+// a request body of the given content type. Use a limit of zero to remove the limit.
+func SetDecoderLimit(contentType ContentType, limit int64) {
+	_ = defaultCtrl.SetDecoderLimit(contentType, limit)
+}
+
+// SupportedDecoders returns the descriptors of the content types with decoders
+func SupportedDecoders() ContentTypeDescriptors { return defaultCtrl.SupportedDecoders() }
 
 // SetResponder will set the responder for the given content type.
 // Use a nil RespondFunc to unset a content type
@@ -153,8 +188,60 @@ func SetResponder(contentType ContentType, responder responders.Func) {
 	_ = defaultCtrl.SetResponder(contentType, responder)
 }
 
-// SupportedResponders returns a ContentTypeSet of the configured Content types with responders
-func SupportedResponders() *ContentTypeSet { return defaultCtrl.SupportedResponders() }
+// RegisterResponder will set the responder registration for the given content type.
+// Use a registration with a nil Func to unset a content type
+func RegisterResponder(contentType ContentType, registration responders.Registration) {
+	_ = defaultCtrl.RegisterResponder(contentType, registration)
+}
+
+// RegisterProfileResponder will set the responder registration used when the
+// client asks for the given profile of the content type.
+// Use a registration with a nil Func to unset the profile.
+func RegisterProfileResponder(contentType ContentType, profile string, registration responders.Registration) {
+	_ = defaultCtrl.RegisterProfileResponder(contentType, profile, registration)
+}
+
+// RegisterTypeEncoder will set the encoder used by the structured responders
+// for values of the given type. Use a nil encoder to unset a type.
+func RegisterTypeEncoder(typ reflect.Type, encoder TypeEncoder) {
+	_ = defaultCtrl.RegisterTypeEncoder(typ, encoder)
+}
+
+// RegisterPayload will set the content type preferred for payloads of the given
+// type. Use ContentTypeNone to unset a type.
+func RegisterPayload(typ reflect.Type, preferred ContentType) {
+	_ = defaultCtrl.RegisterPayload(typ, preferred)
+}
+
+// RegisterTypeConverter will set the converter used when binding strings to
+// values of the given type. Use a nil converter to unset a type.
+func RegisterTypeConverter(typ reflect.Type, converter TypeConverter) {
+	_ = defaultCtrl.RegisterTypeConverter(typ, converter)
+}
+
+// Convert converts the string into v, which must be a pointer, naming the field
+// in the error if it fails.
+func Convert(field, s string, v interface{}) error { return defaultCtrl.Convert(field, s, v) }
+
+// Warm builds the reflection state of the payload types ahead of the first
+// request, using the default controller; see Controller.Warm.
+func Warm(types ...interface{}) { _ = defaultCtrl.Warm(types...) }
+
+// Describe returns the configuration of the default controller
+func Describe() ControllerInfo { return defaultCtrl.Describe() }
+
+// SupportedResponders returns the descriptors of the content types with responders
+func SupportedResponders() ContentTypeDescriptors { return defaultCtrl.SupportedResponders() }
+
+// BindForm binds the request, re-rendering the form if it fails, using the
+// default controller; see Controller.BindForm.
+func BindForm(w http.ResponseWriter, r *http.Request, v Binder, form Renderer) bool {
+	return defaultCtrl.BindForm(w, r, v, form)
+}
+
+// ValidateTemplates validates the responders of the default controller; see
+// Controller.ValidateTemplates.
+func ValidateTemplates() error { return defaultCtrl.ValidateTemplates() }
 
 // Status sets a HTTP response status code hint into request context at any point
 // during the request life-cycle. Before the Responder sends its response header
@@ -178,7 +265,7 @@ func renderer(w http.ResponseWriter, r *http.Request, v Renderer) error {
 	}
 
 	// We call it top-down.
-	if err := v.Render(w, r); err != nil {
+	if err := callRender(w, r, v); err != nil {
 		return err
 	}
 
@@ -208,11 +295,12 @@ func renderer(w http.ResponseWriter, r *http.Request, v Renderer) error {
 			continue
 		}
 
+		name := bindFieldName(rt.Field(i))
 		// Check to see if it's a render type
 		if f.Type().Implements(rendererType) {
 			fv := f.Interface().(Renderer)
 			if err := renderer(w, r, fv); err != nil {
-				return err
+				return wrapPanicError(name, err)
 			}
 			continue
 		}
@@ -237,7 +325,7 @@ func renderer(w http.ResponseWriter, r *http.Request, v Renderer) error {
 		if rvv.Type().Implements(rendererType) {
 			fv := rvv.Interface().(Renderer)
 			if err := renderer(w, r, fv); err != nil {
-				return err
+				return wrapPanicError(name+"[0]", err)
 			}
 		} else if !isInterface {
 			// No need to scan through the rest of the array
@@ -252,7 +340,7 @@ func renderer(w http.ResponseWriter, r *http.Request, v Renderer) error {
 			}
 			fv := rvv.Interface().(Renderer)
 			if err := renderer(w, r, fv); err != nil {
-				return err
+				return wrapPanicError(name+"["+strconv.Itoa(j)+"]", err)
 			}
 		}
 
@@ -270,7 +358,7 @@ func binder(r *http.Request, v Binder) error {
 
 	// Call Binder on non-struct types right away
 	if rv.Kind() != reflect.Struct {
-		return v.Bind(r)
+		return callBind(r, v)
 	}
 
 	// For structs, we call Bind on each field that implements Binder
@@ -281,10 +369,11 @@ func binder(r *http.Request, v Binder) error {
 			continue
 		}
 
+		name := bindFieldName(rv.Type().Field(i))
 		if f.Type().Implements(binderType) {
 			fv := f.Interface().(Binder)
 			if err := binder(r, fv); err != nil {
-				return err
+				return wrapBindError(name, err)
 			}
 
 			continue
@@ -309,7 +398,7 @@ func binder(r *http.Request, v Binder) error {
 		if rvv.Type().Implements(binderType) {
 			fv := rvv.Interface().(Binder)
 			if err := binder(r, fv); err != nil {
-				return err
+				return wrapBindError(name+"[0]", err)
 			}
 		} else if !isInterface {
 			// No need to scan through the rest of the array
@@ -324,14 +413,14 @@ func binder(r *http.Request, v Binder) error {
 			}
 			fv := rvv.Interface().(Binder)
 			if err := binder(r, fv); err != nil {
-				return err
+				return wrapBindError(name+"["+strconv.Itoa(j)+"]", err)
 			}
 		}
 
 	}
 
 	// We call it bottom-up
-	if err := v.Bind(r); err != nil {
+	if err := callBind(r, v); err != nil {
 		return err
 	}
 
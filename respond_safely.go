@@ -0,0 +1,118 @@
+package render
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gdey/chi-render/responders"
+)
+
+// responseRecorder is a http.ResponseWriter that records the headers, status
+// and body written by a responder. Nothing is passed on to the underlying
+// ResponseWriter until the recording is committed, which allows the output
+// of a failed responder to be discarded.
+type responseRecorder struct {
+	w      http.ResponseWriter
+	header http.Header
+	status int
+	body   bytes.Buffer
+	// limit is the maximum size of the body, zero or less means no limit
+	limit    int64
+	exceeded bool
+}
+
+// newResponseRecorder returns a recorder for w; w may be nil if the recording
+// is never going to be committed.
+func newResponseRecorder(w http.ResponseWriter) *responseRecorder {
+	if w == nil {
+		return &responseRecorder{header: make(http.Header)}
+	}
+	return &responseRecorder{
+		w:      w,
+		header: w.Header().Clone(),
+	}
+}
+
+func (rec *responseRecorder) Header() http.Header { return rec.header }
+
+func (rec *responseRecorder) WriteHeader(status int) {
+	// like net/http only the first call counts
+	if rec.status == 0 {
+		rec.status = status
+	}
+}
+
+func (rec *responseRecorder) Write(b []byte) (int, error) {
+	if rec.exceeded {
+		return 0, ErrResponseTooLarge
+	}
+	if rec.limit > 0 && int64(rec.body.Len()+len(b)) > rec.limit {
+		rec.exceeded = true
+		return 0, ErrResponseTooLarge
+	}
+	return rec.body.Write(b)
+}
+
+// commit copies the recorded headers, status and body to the underlying ResponseWriter
+func (rec *responseRecorder) commit() error {
+	dst := rec.w.Header()
+	for name := range dst {
+		if _, ok := rec.header[name]; !ok {
+			delete(dst, name)
+		}
+	}
+	for name, values := range rec.header {
+		dst[name] = values
+	}
+	if rec.status != 0 {
+		rec.w.WriteHeader(rec.status)
+	}
+	if rec.body.Len() == 0 {
+		return nil
+	}
+	_, err := rec.w.Write(rec.body.Bytes())
+	return err
+}
+
+// respondSafely calls the responder making sure that the headers, status and body
+// it writes only make it to the client if the responder succeeds. This allows the
+// controller to cleanly fall back to the next responder if a responder fails,
+// even if it had already written part of a response.
+//
+// A panicking responder is recovered from, and reported as a *PanicError.
+//
+// Once the responder succeeds the complete response is available, so this is
+// where it is checksummed and signed.
+func (ctrl *Controller) respondSafely(w http.ResponseWriter, r *http.Request, contentType ContentType, fn responders.Func, v interface{}) error {
+	rec := newResponseRecorder(w)
+	rec.limit = ctrl.MaxResponseBytes
+	start := time.Now()
+	err := callResponder(contentType, fn, rec, r, v)
+	if rec.exceeded || errors.Is(err, ErrResponseTooLarge) {
+		// the responder may have ignored or wrapped the write error, or
+		// stopped encoding once it went over the limit
+		return &ResponseTooLargeError{
+			ContentType: rec.header.Get("Content-Type"),
+			Limit:       rec.limit,
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if ctrl.ServerTiming {
+		if timing := serverTiming(r, time.Since(start)); timing != "" {
+			rec.header.Set("Server-Timing", timing)
+		}
+	}
+	ctrl.Digest.Set(rec.header, rec.body.Bytes())
+	if ctrl.Signer != nil {
+		if err := ctrl.Signer.Sign(rec.header, rec.body.Bytes()); err != nil {
+			return err
+		}
+	}
+	// If the client has gone away there is nothing we can do about it.
+	_ = rec.commit()
+	return nil
+}
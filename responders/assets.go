@@ -0,0 +1,155 @@
+package responders
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"html/template"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gdey/chi-render/responders/helpers"
+)
+
+// assetHashLen is the number of hex digits of the content hash put in asset paths
+const assetHashLen = 16
+
+// Assets serves static assets, like stylesheets, scripts and images, from an
+// fs.FS such as an embed.FS, alongside the pages rendered with Templates.
+//
+// Path returns the cache busting path of an asset, with a hash of its content
+// in the file name (app.css becomes app.3f2a1b0c9d8e7f60.css). Requests for
+// such paths are served with immutable cache headers, as the path changes
+// whenever the content does; other requests are revalidated with the ETag.
+// The hash of an asset is computed once, when it is first needed.
+//
+//	assets := responders.NewAssets(static, "/static/")
+//	tmpls.Funcs = assets.Funcs() // {{asset "app.css"}}
+//	r.Handle("/static/*", assets)
+type Assets struct {
+	// Prefix is the URL path the assets are served under, like "/static/"
+	Prefix string
+
+	fsys   fs.FS
+	lck    sync.RWMutex
+	hashes map[string]string
+}
+
+// NewAssets returns the assets in fsys served under the URL path prefix
+func NewAssets(fsys fs.FS, prefix string) *Assets {
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	return &Assets{Prefix: prefix, fsys: fsys}
+}
+
+// hash returns the content hash of the named asset, and its content if it had
+// to be read; ok is false if there is no such asset.
+func (a *Assets) hash(name string) (hash string, body []byte, ok bool) {
+	a.lck.RLock()
+	hash, ok = a.hashes[name]
+	a.lck.RUnlock()
+	if ok {
+		return hash, nil, true
+	}
+	body, err := fs.ReadFile(a.fsys, name)
+	if err != nil {
+		return "", nil, false
+	}
+	sum := sha256.Sum256(body)
+	hash = hex.EncodeToString(sum[:])[:assetHashLen]
+	a.lck.Lock()
+	if a.hashes == nil {
+		a.hashes = make(map[string]string)
+	}
+	a.hashes[name] = hash
+	a.lck.Unlock()
+	return hash, body, true
+}
+
+// Path returns the cache busting URL path of the named asset; the plain path
+// is returned for assets that do not exist.
+func (a *Assets) Path(name string) string {
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	hash, _, ok := a.hash(name)
+	if !ok {
+		return a.Prefix + name
+	}
+	ext := path.Ext(name)
+	return a.Prefix + strings.TrimSuffix(name, ext) + "." + hash + ext
+}
+
+// Funcs returns the "asset" template func, which returns the Path of an asset
+func (a *Assets) Funcs() template.FuncMap {
+	return template.FuncMap{"asset": a.Path}
+}
+
+// splitAssetHash returns the name of the asset and the hash in the file name
+// of a cache busting path, if it has one.
+func splitAssetHash(name string) (string, string) {
+	ext := path.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	hash := path.Ext(base)
+	if len(hash) != assetHashLen+1 {
+		return name, ""
+	}
+	if _, err := hex.DecodeString(hash[1:]); err != nil {
+		return name, ""
+	}
+	return strings.TrimSuffix(base, hash) + ext, hash[1:]
+}
+
+// ServeHTTP serves the asset named by the path of the request, relative to
+// Prefix, with an ETag, a Content-Type based on the extension or the content,
+// and Cache-Control headers; ranges and conditional requests are handled by
+// http.ServeContent.
+func (a *Assets) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	name := strings.TrimPrefix(path.Clean("/"+strings.TrimPrefix(r.URL.Path, a.Prefix)), "/")
+	hash, body, ok := a.hash(name)
+	immutable := false
+	if !ok {
+		var requested string
+		name, requested = splitAssetHash(name)
+		if requested == "" {
+			http.NotFound(w, r)
+			return
+		}
+		if hash, body, ok = a.hash(name); !ok {
+			http.NotFound(w, r)
+			return
+		}
+		// a stale hash still gets the current content, but it must not be cached
+		immutable = requested == hash
+	}
+	if body == nil {
+		var err error
+		if body, err = fs.ReadFile(a.fsys, name); err != nil {
+			http.NotFound(w, r)
+			return
+		}
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(name))
+	if contentType == "" {
+		contentType = http.DetectContentType(body)
+	}
+	helpers.SetNoSniffHeader(w)
+	helpers.SetContentTypeHeader(w, contentType)
+	w.Header().Set("ETag", `"`+hash+`"`)
+	if immutable {
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	} else {
+		w.Header().Set("Cache-Control", "no-cache")
+	}
+	http.ServeContent(w, r, name, time.Time{}, bytes.NewReader(body))
+}
@@ -0,0 +1,125 @@
+package responders_test
+
+import (
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/gdey/chi-render/responders"
+)
+
+func TestAssets(t *testing.T) {
+	assets := responders.NewAssets(fstest.MapFS{
+		"css/app.css": {Data: []byte("body{}")},
+		"logo":        {Data: []byte("\x89PNG\r\n\x1a\n")},
+	}, "/static")
+
+	cssPath := assets.Path("css/app.css")
+	if !regexp.MustCompile(`^/static/css/app\.[0-9a-f]{16}\.css$`).MatchString(cssPath) {
+		t.Fatalf("path, expected a hashed path, got %v", cssPath)
+	}
+	if got := assets.Path("missing.js"); got != "/static/missing.js" {
+		t.Errorf("missing path, expected /static/missing.js, got %v", got)
+	}
+
+	var page strings.Builder
+	tmpl := template.Must(template.New("page").Funcs(assets.Funcs()).Parse(`<link href="{{asset "css/app.css"}}">`))
+	if err := tmpl.Execute(&page, nil); err != nil {
+		t.Fatalf("template error, expected nil, got %v", err)
+	}
+	if page.String() != `<link href="`+cssPath+`">` {
+		t.Errorf("template, expected the asset path, got %v", page.String())
+	}
+
+	type tcase struct {
+		Method       string
+		Path         string
+		IfNoneMatch  string
+		Status       int
+		ContentType  string
+		CacheControl string
+		Body         string
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			method := tc.Method
+			if method == "" {
+				method = http.MethodGet
+			}
+			r := httptest.NewRequest(method, tc.Path, nil)
+			if tc.IfNoneMatch != "" {
+				r.Header.Set("If-None-Match", tc.IfNoneMatch)
+			}
+			w := httptest.NewRecorder()
+			assets.ServeHTTP(w, r)
+			if w.Code != tc.Status {
+				t.Errorf("status, expected %v, got %v", tc.Status, w.Code)
+			}
+			if got := w.Header().Get("Content-Type"); tc.ContentType != "" && got != tc.ContentType {
+				t.Errorf("content type, expected %v, got %v", tc.ContentType, got)
+			}
+			if got := w.Header().Get("Cache-Control"); got != tc.CacheControl {
+				t.Errorf("cache control, expected %q, got %q", tc.CacheControl, got)
+			}
+			if got := w.Body.String(); tc.Body != "" && got != tc.Body {
+				t.Errorf("body, expected %q, got %q", tc.Body, got)
+			}
+		}
+	}
+
+	etag := `"` + strings.Split(cssPath, ".")[1] + `"`
+	tests := map[string]tcase{
+		"hashed": {
+			Path:         cssPath,
+			Status:       http.StatusOK,
+			ContentType:  "text/css; charset=utf-8",
+			CacheControl: "public, max-age=31536000, immutable",
+			Body:         "body{}",
+		},
+		"plain": {
+			Path:         "/static/css/app.css",
+			Status:       http.StatusOK,
+			CacheControl: "no-cache",
+			Body:         "body{}",
+		},
+		"stale hash": {
+			Path:         "/static/css/app.0123456789abcdef.css",
+			Status:       http.StatusOK,
+			CacheControl: "no-cache",
+			Body:         "body{}",
+		},
+		"not modified": {
+			Path:         "/static/css/app.css",
+			IfNoneMatch:  etag,
+			Status:       http.StatusNotModified,
+			CacheControl: "no-cache",
+		},
+		"detected content type": {
+			Path:         "/static/logo",
+			Status:       http.StatusOK,
+			ContentType:  "image/png",
+			CacheControl: "no-cache",
+		},
+		"not found": {
+			Path:   "/static/css/missing.css",
+			Status: http.StatusNotFound,
+		},
+		"directory": {
+			Path:   "/static/css",
+			Status: http.StatusNotFound,
+		},
+		"method not allowed": {
+			Method: http.MethodPost,
+			Path:   cssPath,
+			Status: http.StatusMethodNotAllowed,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
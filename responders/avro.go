@@ -0,0 +1,35 @@
+package responders
+
+import (
+	"net/http"
+
+	"github.com/gdey/chi-render/responders/helpers"
+)
+
+// AvroEncoder encodes payloads as Avro binary; decoders.AvroSchemas is one.
+type AvroEncoder interface {
+	// EncodeAvro returns v as Avro binary; ok is false if there is no schema
+	// for v
+	EncodeAvro(v interface{}) (body []byte, ok bool, err error)
+}
+
+// Avro returns a responder that writes payloads as Avro binary, avro/binary,
+// with enc. ErrCanNotEncodeObject is returned for payloads enc has no schema
+// for, so the controller falls back to the other content types the client
+// accepts.
+func Avro(enc AvroEncoder) Func {
+	return func(w http.ResponseWriter, r *http.Request, v interface{}) error {
+		body, ok, err := enc.EncodeAvro(v)
+		if !ok {
+			return ErrCanNotEncodeObject
+		}
+		if err != nil {
+			return err
+		}
+
+		helpers.SetContentTypeHeader(w, "avro/binary")
+		helpers.WriteStatus(w, r.Context())
+		_, _ = w.Write(body)
+		return nil
+	}
+}
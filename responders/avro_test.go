@@ -0,0 +1,42 @@
+package responders_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gdey/chi-render/responders"
+)
+
+// avroStrings encodes strings as their bytes, and has no schema for the rest
+type avroStrings struct{}
+
+func (avroStrings) EncodeAvro(v interface{}) ([]byte, bool, error) {
+	s, ok := v.(string)
+	return []byte(s), ok, nil
+}
+
+func TestAvro(t *testing.T) {
+	respond := responders.Avro(avroStrings{})
+
+	w := httptest.NewRecorder()
+	if err := respond(w, httptest.NewRequest(http.MethodGet, "/", nil), "avro"); err != nil {
+		t.Fatalf("error, expected nil, got %v", err)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "avro/binary" {
+		t.Errorf("content type, expected avro/binary, got %v", ct)
+	}
+	if w.Body.String() != "avro" {
+		t.Errorf("body, expected avro, got %q", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	err := respond(w, httptest.NewRequest(http.MethodGet, "/", nil), 1)
+	if !errors.Is(err, responders.ErrCanNotEncodeObject) {
+		t.Errorf("error, expected %v, got %v", responders.ErrCanNotEncodeObject, err)
+	}
+	if w.Body.Len() != 0 {
+		t.Errorf("body, expected none, got %q", w.Body.String())
+	}
+}
@@ -0,0 +1,51 @@
+package responders
+
+import (
+	"encoding"
+	"encoding/json"
+	"net/http"
+)
+
+// BinaryEnvelope is the JSON object binary payloads are wrapped in by BinaryJSON.
+// encoding/json encodes the Data as a base64 string.
+type BinaryEnvelope struct {
+	Data        []byte `json:"data"`
+	ContentType string `json:"content_type"`
+}
+
+// ContentTyper can be implemented by binary payloads to report their content type;
+// otherwise the content type is sniffed with http.DetectContentType.
+type ContentTyper interface {
+	ContentType() string
+}
+
+// BinaryJSON is like JSON, but []byte and encoding.BinaryMarshaler payloads
+// are wrapped in a BinaryEnvelope instead of being encoded directly, so that
+// binary payloads can safely be sent to clients that only accept JSON.
+// Payloads that are also json.Marshalers, like time.Time, are encoded as JSON.
+func BinaryJSON(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	var (
+		data []byte
+		err  error
+	)
+	switch vv := v.(type) {
+	case json.Marshaler:
+		return JSON(w, r, v)
+	case []byte:
+		data = vv
+	case encoding.BinaryMarshaler:
+		if data, err = vv.MarshalBinary(); err != nil {
+			return err
+		}
+	default:
+		return JSON(w, r, v)
+	}
+
+	env := BinaryEnvelope{Data: data}
+	if ct, ok := v.(ContentTyper); ok {
+		env.ContentType = ct.ContentType()
+	} else {
+		env.ContentType = http.DetectContentType(data)
+	}
+	return JSON(w, r, env)
+}
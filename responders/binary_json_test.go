@@ -0,0 +1,80 @@
+package responders_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdey/chi-render/responders"
+	"github.com/gdey/chi-render/responders/helpers"
+	"github.com/gdey/chi-render/responders/test"
+)
+
+type pngImage []byte
+
+func (img pngImage) MarshalBinary() ([]byte, error) { return []byte(img), nil }
+func (pngImage) ContentType() string                { return "image/png" }
+
+func TestBinaryJSON(t *testing.T) {
+
+	stdHeaders := func(tc *test.Case) *test.Case {
+		if tc.R == nil {
+			tc.R = new(http.Request)
+			helpers.Status(tc.R, tc.W.Status)
+		}
+		if tc.W.Headers == nil {
+			tc.W.Headers = make(http.Header)
+		}
+		helpers.SetNoSniffHeader(test.AsHeaderer(tc.W.Headers))
+		helpers.SetContentTypeHeader(test.AsHeaderer(tc.W.Headers), "application/json; charset=utf-8")
+
+		return tc
+	}
+
+	tests := map[string]test.Case{
+		"bytes": func() test.Case {
+			tc := stdHeaders(&test.Case{
+				W: test.ResponseWriter{
+					Status: http.StatusOK,
+					Body:   strings.NewReader(`{"data":"aGVsbG8gd29ybGQ=","content_type":"text/plain; charset=utf-8"}` + "\n"),
+				},
+				V: []byte("hello world"),
+			})
+			return *tc
+		}(),
+		"BinaryMarshaler": func() test.Case {
+			tc := stdHeaders(&test.Case{
+				W: test.ResponseWriter{
+					Status: http.StatusOK,
+					Body:   strings.NewReader(`{"data":"AQID","content_type":"image/png"}` + "\n"),
+				},
+				V: pngImage{1, 2, 3},
+			})
+			return *tc
+		}(),
+		"json.Marshaler": func() test.Case {
+			tc := stdHeaders(&test.Case{
+				W: test.ResponseWriter{
+					Status: http.StatusOK,
+					Body:   strings.NewReader(`"2021-02-25T00:00:00Z"` + "\n"),
+				},
+				V: time.Date(2021, 2, 25, 0, 0, 0, 0, time.UTC),
+			})
+			return *tc
+		}(),
+		"other": func() test.Case {
+			tc := stdHeaders(&test.Case{
+				W: test.ResponseWriter{
+					Status: http.StatusOK,
+					Body:   strings.NewReader(`{"name":"world"}` + "\n"),
+				},
+				V: map[string]string{"name": "world"},
+			})
+			return *tc
+		}(),
+	}
+	for name, tc := range tests {
+		t.Run(name, tc.Test(responders.BinaryJSON))
+	}
+}
@@ -0,0 +1,32 @@
+package responders
+
+import (
+	"html/template"
+	"net/http"
+
+	"github.com/gdey/chi-render/responders/helpers"
+)
+
+// CSRFFuncs returns the template functions that write the CSRF token of the
+// request, which the controller puts in the request context when it has a CSRF
+// provider:
+//
+//	csrfToken  the token
+//	csrfField  a hidden input with the token: <form method="post">{{csrfField}}…</form>
+//
+// Templates define them for every page. For HTMLTemplate payloads use them as
+// the Funcs of the HTMLOptions, after defining them with placeholders, for
+// example with CSRFFuncs(nil), when parsing the templates.
+func CSRFFuncs(r *http.Request) template.FuncMap {
+	csrf := helpers.CSRFToken(r)
+	return template.FuncMap{
+		"csrfToken": func() string { return csrf.Token },
+		"csrfField": func() template.HTML {
+			if csrf.Token == "" {
+				return ""
+			}
+			return template.HTML(`<input type="hidden" name="` + template.HTMLEscapeString(csrf.Field) +
+				`" value="` + template.HTMLEscapeString(csrf.Token) + `">`)
+		},
+	}
+}
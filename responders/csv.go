@@ -0,0 +1,199 @@
+package responders
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gdey/chi-render/responders/helpers"
+)
+
+// CSVMarshaler is implemented by payloads that can be written as csv records
+type CSVMarshaler interface {
+	MarshalCSV() ([][]interface{}, error)
+}
+
+// CSVRecordMarshaler is implemented by payloads that are a single csv record;
+// a slice of them, such as from RenderList, is written one record per element.
+type CSVRecordMarshaler interface {
+	MarshalCSVRecord() ([]interface{}, error)
+}
+
+// CSVOptions configure how the CSV responder writes records. The zero value
+// writes RFC 4180 csv, with LF line endings.
+type CSVOptions struct {
+	// Delimiter is the field delimiter, defaults to ','
+	Delimiter rune
+	// BOM writes a UTF-8 byte order mark first, which Excel needs to detect
+	// that the file is UTF-8
+	BOM bool
+	// CRLF uses \r\n as the line terminator
+	CRLF bool
+	// DecimalSeparator is used for floating point numbers, defaults to '.'
+	DecimalSeparator rune
+	// ThousandsSeparator, if set, is used to group the digits of numbers
+	ThousandsSeparator rune
+}
+
+// ExcelCSVOptions are options for the csv files Excel opens by default in locales,
+// like most of Europe, that use a comma as the decimal separator
+var ExcelCSVOptions = CSVOptions{
+	Delimiter:        ';',
+	BOM:              true,
+	CRLF:             true,
+	DecimalSeparator: ',',
+}
+
+// CSV writes records to the response, setting the Content-Type as text/csv.
+// See NewCSV for the supported payloads.
+func CSV(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	return NewCSV(CSVOptions{})(w, r, v)
+}
+
+// NewCSV returns a responder that writes csv using the given options.
+//
+// The supported payloads are CSVMarshaler, [][]string, [][]interface{}
+// and slices of CSVRecordMarshaler.
+func NewCSV(opts CSVOptions) Func {
+	return func(w http.ResponseWriter, r *http.Request, v interface{}) error {
+		records, err := csvRecords(v)
+		if err != nil {
+			return err
+		}
+
+		var buff bytes.Buffer
+		if opts.BOM {
+			buff.WriteString("\ufeff")
+		}
+		cw := csv.NewWriter(&buff)
+		if opts.Delimiter != 0 {
+			cw.Comma = opts.Delimiter
+		}
+		cw.UseCRLF = opts.CRLF
+		for _, record := range records {
+			fields := make([]string, len(record))
+			for i := range record {
+				if fields[i], err = opts.format(record[i]); err != nil {
+					return err
+				}
+			}
+			if err := cw.Write(fields); err != nil {
+				return fmt.Errorf("CSV write: %w", err)
+			}
+		}
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			return fmt.Errorf("CSV write: %w", err)
+		}
+
+		helpers.SetNoSniffHeader(w)
+		helpers.SetContentTypeHeader(w, "text/csv; charset=utf-8")
+		helpers.WriteStatus(w, r.Context())
+		_, _ = w.Write(buff.Bytes())
+		return nil
+	}
+}
+
+// csvRecords returns the records of a supported payload
+func csvRecords(v interface{}) ([][]interface{}, error) {
+	switch vv := v.(type) {
+	case CSVMarshaler:
+		return vv.MarshalCSV()
+	case [][]interface{}:
+		return vv, nil
+	case [][]string:
+		records := make([][]interface{}, len(vv))
+		for i, record := range vv {
+			records[i] = make([]interface{}, len(record))
+			for j := range record {
+				records[i][j] = record[j]
+			}
+		}
+		return records, nil
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, ErrCanNotEncodeObject
+	}
+	records := make([][]interface{}, 0, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		m, ok := rv.Index(i).Interface().(CSVRecordMarshaler)
+		if !ok {
+			return nil, ErrCanNotEncodeObject
+		}
+		record, err := m.MarshalCSVRecord()
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// format a field value as a string
+func (opts CSVOptions) format(v interface{}) (string, error) {
+	switch vv := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return vv, nil
+	case encoding.TextMarshaler:
+		b, err := vv.MarshalText()
+		return string(b), err
+	case fmt.Stringer:
+		return vv.String(), nil
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return opts.formatNumber(strconv.FormatInt(rv.Int(), 10)), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return opts.formatNumber(strconv.FormatUint(rv.Uint(), 10)), nil
+	case reflect.Float32, reflect.Float64:
+		return opts.formatNumber(strconv.FormatFloat(rv.Float(), 'f', -1, rv.Type().Bits())), nil
+	case reflect.Bool:
+		return strconv.FormatBool(rv.Bool()), nil
+	default:
+		return fmt.Sprint(v), nil
+	}
+}
+
+// formatNumber applies the separators to a number formatted by strconv
+func (opts CSVOptions) formatNumber(num string) string {
+	if opts.DecimalSeparator == 0 && opts.ThousandsSeparator == 0 {
+		return num
+	}
+	sign := ""
+	if strings.HasPrefix(num, "-") {
+		sign, num = "-", num[1:]
+	}
+	whole, frac := num, ""
+	if i := strings.IndexByte(num, '.'); i >= 0 {
+		whole, frac = num[:i], num[i+1:]
+	}
+	if opts.ThousandsSeparator != 0 && len(whole) > 3 {
+		var grouped strings.Builder
+		for i, digit := range whole {
+			if i > 0 && (len(whole)-i)%3 == 0 {
+				grouped.WriteRune(opts.ThousandsSeparator)
+			}
+			grouped.WriteRune(digit)
+		}
+		whole = grouped.String()
+	}
+	if frac == "" {
+		return sign + whole
+	}
+	decimal := opts.DecimalSeparator
+	if decimal == 0 {
+		decimal = '.'
+	}
+	return sign + whole + string(decimal) + frac
+}
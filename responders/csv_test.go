@@ -0,0 +1,98 @@
+package responders_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gdey/chi-render/responders"
+	"github.com/gdey/chi-render/responders/helpers"
+	"github.com/gdey/chi-render/responders/test"
+)
+
+type csvRow struct {
+	Name  string
+	Total float64
+	Count int
+}
+
+func (row csvRow) MarshalCSVRecord() ([]interface{}, error) {
+	return []interface{}{row.Name, row.Total, row.Count}, nil
+}
+
+func TestCSV(t *testing.T) {
+
+	stdHeaders := func(tc *test.Case) *test.Case {
+		if tc.R == nil {
+			tc.R = new(http.Request)
+			helpers.Status(tc.R, tc.W.Status)
+		}
+		if tc.W.Headers == nil {
+			tc.W.Headers = make(http.Header)
+		}
+		helpers.SetNoSniffHeader(test.AsHeaderer(tc.W.Headers))
+		helpers.SetContentTypeHeader(test.AsHeaderer(tc.W.Headers), "text/csv; charset=utf-8")
+
+		return tc
+	}
+
+	type tcase struct {
+		test.Case
+		Options responders.CSVOptions
+	}
+
+	rows := []csvRow{
+		{Name: "widgets, large", Total: 1234567.5, Count: 1200},
+		{Name: "gadgets", Total: -12.25, Count: 3},
+	}
+
+	tests := map[string]tcase{
+		"default": {
+			Case: *stdHeaders(&test.Case{
+				W: test.ResponseWriter{
+					Status: http.StatusOK,
+					Body:   strings.NewReader("\"widgets, large\",1234567.5,1200\ngadgets,-12.25,3\n"),
+				},
+				V: rows,
+			}),
+		},
+		"excel": {
+			Options: responders.ExcelCSVOptions,
+			Case: *stdHeaders(&test.Case{
+				W: test.ResponseWriter{
+					Status: http.StatusOK,
+					Body:   strings.NewReader("\ufeffwidgets, large;1234567,5;1200\r\ngadgets;-12,25;3\r\n"),
+				},
+				V: rows,
+			}),
+		},
+		"thousands": {
+			Options: responders.CSVOptions{ThousandsSeparator: ','},
+			Case: *stdHeaders(&test.Case{
+				W: test.ResponseWriter{
+					Status: http.StatusOK,
+					Body:   strings.NewReader("name,total\nwidgets,\"1,234,567.5\"\n"),
+				},
+				V: [][]interface{}{{"name", "total"}, {"widgets", 1234567.5}},
+			}),
+		},
+		"strings": {
+			Case: *stdHeaders(&test.Case{
+				W: test.ResponseWriter{
+					Status: http.StatusOK,
+					Body:   strings.NewReader("a,b\nc,d\n"),
+				},
+				V: [][]string{{"a", "b"}, {"c", "d"}},
+			}),
+		},
+		"ErrCanNotEncode": {
+			Case: test.Case{
+				Err: responders.ErrCanNotEncodeObject,
+				V:   42,
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, tc.Test(responders.NewCSV(tc.Options)))
+	}
+}
@@ -0,0 +1,37 @@
+package responders
+
+import (
+	"reflect"
+)
+
+// unsupportedKind reports whether the value, once pointers and interfaces are
+// followed, is of a kind that none of the text encoders can represent.
+func unsupportedKind(v interface{}) (reflect.Kind, bool) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return rv.Kind(), false
+		}
+		rv = rv.Elem()
+	}
+	switch k := rv.Kind(); k {
+	case reflect.Chan, reflect.Func, reflect.Complex64, reflect.Complex128, reflect.UnsafePointer:
+		return k, true
+	default:
+		return k, false
+	}
+}
+
+// CanEncodeJSON reports whether v is a value the JSON responder is able to
+// encode. Channels, functions, complex numbers and unsafe pointers are not.
+func CanEncodeJSON(v interface{}) bool {
+	_, bad := unsupportedKind(v)
+	return !bad
+}
+
+// CanEncodeXML reports whether v is a value the XML responder is able to
+// encode. In addition to the kinds JSON can not encode, XML can not encode maps.
+func CanEncodeXML(v interface{}) bool {
+	kind, bad := unsupportedKind(v)
+	return !bad && kind != reflect.Map
+}
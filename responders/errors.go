@@ -6,4 +6,8 @@ var (
 	// ErrCanNotEncodeObject should be returned by RespondFunc if the Responder should
 	// try a different content type, as we don't know how to respond with this object
 	ErrCanNotEncodeObject = errors.New("error can not encode object")
+
+	// ErrResponseTooLarge is returned by the responders that stop encoding once
+	// the payload is larger than the limit of the request, see helpers.ResponseLimit
+	ErrResponseTooLarge = errors.New("render: response body too large")
 )
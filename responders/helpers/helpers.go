@@ -23,8 +23,114 @@ var (
 	ContentTypeCtxKey = &contextKey{"ContentType"}
 	// RenderCtxKey is a context for getting the render to use
 	RenderCtxKey = &contextKey{name: "Renderer"}
+	// StatsCtxKey is a context for recording the render stats of a request
+	StatsCtxKey = &contextKey{name: "Stats"}
+	// IdempotencyCtxKey is a context for capturing the response of a request
+	// with an Idempotency-Key header
+	IdempotencyCtxKey = &contextKey{name: "Idempotency"}
+	// RedactAllowCtxKey is a context for the redacted fields a route is allowed to see
+	RedactAllowCtxKey = &contextKey{name: "RedactAllow"}
+	// ViewerRolesCtxKey is a context for the roles of the viewer of the response
+	ViewerRolesCtxKey = &contextKey{name: "ViewerRoles"}
+	// FieldMaskCtxKey is a context for the fields present in the request body
+	FieldMaskCtxKey = &contextKey{name: "FieldMask"}
+	// ProfileCtxKey is a context for the profile requested for the response content type
+	ProfileCtxKey = &contextKey{name: "Profile"}
+	// OptionsCtxKey is a context for the render options of a route
+	OptionsCtxKey = &contextKey{name: "Options"}
+	// IndentCtxKey is a context for the indent the responders should pretty
+	// print with
+	IndentCtxKey = &contextKey{name: "Indent"}
+	// FormStateCtxKey is a context for the state of a form that failed to bind
+	FormStateCtxKey = &contextKey{name: "FormState"}
+	// FormValuesCtxKey is a context for the values of the form in the request body
+	FormValuesCtxKey = &contextKey{name: "FormValues"}
+	// CSRFCtxKey is a context for the CSRF token, and the name of its form
+	// field, of the forms in the response
+	CSRFCtxKey = &contextKey{name: "CSRF"}
+	// SizeHintCtxKey is a context for the size hint of the payload being responded with
+	SizeHintCtxKey = &contextKey{name: "SizeHint"}
+	// ResponseLimitCtxKey is a context for the maximum number of bytes the
+	// responders may encode the payload to
+	ResponseLimitCtxKey = &contextKey{name: "ResponseLimit"}
+	// ProjectCtxKey is a context for the projection structured responders of
+	// channels apply to each item
+	ProjectCtxKey = &contextKey{name: "Project"}
 )
 
+// ProjectFunc returns the item as the structured responders should see it,
+// with the policies of the controller, like redaction and views, applied.
+type ProjectFunc func(v interface{}) (interface{}, error)
+
+// Project returns the item of a channel payload projected for the structured
+// responders; the item itself if there is no projection.
+func Project(r *http.Request, v interface{}) (interface{}, error) {
+	if r == nil {
+		return v, nil
+	}
+	project, ok := r.Context().Value(ProjectCtxKey).(ProjectFunc)
+	if !ok {
+		return v, nil
+	}
+	return project(v)
+}
+
+// CSRF is the CSRF token of the forms in a response, along with the name of
+// the form field it is sent back in
+type CSRF struct {
+	Field string
+	Token string
+}
+
+// CSRFToken returns the CSRF token, and the name of its form field, for the
+// forms of the response; empty if the controller has no CSRF provider.
+func CSRFToken(r *http.Request) CSRF {
+	if r == nil {
+		return CSRF{}
+	}
+	csrf, _ := r.Context().Value(CSRFCtxKey).(CSRF)
+	return csrf
+}
+
+// SizeHint returns the number of bytes the payload being responded with is
+// expected to encode to; zero if unknown.
+func SizeHint(r *http.Request) int {
+	if r == nil {
+		return 0
+	}
+	hint, _ := r.Context().Value(SizeHintCtxKey).(int)
+	return hint
+}
+
+// ResponseLimit returns the maximum number of bytes the payload being responded
+// with may encode to; zero if there is no limit.
+func ResponseLimit(r *http.Request) int64 {
+	if r == nil {
+		return 0
+	}
+	limit, _ := r.Context().Value(ResponseLimitCtxKey).(int64)
+	return limit
+}
+
+// Indent returns the indent the structured responders should pretty print the
+// response with; empty if the response should be compact.
+func Indent(r *http.Request) string {
+	if r == nil {
+		return ""
+	}
+	indent, _ := r.Context().Value(IndentCtxKey).(string)
+	return indent
+}
+
+// Profile returns the profile media type parameter the client requested for the
+// content type being responded with, e.g. the schema URI in
+// `application/json;profile="https://example.com/schemas/article"`; empty if
+// none was requested.
+func Profile(r *http.Request) string {
+	profile, _ := r.Context().Value(ProfileCtxKey).(string)
+	return profile
+}
+
 // Status sets a HTTP response status code hint into request context at any point
 // during the request life-cycle. Before the Responder sends its response header
 // it will check the StatusCtxKey
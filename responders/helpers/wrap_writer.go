@@ -0,0 +1,115 @@
+package helpers
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+)
+
+// WrapResponseWriter is a http.ResponseWriter that keeps track of the status
+// code and the number of bytes written to the response body.
+type WrapResponseWriter interface {
+	http.ResponseWriter
+	// Status returns the status code written, or zero if WriteHeader has not been called.
+	Status() int
+	// BytesWritten returns the number of bytes written to the response body.
+	BytesWritten() int64
+	// Unwrap returns the original http.ResponseWriter
+	Unwrap() http.ResponseWriter
+	// Tee causes the response body to also be written to the given writer; a nil
+	// writer stops the tee.
+	Tee(io.Writer)
+}
+
+// WrapWriter wraps a http.ResponseWriter to count the bytes written and capture
+// the status code. The returned writer will implement http.Flusher, http.Hijacker
+// and http.Pusher if the given writer does.
+func WrapWriter(w http.ResponseWriter) WrapResponseWriter {
+	if ww, ok := w.(WrapResponseWriter); ok {
+		return ww
+	}
+	bw := basicWriter{ResponseWriter: w}
+	_, fl := w.(http.Flusher)
+	_, hj := w.(http.Hijacker)
+	_, ps := w.(http.Pusher)
+	switch {
+	case fl && hj:
+		return &hijackWriter{flushWriter{bw}}
+	case fl && ps:
+		return &pushWriter{flushWriter{bw}}
+	case fl:
+		return &flushWriter{bw}
+	default:
+		return &bw
+	}
+}
+
+type basicWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+	status      int
+	bytes       int64
+	tee         io.Writer
+}
+
+func (b *basicWriter) WriteHeader(status int) {
+	if !b.wroteHeader {
+		b.status = status
+		b.wroteHeader = true
+	}
+	b.ResponseWriter.WriteHeader(status)
+}
+
+func (b *basicWriter) Write(buf []byte) (int, error) {
+	if !b.wroteHeader {
+		// net/http will write the header with a status ok
+		b.status = http.StatusOK
+		b.wroteHeader = true
+	}
+	n, err := b.ResponseWriter.Write(buf)
+	if b.tee != nil {
+		_, _ = b.tee.Write(buf[:n])
+	}
+	b.bytes += int64(n)
+	return n, err
+}
+
+func (b *basicWriter) Status() int                 { return b.status }
+func (b *basicWriter) BytesWritten() int64         { return b.bytes }
+func (b *basicWriter) Unwrap() http.ResponseWriter { return b.ResponseWriter }
+func (b *basicWriter) Tee(w io.Writer)             { b.tee = w }
+
+type flushWriter struct {
+	basicWriter
+}
+
+func (f *flushWriter) Flush() {
+	if !f.wroteHeader {
+		f.status = http.StatusOK
+		f.wroteHeader = true
+	}
+	f.basicWriter.ResponseWriter.(http.Flusher).Flush()
+}
+
+type hijackWriter struct {
+	flushWriter
+}
+
+func (h *hijackWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return h.basicWriter.ResponseWriter.(http.Hijacker).Hijack()
+}
+
+type pushWriter struct {
+	flushWriter
+}
+
+func (p *pushWriter) Push(target string, opts *http.PushOptions) error {
+	return p.basicWriter.ResponseWriter.(http.Pusher).Push(target, opts)
+}
+
+var (
+	_ http.Flusher  = &flushWriter{}
+	_ http.Hijacker = &hijackWriter{}
+	_ http.Pusher   = &pushWriter{}
+)
@@ -0,0 +1,68 @@
+package helpers_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gdey/chi-render/responders/helpers"
+)
+
+func TestWrapWriter(t *testing.T) {
+	type tcase struct {
+		Status int
+		Body   string
+		// ExpectedStatus is the expected captured status code
+		ExpectedStatus int
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			ww := helpers.WrapWriter(rec)
+			var tee bytes.Buffer
+			ww.Tee(&tee)
+			if tc.Status != 0 {
+				ww.WriteHeader(tc.Status)
+			}
+			if tc.Body != "" {
+				_, _ = ww.Write([]byte(tc.Body))
+			}
+			if ww.Status() != tc.ExpectedStatus {
+				t.Errorf("status, expected %v, got %v", tc.ExpectedStatus, ww.Status())
+			}
+			if ww.BytesWritten() != int64(len(tc.Body)) {
+				t.Errorf("bytes written, expected %v, got %v", len(tc.Body), ww.BytesWritten())
+			}
+			if tee.String() != tc.Body {
+				t.Errorf("tee, expected %q, got %q", tc.Body, tee.String())
+			}
+			if _, ok := ww.(http.Flusher); !ok {
+				t.Errorf("flusher, expected wrapped writer to be a http.Flusher")
+			}
+			if ww.Unwrap() != rec {
+				t.Errorf("unwrap, expected original writer")
+			}
+			if helpers.WrapWriter(ww) != ww {
+				t.Errorf("wrap, expected wrapping a wrapped writer to return it")
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"nothing written": {},
+		"implied status": {
+			Body:           "hello world",
+			ExpectedStatus: http.StatusOK,
+		},
+		"status": {
+			Status:         http.StatusCreated,
+			Body:           "hello world",
+			ExpectedStatus: http.StatusCreated,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
@@ -3,26 +3,88 @@ package responders
 import (
 	"encoding"
 	"fmt"
+	"html"
+	"html/template"
 	"net/http"
 
 	"github.com/gdey/chi-render/responders/helpers"
 )
 
+// HTMLMarshaler is implemented by payloads that produce their own HTML. The
+// output is trusted, so it is up to the implementation to escape any values
+// it includes; prefer RenderHTMLTemplate which escapes them with html/template.
 type HTMLMarshaler interface {
 	MarshalHTML() ([]byte, error)
 }
 
+// HTMLOptions are the options for the responder returned by NewHTML
+type HTMLOptions struct {
+	// RawStrings writes strings, encoding.TextMarshalers and fmt.Stringers as is.
+	// By default they are escaped, so user provided text can not inject markup
+	// into the page.
+	RawStrings bool
+
+	// Templates is the set that HTMLTemplate payloads are looked up in, if the
+	// payload does not carry its own.
+	Templates *template.Template
+
+	// Funcs, if set, returns functions for the request that are injected into
+	// the Templates before an HTMLTemplate payload is executed, for example a
+	// CSRF token or the current user. The functions must have been defined,
+	// with placeholders, when the templates were parsed; and the Templates
+	// must not have been executed before they are given to NewHTML.
+	Funcs func(r *http.Request) template.FuncMap
+}
+
 // HTML writes a string to the response, setting the Content-Type as text/html.
+//
+// HTMLTemplate payloads are executed with html/template (see RenderHTMLTemplate).
+// HTMLMarshalers and template.HTML values are trusted and written as is; other
+// text is escaped, even if it looks like HTML. Use NewHTML to opt out of the
+// escaping.
 func HTML(w http.ResponseWriter, r *http.Request, v interface{}) error {
-	var txt string
+	return respondHTML(w, r, v, HTMLOptions{})
+}
+
+// NewHTML returns an HTML responder using the given options. It panics if
+// Funcs is set and the Templates have already been executed, as they could
+// not be cloned for each request.
+func NewHTML(opts HTMLOptions) Func {
+	if opts.Funcs != nil && opts.Templates != nil {
+		// html/template can only be cloned before it is executed, so keep a
+		// copy that is never executed to clone for each request.
+		base, err := opts.Templates.Clone()
+		if err != nil {
+			panic(fmt.Sprintf("responders: NewHTML can not clone the templates: %v", err))
+		}
+		opts.Templates = base
+	}
+	return func(w http.ResponseWriter, r *http.Request, v interface{}) error {
+		return respondHTML(w, r, v, opts)
+	}
+}
+
+func respondHTML(w http.ResponseWriter, r *http.Request, v interface{}, opts HTMLOptions) error {
+	var (
+		txt     string
+		trusted bool
+	)
 
 	switch vv := v.(type) {
+	case *HTMLTemplate:
+		btxt, err := vv.execute(r, opts)
+		if err != nil {
+			return err
+		}
+		txt, trusted = string(btxt), true
 	case HTMLMarshaler:
 		btxt, err := vv.MarshalHTML()
 		if err != nil {
 			return err
 		}
-		txt = string(btxt)
+		txt, trusted = string(btxt), true
+	case template.HTML:
+		txt, trusted = string(vv), true
 
 	case encoding.TextMarshaler:
 		btxt, err := vv.MarshalText()
@@ -38,6 +100,10 @@ func HTML(w http.ResponseWriter, r *http.Request, v interface{}) error {
 		return ErrCanNotEncodeObject
 	}
 
+	if !trusted && !opts.RawStrings {
+		txt = html.EscapeString(txt)
+	}
+
 	helpers.SetNoSniffHeader(w)
 	helpers.SetContentTypeHeader(w, "text/html; charset=utf-8")
 	helpers.WriteStatus(w, r.Context())
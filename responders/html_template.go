@@ -0,0 +1,58 @@
+package responders
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"net/http"
+)
+
+// HTMLTemplate is a payload the HTML responder renders by executing the named
+// html/template with Data, so that the values are escaped by html/template
+// instead of being concatenated into the page by hand in a MarshalHTML method.
+type HTMLTemplate struct {
+	// Name of the template to execute
+	Name string
+	// Data is passed to the template
+	Data interface{}
+	// Template is the set the template is looked up in; if nil the Templates
+	// of the HTMLOptions of the responder are used. The request Funcs of the
+	// HTMLOptions are not injected into a payload's own Template.
+	Template *template.Template
+}
+
+// RenderHTMLTemplate returns a payload that renders the named template with data
+func RenderHTMLTemplate(name string, data interface{}) *HTMLTemplate {
+	return &HTMLTemplate{Name: name, Data: data}
+}
+
+// Render does nothing; it allows an HTMLTemplate to be passed to render.Render.
+// Data is not rendered, as it is not a Renderer field; render it before
+// building the HTMLTemplate if it needs to be.
+func (*HTMLTemplate) Render(_ http.ResponseWriter, _ *http.Request) error { return nil }
+
+// execute the template, injecting the request funcs if there are any
+func (t *HTMLTemplate) execute(r *http.Request, opts HTMLOptions) ([]byte, error) {
+	tmpl := t.Template
+	if tmpl != nil {
+		opts.Funcs = nil
+	} else {
+		tmpl = opts.Templates
+	}
+	if tmpl == nil {
+		return nil, fmt.Errorf("html: no templates to look up %q in", t.Name)
+	}
+	if opts.Funcs != nil {
+		// Funcs can only be replaced on a clone that has not been executed
+		clone, err := tmpl.Clone()
+		if err != nil {
+			return nil, fmt.Errorf("html: %w", err)
+		}
+		tmpl = clone.Funcs(opts.Funcs(r))
+	}
+	var buff bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buff, t.Name, t.Data); err != nil {
+		return nil, fmt.Errorf("html: %w", err)
+	}
+	return buff.Bytes(), nil
+}
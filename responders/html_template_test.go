@@ -0,0 +1,133 @@
+package responders_test
+
+import (
+	"errors"
+	"html/template"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gdey/chi-render/responders"
+	"github.com/gdey/chi-render/responders/helpers"
+	"github.com/gdey/chi-render/responders/test"
+)
+
+func TestHTMLTemplate(t *testing.T) {
+	// each case gets its own set, as a set can not have funcs injected once executed
+	tmpls := func() *template.Template {
+		return template.Must(template.New("").Funcs(template.FuncMap{
+			"user": func() string { return "" },
+		}).Parse(`{{define "greeting"}}<h1>Hello {{.}}</h1>{{end}}{{define "whoami"}}<p>{{user}}</p>{{end}}`))
+	}
+
+	type tcase struct {
+		Opts responders.HTMLOptions
+		V    *responders.HTMLTemplate
+		Body string
+		Err  bool
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			r := new(http.Request)
+			helpers.Status(r, http.StatusOK)
+			headers := make(http.Header)
+			helpers.SetNoSniffHeader(test.AsHeaderer(headers))
+			helpers.SetContentTypeHeader(test.AsHeaderer(headers), "text/html; charset=utf-8")
+
+			c := test.Case{
+				R: r,
+				V: tc.V,
+				W: test.ResponseWriter{
+					Status:  http.StatusOK,
+					Headers: headers,
+					Body:    strings.NewReader(tc.Body),
+				},
+			}
+			if tc.Err {
+				c = test.Case{
+					R:             r,
+					V:             tc.V,
+					Err:           errors.New("any error"),
+					ErrComparator: func(_, got error) bool { return got != nil },
+				}
+			}
+			c.Test(responders.NewHTML(tc.Opts))(t)
+		}
+	}
+
+	tests := map[string]tcase{
+		"escaped": {
+			Opts: responders.HTMLOptions{Templates: tmpls()},
+			V:    responders.RenderHTMLTemplate("greeting", "<script>"),
+			Body: "<h1>Hello &lt;script&gt;</h1>",
+		},
+		"own template": {
+			V:    &responders.HTMLTemplate{Name: "greeting", Data: "world", Template: tmpls()},
+			Body: "<h1>Hello world</h1>",
+		},
+		"injected funcs": {
+			Opts: responders.HTMLOptions{
+				Templates: tmpls(),
+				Funcs: func(_ *http.Request) template.FuncMap {
+					return template.FuncMap{"user": func() string { return "gopher" }}
+				},
+			},
+			V:    responders.RenderHTMLTemplate("whoami", nil),
+			Body: "<p>gopher</p>",
+		},
+		"no templates": {
+			V:   responders.RenderHTMLTemplate("greeting", "world"),
+			Err: true,
+		},
+		"unknown template": {
+			Opts: responders.HTMLOptions{Templates: tmpls()},
+			V:    responders.RenderHTMLTemplate("farewell", "world"),
+			Err:  true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestHTMLTemplateFuncsEachRequest(t *testing.T) {
+	tmpls := template.Must(template.New("").Funcs(template.FuncMap{
+		"user": func() string { return "" },
+	}).Parse(`{{define "whoami"}}{{user}}{{end}}`))
+
+	responder := responders.NewHTML(responders.HTMLOptions{
+		Templates: tmpls,
+		Funcs: func(r *http.Request) template.FuncMap {
+			return template.FuncMap{"user": func() string { return r.URL.Query().Get("user") }}
+		},
+	})
+	for _, user := range []string{"alice", "bob"} {
+		r := httptest.NewRequest(http.MethodGet, "/?user="+user, nil)
+		w := httptest.NewRecorder()
+		if err := responder(w, r, responders.RenderHTMLTemplate("whoami", nil)); err != nil {
+			t.Fatalf("error, expected nil, got %v", err)
+		}
+		if got := w.Body.String(); got != user {
+			t.Errorf("body, expected %v, got %v", user, got)
+		}
+	}
+}
+
+func TestNewHTMLExecutedTemplates(t *testing.T) {
+	tmpls := template.Must(template.New("page").Parse(`hi`))
+	if err := tmpls.Execute(io.Discard, nil); err != nil {
+		t.Fatalf("execute, unexpected error %v", err)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("panic, expected NewHTML to panic for executed templates")
+		}
+	}()
+	responders.NewHTML(responders.HTMLOptions{
+		Templates: tmpls,
+		Funcs:     func(*http.Request) template.FuncMap { return nil },
+	})
+}
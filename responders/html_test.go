@@ -3,6 +3,7 @@ package responders_test
 import (
 	"bytes"
 	"errors"
+	"html/template"
 	"net/http"
 	"net/url"
 	"strings"
@@ -94,6 +95,36 @@ func TestHTML(t *testing.T) {
 			})
 			return *tc
 		}(),
+		"escaped string": func() test.Case {
+			tc := stdHeaders(&test.Case{
+				W: test.ResponseWriter{
+					Status: http.StatusOK,
+					Body:   strings.NewReader("&lt;script&gt;alert(&#34;hi&#34;)&lt;/script&gt;"),
+				},
+				V: `<script>alert("hi")</script>`,
+			})
+			return *tc
+		}(),
+		"html document string": func() test.Case {
+			tc := stdHeaders(&test.Case{
+				W: test.ResponseWriter{
+					Status: http.StatusOK,
+					Body:   strings.NewReader("&lt;!DOCTYPE html&gt;&lt;html&gt;&lt;body&gt;hi&lt;/body&gt;&lt;/html&gt;"),
+				},
+				V: "<!DOCTYPE html><html><body>hi</body></html>",
+			})
+			return *tc
+		}(),
+		"template.HTML": func() test.Case {
+			tc := stdHeaders(&test.Case{
+				W: test.ResponseWriter{
+					Status: http.StatusOK,
+					Body:   strings.NewReader("<b>hi</b>"),
+				},
+				V: template.HTML("<b>hi</b>"),
+			})
+			return *tc
+		}(),
 		"ErrCanNotEncode": {
 			Err: responders.ErrCanNotEncodeObject,
 			V:   42,
@@ -103,3 +134,16 @@ func TestHTML(t *testing.T) {
 		t.Run(name, tc.Test(responders.HTML))
 	}
 }
+
+func TestNewHTML(t *testing.T) {
+	tc := test.Case{
+		R: new(http.Request),
+		W: test.ResponseWriter{
+			Status: http.StatusOK,
+			Body:   strings.NewReader("<b>hi</b>"),
+		},
+		V: "<b>hi</b>",
+	}
+	helpers.Status(tc.R, http.StatusOK)
+	t.Run("raw strings", tc.Test(responders.NewHTML(responders.HTMLOptions{RawStrings: true})))
+}
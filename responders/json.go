@@ -1,22 +1,37 @@
 package responders
 
 import (
-	"bytes"
+	"encoding"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/gdey/chi-render/responders/helpers"
 	"net/http"
+	"reflect"
+)
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
 )
 
 
 // JSON marshals 'v' to JSON, automatically escaping HTML and setting the
 // Content-Type as application/json.
+//
+// ErrCanNotEncodeObject is returned for values JSON can not represent (see
+// CanEncodeJSON), so another responder can be tried.
 func JSON(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	if !CanEncodeJSON(v) {
+		return ErrCanNotEncodeObject
+	}
 
-	buf := &bytes.Buffer{}
-	enc := json.NewEncoder(buf)
-	enc.SetEscapeHTML(true)
-	if err := enc.Encode(v); err != nil {
+	buf := newBuffer(r, v)
+	if err := encodeJSON(buf, v, helpers.Indent(r)); err != nil {
+		var typeErr *json.UnsupportedTypeError
+		if errors.As(err, &typeErr) {
+			return ErrCanNotEncodeObject
+		}
 		return fmt.Errorf("JSON encode: %w", err)
 	}
 
@@ -27,3 +42,63 @@ func JSON(w http.ResponseWriter, r *http.Request, v interface{}) error {
 
 	return nil
 }
+
+// encodeJSON writes v to buf as a json.Encoder would. If the response is limited
+// lists are encoded an item at a time, so a list that is too large is refused
+// once the limit is reached rather than after it has been encoded in full.
+func encodeJSON(buf *responseBuffer, v interface{}, indent string) error {
+	rv := reflect.ValueOf(v)
+	streams := buf.limit > 0 && rv.IsValid() &&
+		(rv.Kind() == reflect.Slice && !rv.IsNil() || rv.Kind() == reflect.Array) &&
+		rv.Type().Elem().Kind() != reflect.Uint8 &&
+		!rv.Type().Implements(jsonMarshalerType) && !rv.Type().Implements(textMarshalerType)
+	if !streams {
+		enc := json.NewEncoder(buf)
+		enc.SetEscapeHTML(true)
+		if indent != "" {
+			enc.SetIndent("", indent)
+		}
+		return enc.Encode(v)
+	}
+
+	if _, err := buf.Write([]byte{'['}); err != nil {
+		return err
+	}
+	for i := 0; i < rv.Len(); i++ {
+		var sep []byte
+		if i > 0 {
+			sep = append(sep, ',')
+		}
+		if indent != "" {
+			sep = append(append(sep, '\n'), indent...)
+		}
+		if _, err := buf.Write(sep); err != nil {
+			return err
+		}
+		// items of slices are addressable, so their pointer methods are
+		// used by encoding/json
+		elem := rv.Index(i)
+		if elem.CanAddr() {
+			elem = elem.Addr()
+		}
+		var item []byte
+		var err error
+		if indent != "" {
+			item, err = json.MarshalIndent(elem.Interface(), indent, indent)
+		} else {
+			item, err = json.Marshal(elem.Interface())
+		}
+		if err != nil {
+			return err
+		}
+		if _, err := buf.Write(item); err != nil {
+			return err
+		}
+	}
+	end := "]\n"
+	if indent != "" && rv.Len() > 0 {
+		end = "\n]\n"
+	}
+	_, err := buf.Write([]byte(end))
+	return err
+}
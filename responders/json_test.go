@@ -1,7 +1,10 @@
 package responders_test
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 
@@ -11,6 +14,14 @@ import (
 	"github.com/gdey/chi-render/responders/test"
 )
 
+// sizeHinted is a payload with an EncodedSizeHint
+type sizeHinted struct {
+	Greeting string `json:"greeting,omitempty"`
+	hint     int
+}
+
+func (s sizeHinted) EncodedSizeHint() int { return s.hint }
+
 func TestJSON(t *testing.T) {
 
 	stdHeaders := func(tc *test.Case) *test.Case {
@@ -50,8 +61,97 @@ func TestJSON(t *testing.T) {
 			})
 			return *tc
 		}(),
+		"size hint": func() test.Case {
+
+			tc := stdHeaders(&test.Case{
+				W: test.ResponseWriter{
+					Status: http.StatusOK,
+					Body:   strings.NewReader("{\"greeting\":\"hello\"}\n"),
+				},
+				V: sizeHinted{Greeting: "hello", hint: 64},
+			})
+			return *tc
+		}(),
+		"bad size hint": func() test.Case {
+
+			tc := stdHeaders(&test.Case{
+				W: test.ResponseWriter{
+					Status: http.StatusOK,
+					Body:   strings.NewReader("{}\n"),
+				},
+				V: sizeHinted{hint: -1},
+			})
+			return *tc
+		}(),
+		"channel": {
+			Err: responders.ErrCanNotEncodeObject,
+			V:   make(chan int),
+		},
+		"nested func": {
+			Err: responders.ErrCanNotEncodeObject,
+			V:   map[string]interface{}{"fn": func() {}},
+		},
 	}
 	for name, tc := range tests {
 		t.Run(name, tc.Test(responders.JSON))
 	}
 }
+
+// countedItem counts the number of times it is encoded
+type countedItem struct {
+	count *int
+}
+
+func (item *countedItem) MarshalJSON() ([]byte, error) {
+	*item.count++
+	return []byte(`"` + strings.Repeat("x", 10) + `"`), nil
+}
+
+func TestJSONResponseLimit(t *testing.T) {
+	limited := func(limit int64, indent string) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		ctx := context.WithValue(r.Context(), helpers.ResponseLimitCtxKey, limit)
+		if indent != "" {
+			ctx = context.WithValue(ctx, helpers.IndentCtxKey, indent)
+		}
+		return r.WithContext(ctx)
+	}
+
+	t.Run("stops early", func(t *testing.T) {
+		var count int
+		items := make([]countedItem, 100)
+		for i := range items {
+			items[i].count = &count
+		}
+		err := responders.JSON(httptest.NewRecorder(), limited(50, ""), items)
+		if !errors.Is(err, responders.ErrResponseTooLarge) {
+			t.Errorf("error, expected %v, got %v", responders.ErrResponseTooLarge, err)
+		}
+		if count >= len(items) {
+			t.Errorf("items encoded, expected fewer than %v, got %v", len(items), count)
+		}
+	})
+
+	for _, indent := range []string{"", "  "} {
+		for name, v := range map[string]interface{}{
+			"list":  []map[string]interface{}{{"a": 1, "b": []int{1, 2}}, {"html": "<b>"}},
+			"empty": []string{},
+			"nil":   []string(nil),
+			"array": [2]string{"a", "b"},
+		} {
+			t.Run(name+" as unlimited"+indent, func(t *testing.T) {
+				want := httptest.NewRecorder()
+				if err := responders.JSON(want, limited(0, indent), v); err != nil {
+					t.Fatalf("unlimited, unexpected error %v", err)
+				}
+				got := httptest.NewRecorder()
+				if err := responders.JSON(got, limited(1000, indent), v); err != nil {
+					t.Fatalf("limited, unexpected error %v", err)
+				}
+				if got.Body.String() != want.Body.String() {
+					t.Errorf("body, expected %q, got %q", want.Body.String(), got.Body.String())
+				}
+			})
+		}
+	}
+}
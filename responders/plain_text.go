@@ -1,18 +1,61 @@
 package responders
 
 import (
+	"bytes"
 	"encoding"
+	"encoding/base64"
 	"encoding/binary"
+	"encoding/hex"
 	"fmt"
 	"net/http"
 
 	"github.com/gdey/chi-render/responders/helpers"
 )
 
+// BinaryEncoding is how PlainText and Data write binary ([]byte) payloads
+type BinaryEncoding int
+
+const (
+	// EncodingRaw writes the bytes as is
+	EncodingRaw BinaryEncoding = iota
+	// EncodingHex writes the bytes as lower case hex
+	EncodingHex
+	// EncodingBase64 writes the bytes as standard base64
+	EncodingBase64
+)
+
+// Encode returns the bytes in the encoding
+func (enc BinaryEncoding) Encode(b []byte) []byte {
+	switch enc {
+	case EncodingHex:
+		dst := make([]byte, hex.EncodedLen(len(b)))
+		hex.Encode(dst, b)
+		return dst
+	case EncodingBase64:
+		dst := make([]byte, base64.StdEncoding.EncodedLen(len(b)))
+		base64.StdEncoding.Encode(dst, b)
+		return dst
+	default:
+		return b
+	}
+}
+
 // PlainText writes a string to the response, setting the Content-Type as
-// text/plain.
+// text/plain. []byte payloads are written as is.
 func PlainText(w http.ResponseWriter, r *http.Request, v interface{}) error {
-	var txt string
+	return plainText(w, r, v, EncodingRaw)
+}
+
+// PlainTextEncoded returns a PlainText responder that writes []byte payloads
+// using the given encoding; useful for token or debug endpoints.
+func PlainTextEncoded(enc BinaryEncoding) Func {
+	return func(w http.ResponseWriter, r *http.Request, v interface{}) error {
+		return plainText(w, r, v, enc)
+	}
+}
+
+func plainText(w http.ResponseWriter, r *http.Request, v interface{}, enc BinaryEncoding) error {
+	var txt []byte
 
 	switch vv := v.(type) {
 	case encoding.TextMarshaler:
@@ -20,11 +63,13 @@ func PlainText(w http.ResponseWriter, r *http.Request, v interface{}) error {
 		if err != nil {
 			return err
 		}
-		txt = string(btxt)
+		txt = btxt
 	case string:
-		txt = vv
+		txt = []byte(vv)
+	case []byte:
+		txt = enc.Encode(vv)
 	case fmt.Stringer:
-		txt = vv.String()
+		txt = []byte(vv.String())
 	default:
 		return ErrCanNotEncodeObject
 	}
@@ -33,19 +78,38 @@ func PlainText(w http.ResponseWriter, r *http.Request, v interface{}) error {
 	helpers.SetContentTypeHeader(w, "text/plain; charset=utf-8")
 	helpers.WriteStatus(w, r.Context())
 
-	w.Write([]byte(txt))
+	w.Write(txt)
 
 	return nil
 }
 
+// IsBinary reports whether v is a []byte or encoding.BinaryMarshaler; it can be used
+// as the CanEncode of a Data registration so only binary payloads are sent as
+// application/octet-stream.
+func IsBinary(v interface{}) bool {
+	switch v.(type) {
+	case []byte, encoding.BinaryMarshaler:
+		return true
+	default:
+		return false
+	}
+}
+
 // Data writes raw bytes to the response, setting the Content-Type as
 // application/octet-stream.
-func Data(w http.ResponseWriter, r *http.Request, v interface{}) {
+func Data(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	return data(w, r, v, EncodingRaw)
+}
 
-	helpers.SetNoSniffHeader(w)
-	helpers.SetContentTypeHeader(w, "application/octet-stream")
-	helpers.WriteStatus(w, r.Context())
+// DataEncoded returns a Data responder that writes the bytes using the given
+// encoding.
+func DataEncoded(enc BinaryEncoding) Func {
+	return func(w http.ResponseWriter, r *http.Request, v interface{}) error {
+		return data(w, r, v, enc)
+	}
+}
 
+func data(w http.ResponseWriter, r *http.Request, v interface{}, enc BinaryEncoding) error {
 	var (
 		b   []byte
 		err error
@@ -55,25 +119,32 @@ func Data(w http.ResponseWriter, r *http.Request, v interface{}) {
 	case encoding.BinaryMarshaler:
 		b, err = vv.MarshalBinary()
 		if err != nil {
-			return
+			return err
 		}
 	case []byte:
 		b = vv
 	case encoding.TextMarshaler:
-		t, err := vv.MarshalText()
+		b, err = vv.MarshalText()
 		if err != nil {
-			return
+			return err
 		}
-		b = t
 	case string:
 		b = []byte(vv)
 	case fmt.Stringer:
 		b = []byte(vv.String())
 
 	default:
-		binary.Write(w, binary.BigEndian, v)
-		return
+		var buff bytes.Buffer
+		if err = binary.Write(&buff, binary.BigEndian, v); err != nil {
+			// Not a fixed size value
+			return ErrCanNotEncodeObject
+		}
+		b = buff.Bytes()
 	}
-	w.Write(b)
-	return
+
+	helpers.SetNoSniffHeader(w)
+	helpers.SetContentTypeHeader(w, "application/octet-stream")
+	helpers.WriteStatus(w, r.Context())
+	w.Write(enc.Encode(b))
+	return nil
 }
@@ -2,6 +2,7 @@ package responders_test
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"net/url"
 	"strings"
@@ -93,4 +94,65 @@ func TestPlainText(t *testing.T) {
 	for name, tc := range tests {
 		t.Run(name, tc.Test(responders.PlainText))
 	}
+
+	encodedTests := map[responders.BinaryEncoding]string{
+		responders.EncodingRaw:    "\x01\xfetoken",
+		responders.EncodingHex:    "01fe746f6b656e",
+		responders.EncodingBase64: "Af50b2tlbg==",
+	}
+	for enc, body := range encodedTests {
+		tc := stdHeaders(&test.Case{
+			W: test.ResponseWriter{
+				Status: http.StatusOK,
+				Body:   strings.NewReader(body),
+			},
+			V: []byte("\x01\xfetoken"),
+		})
+		t.Run(fmt.Sprintf("bytes encoding %d", enc), tc.Test(responders.PlainTextEncoded(enc)))
+	}
+}
+
+func TestData(t *testing.T) {
+
+	stdHeaders := func(tc *test.Case) *test.Case {
+		if tc.R == nil {
+			tc.R = new(http.Request)
+			helpers.Status(tc.R, tc.W.Status)
+		}
+		if tc.W.Headers == nil {
+			tc.W.Headers = make(http.Header)
+		}
+		helpers.SetNoSniffHeader(test.AsHeaderer(tc.W.Headers))
+		helpers.SetContentTypeHeader(test.AsHeaderer(tc.W.Headers), "application/octet-stream")
+
+		return tc
+	}
+
+	tests := map[string]test.Case{
+		"bytes": *stdHeaders(&test.Case{
+			W: test.ResponseWriter{
+				Status: http.StatusOK,
+				Body:   strings.NewReader("\x01\x02"),
+			},
+			V: []byte{1, 2},
+		}),
+		"fixed size": *stdHeaders(&test.Case{
+			W: test.ResponseWriter{
+				Status: http.StatusOK,
+				Body:   strings.NewReader("\x00\x2a"),
+			},
+			V: uint16(42),
+		}),
+		"TextMarshaler Error": {
+			V:   TextMarshalerError{errors.New("expected marshaller error")},
+			Err: errors.New("expected marshaller error"),
+		},
+		"ErrCanNotEncode": {
+			Err: responders.ErrCanNotEncodeObject,
+			V:   map[string]int{},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, tc.Test(responders.Data))
+	}
 }
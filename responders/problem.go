@@ -0,0 +1,86 @@
+package responders
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gdey/chi-render/responders/helpers"
+)
+
+// ProblemDetails is a RFC 7807 problem details object
+type ProblemDetails struct {
+	Type     string `json:"type,omitempty"`
+	Title    string `json:"title,omitempty"`
+	Status   int    `json:"status,omitempty"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+
+	// Extensions are additional members that will be added to the problem object
+	Extensions map[string]interface{} `json:"-"`
+}
+
+// MarshalJSON encodes the problem details along with any extension members
+func (pd ProblemDetails) MarshalJSON() ([]byte, error) {
+	obj := make(map[string]interface{}, len(pd.Extensions)+5)
+	for name, value := range pd.Extensions {
+		obj[name] = value
+	}
+	if pd.Type != "" {
+		obj["type"] = pd.Type
+	}
+	if pd.Title != "" {
+		obj["title"] = pd.Title
+	}
+	if pd.Status != 0 {
+		obj["status"] = pd.Status
+	}
+	if pd.Detail != "" {
+		obj["detail"] = pd.Detail
+	}
+	if pd.Instance != "" {
+		obj["instance"] = pd.Instance
+	}
+	return json.Marshal(obj)
+}
+
+// ProblemDetailer is implemented by objects that can describe themselves as
+// problem details
+type ProblemDetailer interface {
+	ProblemDetails() ProblemDetails
+}
+
+// ProblemJSON marshals 'v' to JSON problem details, setting the Content-Type as
+// application/problem+json. 'v' must be a ProblemDetails or a ProblemDetailer
+// otherwise ErrCanNotEncodeObject is returned.
+func ProblemJSON(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	var pd ProblemDetails
+	switch vv := v.(type) {
+	case ProblemDetails:
+		pd = vv
+	case *ProblemDetails:
+		pd = *vv
+	case ProblemDetailer:
+		pd = vv.ProblemDetails()
+	default:
+		return ErrCanNotEncodeObject
+	}
+
+	buf := newBuffer(r, v)
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(true)
+	if err := enc.Encode(pd); err != nil {
+		return fmt.Errorf("problem JSON encode: %w", err)
+	}
+
+	helpers.SetNoSniffHeader(w)
+	helpers.SetContentTypeHeader(w, "application/problem+json; charset=utf-8")
+	if pd.Status != 0 {
+		w.WriteHeader(pd.Status)
+	} else {
+		helpers.WriteStatus(w, r.Context())
+	}
+	_, _ = w.Write(buf.Bytes())
+
+	return nil
+}
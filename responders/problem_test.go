@@ -0,0 +1,58 @@
+package responders_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gdey/chi-render/responders"
+	"github.com/gdey/chi-render/responders/helpers"
+	"github.com/gdey/chi-render/responders/test"
+)
+
+type notFound string
+
+func (nf notFound) ProblemDetails() responders.ProblemDetails {
+	return responders.ProblemDetails{
+		Title:      "Not Found",
+		Status:     http.StatusNotFound,
+		Detail:     string(nf) + " was not found",
+		Extensions: map[string]interface{}{"resource": string(nf)},
+	}
+}
+
+func TestProblemJSON(t *testing.T) {
+
+	stdHeaders := func(tc *test.Case) *test.Case {
+		if tc.R == nil {
+			tc.R = new(http.Request)
+		}
+		if tc.W.Headers == nil {
+			tc.W.Headers = make(http.Header)
+		}
+		helpers.SetNoSniffHeader(test.AsHeaderer(tc.W.Headers))
+		helpers.SetContentTypeHeader(test.AsHeaderer(tc.W.Headers), "application/problem+json; charset=utf-8")
+
+		return tc
+	}
+
+	tests := map[string]test.Case{
+		"ProblemDetailer": func() test.Case {
+			tc := stdHeaders(&test.Case{
+				W: test.ResponseWriter{
+					Status: http.StatusNotFound,
+					Body:   strings.NewReader(`{"detail":"article was not found","resource":"article","status":404,"title":"Not Found"}` + "\n"),
+				},
+				V: notFound("article"),
+			})
+			return *tc
+		}(),
+		"ErrCanNotEncode": {
+			Err: responders.ErrCanNotEncodeObject,
+			V:   42,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, tc.Test(responders.ProblemJSON))
+	}
+}
@@ -0,0 +1,33 @@
+package responders
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gdey/chi-render/responders/helpers"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// ProtoJSON is like JSON, but proto.Message payloads are encoded with
+// protojson, the canonical JSON mapping of protocol buffers: lowerCamelCase
+// field names, enums by name, and the JSON forms of the well known types, like
+// google.protobuf.Timestamp, as gRPC transcoding gateways write them.
+func ProtoJSON(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return JSON(w, r, v)
+	}
+	opts := protojson.MarshalOptions{Indent: helpers.Indent(r)}
+	data, err := opts.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("protojson encode: %w", err)
+	}
+
+	helpers.SetNoSniffHeader(w)
+	helpers.SetContentTypeHeader(w, "application/json; charset=utf-8")
+	helpers.WriteStatus(w, r.Context())
+	_, _ = w.Write(append(data, '\n'))
+
+	return nil
+}
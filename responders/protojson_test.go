@@ -0,0 +1,84 @@
+package responders_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/gdey/chi-render/responders"
+	"github.com/gdey/chi-render/responders/helpers"
+	"github.com/gdey/chi-render/responders/test"
+)
+
+func TestProtoJSON(t *testing.T) {
+	// protojson does not promise a stable output, so the bodies are compared
+	// as json values
+	jsonEqual := func(expected, got []byte) bool {
+		var e, g interface{}
+		if err := json.Unmarshal(expected, &e); err != nil {
+			return false
+		}
+		if err := json.Unmarshal(got, &g); err != nil {
+			return false
+		}
+		return reflect.DeepEqual(e, g)
+	}
+	stdHeaders := func(tc *test.Case) *test.Case {
+		if tc.R == nil {
+			tc.R = new(http.Request)
+			helpers.Status(tc.R, tc.W.Status)
+		}
+		if tc.W.Headers == nil {
+			tc.W.Headers = make(http.Header)
+		}
+		helpers.SetNoSniffHeader(test.AsHeaderer(tc.W.Headers))
+		helpers.SetContentTypeHeader(test.AsHeaderer(tc.W.Headers), "application/json; charset=utf-8")
+		tc.W.BodyComparator = jsonEqual
+		return tc
+	}
+	st, err := structpb.NewStruct(map[string]interface{}{"name": "world", "tags": []interface{}{"a"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]test.Case{
+		"timestamp": *stdHeaders(&test.Case{
+			W: test.ResponseWriter{
+				Status: http.StatusOK,
+				Body:   strings.NewReader(`"2021-02-25T00:00:00Z"`),
+			},
+			V: timestamppb.New(time.Date(2021, 2, 25, 0, 0, 0, 0, time.UTC)),
+		}),
+		"duration": *stdHeaders(&test.Case{
+			W: test.ResponseWriter{
+				Status: http.StatusOK,
+				Body:   strings.NewReader(`"1.500s"`),
+			},
+			V: durationpb.New(1500 * time.Millisecond),
+		}),
+		"struct": *stdHeaders(&test.Case{
+			W: test.ResponseWriter{
+				Status: http.StatusOK,
+				Body:   strings.NewReader(`{"name":"world","tags":["a"]}`),
+			},
+			V: st,
+		}),
+		"not a message": *stdHeaders(&test.Case{
+			W: test.ResponseWriter{
+				Status: http.StatusOK,
+				Body:   strings.NewReader(`{"name":"world"}`),
+			},
+			V: map[string]string{"name": "world"},
+		}),
+	}
+	for name, tc := range tests {
+		t.Run(name, tc.Test(responders.ProtoJSON))
+	}
+}
@@ -8,4 +8,49 @@ type M map[string]interface{}
 
 // Func defined a function that will take an object and Marshal it into a content type
 // before writing it to the http.ResponseWriter
+//
+// The headers, status code and body written by a Func are held back by the
+// controller until the Func returns successfully; so if the Func returns an
+// error, like ErrCanNotEncodeObject, none of what it wrote will reach the client,
+// and the controller is free to try another responder.
 type Func func(http.ResponseWriter, *http.Request, interface{}) error
+
+// Registration is a responder along with the optional capabilities a controller
+// can use during content negotiation.
+type Registration struct {
+	// Func is the responder that will encode the object
+	Func Func
+
+	// CanEncode, if not nil, is called before Func to ask if the responder is able
+	// to encode the object. If it returns false the responder will be skipped
+	// without being called, as if it had returned ErrCanNotEncodeObject.
+	CanEncode func(v interface{}) bool
+
+	// Structured marks responders, like JSON and XML, that encode the structure
+	// of the object through encoding/json or encoding/xml. The controller may
+	// hand these responders a projection of the object, with the controller's
+	// policies (like redaction) applied, in place of the object itself. The
+	// JSON and XML responders of this package are treated as structured
+	// however they are registered. Responders that are not structured are
+	// skipped for objects that have fields the policies would hide. For
+	// channel payloads, structured responders get the channel itself, and
+	// project each item with helpers.Project.
+	Structured bool
+
+	// Validate, if not nil, checks that the responder is ready to encode
+	// objects, for example that its templates parse and execute; it is called
+	// by the controller's ValidateTemplates, usually at startup.
+	Validate func() error
+}
+
+// Encodes reports whether the responder is able to encode the object; if
+// CanEncode is not set it is assumed that the responder can.
+func (reg Registration) Encodes(v interface{}) bool {
+	if reg.Func == nil {
+		return false
+	}
+	if reg.CanEncode == nil {
+		return true
+	}
+	return reg.CanEncode(v)
+}
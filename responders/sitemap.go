@@ -0,0 +1,143 @@
+package responders
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gdey/chi-render/responders/helpers"
+)
+
+// SitemapEntry is a url entry of a sitemap, see https://www.sitemaps.org/protocol.html
+type SitemapEntry struct {
+	// Loc is the URL of the page; required
+	Loc string
+	// LastMod is the time the page was last modified; optional
+	LastMod time.Time
+	// ChangeFreq is how frequently the page is likely to change; optional
+	ChangeFreq string
+	// Priority of the page relative to other pages of the site, 0.0 to 1.0; optional
+	Priority float64
+}
+
+// Sitemapper is implemented by payloads that can be rendered as a sitemap
+type Sitemapper interface {
+	SitemapEntries() []SitemapEntry
+}
+
+type sitemapURL struct {
+	Loc        string `xml:"loc"`
+	LastMod    string `xml:"lastmod,omitempty"`
+	ChangeFreq string `xml:"changefreq,omitempty"`
+	Priority   string `xml:"priority,omitempty"`
+}
+
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+// Sitemap writes the entries of a Sitemapper as a sitemap, setting the
+// Content-Type as application/xml. Payloads that are not Sitemappers are handed
+// off to the XML responder, so it can be registered for application/xml.
+func Sitemap(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	sm, ok := v.(Sitemapper)
+	if !ok {
+		return XML(w, r, v)
+	}
+
+	entries := sm.SitemapEntries()
+	set := sitemapURLSet{URLs: make([]sitemapURL, 0, len(entries))}
+	for _, entry := range entries {
+		u := sitemapURL{
+			Loc:        entry.Loc,
+			ChangeFreq: entry.ChangeFreq,
+		}
+		if !entry.LastMod.IsZero() {
+			u.LastMod = entry.LastMod.Format(time.RFC3339)
+		}
+		if entry.Priority != 0 {
+			u.Priority = strconv.FormatFloat(entry.Priority, 'f', 1, 64)
+		}
+		set.URLs = append(set.URLs, u)
+	}
+
+	b, err := xml.Marshal(set)
+	if err != nil {
+		return fmt.Errorf("sitemap marshal: %w", err)
+	}
+
+	helpers.SetNoSniffHeader(w)
+	helpers.SetContentTypeHeader(w, "application/xml; charset=utf-8")
+	helpers.WriteStatus(w, r.Context())
+	_, _ = w.Write([]byte(xml.Header))
+	_, _ = w.Write(b)
+	return nil
+}
+
+// RobotsGroup is a group of rules for a set of user agents in a robots.txt file
+type RobotsGroup struct {
+	UserAgents []string
+	Allow      []string
+	Disallow   []string
+	// CrawlDelay in seconds, zero means it will not be included
+	CrawlDelay int
+}
+
+// RobotsTxt is a robots.txt file. It implements encoding.TextMarshaler, so it
+// can be rendered with PlainText or Robots.
+type RobotsTxt struct {
+	Groups   []RobotsGroup
+	Sitemaps []string
+}
+
+// MarshalText returns the robots.txt file
+func (robots RobotsTxt) MarshalText() ([]byte, error) {
+	var buff bytes.Buffer
+	for i, group := range robots.Groups {
+		if len(group.UserAgents) == 0 {
+			return nil, fmt.Errorf("robots: group %d has no user agents", i)
+		}
+		if i > 0 {
+			buff.WriteString("\n")
+		}
+		for _, agent := range group.UserAgents {
+			fmt.Fprintf(&buff, "User-agent: %s\n", agent)
+		}
+		for _, path := range group.Allow {
+			fmt.Fprintf(&buff, "Allow: %s\n", path)
+		}
+		for _, path := range group.Disallow {
+			fmt.Fprintf(&buff, "Disallow: %s\n", path)
+		}
+		if group.CrawlDelay > 0 {
+			fmt.Fprintf(&buff, "Crawl-delay: %d\n", group.CrawlDelay)
+		}
+	}
+	if len(robots.Sitemaps) > 0 && len(robots.Groups) > 0 {
+		buff.WriteString("\n")
+	}
+	for _, sitemap := range robots.Sitemaps {
+		fmt.Fprintf(&buff, "Sitemap: %s\n", strings.TrimSpace(sitemap))
+	}
+	return buff.Bytes(), nil
+}
+
+// RobotsTxter is implemented by payloads that can be rendered as a robots.txt file
+type RobotsTxter interface {
+	RobotsTxt() RobotsTxt
+}
+
+// Robots writes a RobotsTxt or RobotsTxter, setting the Content-Type as text/plain.
+// Other payloads are handed off to the PlainText responder, so it can be
+// registered for text/plain.
+func Robots(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	if robots, ok := v.(RobotsTxter); ok {
+		v = robots.RobotsTxt()
+	}
+	return PlainText(w, r, v)
+}
@@ -0,0 +1,115 @@
+package responders_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdey/chi-render/responders"
+	"github.com/gdey/chi-render/responders/helpers"
+	"github.com/gdey/chi-render/responders/test"
+)
+
+type articleSitemap []string
+
+func (slugs articleSitemap) SitemapEntries() []responders.SitemapEntry {
+	entries := make([]responders.SitemapEntry, 0, len(slugs))
+	for _, slug := range slugs {
+		entries = append(entries, responders.SitemapEntry{
+			Loc:        "https://example.org/articles/" + slug,
+			LastMod:    time.Date(2021, 2, 25, 0, 0, 0, 0, time.UTC),
+			ChangeFreq: "daily",
+			Priority:   0.5,
+		})
+	}
+	return entries
+}
+
+type siteRobots struct{}
+
+func (siteRobots) RobotsTxt() responders.RobotsTxt {
+	return responders.RobotsTxt{
+		Groups: []responders.RobotsGroup{
+			{UserAgents: []string{"*"}, Disallow: []string{"/admin"}},
+		},
+		Sitemaps: []string{"https://example.org/sitemap.xml"},
+	}
+}
+
+func TestSitemap(t *testing.T) {
+
+	stdHeaders := func(tc *test.Case) *test.Case {
+		if tc.R == nil {
+			tc.R = new(http.Request)
+			helpers.Status(tc.R, tc.W.Status)
+		}
+		if tc.W.Headers == nil {
+			tc.W.Headers = make(http.Header)
+		}
+		helpers.SetNoSniffHeader(test.AsHeaderer(tc.W.Headers))
+		helpers.SetContentTypeHeader(test.AsHeaderer(tc.W.Headers), "application/xml; charset=utf-8")
+
+		return tc
+	}
+
+	tests := map[string]test.Case{
+		"sitemap": func() test.Case {
+			tc := stdHeaders(&test.Case{
+				W: test.ResponseWriter{
+					Status: http.StatusOK,
+					Body: strings.NewReader(`<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9"><url><loc>https://example.org/articles/hi</loc><lastmod>2021-02-25T00:00:00Z</lastmod><changefreq>daily</changefreq><priority>0.5</priority></url></urlset>`),
+				},
+				V: articleSitemap{"hi"},
+			})
+			return *tc
+		}(),
+	}
+	for name, tc := range tests {
+		t.Run(name, tc.Test(responders.Sitemap))
+	}
+}
+
+func TestRobots(t *testing.T) {
+
+	stdHeaders := func(tc *test.Case) *test.Case {
+		if tc.R == nil {
+			tc.R = new(http.Request)
+			helpers.Status(tc.R, tc.W.Status)
+		}
+		if tc.W.Headers == nil {
+			tc.W.Headers = make(http.Header)
+		}
+		helpers.SetNoSniffHeader(test.AsHeaderer(tc.W.Headers))
+		helpers.SetContentTypeHeader(test.AsHeaderer(tc.W.Headers), "text/plain; charset=utf-8")
+
+		return tc
+	}
+
+	tests := map[string]test.Case{
+		"robots": func() test.Case {
+			tc := stdHeaders(&test.Case{
+				W: test.ResponseWriter{
+					Status: http.StatusOK,
+					Body:   strings.NewReader("User-agent: *\nDisallow: /admin\n\nSitemap: https://example.org/sitemap.xml\n"),
+				},
+				V: siteRobots{},
+			})
+			return *tc
+		}(),
+		"string": func() test.Case {
+			tc := stdHeaders(&test.Case{
+				W: test.ResponseWriter{
+					Status: http.StatusOK,
+					Body:   strings.NewReader("Hello world!"),
+				},
+				V: "Hello world!",
+			})
+			return *tc
+		}(),
+	}
+	for name, tc := range tests {
+		t.Run(name, tc.Test(responders.Robots))
+	}
+}
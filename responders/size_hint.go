@@ -0,0 +1,67 @@
+package responders
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/gdey/chi-render/responders/helpers"
+)
+
+// maxSizeHint caps the buffers preallocated from size hints, so a bad hint can
+// not allocate an unbounded amount of memory.
+const maxSizeHint = 64 << 20
+
+// EncodedSizeHinter is an optional interface for payloads that know about how
+// many bytes they encode to, like large exports. The JSON and XML responders
+// use the hint to size their buffers up front, rather than growing them as they
+// encode.
+type EncodedSizeHinter interface {
+	EncodedSizeHint() int
+}
+
+// sizeHint returns the number of bytes v is expected to encode to, either from
+// v or from the size hint recorded in the request (see helpers.SizeHintCtxKey)
+// for payloads that were projected before being handed to the responder.
+func sizeHint(r *http.Request, v interface{}) int {
+	hint := helpers.SizeHint(r)
+	if hinter, ok := v.(EncodedSizeHinter); ok {
+		hint = hinter.EncodedSizeHint()
+	}
+	switch {
+	case hint < 0:
+		return 0
+	case hint > maxSizeHint:
+		return maxSizeHint
+	default:
+		return hint
+	}
+}
+
+// newBuffer returns a buffer sized for encoding v, that refuses to grow past
+// the response limit of the request
+func newBuffer(r *http.Request, v interface{}) *responseBuffer {
+	limit := helpers.ResponseLimit(r)
+	size := int64(sizeHint(r, v))
+	if limit > 0 && size > limit {
+		size = limit
+	}
+	return &responseBuffer{
+		Buffer: *bytes.NewBuffer(make([]byte, 0, size)),
+		limit:  limit,
+	}
+}
+
+// responseBuffer is a bytes.Buffer that fails with ErrResponseTooLarge once
+// more than limit bytes are written to it, so the encoders writing to it stop
+// early; zero or less means no limit.
+type responseBuffer struct {
+	bytes.Buffer
+	limit int64
+}
+
+func (buf *responseBuffer) Write(p []byte) (int, error) {
+	if buf.limit > 0 && int64(buf.Len()+len(p)) > buf.limit {
+		return 0, ErrResponseTooLarge
+	}
+	return buf.Buffer.Write(p)
+}
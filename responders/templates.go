@@ -0,0 +1,289 @@
+package responders
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/gdey/chi-render/responders/helpers"
+)
+
+// Templates is a set of html templates made up of layouts, partials and pages.
+//
+// A layout is the base document; it uses named blocks (`{{block "content" .}}{{end}}`)
+// that pages fill in by defining them (`{{define "content"}}...{{end}}`). Partials
+// are shared templates available to every layout and page. A page is registered
+// for a payload type along with the layout it should be rendered in.
+//
+// In production the templates are parsed once, either by calling Compile at
+// startup or on first use. When Dev is true the template files are re-read and
+// parsed on every render, so changes show up without restarting.
+type Templates struct {
+	// Dev turns on hot-reloading of the template files
+	Dev bool
+
+	// Funcs are added to the templates before they are parsed; the CSRF funcs
+	// (see CSRFFuncs) are always defined.
+	Funcs template.FuncMap
+
+	// ReadFile is used to read the template files, defaults to ioutil.ReadFile
+	ReadFile func(filename string) ([]byte, error)
+
+	lck      sync.RWMutex
+	layouts  map[string]string
+	partials []string
+	pages    map[reflect.Type]templatePage
+	compiled map[reflect.Type]*template.Template
+}
+
+// templatePage is the layout and page file used to render a payload type
+type templatePage struct {
+	layout string
+	file   string
+}
+
+// NewTemplates returns an empty set of templates
+func NewTemplates() *Templates { return new(Templates) }
+
+// AddLayout registers the file as the layout with the given name
+func (t *Templates) AddLayout(name, filename string) {
+	t.lck.Lock()
+	defer t.lck.Unlock()
+	if t.layouts == nil {
+		t.layouts = make(map[string]string)
+	}
+	t.layouts[name] = filename
+	t.compiled = nil
+}
+
+// AddPartials registers files that will be available to all layouts and pages
+func (t *Templates) AddPartials(filenames ...string) {
+	t.lck.Lock()
+	defer t.lck.Unlock()
+	t.partials = append(t.partials, filenames...)
+	t.compiled = nil
+}
+
+// AddPage registers the page file, rendered within the named layout, for payloads
+// of the same type as v. Pointers and the values they point to are treated
+// as the same type.
+func (t *Templates) AddPage(v interface{}, layout, filename string) {
+	t.lck.Lock()
+	defer t.lck.Unlock()
+	if t.pages == nil {
+		t.pages = make(map[reflect.Type]templatePage)
+	}
+	t.pages[payloadType(v)] = templatePage{layout: layout, file: filename}
+	t.compiled = nil
+}
+
+// Compile parses all the registered pages; this should be called at startup
+// so that errors in the templates are found before traffic arrives.
+func (t *Templates) Compile() error {
+	t.lck.Lock()
+	defer t.lck.Unlock()
+	compiled := make(map[reflect.Type]*template.Template, len(t.pages))
+	for typ, page := range t.pages {
+		tmpl, err := t.parse(page)
+		if err != nil {
+			return fmt.Errorf("templates: %v: %w", typ, err)
+		}
+		compiled[typ] = tmpl
+	}
+	t.compiled = compiled
+	return nil
+}
+
+// Validate compiles the registered pages and executes each of them against
+// the zero value of its payload type, with nil pointers to structs allocated,
+// so that references to missing fields and methods are found before traffic
+// arrives rather than on the first request for the page.
+func (t *Templates) Validate() error {
+	if err := t.Compile(); err != nil {
+		return err
+	}
+	t.lck.RLock()
+	defer t.lck.RUnlock()
+	types := make([]reflect.Type, 0, len(t.pages))
+	for typ := range t.pages {
+		types = append(types, typ)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i].String() < types[j].String() })
+	for _, typ := range types {
+		tmpl := t.compiled[typ]
+		if tmpl == nil {
+			// registered after Compile returned
+			continue
+		}
+		// execute a clone, so the compiled template can still be cloned
+		// to bind the CSRF funcs of a request
+		tmpl, err := tmpl.Clone()
+		if err != nil {
+			return fmt.Errorf("templates: %v: %w", typ, err)
+		}
+		zero := zeroPayload(typ, make(map[reflect.Type]bool))
+		if err := tmpl.ExecuteTemplate(ioutil.Discard, t.pages[typ].layout, zero.Interface()); err != nil {
+			return fmt.Errorf("templates: %v: %w", typ, err)
+		}
+	}
+	return nil
+}
+
+// Registration returns the registration of the HTML responder of the
+// templates, which the controller can validate with ValidateTemplates.
+func (t *Templates) Registration() Registration {
+	return Registration{Func: t.HTML, Validate: t.Validate}
+}
+
+// zeroPayload returns a pointer to the zero value of typ, with the nil pointers
+// to structs it holds allocated so that templates can walk through them.
+func zeroPayload(typ reflect.Type, seen map[reflect.Type]bool) reflect.Value {
+	v := reflect.New(typ)
+	if typ.Kind() != reflect.Struct || seen[typ] {
+		return v
+	}
+	seen[typ] = true
+	defer delete(seen, typ)
+	for i := 0; i < typ.NumField(); i++ {
+		field := v.Elem().Field(i)
+		ft := typ.Field(i).Type
+		if !field.CanSet() || ft.Kind() != reflect.Ptr || ft.Elem().Kind() != reflect.Struct {
+			continue
+		}
+		field.Set(zeroPayload(ft.Elem(), seen))
+	}
+	return v
+}
+
+// Has reports whether there is a page registered for the type of v
+func (t *Templates) Has(v interface{}) bool {
+	t.lck.RLock()
+	defer t.lck.RUnlock()
+	_, ok := t.pages[payloadType(v)]
+	return ok
+}
+
+// lookup returns the parsed template and name of the layout for the payload
+func (t *Templates) lookup(v interface{}) (*template.Template, string, error) {
+	typ := payloadType(v)
+
+	t.lck.RLock()
+	page, ok := t.pages[typ]
+	tmpl := t.compiled[typ]
+	t.lck.RUnlock()
+
+	if !ok {
+		return nil, "", ErrCanNotEncodeObject
+	}
+	if tmpl != nil && !t.Dev {
+		return tmpl, page.layout, nil
+	}
+
+	t.lck.Lock()
+	defer t.lck.Unlock()
+	tmpl, err := t.parse(page)
+	if err != nil {
+		return nil, "", fmt.Errorf("templates: %v: %w", typ, err)
+	}
+	if !t.Dev {
+		if t.compiled == nil {
+			t.compiled = make(map[reflect.Type]*template.Template)
+		}
+		t.compiled[typ] = tmpl
+	}
+	return tmpl, page.layout, nil
+}
+
+// parse the layout, partials and page file into a template; the lock must be
+// held, a read lock is enough
+func (t *Templates) parse(page templatePage) (*template.Template, error) {
+	layoutFile, ok := t.layouts[page.layout]
+	if !ok {
+		return nil, fmt.Errorf("unknown layout %q", page.layout)
+	}
+	tmpl := template.New(page.layout).Funcs(CSRFFuncs(nil)).Funcs(t.Funcs)
+	files := append(append([]string{layoutFile}, t.partials...), page.file)
+	for i, filename := range files {
+		body, err := t.readFile(filename)
+		if err != nil {
+			return nil, err
+		}
+		target := tmpl
+		if i > 0 {
+			target = tmpl.New(filename)
+		}
+		if _, err := target.Parse(string(body)); err != nil {
+			return nil, err
+		}
+	}
+	return tmpl, nil
+}
+
+func (t *Templates) readFile(filename string) ([]byte, error) {
+	if t.ReadFile != nil {
+		return t.ReadFile(filename)
+	}
+	return ioutil.ReadFile(filename)
+}
+
+// HTML is a responder that renders the page registered for the payload type,
+// setting the Content-Type as text/html. Payloads without a registered page are
+// handed off to the HTML responder.
+func (t *Templates) HTML(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	tmpl, layout, err := t.lookup(v)
+	if err == ErrCanNotEncodeObject {
+		return HTML(w, r, v)
+	}
+	if err != nil {
+		return err
+	}
+
+	if csrf := helpers.CSRFToken(r); csrf.Token != "" {
+		if tmpl, err = t.withCSRF(tmpl, v, r); err != nil {
+			return err
+		}
+	}
+
+	var buff bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buff, layout, v); err != nil {
+		return fmt.Errorf("templates: %w", err)
+	}
+
+	helpers.SetNoSniffHeader(w)
+	helpers.SetContentTypeHeader(w, "text/html; charset=utf-8")
+	helpers.WriteStatus(w, r.Context())
+	_, _ = w.Write(buff.Bytes())
+	return nil
+}
+
+// withCSRF returns a copy of the template with the CSRF funcs bound to the
+// token of the request
+func (t *Templates) withCSRF(tmpl *template.Template, v interface{}, r *http.Request) (*template.Template, error) {
+	clone, err := tmpl.Clone()
+	if err != nil {
+		// html/template can not clone a template that has been executed,
+		// which the cached templates are once a request without a token
+		// used them; parse a fresh copy instead.
+		t.lck.RLock()
+		clone, err = t.parse(t.pages[payloadType(v)])
+		t.lck.RUnlock()
+		if err != nil {
+			return nil, fmt.Errorf("templates: %v: %w", payloadType(v), err)
+		}
+	}
+	return clone.Funcs(CSRFFuncs(r)), nil
+}
+
+// payloadType returns the type of v, with pointers removed
+func payloadType(v interface{}) reflect.Type {
+	typ := reflect.TypeOf(v)
+	for typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return typ
+}
@@ -0,0 +1,84 @@
+package responders
+
+import (
+	"errors"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// Directories of the conventional template layout read by LoadFS
+const (
+	LayoutsDir  = "layouts"
+	PartialsDir = "partials"
+	PagesDir    = "pages"
+)
+
+// TemplatesFS returns templates read from fsys, like an embed.FS, so binaries
+// can ship their templates without depending on the disk; see LoadFS.
+//
+//	//go:embed templates
+//	var files embed.FS
+//
+//	sub, _ := fs.Sub(files, "templates")
+//	tmpls, err := responders.TemplatesFS(sub)
+//	…
+//	tmpls.AddPage(ArticleResponse{}, "base", "pages/article.html")
+func TemplatesFS(fsys fs.FS) (*Templates, error) {
+	t := NewTemplates()
+	if err := t.LoadFS(fsys); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// LoadFS reads the template files from fsys, and registers the layouts and
+// partials laid out by convention:
+//
+//	layouts/base.html        the layout named "base"
+//	layouts/admin/base.html  the layout named "admin/base"
+//	partials/...             every file is a partial
+//	pages/...                the pages, registered with AddPage
+//
+// Missing directories are skipped; pages still have to be registered for their
+// payload types with AddPage, using their path in fsys.
+func (t *Templates) LoadFS(fsys fs.FS) error {
+	t.ReadFile = func(filename string) ([]byte, error) { return fs.ReadFile(fsys, filename) }
+
+	layouts, err := filesIn(fsys, LayoutsDir)
+	if err != nil {
+		return err
+	}
+	for _, filename := range layouts {
+		name := strings.TrimPrefix(filename, LayoutsDir+"/")
+		t.AddLayout(strings.TrimSuffix(name, path.Ext(name)), filename)
+	}
+
+	partials, err := filesIn(fsys, PartialsDir)
+	if err != nil {
+		return err
+	}
+	if len(partials) > 0 {
+		t.AddPartials(partials...)
+	}
+	return nil
+}
+
+// filesIn returns the regular files in the directory, and its sub-directories,
+// in lexical order; no files are returned if the directory does not exist.
+func filesIn(fsys fs.FS, dir string) ([]string, error) {
+	var files []string
+	err := fs.WalkDir(fsys, dir, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if name == dir && errors.Is(err, fs.ErrNotExist) {
+				return fs.SkipDir
+			}
+			return err
+		}
+		if d.Type().IsRegular() {
+			files = append(files, name)
+		}
+		return nil
+	})
+	return files, err
+}
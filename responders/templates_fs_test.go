@@ -0,0 +1,52 @@
+package responders_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/gdey/chi-render/responders"
+	"github.com/gdey/chi-render/responders/test"
+)
+
+func TestTemplatesFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"layouts/base.html":       {Data: []byte(`<main>{{template "nav"}}{{block "content" .}}{{end}}</main>`)},
+		"layouts/admin/base.html": {Data: []byte(`<admin>{{block "content" .}}{{end}}</admin>`)},
+		"partials/nav.html":       {Data: []byte(`{{define "nav"}}<nav></nav>{{end}}`)},
+		"pages/article.html":      {Data: []byte(`{{define "content"}}<h1>{{.Title}}</h1>{{end}}`)},
+		"pages/author.html":       {Data: []byte(`{{define "content"}}<p>{{.Name}}</p>{{end}}`)},
+	}
+
+	tmpls, err := responders.TemplatesFS(fsys)
+	if err != nil {
+		t.Fatalf("error, expected nil, got %v", err)
+	}
+	tmpls.AddPage(templatePage{}, "base", "pages/article.html")
+	tmpls.AddPage(templateAuthor{}, "admin/base", "pages/author.html")
+	if err := tmpls.Compile(); err != nil {
+		t.Fatalf("compile error, expected nil, got %v", err)
+	}
+
+	tests := map[string]struct {
+		V    interface{}
+		Body string
+	}{
+		"layout":        {V: templatePage{Title: "hello"}, Body: `<main><nav></nav><h1>hello</h1></main>`},
+		"nested layout": {V: templateAuthor{Name: "gopher"}, Body: `<admin><p>gopher</p></admin>`},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			w := test.ResponseWriter{Body: strings.NewReader(tc.Body)}
+			if err := tmpls.HTML(&w, new(http.Request), tc.V); err != nil {
+				t.Fatalf("error, expected nil, got %v", err)
+			}
+			w.CheckBody(t)
+		})
+	}
+
+	if _, err := responders.TemplatesFS(fstest.MapFS{}); err != nil {
+		t.Errorf("empty fs error, expected nil, got %v", err)
+	}
+}
@@ -0,0 +1,186 @@
+package responders_test
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gdey/chi-render/responders"
+	"github.com/gdey/chi-render/responders/helpers"
+	"github.com/gdey/chi-render/responders/test"
+)
+
+type templatePage struct {
+	Title string
+}
+
+func TestTemplates(t *testing.T) {
+
+	files := map[string]string{
+		"layouts/base.html":  `<html><head><title>{{block "title" .}}default{{end}}</title></head><body>{{template "nav"}}{{block "content" .}}{{end}}</body></html>`,
+		"partials/nav.html":  `{{define "nav"}}<nav></nav>{{end}}`,
+		"pages/article.html": `{{define "title"}}{{.Title}}{{end}}{{define "content"}}<h1>{{.Title}}</h1>{{end}}`,
+	}
+
+	newTemplates := func(dev bool) *responders.Templates {
+		tmpls := responders.NewTemplates()
+		tmpls.Dev = dev
+		tmpls.ReadFile = func(filename string) ([]byte, error) {
+			body, ok := files[filename]
+			if !ok {
+				return nil, os.ErrNotExist
+			}
+			return []byte(body), nil
+		}
+		tmpls.AddLayout("base", "layouts/base.html")
+		tmpls.AddPartials("partials/nav.html")
+		tmpls.AddPage(templatePage{}, "base", "pages/article.html")
+		return tmpls
+	}
+
+	stdHeaders := func(tc *test.Case) *test.Case {
+		if tc.R == nil {
+			tc.R = new(http.Request)
+			helpers.Status(tc.R, tc.W.Status)
+		}
+		if tc.W.Headers == nil {
+			tc.W.Headers = make(http.Header)
+		}
+		helpers.SetNoSniffHeader(test.AsHeaderer(tc.W.Headers))
+		helpers.SetContentTypeHeader(test.AsHeaderer(tc.W.Headers), "text/html; charset=utf-8")
+
+		return tc
+	}
+
+	tests := map[string]test.Case{
+		"page": func() test.Case {
+			tc := stdHeaders(&test.Case{
+				W: test.ResponseWriter{
+					Status: http.StatusOK,
+					Body:   strings.NewReader(`<html><head><title>Hello &lt;world&gt;</title></head><body><nav></nav><h1>Hello &lt;world&gt;</h1></body></html>`),
+				},
+				V: &templatePage{Title: "Hello <world>"},
+			})
+			return *tc
+		}(),
+		"not registered": func() test.Case {
+			tc := stdHeaders(&test.Case{
+				W: test.ResponseWriter{
+					Status: http.StatusOK,
+					Body:   strings.NewReader("Hello world!"),
+				},
+				V: "Hello world!",
+			})
+			return *tc
+		}(),
+	}
+
+	tmpls := newTemplates(false)
+	if err := tmpls.Compile(); err != nil {
+		t.Fatalf("compile error, expected nil, got %v", err)
+	}
+	for name, tc := range tests {
+		t.Run(name, tc.Test(tmpls.HTML))
+	}
+
+	t.Run("dev reload", func(t *testing.T) {
+		tmpls := newTemplates(true)
+		files["pages/reload.html"] = `{{define "content"}}before{{end}}`
+		tmpls.AddPage(struct{ Reload bool }{}, "base", "pages/reload.html")
+
+		r := new(http.Request)
+		_ = tmpls.HTML(new(test.ResponseWriter), r, struct{ Reload bool }{})
+		files["pages/reload.html"] = `{{define "content"}}after{{end}}`
+		w := test.ResponseWriter{
+			Body: strings.NewReader(`<html><head><title>default</title></head><body><nav></nav>after</body></html>`),
+		}
+		if err := tmpls.HTML(&w, r, struct{ Reload bool }{}); err != nil {
+			t.Fatalf("error, expected nil, got %v", err)
+		}
+		w.CheckBody(t)
+	})
+
+	t.Run("csrf", func(t *testing.T) {
+		type formPage struct{ Form bool }
+		tmpls := newTemplates(false)
+		files["pages/form.html"] = `{{define "content"}}<form method="post">{{csrfField}}</form>{{end}}`
+		tmpls.AddPage(formPage{}, "base", "pages/form.html")
+
+		w := test.ResponseWriter{
+			Body: strings.NewReader(`<html><head><title>default</title></head><body><nav></nav><form method="post"></form></body></html>`),
+		}
+		if err := tmpls.HTML(&w, new(http.Request), formPage{}); err != nil {
+			t.Fatalf("error, expected nil, got %v", err)
+		}
+		w.CheckBody(t)
+
+		r := new(http.Request)
+		r = r.WithContext(context.WithValue(context.Background(), helpers.CSRFCtxKey, helpers.CSRF{Field: "csrf_token", Token: "a&b"}))
+		w = test.ResponseWriter{
+			Body: strings.NewReader(`<html><head><title>default</title></head><body><nav></nav><form method="post"><input type="hidden" name="csrf_token" value="a&amp;b"></form></body></html>`),
+		}
+		if err := tmpls.HTML(&w, r, formPage{}); err != nil {
+			t.Fatalf("error, expected nil, got %v", err)
+		}
+		w.CheckBody(t)
+	})
+}
+
+type templateAuthor struct {
+	Name string
+}
+
+type templateArticle struct {
+	Title  string
+	Author *templateAuthor
+}
+
+func TestTemplatesValidate(t *testing.T) {
+	type tcase struct {
+		Page string
+		Err  string
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			files := map[string]string{
+				"layout.html": `{{block "content" .}}{{end}}`,
+				"page.html":   tc.Page,
+			}
+			tmpls := responders.NewTemplates()
+			tmpls.ReadFile = func(filename string) ([]byte, error) { return []byte(files[filename]), nil }
+			tmpls.AddLayout("base", "layout.html")
+			tmpls.AddPage(templateArticle{}, "base", "page.html")
+
+			err := tmpls.Registration().Validate()
+			if tc.Err == "" {
+				if err != nil {
+					t.Errorf("error, expected nil, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.Err) {
+				t.Errorf("error, expected %q, got %v", tc.Err, err)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"valid": {
+			Page: `{{define "content"}}<h1>{{.Title}}</h1>{{.Author.Name}}{{end}}`,
+		},
+		"missing field": {
+			Page: `{{define "content"}}{{.Subtitle}}{{end}}`,
+			Err:  "can't evaluate field Subtitle",
+		},
+		"missing func": {
+			Page: `{{define "content"}}{{upper .Title}}{{end}}`,
+			Err:  `function "upper" not defined`,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
@@ -0,0 +1,76 @@
+package responders
+
+import (
+	"bytes"
+	"net/http"
+	"reflect"
+
+	"github.com/gdey/chi-render/responders/helpers"
+)
+
+// VCardMarshaler is implemented by contact payloads that can be encoded as a vCard
+type VCardMarshaler interface {
+	MarshalVCard() ([]byte, error)
+}
+
+// VCard writes a VCardMarshaler, or a slice of them, setting the Content-Type
+// as text/vcard. The vCards of a slice are concatenated into one response,
+// as allowed by RFC 6350.
+func VCard(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	var buff bytes.Buffer
+
+	switch vv := v.(type) {
+	case VCardMarshaler:
+		card, err := vv.MarshalVCard()
+		if err != nil {
+			return err
+		}
+		buff.Write(card)
+	default:
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			return ErrCanNotEncodeObject
+		}
+		for i := 0; i < rv.Len(); i++ {
+			m, ok := rv.Index(i).Interface().(VCardMarshaler)
+			if !ok {
+				return ErrCanNotEncodeObject
+			}
+			card, err := m.MarshalVCard()
+			if err != nil {
+				return err
+			}
+			buff.Write(card)
+		}
+	}
+
+	helpers.SetNoSniffHeader(w)
+	helpers.SetContentTypeHeader(w, "text/vcard; charset=utf-8")
+	helpers.WriteStatus(w, r.Context())
+	_, _ = w.Write(buff.Bytes())
+	return nil
+}
+
+// ICalendarMarshaler is implemented by payloads that can be encoded as an
+// iCalendar object
+type ICalendarMarshaler interface {
+	MarshalICalendar() ([]byte, error)
+}
+
+// Calendar writes a ICalendarMarshaler, setting the Content-Type as text/calendar.
+func Calendar(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	m, ok := v.(ICalendarMarshaler)
+	if !ok {
+		return ErrCanNotEncodeObject
+	}
+	cal, err := m.MarshalICalendar()
+	if err != nil {
+		return err
+	}
+
+	helpers.SetNoSniffHeader(w)
+	helpers.SetContentTypeHeader(w, "text/calendar; charset=utf-8")
+	helpers.WriteStatus(w, r.Context())
+	_, _ = w.Write(cal)
+	return nil
+}
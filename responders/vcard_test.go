@@ -0,0 +1,73 @@
+package responders_test
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gdey/chi-render/responders"
+	"github.com/gdey/chi-render/responders/helpers"
+	"github.com/gdey/chi-render/responders/test"
+)
+
+type contact string
+
+func (c contact) MarshalVCard() ([]byte, error) {
+	if c == "" {
+		return nil, errors.New("contact has no name")
+	}
+	return []byte("BEGIN:VCARD\r\nVERSION:4.0\r\nFN:" + string(c) + "\r\nEND:VCARD\r\n"), nil
+}
+
+func TestVCard(t *testing.T) {
+
+	stdHeaders := func(tc *test.Case) *test.Case {
+		if tc.R == nil {
+			tc.R = new(http.Request)
+			helpers.Status(tc.R, tc.W.Status)
+		}
+		if tc.W.Headers == nil {
+			tc.W.Headers = make(http.Header)
+		}
+		helpers.SetNoSniffHeader(test.AsHeaderer(tc.W.Headers))
+		helpers.SetContentTypeHeader(test.AsHeaderer(tc.W.Headers), "text/vcard; charset=utf-8")
+
+		return tc
+	}
+
+	tests := map[string]test.Case{
+		"contact": func() test.Case {
+			tc := stdHeaders(&test.Case{
+				W: test.ResponseWriter{
+					Status: http.StatusOK,
+					Body:   strings.NewReader("BEGIN:VCARD\r\nVERSION:4.0\r\nFN:Peter\r\nEND:VCARD\r\n"),
+				},
+				V: contact("Peter"),
+			})
+			return *tc
+		}(),
+		"contacts": func() test.Case {
+			tc := stdHeaders(&test.Case{
+				W: test.ResponseWriter{
+					Status: http.StatusOK,
+					Body:   strings.NewReader("BEGIN:VCARD\r\nVERSION:4.0\r\nFN:Peter\r\nEND:VCARD\r\nBEGIN:VCARD\r\nVERSION:4.0\r\nFN:Julia\r\nEND:VCARD\r\n"),
+				},
+				V: []interface{}{contact("Peter"), contact("Julia")},
+			})
+			return *tc
+		}(),
+		"marshaler error": {
+			V:             contact(""),
+			Err:           errors.New("contact has no name"),
+			ErrComparator: func(expected, got error) bool { return got != nil && expected.Error() == got.Error() },
+		},
+		"ErrCanNotEncode": {
+			Err: responders.ErrCanNotEncodeObject,
+			V:   []interface{}{contact("Peter"), 42},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, tc.Test(responders.VCard))
+	}
+}
@@ -3,6 +3,7 @@ package responders
 import (
 	"bytes"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"github.com/gdey/chi-render/responders/helpers"
 	"net/http"
@@ -11,11 +12,27 @@ import (
 // XML marshals 'v' to XML, setting the Content-Type as application/xml. It
 // will automatically prepend a generic XML header (see encoding/xml.Header) if
 // one is not found in the first 100 bytes of 'v'.
+//
+// ErrCanNotEncodeObject is returned for values XML can not represent (see
+// CanEncodeXML), so another responder can be tried.
 func XML(w http.ResponseWriter, r *http.Request, v interface{}) error {
-	b, err := xml.Marshal(v)
-	if err != nil {
+	if !CanEncodeXML(v) {
+		return ErrCanNotEncodeObject
+	}
+
+	buf := newBuffer(r, v)
+	enc := xml.NewEncoder(buf)
+	if indent := helpers.Indent(r); indent != "" {
+		enc.Indent("", indent)
+	}
+	if err := enc.Encode(v); err != nil {
+		var typeErr *xml.UnsupportedTypeError
+		if errors.As(err, &typeErr) {
+			return ErrCanNotEncodeObject
+		}
 		return fmt.Errorf("XML marshal: %w", err)
 	}
+	b := buf.Bytes()
 
 	helpers.SetNoSniffHeader(w)
 	helpers.SetContentTypeHeader(w,"application/xml; charset=utf-8")
@@ -1,8 +1,11 @@
 package responders_test
 
 import (
+	"context"
 	"encoding/xml"
+	"errors"
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 
@@ -56,8 +59,35 @@ func TestXML(t *testing.T) {
 			})
 			return *tc
 		}(),
+		"map": {
+			Err: responders.ErrCanNotEncodeObject,
+			V:   map[string]string{"greeting": "hello"},
+		},
+		"channel": {
+			Err: responders.ErrCanNotEncodeObject,
+			V:   make(chan int),
+		},
 	}
 	for name, tc := range tests {
 		t.Run(name, tc.Test(responders.XML))
 	}
 }
+
+func TestXMLResponseLimit(t *testing.T) {
+	type item struct {
+		Name string `xml:"name"`
+	}
+	items := make([]item, 10000)
+	for i := range items {
+		items[i].Name = strings.Repeat("x", 10)
+	}
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r = r.WithContext(context.WithValue(r.Context(), helpers.ResponseLimitCtxKey, int64(100)))
+	err := responders.XML(httptest.NewRecorder(), r, struct {
+		XMLName struct{} `xml:"items"`
+		Items   []item
+	}{Items: items})
+	if !errors.Is(err, responders.ErrResponseTooLarge) {
+		t.Errorf("error, expected %v, got %v", responders.ErrResponseTooLarge, err)
+	}
+}
@@ -0,0 +1,64 @@
+package render
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Timing is a named span of the time spent handling a request
+type Timing struct {
+	Name     string
+	Duration time.Duration
+}
+
+// AddTiming adds the duration to the named timing span of the request; spans
+// with the same name are accumulated. If the controller has ServerTiming set,
+// the spans are sent to the client in the Server-Timing header, along with the
+// phases the controller measures itself: decode, bind, render and encode.
+//
+// The name must be a http token, like db or cache.
+func AddTiming(r *http.Request, name string, d time.Duration) {
+	statsFor(r).update(func(stats *RenderStats) {
+		for i := range stats.Timings {
+			if stats.Timings[i].Name == name {
+				stats.Timings[i].Duration += d
+				return
+			}
+		}
+		stats.Timings = append(stats.Timings, Timing{Name: name, Duration: d})
+	})
+}
+
+// StartTiming starts timing the named span of the request; call the returned
+// func to stop it and add it like AddTiming.
+//
+//	stop := render.StartTiming(r, "db")
+//	article, err := dbGetArticle(id)
+//	stop()
+func StartTiming(r *http.Request, name string) (stop func()) {
+	start := time.Now()
+	return func() { AddTiming(r, name, time.Since(start)) }
+}
+
+// serverTiming returns the value of the Server-Timing header for the request;
+// encode is the time spent in the responder.
+func serverTiming(r *http.Request, encode time.Duration) string {
+	stats := statsFor(r).Snapshot()
+	timings := append(stats.Timings,
+		Timing{Name: "decode", Duration: stats.DecodeDuration},
+		Timing{Name: "bind", Duration: stats.BindDuration},
+		Timing{Name: "render", Duration: stats.RenderDuration},
+		Timing{Name: "encode", Duration: encode},
+	)
+	metrics := make([]string, 0, len(timings))
+	for _, timing := range timings {
+		if timing.Duration <= 0 {
+			continue
+		}
+		ms := float64(timing.Duration) / float64(time.Millisecond)
+		metrics = append(metrics, timing.Name+";dur="+strconv.FormatFloat(ms, 'f', 3, 64))
+	}
+	return strings.Join(metrics, ", ")
+}
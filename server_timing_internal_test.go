@@ -0,0 +1,67 @@
+package render
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServerTiming(t *testing.T) {
+	type payload struct {
+		NilRender
+		NilBinder
+		Name string `json:"name"`
+	}
+	type tcase struct {
+		ServerTiming bool
+		Expected     []string
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			ctrl := defaultCtrl.Clone()
+			ctrl.ServerTiming = tc.ServerTiming
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"world"}`))
+			r.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			var p payload
+			if err := ctrl.Bind(r, &p); err != nil {
+				t.Fatalf("bind error, expected nil, got %v", err)
+			}
+			AddTiming(r, "db", 2*time.Millisecond)
+			stop := StartTiming(r, "db")
+			stop()
+			AddTiming(r, "cache", 500*time.Microsecond)
+			if err := ctrl.Render(w, r, &p); err != nil {
+				t.Fatalf("render error, expected nil, got %v", err)
+			}
+
+			header := w.Header().Get("Server-Timing")
+			if len(tc.Expected) == 0 && header != "" {
+				t.Errorf("header, expected none, got %q", header)
+			}
+			for _, metric := range tc.Expected {
+				if !strings.Contains(header, metric) {
+					t.Errorf("header, expected %q in %q", metric, header)
+				}
+			}
+			if timings := Stats(r).Snapshot().Timings; len(timings) != 2 || timings[0].Name != "db" || timings[0].Duration < 2*time.Millisecond {
+				t.Errorf("timings, expected db and cache, got %v", timings)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"on": {
+			ServerTiming: true,
+			Expected:     []string{"db;dur=2.", "cache;dur=0.500", "decode;dur=", "encode;dur="},
+		},
+		"off": {},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
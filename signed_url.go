@@ -0,0 +1,204 @@
+package render
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// ErrURLExpired is returned by HMACURLSigner.Verify for signed URLs that have expired
+var ErrURLExpired = errors.New("render: signed url has expired")
+
+// URLSigner turns the reference to an attachment, like a storage key, into a
+// URL that can be used to fetch it until expires.
+type URLSigner interface {
+	SignURL(ref string, expires time.Time) (string, error)
+}
+
+// URLSignerFunc is an adapter to allow the use of ordinary functions as URLSigners
+type URLSignerFunc func(ref string, expires time.Time) (string, error)
+
+// SignURL calls fn(ref, expires)
+func (fn URLSignerFunc) SignURL(ref string, expires time.Time) (string, error) {
+	return fn(ref, expires)
+}
+
+// SignURLs rewrites the attachment references in the payload into signed URLs,
+// and is meant to be called from the Render method of the payload. References
+// are string fields, pointers to strings or slices of strings, tagged with
+// `signed_url:"true"`, in which case the URLs are valid for ttl, or with the
+// duration the URLs are valid for. Empty references are left as is.
+//
+//	type Article struct {
+//		Title string `json:"title"`
+//		Cover string `json:"cover" signed_url:"true"`
+//		Files []string `json:"files" signed_url:"1h"`
+//	}
+//
+//	func (a *Article) Render(w http.ResponseWriter, r *http.Request) error {
+//		return render.SignURLs(a, storage, 15*time.Minute)
+//	}
+func SignURLs(v interface{}, signer URLSigner, ttl time.Duration) error {
+	now := time.Now()
+	return signURLs(reflect.ValueOf(v), func(ref string, sf structField) (string, error) {
+		expires := now.Add(ttl)
+		if tag := sf.tag.Get("signed_url"); tag != "true" {
+			d, err := time.ParseDuration(tag)
+			if err != nil {
+				return "", fmt.Errorf("render: invalid signed_url duration %q: %w", tag, err)
+			}
+			expires = now.Add(d)
+		}
+		return signer.SignURL(ref, expires)
+	}, 0)
+}
+
+func signURLs(v reflect.Value, sign func(ref string, sf structField) (string, error), depth int) error {
+	if !v.IsValid() {
+		return nil
+	}
+	if depth > maxProjectionDepth {
+		return ErrProjectionTooDeep
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return signURLs(v.Elem(), sign, depth+1)
+
+	case reflect.Struct:
+		for _, sf := range structFields(v.Type()) {
+			fv, ok := fieldByIndex(v, sf.index)
+			if !ok {
+				continue
+			}
+			if tag, _ := sf.tag.Lookup("signed_url"); tag == "" || tag == "false" {
+				if err := signURLs(fv, sign, depth+1); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := signField(fv, sf, sign); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := signURLs(v.Index(i), sign, depth+1); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Map:
+		// map values can not be set, but they may point to structs that can
+		iter := v.MapRange()
+		for iter.Next() {
+			if err := signURLs(iter.Value(), sign, depth+1); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// signField replaces the references in a string field, a pointer to a string
+// or a slice of strings with signed URLs
+func signField(v reflect.Value, sf structField, sign func(ref string, sf structField) (string, error)) error {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		return signField(v.Elem(), sf, sign)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := signField(v.Index(i), sf, sign); err != nil {
+				return err
+			}
+		}
+	case reflect.String:
+		if v.String() == "" || !v.CanSet() {
+			return nil
+		}
+		signed, err := sign(v.String(), sf)
+		if err != nil {
+			return err
+		}
+		v.SetString(signed)
+	}
+	return nil
+}
+
+// HMACURLSigner signs URLs with an HMAC of their path and query, including the
+// expiry, which are added to the query as the "expires" and "signature"
+// parameters. The route
+// serving the attachments checks them with Verify.
+type HMACURLSigner struct {
+	// Key is the shared secret
+	Key []byte
+	// BaseURL is prefixed to the references to build the URLs
+	BaseURL string
+}
+
+// sum returns the HMAC of the path and the query, which includes the expiry,
+// without the signature. The query is encoded with its keys sorted, so the
+// order of the parameters does not matter.
+func (s HMACURLSigner) sum(path string, query url.Values) []byte {
+	canonical := make(url.Values, len(query))
+	for key, values := range query {
+		if key != "signature" {
+			canonical[key] = values
+		}
+	}
+	mac := hmac.New(sha256.New, s.Key)
+	_, _ = mac.Write([]byte(path))
+	_, _ = mac.Write([]byte{'\n'})
+	_, _ = mac.Write([]byte(canonical.Encode()))
+	return mac.Sum(nil)
+}
+
+// SignURL returns the URL of the reference, relative to BaseURL, signed until expires
+func (s HMACURLSigner) SignURL(ref string, expires time.Time) (string, error) {
+	u, err := url.Parse(s.BaseURL + ref)
+	if err != nil {
+		return "", err
+	}
+	exp := strconv.FormatInt(expires.Unix(), 10)
+	query := u.Query()
+	query.Set("expires", exp)
+	query.Set("signature", base64.RawURLEncoding.EncodeToString(s.sum(u.EscapedPath(), query)))
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// Verify checks the signature of the URL of the request; ErrInvalidSignature
+// is returned if it is missing or does not match, and ErrURLExpired if the URL
+// is past its expiry.
+func (s HMACURLSigner) Verify(r *http.Request) error {
+	query := r.URL.Query()
+	exp := query.Get("expires")
+	sig, err := base64.RawURLEncoding.DecodeString(query.Get("signature"))
+	if err != nil || len(sig) == 0 || exp == "" {
+		return ErrInvalidSignature
+	}
+	if !hmac.Equal(sig, s.sum(r.URL.EscapedPath(), query)) {
+		return ErrInvalidSignature
+	}
+	expires, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if time.Now().Unix() > expires {
+		return ErrURLExpired
+	}
+	return nil
+}
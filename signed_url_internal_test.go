@@ -0,0 +1,107 @@
+package render
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type signedAttachment struct {
+	Name string `json:"name"`
+	Ref  string `json:"ref" signed_url:"true"`
+}
+
+type signedArticle struct {
+	Title       string              `json:"title"`
+	Cover       string              `json:"cover" signed_url:"true"`
+	Thumbnail   *string             `json:"thumbnail" signed_url:"1h"`
+	Files       []string            `json:"files" signed_url:"true"`
+	Empty       string              `json:"empty" signed_url:"true"`
+	Attachments []*signedAttachment `json:"attachments"`
+}
+
+func (a *signedArticle) Render(w http.ResponseWriter, r *http.Request) error {
+	return SignURLs(a, URLSignerFunc(func(ref string, expires time.Time) (string, error) {
+		return "https://cdn.example.org/" + ref + "?ttl=" + time.Until(expires).Round(time.Minute).String(), nil
+	}), 15*time.Minute)
+}
+
+func TestSignURLs(t *testing.T) {
+	thumb := "thumb.png"
+	article := &signedArticle{
+		Title:       "title.png",
+		Cover:       "cover.png",
+		Thumbnail:   &thumb,
+		Files:       []string{"a.pdf", "b.pdf"},
+		Attachments: []*signedAttachment{{Name: "c", Ref: "c.pdf"}, nil},
+	}
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	if err := renderer(httptest.NewRecorder(), r, article); err != nil {
+		t.Fatalf("error, expected nil, got %v", err)
+	}
+
+	thumb = "https://cdn.example.org/thumb.png?ttl=1h0m0s"
+	expected := &signedArticle{
+		Title:     "title.png",
+		Cover:     "https://cdn.example.org/cover.png?ttl=15m0s",
+		Thumbnail: &thumb,
+		Files:     []string{"https://cdn.example.org/a.pdf?ttl=15m0s", "https://cdn.example.org/b.pdf?ttl=15m0s"},
+		Attachments: []*signedAttachment{
+			{Name: "c", Ref: "https://cdn.example.org/c.pdf?ttl=15m0s"},
+			nil,
+		},
+	}
+	if !reflect.DeepEqual(article, expected) {
+		t.Errorf("article, expected %+v, got %+v", expected, article)
+	}
+
+	err := SignURLs(&struct {
+		Ref string `signed_url:"soon"`
+	}{Ref: "a"}, URLSignerFunc(func(ref string, _ time.Time) (string, error) { return ref, nil }), time.Minute)
+	if err == nil {
+		t.Errorf("error, expected invalid duration, got nil")
+	}
+}
+
+func TestHMACURLSigner(t *testing.T) {
+	signer := HMACURLSigner{Key: []byte("secret"), BaseURL: "https://files.example.org/files/"}
+
+	signed, err := signer.SignURL("report 1.pdf", time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("error, expected nil, got %v", err)
+	}
+	if !strings.HasPrefix(signed, "https://files.example.org/files/report%201.pdf?expires=") {
+		t.Errorf("url, expected the file url, got %v", signed)
+	}
+	if err := signer.Verify(httptest.NewRequest(http.MethodGet, signed, nil)); err != nil {
+		t.Errorf("verify error, expected nil, got %v", err)
+	}
+
+	tampered := strings.Replace(signed, "report%201", "report%202", 1)
+	if err := signer.Verify(httptest.NewRequest(http.MethodGet, tampered, nil)); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("verify tampered error, expected %v, got %v", ErrInvalidSignature, err)
+	}
+
+	tampered = strings.Replace(signed, "?expires=", "?download=1&expires=", 1)
+	if err := signer.Verify(httptest.NewRequest(http.MethodGet, tampered, nil)); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("verify tampered query error, expected %v, got %v", ErrInvalidSignature, err)
+	}
+
+	signed, _ = signer.SignURL("report.pdf?version=2", time.Now().Add(time.Minute))
+	if err := signer.Verify(httptest.NewRequest(http.MethodGet, signed, nil)); err != nil {
+		t.Errorf("verify query error, expected nil, got %v", err)
+	}
+	tampered = strings.Replace(signed, "version=2", "version=3", 1)
+	if err := signer.Verify(httptest.NewRequest(http.MethodGet, tampered, nil)); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("verify tampered version error, expected %v, got %v", ErrInvalidSignature, err)
+	}
+
+	expired, _ := signer.SignURL("report 1.pdf", time.Now().Add(-time.Minute))
+	if err := signer.Verify(httptest.NewRequest(http.MethodGet, expired, nil)); !errors.Is(err, ErrURLExpired) {
+		t.Errorf("verify expired error, expected %v, got %v", ErrURLExpired, err)
+	}
+}
@@ -0,0 +1,150 @@
+package render
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"hash"
+	"net/http"
+	"strings"
+)
+
+var (
+	// ErrInvalidSignature is returned by the Verify methods when the signature
+	// header is missing, malformed, or does not match the body.
+	ErrInvalidSignature = errors.New("render: invalid response signature")
+)
+
+// Signer signs a rendered response. Sign is called with the headers and the
+// complete body the responder produced, before anything is sent to the
+// client, and is expected to add the signature to the headers. If Sign returns
+// an error the client gets a 500 response instead.
+type Signer interface {
+	Sign(header http.Header, body []byte) error
+}
+
+// DefaultSignatureHeader is the header the signers use if one is not provided
+const DefaultSignatureHeader = "X-Signature"
+
+// HMACSigner signs the body with an HMAC, the signature is emitted base64url
+// encoded, without padding.
+type HMACSigner struct {
+	// Key is the shared secret
+	Key []byte
+	// Header is the header the signature will be set in; DefaultSignatureHeader
+	// if empty.
+	Header string
+	// Hash is the hash to use; sha256.New if nil.
+	Hash func() hash.Hash
+}
+
+func (s HMACSigner) header() string {
+	if s.Header == "" {
+		return DefaultSignatureHeader
+	}
+	return s.Header
+}
+
+func (s HMACSigner) sum(body []byte) []byte {
+	h := s.Hash
+	if h == nil {
+		h = sha256.New
+	}
+	mac := hmac.New(h, s.Key)
+	_, _ = mac.Write(body)
+	return mac.Sum(nil)
+}
+
+// Sign sets the signature header to the HMAC of the body
+func (s HMACSigner) Sign(header http.Header, body []byte) error {
+	header.Set(s.header(), base64.RawURLEncoding.EncodeToString(s.sum(body)))
+	return nil
+}
+
+// Verify checks the signature in the header against the body; it is meant to
+// be used by clients that share the key.
+func (s HMACSigner) Verify(header http.Header, body []byte) error {
+	sig, err := base64.RawURLEncoding.DecodeString(header.Get(s.header()))
+	if err != nil || len(sig) == 0 {
+		return ErrInvalidSignature
+	}
+	if !hmac.Equal(sig, s.sum(body)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// JWSSigner signs the body with a detached JWS (RFC 7515 Appendix F) using
+// HS256. The header will contain the compact serialization with an empty
+// payload: <protected header>..<signature>
+type JWSSigner struct {
+	// Key is the shared secret
+	Key []byte
+	// KeyID, if set, is added to the protected header as "kid"
+	KeyID string
+	// Header is the header the signature will be set in; DefaultSignatureHeader
+	// if empty.
+	Header string
+}
+
+type jwsHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid,omitempty"`
+}
+
+func (s JWSSigner) header() string {
+	if s.Header == "" {
+		return DefaultSignatureHeader
+	}
+	return s.Header
+}
+
+func (s JWSSigner) signature(protected string, body []byte) []byte {
+	mac := hmac.New(sha256.New, s.Key)
+	_, _ = mac.Write([]byte(protected))
+	_, _ = mac.Write([]byte{'.'})
+	_, _ = mac.Write([]byte(base64.RawURLEncoding.EncodeToString(body)))
+	return mac.Sum(nil)
+}
+
+// Sign sets the signature header to the detached JWS of the body
+func (s JWSSigner) Sign(header http.Header, body []byte) error {
+	hdr, err := json.Marshal(jwsHeader{Alg: "HS256", Kid: s.KeyID})
+	if err != nil {
+		return err
+	}
+	protected := base64.RawURLEncoding.EncodeToString(hdr)
+	sig := base64.RawURLEncoding.EncodeToString(s.signature(protected, body))
+	header.Set(s.header(), protected+".."+sig)
+	return nil
+}
+
+// Verify checks the detached JWS in the header against the body; it is meant
+// to be used by clients that share the key.
+func (s JWSSigner) Verify(header http.Header, body []byte) error {
+	parts := strings.Split(header.Get(s.header()), ".")
+	if len(parts) != 3 || parts[1] != "" {
+		return ErrInvalidSignature
+	}
+	hdr, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	var jh jwsHeader
+	if err = json.Unmarshal(hdr, &jh); err != nil || jh.Alg != "HS256" {
+		return ErrInvalidSignature
+	}
+	if s.KeyID != "" && jh.Kid != s.KeyID {
+		return ErrInvalidSignature
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if !hmac.Equal(sig, s.signature(parts[0], body)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
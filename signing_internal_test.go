@@ -0,0 +1,63 @@
+package render
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type verifier interface {
+	Signer
+	Verify(header http.Header, body []byte) error
+}
+
+func TestSigners(t *testing.T) {
+	type tcase struct {
+		Signer verifier
+		Other  verifier
+		Header string
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			ctrl := defaultCtrl.Clone()
+			ctrl.Signer = tc.Signer
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			w := httptest.NewRecorder()
+			ctrl.respond(w, r, map[string]int{"answer": 42})
+
+			if w.Header().Get(tc.Header) == "" {
+				t.Fatalf("signature header %v, expected to be set", tc.Header)
+			}
+			body := w.Body.Bytes()
+			if err := tc.Signer.Verify(w.Header(), body); err != nil {
+				t.Errorf("verify, expected nil, got %v", err)
+			}
+			tampered := append([]byte{}, body...)
+			tampered[0] = '['
+			if err := tc.Signer.Verify(w.Header(), tampered); err != ErrInvalidSignature {
+				t.Errorf("verify tampered, expected %v, got %v", ErrInvalidSignature, err)
+			}
+			if err := tc.Other.Verify(w.Header(), body); err != ErrInvalidSignature {
+				t.Errorf("verify other key, expected %v, got %v", ErrInvalidSignature, err)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"hmac": {
+			Signer: HMACSigner{Key: []byte("secret")},
+			Other:  HMACSigner{Key: []byte("not the secret")},
+			Header: DefaultSignatureHeader,
+		},
+		"jws": {
+			Signer: JWSSigner{Key: []byte("secret"), KeyID: "k1", Header: "X-JWS-Signature"},
+			Other:  JWSSigner{Key: []byte("secret"), KeyID: "k2", Header: "X-JWS-Signature"},
+			Header: "X-JWS-Signature",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
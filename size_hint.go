@@ -0,0 +1,24 @@
+package render
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gdey/chi-render/responders"
+	"github.com/gdey/chi-render/responders/helpers"
+)
+
+// SizeHintCtxKey is a context key for the size hint of the payload being
+// responded with
+var SizeHintCtxKey = helpers.SizeHintCtxKey
+
+// withSizeHint records the size hint of the payload (see
+// responders.EncodedSizeHinter) in the request context, as the projected
+// payloads the structured responders get no longer carry it.
+func withSizeHint(r *http.Request, v interface{}) {
+	hinter, ok := v.(responders.EncodedSizeHinter)
+	if !ok {
+		return
+	}
+	*r = *r.WithContext(context.WithValue(r.Context(), SizeHintCtxKey, hinter.EncodedSizeHint()))
+}
@@ -0,0 +1,36 @@
+package render
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gdey/chi-render/responders"
+	"github.com/gdey/chi-render/responders/helpers"
+)
+
+type hintedUser struct {
+	projectionUser
+}
+
+func (hintedUser) EncodedSizeHint() int { return 512 }
+
+func TestSizeHint(t *testing.T) {
+	ctrl := defaultCtrl.Clone()
+	ctrl.Redact = RedactRemove
+	var hint int
+	ctrl.RegisterResponder(ContentTypeJSON, responders.Registration{
+		Func: func(w http.ResponseWriter, r *http.Request, v interface{}) error {
+			hint = helpers.SizeHint(r)
+			return nil
+		},
+		Structured: true,
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept", "application/json")
+	ctrl.respond(httptest.NewRecorder(), r, hintedUser{projectionUser{ID: 1}})
+	if hint != 512 {
+		t.Errorf("hint, expected 512, got %v", hint)
+	}
+}
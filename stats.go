@@ -0,0 +1,111 @@
+package render
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gdey/chi-render/responders/helpers"
+)
+
+var (
+	StatsCtxKey = helpers.StatsCtxKey
+)
+
+// RenderStats records the sizes and durations of the decode, render and respond
+// phases for a request. If a phase happens more than once for a request, for
+// example Render is called twice, the values are accumulated.
+type RenderStats struct {
+	lck sync.Mutex
+
+	// BytesRead is the number of bytes read from the request body by decoders
+	BytesRead int64
+	// BytesWritten is the number of bytes written to the response body by responders
+	BytesWritten int64
+	// Status is the http status code the responder wrote
+	Status int
+	// ContentType is the value of the Content-Type header of the response
+	ContentType string
+
+	// DecodeDuration is the time spent decoding the request body
+	DecodeDuration time.Duration
+	// BindDuration is the time spent in the Bind methods of the payload
+	BindDuration time.Duration
+	// RenderDuration is the time spent in the Render methods of the payload
+	RenderDuration time.Duration
+	// RespondDuration is the time spent in the responders
+	RespondDuration time.Duration
+
+	// Timings are the spans added with AddTiming, in the order they were first added
+	Timings []Timing
+}
+
+// Snapshot returns a copy of the stats that is safe to read
+func (stats *RenderStats) Snapshot() RenderStats {
+	if stats == nil {
+		return RenderStats{}
+	}
+	stats.lck.Lock()
+	defer stats.lck.Unlock()
+	return RenderStats{
+		BytesRead:       stats.BytesRead,
+		BytesWritten:    stats.BytesWritten,
+		Status:          stats.Status,
+		ContentType:     stats.ContentType,
+		DecodeDuration:  stats.DecodeDuration,
+		BindDuration:    stats.BindDuration,
+		RenderDuration:  stats.RenderDuration,
+		RespondDuration: stats.RespondDuration,
+		Timings:         append([]Timing(nil), stats.Timings...),
+	}
+}
+
+func (stats *RenderStats) update(fn func(stats *RenderStats)) {
+	if stats == nil {
+		return
+	}
+	stats.lck.Lock()
+	fn(stats)
+	stats.lck.Unlock()
+}
+
+// Stats returns the render stats for the request. The stats will be nil if
+// the request has not been through CollectStats or a controller.
+func Stats(r *http.Request) *RenderStats {
+	stats, _ := r.Context().Value(StatsCtxKey).(*RenderStats)
+	return stats
+}
+
+// CollectStats is a middleware that adds a *RenderStats to the request context;
+// use it before any logging middleware that wants to report on the render stats
+// via Stats(r), as the controllers will record into the same object.
+func CollectStats(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), StatsCtxKey, new(RenderStats))))
+	})
+}
+
+// statsFor returns the render stats for the request, adding one to the request
+// if there isn't already one.
+func statsFor(r *http.Request) *RenderStats {
+	if stats := Stats(r); stats != nil {
+		return stats
+	}
+	stats := new(RenderStats)
+	*r = *r.WithContext(context.WithValue(r.Context(), StatsCtxKey, stats))
+	return stats
+}
+
+// countingReader counts the number of bytes read
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n += int64(n)
+	return n, err
+}
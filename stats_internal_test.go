@@ -0,0 +1,48 @@
+package render
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	type payload struct {
+		NilRender
+		NilBinder
+		Name string `json:"name"`
+	}
+
+	var stats RenderStats
+	handler := CollectStats(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p payload
+		if err := Bind(r, &p); err != nil {
+			t.Fatalf("bind error, expected nil, got %v", err)
+		}
+		if err := Render(w, r, &p); err != nil {
+			t.Fatalf("render error, expected nil, got %v", err)
+		}
+		stats = Stats(r).Snapshot()
+	}))
+
+	body := `{"name":"world"}`
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("Accept", "application/json")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	if stats.BytesRead != int64(len(body)) {
+		t.Errorf("bytes read, expected %v, got %v", len(body), stats.BytesRead)
+	}
+	if stats.BytesWritten != int64(w.Body.Len()) {
+		t.Errorf("bytes written, expected %v, got %v", w.Body.Len(), stats.BytesWritten)
+	}
+	if stats.Status != http.StatusOK {
+		t.Errorf("status, expected %v, got %v", http.StatusOK, stats.Status)
+	}
+	if stats.ContentType != "application/json; charset=utf-8" {
+		t.Errorf("content type, expected application/json; charset=utf-8, got %v", stats.ContentType)
+	}
+}
@@ -0,0 +1,47 @@
+package render
+
+import (
+	"mime"
+	"net/http"
+
+	"github.com/gdey/chi-render/responders"
+)
+
+// suffixContentTypes are the content types whose decoders and responders are
+// used for media types with their structured syntax suffix, like
+// application/vnd.acme.article+json, when none are registered for the media
+// type itself.
+var suffixContentTypes = map[string]ContentType{
+	"json": ContentTypeJSON,
+	"xml":  ContentTypeXML,
+}
+
+// suffixFallback returns the content type whose handlers are used for the
+// content type by its structured syntax suffix; ok is false if it has none.
+func suffixFallback(contentType ContentType) (fallback ContentType, ok bool) {
+	if isWildcard(contentType) {
+		return "", false
+	}
+	fallback, ok = suffixContentTypes[contentTypeSuffix(contentType)]
+	return fallback, ok
+}
+
+// suffixResponder returns a responder that labels the response of fn with the
+// content type, keeping the parameters, like the charset, fn set. The header
+// is changed after fn writes the response, which is fine as the controller
+// records the response before passing it on to the client.
+func suffixResponder(contentType ContentType, fn responders.Func) responders.Func {
+	return func(w http.ResponseWriter, r *http.Request, v interface{}) error {
+		if err := fn(w, r, v); err != nil {
+			return err
+		}
+		_, params, err := mime.ParseMediaType(w.Header().Get("Content-Type"))
+		if err != nil {
+			params = nil
+		}
+		if value := mime.FormatMediaType(string(contentType), params); value != "" {
+			w.Header().Set("Content-Type", value)
+		}
+		return nil
+	}
+}
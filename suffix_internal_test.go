@@ -0,0 +1,131 @@
+package render
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gdey/chi-render/decoders"
+)
+
+func TestSuffixFallback(t *testing.T) {
+	const vendorJSON = ContentType("application/vnd.acme.article+json")
+
+	t.Run("respond", func(t *testing.T) {
+		type tcase struct {
+			// Exact registers a responder for the vendor type itself
+			Exact       bool
+			Accept      string
+			ContentType string
+			Body        string
+		}
+
+		fn := func(tc tcase) func(*testing.T) {
+			return func(t *testing.T) {
+				ctrl := CloneDefault()
+				if tc.Exact {
+					ctrl.SetResponder(vendorJSON, func(w http.ResponseWriter, r *http.Request, v interface{}) error {
+						w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+						_, err := w.Write([]byte("exact"))
+						return err
+					})
+				}
+				r := httptest.NewRequest(http.MethodGet, "/", nil)
+				r.Header.Set("Accept", tc.Accept)
+				w := httptest.NewRecorder()
+				if err := ctrl.Render(w, r, &fallbackPayload{Name: "gdey"}); err != nil {
+					t.Fatalf("error, expected nil, got %v", err)
+				}
+				if got := w.Header().Get("Content-Type"); got != tc.ContentType {
+					t.Errorf("content type, expected %v, got %v", tc.ContentType, got)
+				}
+				if got := w.Body.String(); !strings.Contains(got, tc.Body) {
+					t.Errorf("body, expected %s, got %s", tc.Body, got)
+				}
+			}
+		}
+
+		tests := map[string]tcase{
+			"json suffix": {
+				Accept:      "application/vnd.acme.article+json",
+				ContentType: "application/vnd.acme.article+json; charset=utf-8",
+				Body:        `{"name":"gdey"}`,
+			},
+			"xml suffix": {
+				Accept:      "application/vnd.acme.article+xml",
+				ContentType: "application/vnd.acme.article+xml; charset=utf-8",
+				Body:        "<name>gdey</name>",
+			},
+			"exact responder": {
+				Exact:       true,
+				Accept:      "application/vnd.acme.article+json",
+				ContentType: "text/plain; charset=utf-8",
+				Body:        "exact",
+			},
+			"unknown suffix": {
+				Accept:      "application/vnd.acme.article+yaml",
+				ContentType: "application/json; charset=utf-8",
+				Body:        `{"name":"gdey"}`,
+			},
+		}
+		for name, tc := range tests {
+			t.Run(name, fn(tc))
+		}
+	})
+
+	t.Run("decode", func(t *testing.T) {
+		type tcase struct {
+			ContentType string
+			Body        string
+			// Err is true if an error is expected
+			Err bool
+		}
+
+		fn := func(tc tcase) func(*testing.T) {
+			return func(t *testing.T) {
+				ctrl := CloneDefault()
+				ctrl.SetDecoder(ContentType("application/vnd.acme.exact+json"), decoders.XML)
+				r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.Body))
+				r.Header.Set("Content-Type", tc.ContentType)
+				var got fallbackPayload
+				err := ctrl.decode(r, &got)
+				if tc.Err {
+					if err == nil {
+						t.Errorf("error, expected an error, got nil")
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("error, expected nil, got %v", err)
+				}
+				if got.Name != "gdey" {
+					t.Errorf("name, expected gdey, got %q", got.Name)
+				}
+			}
+		}
+
+		tests := map[string]tcase{
+			"json suffix": {
+				ContentType: "application/vnd.acme.article+json; charset=utf-8",
+				Body:        `{"name":"gdey"}`,
+			},
+			"xml suffix": {
+				ContentType: "application/vnd.acme.article+xml",
+				Body:        "<fallbackPayload><name>gdey</name></fallbackPayload>",
+			},
+			"exact decoder": {
+				ContentType: "application/vnd.acme.exact+json",
+				Body:        "<fallbackPayload><name>gdey</name></fallbackPayload>",
+			},
+			"unknown suffix": {
+				ContentType: "application/vnd.acme.article+yaml",
+				Body:        "name: gdey",
+				Err:         true,
+			},
+		}
+		for name, tc := range tests {
+			t.Run(name, fn(tc))
+		}
+	})
+}
@@ -0,0 +1,107 @@
+package render
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ContentTypeDescriptor describes a content type a controller can respond
+// with, or decode, so OPTIONS handlers and API documentation can report the
+// capabilities of the controller.
+type ContentTypeDescriptor struct {
+	ContentType ContentType `json:"content_type"`
+	// Aliases are the wildcard content types, like */*, that are registered
+	// with the same function, and so are served as ContentType
+	Aliases []ContentType `json:"aliases,omitempty"`
+	// Suffix is the structured syntax suffix of the content type, like json
+	// for application/problem+json; empty if it has none
+	Suffix string `json:"suffix,omitempty"`
+	// Streaming is true if the responses are streamed to the client as they
+	// are produced, rather than buffered
+	Streaming bool `json:"streaming,omitempty"`
+}
+
+// ContentTypeDescriptors are descriptors sorted by content type
+type ContentTypeDescriptors []ContentTypeDescriptor
+
+// Types returns the content types of the descriptors, without the aliases
+func (descriptors ContentTypeDescriptors) Types() []ContentType {
+	types := make([]ContentType, 0, len(descriptors))
+	for _, d := range descriptors {
+		types = append(types, d.ContentType)
+	}
+	return types
+}
+
+// Has reports whether the content type, or an alias, is one of the descriptors
+func (descriptors ContentTypeDescriptors) Has(contentType ContentType) bool {
+	for _, d := range descriptors {
+		if d.ContentType == contentType {
+			return true
+		}
+		for _, alias := range d.Aliases {
+			if alias == contentType {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isWildcard reports whether the content type is a media range, like */* or text/*
+func isWildcard(contentType ContentType) bool {
+	return strings.Contains(string(contentType), "*")
+}
+
+// contentTypeSuffix returns the structured syntax suffix of the content type
+func contentTypeSuffix(contentType ContentType) string {
+	if i := strings.LastIndexByte(string(contentType), '+'); i >= 0 {
+		return string(contentType[i+1:])
+	}
+	return ""
+}
+
+// describeContentTypes returns the sorted descriptors for the content types,
+// which are mapped to the functions registered for them. Wildcard content types
+// are folded into the first concrete content type with the same function, as
+// aliases.
+func describeContentTypes(funcs map[ContentType]interface{}) ContentTypeDescriptors {
+	types := make([]string, 0, len(funcs))
+	for ct := range funcs {
+		types = append(types, string(ct))
+	}
+	sort.Strings(types)
+
+	var (
+		descriptors ContentTypeDescriptors
+		wildcards   []ContentType
+	)
+	for _, str := range types {
+		ct := ContentType(str)
+		if isWildcard(ct) {
+			wildcards = append(wildcards, ct)
+			continue
+		}
+		descriptors = append(descriptors, ContentTypeDescriptor{
+			ContentType: ct,
+			Suffix:      contentTypeSuffix(ct),
+			Streaming:   ct == ContentTypeEventStream,
+		})
+	}
+wildcards:
+	for _, wildcard := range wildcards {
+		fn := reflect.ValueOf(funcs[wildcard]).Pointer()
+		for i := range descriptors {
+			if reflect.ValueOf(funcs[descriptors[i].ContentType]).Pointer() == fn {
+				descriptors[i].Aliases = append(descriptors[i].Aliases, wildcard)
+				continue wildcards
+			}
+		}
+		descriptors = append(descriptors, ContentTypeDescriptor{ContentType: wildcard})
+	}
+	sort.Slice(descriptors, func(i, j int) bool {
+		return descriptors[i].ContentType < descriptors[j].ContentType
+	})
+	return descriptors
+}
@@ -0,0 +1,44 @@
+package render
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/gdey/chi-render/decoders"
+	"github.com/gdey/chi-render/responders"
+)
+
+func TestSupportedResponders(t *testing.T) {
+	ctrl := defaultCtrl.Clone()
+	_ = ctrl.SetResponder(ContentTypeProblemJSON, responders.ProblemJSON)
+	_ = ctrl.SetResponder(ContentType("text/*"), func(http.ResponseWriter, *http.Request, interface{}) error { return nil })
+	_ = ctrl.SetResponder(ContentTypeHTML, nil)
+
+	expected := ContentTypeDescriptors{
+		{ContentType: ContentTypeJSON, Aliases: []ContentType{ContentTypeDefault}},
+		{ContentType: ContentTypeData},
+		{ContentType: ContentTypeProblemJSON, Suffix: "json"},
+		{ContentType: ContentType("text/*")},
+		{ContentType: ContentTypeEventStream, Streaming: true},
+		{ContentType: ContentTypeXML},
+	}
+	got := ctrl.SupportedResponders()
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("responders, expected %+v, got %+v", expected, got)
+	}
+	for _, ct := range []ContentType{ContentTypeDefault, ContentTypeJSON, ContentType("text/*")} {
+		if !got.Has(ct) {
+			t.Errorf("has %v, expected true", ct)
+		}
+	}
+	if got.Has(ContentTypeHTML) {
+		t.Errorf("has %v, expected false", ContentTypeHTML)
+	}
+
+	_ = ctrl.SetDecoder(ContentType("application/merge-patch+json"), decoders.JSON)
+	expectedTypes := []ContentType{ContentTypeJSON, ContentType("application/merge-patch+json"), ContentTypeURLEncoded, ContentTypeForm, ContentTypeXML}
+	if got := ctrl.SupportedDecoders().Types(); !reflect.DeepEqual(got, expectedTypes) {
+		t.Errorf("decoders, expected %v, got %v", expectedTypes, got)
+	}
+}
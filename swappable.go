@@ -0,0 +1,44 @@
+package render
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/gdey/chi-render/responders/helpers"
+)
+
+// SwappableController holds a controller that can be atomically replaced while
+// requests are being served; for example when the configuration is reloaded
+// to roll out a new format.
+type SwappableController struct {
+	v atomic.Value
+}
+
+// NewSwappableController returns a SwappableController holding the given controller
+func NewSwappableController(c *Controller) *SwappableController {
+	s := new(SwappableController)
+	s.Store(c)
+	return s
+}
+
+// Load returns the current controller
+func (s *SwappableController) Load() *Controller {
+	c, _ := s.v.Load().(*Controller)
+	return c
+}
+
+// Store replaces the current controller; requests already in flight will
+// continue to use the controller they started with.
+func (s *SwappableController) Store(c *Controller) { s.v.Store(c) }
+
+// WithSwappableCtx is like WithCtx, but attaches the controller currently held by
+// the SwappableController at the start of each request.
+func WithSwappableCtx(s *SwappableController) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			*r = *r.WithContext(context.WithValue(r.Context(), helpers.RenderCtxKey, s.Load()))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package render
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ValidateTemplates calls the Validate func of every registered responder,
+// error responder and profile responder that has one, such as the
+// registration of responders.Templates, so that broken templates are found at
+// startup rather than by the first request that uses them.
+//
+//	_ = ctrl.RegisterResponder(render.ContentTypeHTML, tmpls.Registration())
+//	if err := ctrl.ValidateTemplates(); err != nil {
+//		log.Fatal(err)
+//	}
+func (ctrl *Controller) ValidateTemplates() error {
+	if ctrl == nil {
+		return ErrControllerIsNil
+	}
+	type validator struct {
+		name     string
+		validate func() error
+	}
+	var validators []validator
+	ctrl.responderLck.RLock()
+	for ct, reg := range ctrl.responders {
+		if reg.Validate != nil {
+			validators = append(validators, validator{string(ct), reg.Validate})
+		}
+	}
+	for ct, reg := range ctrl.errorResponders {
+		if reg.Validate != nil {
+			validators = append(validators, validator{"error " + string(ct), reg.Validate})
+		}
+	}
+	for key, reg := range ctrl.profileResponders {
+		if reg.Validate != nil {
+			validators = append(validators, validator{string(key.contentType) + `;profile="` + key.profile + `"`, reg.Validate})
+		}
+	}
+	ctrl.responderLck.RUnlock()
+
+	sort.Slice(validators, func(i, j int) bool { return validators[i].name < validators[j].name })
+	for _, v := range validators {
+		if err := v.validate(); err != nil {
+			return fmt.Errorf("render: responder '%s': %w", v.name, err)
+		}
+	}
+	return nil
+}
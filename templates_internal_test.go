@@ -0,0 +1,34 @@
+package render
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gdey/chi-render/responders"
+)
+
+func TestValidateTemplates(t *testing.T) {
+	ctrl := defaultCtrl.Clone()
+	if err := ctrl.ValidateTemplates(); err != nil {
+		t.Fatalf("error, expected nil, got %v", err)
+	}
+
+	errBroken := errors.New("broken template")
+	_ = ctrl.RegisterProfileResponder(ContentTypeHTML, "https://example.org/card", responders.Registration{
+		Func:     responders.HTML,
+		Validate: func() error { return errBroken },
+	})
+	err := ctrl.ValidateTemplates()
+	if !errors.Is(err, errBroken) {
+		t.Fatalf("error, expected %v, got %v", errBroken, err)
+	}
+	if !strings.Contains(err.Error(), `text/html;profile="https://example.org/card"`) {
+		t.Errorf("error, expected the responder in %q", err)
+	}
+
+	var nilCtrl *Controller
+	if err := nilCtrl.ValidateTemplates(); err != ErrControllerIsNil {
+		t.Errorf("nil controller error, expected %v, got %v", ErrControllerIsNil, err)
+	}
+}
@@ -0,0 +1,97 @@
+package render
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// TimeFormat is how time.Time values are written by the structured responders.
+// Besides the constants below, any other value is used as the layout for
+// time.Format.
+type TimeFormat string
+
+const (
+	// TimeAsIs leaves the encoding to the time.Time type (RFC 3339 with nanoseconds)
+	TimeAsIs TimeFormat = ""
+	// TimeRFC3339 writes the time as a RFC 3339 string, without fractional seconds
+	TimeRFC3339 TimeFormat = time.RFC3339
+	// TimeUnix writes the time as the number of seconds since the Unix epoch
+	TimeUnix TimeFormat = "unix"
+	// TimeUnixMillis writes the time as the number of milliseconds since the Unix epoch
+	TimeUnixMillis TimeFormat = "unix_millis"
+)
+
+// DurationFormat is how time.Duration values are written by the structured responders
+type DurationFormat uint8
+
+const (
+	// DurationAsIs writes durations as the number of nanoseconds
+	DurationAsIs DurationFormat = iota
+	// DurationString writes durations as strings like "1h2m0.5s"
+	DurationString
+	// DurationSeconds writes durations as a floating point number of seconds
+	DurationSeconds
+	// DurationMillis writes durations as the number of milliseconds
+	DurationMillis
+)
+
+// String returns the name of the format, as used by Describe
+func (format DurationFormat) String() string {
+	switch format {
+	case DurationAsIs:
+		return "as_is"
+	case DurationString:
+		return "string"
+	case DurationSeconds:
+		return "seconds"
+	case DurationMillis:
+		return "millis"
+	default:
+		return fmt.Sprintf("DurationFormat(%d)", uint8(format))
+	}
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// formatTime applies the time and duration formats of the controller to v; ok is
+// false if v is not a time or duration, or there is no format for it.
+func (ctrl *Controller) formatTime(v reflect.Value) (out interface{}, ok bool) {
+	typ := v.Type()
+	if typ.Kind() == reflect.Ptr && (typ.Elem() == timeType || typ.Elem() == durationType) {
+		if v.IsNil() {
+			return nil, ctrl.TimeFormat != TimeAsIs || ctrl.DurationFormat != DurationAsIs
+		}
+		v, typ = v.Elem(), typ.Elem()
+	}
+	switch typ {
+	case timeType:
+		t := v.Interface().(time.Time)
+		switch ctrl.TimeFormat {
+		case TimeAsIs:
+			return nil, false
+		case TimeUnix:
+			return t.Unix(), true
+		case TimeUnixMillis:
+			return t.UnixNano() / int64(time.Millisecond), true
+		default:
+			return t.Format(string(ctrl.TimeFormat)), true
+		}
+	case durationType:
+		d := v.Interface().(time.Duration)
+		switch ctrl.DurationFormat {
+		case DurationString:
+			return d.String(), true
+		case DurationSeconds:
+			return d.Seconds(), true
+		case DurationMillis:
+			return d.Milliseconds(), true
+		default:
+			return nil, false
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,66 @@
+package render
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTimeFormat(t *testing.T) {
+	type event struct {
+		At      time.Time      `json:"at"`
+		Ends    *time.Time     `json:"ends"`
+		Took    time.Duration  `json:"took"`
+		Timeout *time.Duration `json:"timeout,omitempty"`
+	}
+	type tcase struct {
+		Time     TimeFormat
+		Duration DurationFormat
+		Body     string
+	}
+
+	at := time.Date(2021, 3, 4, 5, 6, 7, 500000000, time.UTC)
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			ctrl := defaultCtrl.Clone()
+			ctrl.TimeFormat = tc.Time
+			ctrl.DurationFormat = tc.Duration
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			w := httptest.NewRecorder()
+			ctrl.respond(w, r, event{At: at, Took: 1500 * time.Millisecond})
+			if got := w.Body.String(); got != tc.Body {
+				t.Errorf("body, expected %s, got %s", tc.Body, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"as is": {
+			Body: `{"at":"2021-03-04T05:06:07.5Z","ends":null,"took":1500000000}` + "\n",
+		},
+		"rfc3339 and string": {
+			Time:     TimeRFC3339,
+			Duration: DurationString,
+			Body:     `{"at":"2021-03-04T05:06:07Z","ends":null,"took":"1.5s"}` + "\n",
+		},
+		"unix millis and millis": {
+			Time:     TimeUnixMillis,
+			Duration: DurationMillis,
+			Body:     `{"at":1614834367500,"ends":null,"took":1500}` + "\n",
+		},
+		"unix and seconds": {
+			Time:     TimeUnix,
+			Duration: DurationSeconds,
+			Body:     `{"at":1614834367,"ends":null,"took":1.5}` + "\n",
+		},
+		"layout": {
+			Time: TimeFormat("2006-01-02"),
+			Body: `{"at":"2021-03-04","ends":null,"took":1500000000}` + "\n",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
@@ -0,0 +1,62 @@
+package render
+
+import (
+	"reflect"
+)
+
+// TypeEncoder returns the value that should be encoded in place of v by the
+// structured responders; v will be of the type the encoder was registered for.
+type TypeEncoder func(v interface{}) (interface{}, error)
+
+// RegisterTypeEncoder will set the encoder used by the structured responders
+// for values of the given type, wherever they appear in the payload. This gives
+// third party types, like uuids or decimals, a consistent encoding without
+// wrapper types. Use a nil encoder to unset a type.
+// Only error this function will return is ErrControllerIsNil; is returned
+// if the Controller object is nil.
+func (ctrl *Controller) RegisterTypeEncoder(typ reflect.Type, encoder TypeEncoder) error {
+	if ctrl == nil {
+		return ErrControllerIsNil
+	}
+	ctrl.typeEncoderLck.Lock()
+	// the map is replaced, not modified, so projections in flight can keep
+	// using the one they started with
+	encoders := make(map[reflect.Type]TypeEncoder, len(ctrl.typeEncoders)+1)
+	for t, enc := range ctrl.typeEncoders {
+		encoders[t] = enc
+	}
+	if encoder == nil {
+		delete(encoders, typ)
+	} else {
+		encoders[typ] = encoder
+	}
+	ctrl.typeEncoders = encoders
+	ctrl.typeEncoderLck.Unlock()
+	return nil
+}
+
+// encoders returns the registered type encoders; the map must not be modified
+func (ctrl *Controller) encoders() map[reflect.Type]TypeEncoder {
+	ctrl.typeEncoderLck.RLock()
+	defer ctrl.typeEncoderLck.RUnlock()
+	return ctrl.typeEncoders
+}
+
+// encodeType applies the encoder registered for the type of v, if any
+func (p *projector) encodeType(v reflect.Value, path string, depth int) (interface{}, bool, error) {
+	typ := v.Type()
+	encoder, ok := p.encoders[typ]
+	if !ok {
+		return nil, false, nil
+	}
+	encoded, err := encoder(v.Interface())
+	if err != nil {
+		return nil, true, err
+	}
+	if encoded == nil || reflect.TypeOf(encoded) == typ {
+		// don't encode the value again
+		return encoded, true, nil
+	}
+	value, err := p.value(reflect.ValueOf(encoded), path, depth+1)
+	return value, true, err
+}
@@ -0,0 +1,134 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+type testUUID [4]byte
+
+type testTag struct {
+	ID testUUID
+}
+
+type testPoint struct {
+	X, Y float64
+}
+
+func TestRegisterTypeEncoder(t *testing.T) {
+	type place struct {
+		ID     testUUID            `json:"id"`
+		At     testPoint           `json:"at"`
+		Path   []testPoint         `json:"path,omitempty"`
+		Nearby map[string]testUUID `json:"nearby,omitempty"`
+		Owner  *testUUID           `json:"owner"`
+	}
+	type tcase struct {
+		Encoders map[reflect.Type]TypeEncoder
+		Accept   string
+		Payload  interface{}
+		Status   int
+		Body     string
+	}
+
+	uuid := func(v interface{}) (interface{}, error) {
+		id := v.(testUUID)
+		return fmt.Sprintf("%x-%x", id[:2], id[2:]), nil
+	}
+	point := func(v interface{}) (interface{}, error) {
+		p := v.(testPoint)
+		return []float64{p.X, p.Y}, nil
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			ctrl := defaultCtrl.Clone()
+			for typ, enc := range tc.Encoders {
+				_ = ctrl.RegisterTypeEncoder(typ, enc)
+			}
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.Accept != "" {
+				r.Header.Set("Accept", tc.Accept)
+			}
+			w := httptest.NewRecorder()
+			ctrl.respond(w, r, tc.Payload)
+			status := tc.Status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			if w.Code != status {
+				t.Errorf("status, expected %v, got %v", status, w.Code)
+			}
+			if tc.Body == "" {
+				return
+			}
+			if got := w.Body.String(); got != tc.Body {
+				t.Errorf("body, expected %s, got %s", tc.Body, got)
+			}
+		}
+	}
+
+	owner := testUUID{9, 9, 9, 9}
+	tests := map[string]tcase{
+		"none": {
+			Payload: place{ID: testUUID{1, 2, 3, 4}, At: testPoint{1, 2}},
+			Body:    `{"id":[1,2,3,4],"at":{"X":1,"Y":2},"owner":null}` + "\n",
+		},
+		"encoded": {
+			Encoders: map[reflect.Type]TypeEncoder{
+				reflect.TypeOf(testUUID{}):  uuid,
+				reflect.TypeOf(testPoint{}): point,
+			},
+			Payload: place{
+				ID:     testUUID{1, 2, 3, 4},
+				At:     testPoint{1, 2},
+				Path:   []testPoint{{3, 4}},
+				Nearby: map[string]testUUID{"a": {0xa, 0xb, 0xc, 0xd}},
+				Owner:  &owner,
+			},
+			Body: `{"id":"0102-0304","at":[1,2],"path":[[3,4]],"nearby":{"a":"0a0b-0c0d"},"owner":"0909-0909"}` + "\n",
+		},
+		"xml": {
+			Encoders: map[reflect.Type]TypeEncoder{
+				reflect.TypeOf(testUUID{}): uuid,
+			},
+			Accept:  "text/xml",
+			Payload: testTag{ID: testUUID{1, 2, 3, 4}},
+			Body:    `<?xml version="1.0" encoding="UTF-8"?>` + "\n" + `<testTag><ID>0102-0304</ID></testTag>`,
+		},
+		"same type": {
+			Encoders: map[reflect.Type]TypeEncoder{
+				reflect.TypeOf(testPoint{}): func(v interface{}) (interface{}, error) {
+					p := v.(testPoint)
+					return testPoint{X: p.Y, Y: p.X}, nil
+				},
+			},
+			Payload: testPoint{1, 2},
+			Body:    `{"X":2,"Y":1}` + "\n",
+		},
+		"unset": {
+			Encoders: map[reflect.Type]TypeEncoder{
+				reflect.TypeOf(testPoint{}): nil,
+			},
+			Payload: testPoint{1, 2},
+			Body:    `{"X":1,"Y":2}` + "\n",
+		},
+		"error": {
+			Encoders: map[reflect.Type]TypeEncoder{
+				reflect.TypeOf(testPoint{}): func(interface{}) (interface{}, error) {
+					return nil, errors.New("bad point")
+				},
+			},
+			Payload: testPoint{1, 2},
+			Status:  http.StatusInternalServerError,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
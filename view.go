@@ -0,0 +1,86 @@
+package render
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/gdey/chi-render/responders/helpers"
+)
+
+var (
+	ViewerRolesCtxKey = helpers.ViewerRolesCtxKey
+)
+
+// SetViewerRoles records the roles of the viewer of the response in the request
+// context. Fields tagged with `view:"admin,owner"` are only included by the
+// structured responders if the viewer has one of the listed roles; requests
+// that have no roles set see none of them, unless the controller IgnoreViews.
+func SetViewerRoles(r *http.Request, roles ...string) {
+	*r = *r.WithContext(context.WithValue(r.Context(), ViewerRolesCtxKey, roles))
+}
+
+// ViewerRoles returns the roles of the viewer of the response
+func ViewerRoles(r *http.Request) []string {
+	roles, _ := r.Context().Value(ViewerRolesCtxKey).([]string)
+	return roles
+}
+
+var (
+	// containsViewCache caches whether a type can hold fields with views
+	containsViewCache sync.Map // map[reflect.Type]bool
+	// containsInterfaceCache caches whether a type can hold interfaces
+	containsInterfaceCache sync.Map // map[reflect.Type]bool
+)
+
+// containsView reports whether values of the type can hold fields tagged with
+// `view`; values held by interfaces are not considered.
+func containsView(typ reflect.Type) bool {
+	if has, ok := containsViewCache.Load(typ); ok {
+		return has.(bool)
+	}
+	has := typeContains(typ, func(_ reflect.Type, sf *structField) bool {
+		if sf == nil {
+			return false
+		}
+		_, ok := sf.tag.Lookup("view")
+		return ok
+	})
+	containsViewCache.Store(typ, has)
+	return has
+}
+
+// containsInterface reports whether values of the type can hold interfaces
+func containsInterface(typ reflect.Type) bool {
+	if has, ok := containsInterfaceCache.Load(typ); ok {
+		return has.(bool)
+	}
+	has := typeContains(typ, func(typ reflect.Type, _ *structField) bool {
+		return typ.Kind() == reflect.Interface
+	})
+	containsInterfaceCache.Store(typ, has)
+	return has
+}
+
+// holdsView reports whether the value holds fields tagged with `view`; the
+// values held by interfaces are walked to find out. Values too deep to tell
+// are projected, and so refused as too deep.
+func holdsView(v reflect.Value, depth int) bool {
+	return holds(v, depth, containsView)
+}
+
+// viewable reports whether a field with the given view tag is visible to the roles
+func viewable(sf structField, roles map[string]bool) bool {
+	tag, ok := sf.tag.Lookup("view")
+	if !ok {
+		return true
+	}
+	for _, role := range strings.Split(tag, ",") {
+		if roles[strings.TrimSpace(role)] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+package render
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestViews(t *testing.T) {
+	type account struct {
+		ID      int    `json:"id"`
+		Email   string `json:"email" view:"admin,owner"`
+		Notes   string `json:"notes" view:"admin"`
+		Balance int    `json:"balance" view:"owner"`
+	}
+	type tcase struct {
+		Ignore bool
+		Roles  []string
+		// Wrap has the account held by an interface
+		Wrap bool
+		Body string
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			ctrl := defaultCtrl.Clone()
+			ctrl.IgnoreViews = tc.Ignore
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("Accept", "application/json")
+			if tc.Roles != nil {
+				SetViewerRoles(r, tc.Roles...)
+			}
+			w := httptest.NewRecorder()
+			var v interface{} = account{ID: 1, Email: "g@example.org", Notes: "vip", Balance: 10}
+			if tc.Wrap {
+				v = []interface{}{v}
+			}
+			ctrl.respond(w, r, v)
+			if got := w.Body.String(); got != tc.Body {
+				t.Errorf("body, expected %s, got %s", tc.Body, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"ignored": {
+			Ignore: true,
+			Roles:  []string{"owner"},
+			Body:   `{"id":1,"email":"g@example.org","notes":"vip","balance":10}` + "\n",
+		},
+		"no viewer": {
+			Body: `{"id":1}` + "\n",
+		},
+		"no viewer in interface": {
+			Wrap: true,
+			Body: `[{"id":1}]` + "\n",
+		},
+		"no roles": {
+			Roles: []string{},
+			Body:  `{"id":1}` + "\n",
+		},
+		"admin": {
+			Roles: []string{"admin"},
+			Body:  `{"id":1,"email":"g@example.org","notes":"vip"}` + "\n",
+		},
+		"owner": {
+			Roles: []string{"owner"},
+			Body:  `{"id":1,"email":"g@example.org","balance":10}` + "\n",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
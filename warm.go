@@ -0,0 +1,69 @@
+package render
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"reflect"
+)
+
+// Warm builds, ahead of the first request, the reflection state the controller
+// and the encoders keep for the given payload types: the fields of the structs
+// reachable from them, whether they hold enums or protected fields, and the
+// encoding/json and encoding/xml encoders. Call it at startup in latency
+// sensitive services, so the first requests for a payload are not slower than
+// the rest.
+//
+//	ctrl.Warm(&ArticleResponse{}, &ArticleRequest{}, reflect.TypeOf(Page{}))
+//
+// The types are given as values of the type, or as reflect.Type; nil values are
+// ignored. Only error this function will return is ErrControllerIsNil; is
+// returned if the Controller object is nil.
+func (ctrl *Controller) Warm(types ...interface{}) error {
+	if ctrl == nil {
+		return ErrControllerIsNil
+	}
+	for _, v := range types {
+		typ, ok := v.(reflect.Type)
+		if !ok {
+			typ = reflect.TypeOf(v)
+		}
+		if typ == nil {
+			continue
+		}
+		for typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+		warmType(typ)
+	}
+	return nil
+}
+
+// warmType fills the reflection caches for the type, and for pointers to it,
+// which is how payloads are usually bound and rendered
+func warmType(typ reflect.Type) {
+	ptr := reflect.New(typ)
+
+	// structFields caches the fields of every struct on the way
+	never := func(reflect.Type, *structField) bool { return false }
+	typeContains(ptr.Type(), never)
+	// and boundFields the fields Bind sets, which include the ones
+	// encoding/json skips
+	typeContainsIn(ptr.Type(), boundFields, never)
+	containsEnum(typ)
+	containsEnum(ptr.Type())
+	// a new value has nothing to copy, only the type is looked at
+	protectedFields(ptr.Interface())
+
+	// the encoders are built the first time a type is marshaled; the
+	// errors of types that can not be encoded are of no interest here
+	warmEncoder(json.Marshal, ptr.Interface())
+	warmEncoder(xml.Marshal, ptr.Interface())
+}
+
+// warmEncoder marshals the zero value to build the encoder of its type. The
+// Marshal methods of the type may not expect a zero value and panic, which
+// does not stop the encoder from being built, so the panic is recovered.
+func warmEncoder(marshal func(v interface{}) ([]byte, error), v interface{}) {
+	defer func() { _ = recover() }()
+	_, _ = marshal(v)
+}
@@ -0,0 +1,115 @@
+package render
+
+import (
+	"encoding/xml"
+	"reflect"
+	"testing"
+)
+
+// panicky panics when marshaled as a zero value
+type panicky struct {
+	Inner *struct{ Name string }
+}
+
+func (p *panicky) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + p.Inner.Name + `"`), nil
+}
+
+func (p *panicky) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement(p.Inner.Name, start)
+}
+
+func TestWarm(t *testing.T) {
+	type address struct {
+		City string `json:"city"`
+	}
+	type customer struct {
+		ID        string              `json:"id" protect:"true"`
+		Status    testStatus          `json:"status"`
+		Addresses []address           `json:"addresses"`
+		Tags      map[string]*address `json:"tags"`
+	}
+	type order struct {
+		Customer *customer `json:"customer"`
+	}
+	type tcase struct {
+		Types []interface{}
+		// Structs are the types expected in the fields cache
+		Structs []reflect.Type
+		// Enums are the types expected in the enum cache, with their value
+		Enums map[reflect.Type]bool
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			if err := (&Controller{}).Warm(tc.Types...); err != nil {
+				t.Fatalf("error, expected nil, got %v", err)
+			}
+			for _, typ := range tc.Structs {
+				if _, ok := structFieldsCache.Load(typ); !ok {
+					t.Errorf("fields of %v, expected them cached", typ)
+				}
+			}
+			for typ, expected := range tc.Enums {
+				has, ok := containsEnumCache.Load(typ)
+				if !ok || has.(bool) != expected {
+					t.Errorf("enums of %v, expected %v, got %v", typ, expected, has)
+				}
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"nested": {
+			Types:   []interface{}{&order{}},
+			Structs: []reflect.Type{reflect.TypeOf(order{}), reflect.TypeOf(customer{}), reflect.TypeOf(address{})},
+			Enums: map[reflect.Type]bool{
+				reflect.TypeOf(order{}):  true,
+				reflect.TypeOf(&order{}): true,
+			},
+		},
+		"reflect type": {
+			Types:   []interface{}{reflect.TypeOf(address{})},
+			Structs: []reflect.Type{reflect.TypeOf(address{})},
+			Enums:   map[reflect.Type]bool{reflect.TypeOf(&address{}): false},
+		},
+		"not structs": {
+			Types: []interface{}{nil, 1, "s", []int{}, map[string]interface{}{}, make(chan int), func() {}},
+			Enums: map[reflect.Type]bool{reflect.TypeOf(""): false},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+
+	t.Run("protected", func(t *testing.T) {
+		Warm(customer{})
+		if has, ok := protectedCache.Load(reflect.TypeOf(&customer{})); !ok || !has.(bool) {
+			t.Errorf("protected fields, expected them cached, got %v", has)
+		}
+	})
+	t.Run("panicking marshaler", func(t *testing.T) {
+		if err := (&Controller{}).Warm(&panicky{}); err != nil {
+			t.Errorf("error, expected nil, got %v", err)
+		}
+	})
+	t.Run("bound fields", func(t *testing.T) {
+		type search struct {
+			Query string `json:"-" query:"q"`
+		}
+		type request struct {
+			Search search `json:"search"`
+		}
+		Warm(request{})
+		fields, ok := boundFieldsCache.Load(reflect.TypeOf(search{}))
+		if !ok || len(fields.([]structField)) != 1 {
+			t.Errorf("bound fields, expected them cached, got %v", fields)
+		}
+	})
+	t.Run("nil controller", func(t *testing.T) {
+		var ctrl *Controller
+		if err := ctrl.Warm(order{}); err != ErrControllerIsNil {
+			t.Errorf("error, expected %v, got %v", ErrControllerIsNil, err)
+		}
+	})
+}